@@ -0,0 +1,329 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.35.1
+// 	protoc        v5.29.3
+// source: goose/annotations.proto
+
+package annotations
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// BodyEncoding is the encoding of a request body.
+//
+// In forms, scalar, enum and wrapper fields are sent as form values, repeated fields as
+// repeated values, bytes fields as files, google.api.HttpBody fields as files with their
+// content type. Other message and map fields are not sent.
+type BodyEncoding int32
+
+const (
+	// BODY_ENCODING_JSON encodes the body as JSON, the default.
+	BodyEncoding_BODY_ENCODING_JSON BodyEncoding = 0
+	// BODY_ENCODING_FORM encodes the body as application/x-www-form-urlencoded,
+	// bytes fields are sent as values, google.api.HttpBody fields are not supported.
+	BodyEncoding_BODY_ENCODING_FORM BodyEncoding = 1
+	// BODY_ENCODING_MULTIPART encodes the body as multipart/form-data.
+	BodyEncoding_BODY_ENCODING_MULTIPART BodyEncoding = 2
+)
+
+// Enum value maps for BodyEncoding.
+var (
+	BodyEncoding_name = map[int32]string{
+		0: "BODY_ENCODING_JSON",
+		1: "BODY_ENCODING_FORM",
+		2: "BODY_ENCODING_MULTIPART",
+	}
+	BodyEncoding_value = map[string]int32{
+		"BODY_ENCODING_JSON":      0,
+		"BODY_ENCODING_FORM":      1,
+		"BODY_ENCODING_MULTIPART": 2,
+	}
+)
+
+func (x BodyEncoding) Enum() *BodyEncoding {
+	p := new(BodyEncoding)
+	*p = x
+	return p
+}
+
+func (x BodyEncoding) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BodyEncoding) Descriptor() protoreflect.EnumDescriptor {
+	return file_goose_annotations_proto_enumTypes[0].Descriptor()
+}
+
+func (BodyEncoding) Type() protoreflect.EnumType {
+	return &file_goose_annotations_proto_enumTypes[0]
+}
+
+func (x BodyEncoding) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BodyEncoding.Descriptor instead.
+func (BodyEncoding) EnumDescriptor() ([]byte, []int) {
+	return file_goose_annotations_proto_rawDescGZIP(), []int{0}
+}
+
+// Response describes the successful HTTP response of a method.
+//
+// Example:
+//
+//	rpc CreateUser(CreateUserRequest) returns (CreateUserResponse) {
+//	  option (google.api.http) = {
+//	    post: "/v1/user"
+//	    body: "*"
+//	  };
+//	  option (leo.goose.response) = {
+//	    status: 201
+//	  };
+//	}
+type Response struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// status is the HTTP status code of a successful response, 200 if unset.
+	// Responses with status 1xx, 204 and 304 have no body.
+	// It is ignored for methods returning google.rpc.HttpResponse, which carries its own status.
+	Status int32 `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+}
+
+func (x *Response) Reset() {
+	*x = Response{}
+	mi := &file_goose_annotations_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Response) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Response) ProtoMessage() {}
+
+func (x *Response) ProtoReflect() protoreflect.Message {
+	mi := &file_goose_annotations_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Response.ProtoReflect.Descriptor instead.
+func (*Response) Descriptor() ([]byte, []int) {
+	return file_goose_annotations_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Response) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+// Request describes the HTTP request of a method.
+//
+// Example:
+//
+//	rpc UploadAvatar(UploadAvatarRequest) returns (UploadAvatarResponse) {
+//	  option (google.api.http) = {
+//	    post: "/v1/user/{id}/avatar"
+//	    body: "*"
+//	  };
+//	  option (leo.goose.request) = {
+//	    body_encoding: BODY_ENCODING_MULTIPART
+//	  };
+//	}
+type Request struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// body_encoding is the encoding of the request body sent by the client.
+	// Whatever the encoding, the server accepts JSON, URL-encoded and multipart bodies.
+	// Only valid for methods whose body is a message, neither google.api.HttpBody nor google.rpc.HttpRequest.
+	BodyEncoding BodyEncoding `protobuf:"varint,1,opt,name=body_encoding,json=bodyEncoding,proto3,enum=leo.goose.BodyEncoding" json:"body_encoding,omitempty"`
+}
+
+func (x *Request) Reset() {
+	*x = Request{}
+	mi := &file_goose_annotations_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Request) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Request) ProtoMessage() {}
+
+func (x *Request) ProtoReflect() protoreflect.Message {
+	mi := &file_goose_annotations_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Request.ProtoReflect.Descriptor instead.
+func (*Request) Descriptor() ([]byte, []int) {
+	return file_goose_annotations_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Request) GetBodyEncoding() BodyEncoding {
+	if x != nil {
+		return x.BodyEncoding
+	}
+	return BodyEncoding_BODY_ENCODING_JSON
+}
+
+var file_goose_annotations_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
+		ExtensionType: (*Response)(nil),
+		Field:         52711,
+		Name:          "leo.goose.response",
+		Tag:           "bytes,52711,opt,name=response",
+		Filename:      "goose/annotations.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
+		ExtensionType: (*Request)(nil),
+		Field:         52712,
+		Name:          "leo.goose.request",
+		Tag:           "bytes,52712,opt,name=request",
+		Filename:      "goose/annotations.proto",
+	},
+}
+
+// Extension fields to descriptorpb.MethodOptions.
+var (
+	// response describes the successful HTTP response of the method.
+	//
+	// optional leo.goose.Response response = 52711;
+	E_Response = &file_goose_annotations_proto_extTypes[0]
+	// request describes the HTTP request of the method.
+	//
+	// optional leo.goose.Request request = 52712;
+	E_Request = &file_goose_annotations_proto_extTypes[1]
+)
+
+var File_goose_annotations_proto protoreflect.FileDescriptor
+
+var file_goose_annotations_proto_rawDesc = []byte{
+	0x0a, 0x17, 0x67, 0x6f, 0x6f, 0x73, 0x65, 0x2f, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09, 0x6c, 0x65, 0x6f, 0x2e, 0x67,
+	0x6f, 0x6f, 0x73, 0x65, 0x1a, 0x20, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x22, 0x0a, 0x08, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x47, 0x0a, 0x07, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3c, 0x0a, 0x0d, 0x62, 0x6f, 0x64, 0x79, 0x5f, 0x65, 0x6e,
+	0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x6c,
+	0x65, 0x6f, 0x2e, 0x67, 0x6f, 0x6f, 0x73, 0x65, 0x2e, 0x42, 0x6f, 0x64, 0x79, 0x45, 0x6e, 0x63,
+	0x6f, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x0c, 0x62, 0x6f, 0x64, 0x79, 0x45, 0x6e, 0x63, 0x6f, 0x64,
+	0x69, 0x6e, 0x67, 0x2a, 0x5b, 0x0a, 0x0c, 0x42, 0x6f, 0x64, 0x79, 0x45, 0x6e, 0x63, 0x6f, 0x64,
+	0x69, 0x6e, 0x67, 0x12, 0x16, 0x0a, 0x12, 0x42, 0x4f, 0x44, 0x59, 0x5f, 0x45, 0x4e, 0x43, 0x4f,
+	0x44, 0x49, 0x4e, 0x47, 0x5f, 0x4a, 0x53, 0x4f, 0x4e, 0x10, 0x00, 0x12, 0x16, 0x0a, 0x12, 0x42,
+	0x4f, 0x44, 0x59, 0x5f, 0x45, 0x4e, 0x43, 0x4f, 0x44, 0x49, 0x4e, 0x47, 0x5f, 0x46, 0x4f, 0x52,
+	0x4d, 0x10, 0x01, 0x12, 0x1b, 0x0a, 0x17, 0x42, 0x4f, 0x44, 0x59, 0x5f, 0x45, 0x4e, 0x43, 0x4f,
+	0x44, 0x49, 0x4e, 0x47, 0x5f, 0x4d, 0x55, 0x4c, 0x54, 0x49, 0x50, 0x41, 0x52, 0x54, 0x10, 0x02,
+	0x3a, 0x51, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d,
+	0x65, 0x74, 0x68, 0x6f, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xe7, 0x9b, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x6c, 0x65, 0x6f, 0x2e, 0x67, 0x6f, 0x6f, 0x73, 0x65,
+	0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x3a, 0x4e, 0x0a, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1e,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xe8,
+	0x9b, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6c, 0x65, 0x6f, 0x2e, 0x67, 0x6f, 0x6f,
+	0x73, 0x65, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x07, 0x72, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x42, 0x31, 0x5a, 0x2f, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2f, 0x67, 0x6f, 0x2d, 0x6c, 0x65, 0x6f, 0x2f, 0x67, 0x6f, 0x6f, 0x73, 0x65, 0x2f, 0x61,
+	0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x3b, 0x61, 0x6e, 0x6e, 0x6f, 0x74,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_goose_annotations_proto_rawDescOnce sync.Once
+	file_goose_annotations_proto_rawDescData = file_goose_annotations_proto_rawDesc
+)
+
+func file_goose_annotations_proto_rawDescGZIP() []byte {
+	file_goose_annotations_proto_rawDescOnce.Do(func() {
+		file_goose_annotations_proto_rawDescData = protoimpl.X.CompressGZIP(file_goose_annotations_proto_rawDescData)
+	})
+	return file_goose_annotations_proto_rawDescData
+}
+
+var file_goose_annotations_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_goose_annotations_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_goose_annotations_proto_goTypes = []any{
+	(BodyEncoding)(0),                  // 0: leo.goose.BodyEncoding
+	(*Response)(nil),                   // 1: leo.goose.Response
+	(*Request)(nil),                    // 2: leo.goose.Request
+	(*descriptorpb.MethodOptions)(nil), // 3: google.protobuf.MethodOptions
+}
+var file_goose_annotations_proto_depIdxs = []int32{
+	0, // 0: leo.goose.Request.body_encoding:type_name -> leo.goose.BodyEncoding
+	3, // 1: leo.goose.response:extendee -> google.protobuf.MethodOptions
+	3, // 2: leo.goose.request:extendee -> google.protobuf.MethodOptions
+	1, // 3: leo.goose.response:type_name -> leo.goose.Response
+	2, // 4: leo.goose.request:type_name -> leo.goose.Request
+	5, // [5:5] is the sub-list for method output_type
+	5, // [5:5] is the sub-list for method input_type
+	3, // [3:5] is the sub-list for extension type_name
+	1, // [1:3] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_goose_annotations_proto_init() }
+func file_goose_annotations_proto_init() {
+	if File_goose_annotations_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_goose_annotations_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   2,
+			NumExtensions: 2,
+			NumServices:   0,
+		},
+		GoTypes:           file_goose_annotations_proto_goTypes,
+		DependencyIndexes: file_goose_annotations_proto_depIdxs,
+		EnumInfos:         file_goose_annotations_proto_enumTypes,
+		MessageInfos:      file_goose_annotations_proto_msgTypes,
+		ExtensionInfos:    file_goose_annotations_proto_extTypes,
+	}.Build()
+	File_goose_annotations_proto = out.File
+	file_goose_annotations_proto_rawDesc = nil
+	file_goose_annotations_proto_goTypes = nil
+	file_goose_annotations_proto_depIdxs = nil
+}
@@ -0,0 +1,317 @@
+// Package app runs goose services on an HTTP server with graceful shutdown
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/go-leo/goose"
+	"github.com/go-leo/goose/health"
+	"github.com/go-leo/goose/server"
+)
+
+// options holds configuration options for the application
+type options struct {
+	addr            string                                       // Address to listen on
+	listener        net.Listener                                 // Listener to serve, instead of listening on addr
+	httpServer      *http.Server                                 // Template of the HTTP server
+	routes          []func(router *http.ServeMux) *http.ServeMux // Functions registering the routes
+	middlewares     []server.Middleware                          // Middlewares wrapping all the routes
+	checker         *health.Checker                              // Health checker, toggled to not serving on shutdown
+	signals         []os.Signal                                  // Signals triggering the shutdown
+	drainDelay      time.Duration                                // Delay between toggling the health and draining
+	shutdownTimeout time.Duration                                // Maximum duration of the draining
+}
+
+// apply applies the given options to the options struct
+// Parameters:
+//   - opts: Variable number of Option functions
+//
+// Returns:
+//   - *options: Pointer to the updated options struct
+func (o *options) apply(opts ...Option) *options {
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Option is a function type for configuring the application
+type Option func(o *options)
+
+// defaultOptions returns the default configuration options
+// Returns:
+//   - *options: Default options, listening on ":8080", a checker of its own, shutting down on SIGINT and SIGTERM
+//     with no drain delay and a shutdown timeout of 30 seconds
+func defaultOptions() *options {
+	return &options{
+		addr:            ":8080",
+		checker:         health.NewChecker(),
+		signals:         []os.Signal{os.Interrupt, syscall.SIGTERM},
+		shutdownTimeout: 30 * time.Second,
+	}
+}
+
+// Addr sets the address to listen on, ":8080" by default
+// Parameters:
+//   - addr: TCP address, such as ":8080" or "127.0.0.1:0"
+//
+// Returns:
+//   - Option: Function to set the address option
+func Addr(addr string) Option {
+	return func(o *options) {
+		o.addr = addr
+	}
+}
+
+// Listener sets a listener to serve instead of listening on the address, such as a systemd socket or a TLS listener
+// Parameters:
+//   - listener: The listener, closed by the application
+//
+// Returns:
+//   - Option: Function to set the listener option
+func Listener(listener net.Listener) Option {
+	return func(o *options) {
+		o.listener = listener
+	}
+}
+
+// HttpServer sets the template of the HTTP server, to configure its timeouts, TLS or error log.
+// Each Run serves with a new server copying its settings, the template itself is left unchanged,
+// with the Addr and Handler of the application.
+// Parameters:
+//   - srv: The HTTP server template
+//
+// Returns:
+//   - Option: Function to set the HTTP server option
+func HttpServer(srv *http.Server) Option {
+	return func(o *options) {
+		o.httpServer = srv
+	}
+}
+
+// Routes adds functions registering routes on the router of the application, such as debug.Append
+// Parameters:
+//   - routes: Functions registering routes
+//
+// Returns:
+//   - Option: Function to add the routes
+func Routes(routes ...func(router *http.ServeMux) *http.ServeMux) Option {
+	return func(o *options) {
+		o.routes = append(o.routes, routes...)
+	}
+}
+
+// Service adds the routes of a goose service, registered with its generated AppendXxxGooseRoute function.
+// The implementation must be converted to the service interface, such as user.UserGooseService(svc),
+// for the type parameter to be inferred.
+// Parameters:
+//   - appendRoute: The generated function, such as user.AppendUserGooseRoute
+//   - service: The implementation of the service
+//   - opts: Server options of the service, such as server.Middlewares
+//
+// Returns:
+//   - Option: Function to add the routes of the service
+func Service[S any](appendRoute func(router *http.ServeMux, service S, opts ...server.Option) *http.ServeMux, service S, opts ...server.Option) Option {
+	return Routes(func(router *http.ServeMux) *http.ServeMux {
+		return appendRoute(router, service, opts...)
+	})
+}
+
+// Middlewares adds middlewares wrapping all the routes of the application, including the health endpoints
+// Parameters:
+//   - middlewares: The middlewares, the first one is the outermost
+//
+// Returns:
+//   - Option: Function to add the middlewares
+func Middlewares(middlewares ...server.Middleware) Option {
+	return func(o *options) {
+		o.middlewares = append(o.middlewares, middlewares...)
+	}
+}
+
+// Health sets the health checker whose endpoints are registered, a checker of the application by default,
+// so that applications running in the same process do not share their serving status
+// Parameters:
+//   - checker: The health checker, set to not serving when the shutdown starts
+//
+// Returns:
+//   - Option: Function to set the health option
+func Health(checker *health.Checker) Option {
+	return func(o *options) {
+		o.checker = checker
+	}
+}
+
+// Signals sets the signals triggering the shutdown, SIGINT and SIGTERM by default
+// Parameters:
+//   - signals: The signals, none to shut down only when the context of Run is done
+//
+// Returns:
+//   - Option: Function to set the signals option
+func Signals(signals ...os.Signal) Option {
+	return func(o *options) {
+		o.signals = signals
+	}
+}
+
+// DrainDelay sets the delay between toggling the health to not serving and draining the connections,
+// so that load balancers stop sending new requests first, 0 by default
+// Parameters:
+//   - d: Delay, such as a few periods of the readiness probe
+//
+// Returns:
+//   - Option: Function to set the drain delay option
+func DrainDelay(d time.Duration) Option {
+	return func(o *options) {
+		o.drainDelay = d
+	}
+}
+
+// ShutdownTimeout sets the maximum duration of the draining, after which the remaining connections are closed,
+// 30 seconds by default
+// Parameters:
+//   - d: Shutdown timeout
+//
+// Returns:
+//   - Option: Function to set the shutdown timeout option
+func ShutdownTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.shutdownTimeout = d
+	}
+}
+
+// App serves goose services on an HTTP server
+type App struct {
+	opt *options
+}
+
+// New creates an application
+// Parameters:
+//   - opts: Variable number of Option functions for configuration
+//
+// Returns:
+//   - *App: The application
+func New(opts ...Option) *App {
+	return &App{opt: defaultOptions().apply(opts...)}
+}
+
+// Handler builds the handler of the application: the health endpoints and the routes, wrapped by the middlewares.
+// It opts in to the conflict detection of goose.DefaultRouteRegistry:
+// the conflicts between the routes panic naming both RPC methods.
+// Returns:
+//   - http.Handler: The handler
+func (a *App) Handler() http.Handler {
+	router := health.Append(http.NewServeMux(), a.opt.checker)
+	_ = goose.DefaultRouteRegistry.DetectConflicts(router)
+	defer goose.DefaultRouteRegistry.Forget(router)
+	for _, route := range a.opt.routes {
+		router = route(router)
+	}
+	middleware := server.Chain(a.opt.middlewares...)
+	if middleware == nil {
+		return router
+	}
+	return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		middleware(response, request, router.ServeHTTP)
+	})
+}
+
+// Run serves the application until the context is done or a shutdown signal is received
+// Parameters:
+//   - ctx: Context of the application
+//
+// Returns:
+//   - error: Error if the listener or the server fails, or if the draining times out
+//
+// Behavior:
+//  1. Listens on the address, or serves the listener, and sets the health to serving
+//  2. On a signal or when the context is done, sets the health to not serving and waits for the drain delay
+//  3. Shuts the server down, waiting for the requests in flight up to the shutdown timeout,
+//     then closes the remaining connections
+func (a *App) Run(ctx context.Context) error {
+	listener := a.opt.listener
+	if listener == nil {
+		var err error
+		if listener, err = net.Listen("tcp", a.opt.addr); err != nil {
+			return fmt.Errorf("app: failed to listen on %s: %w", a.opt.addr, err)
+		}
+	}
+	srv := newServer(a.opt.httpServer)
+	srv.Addr = listener.Addr().String()
+	srv.Handler = a.Handler()
+
+	if len(a.opt.signals) > 0 {
+		var stop context.CancelFunc
+		ctx, stop = signal.NotifyContext(ctx, a.opt.signals...)
+		defer stop()
+	}
+
+	a.opt.checker.Resume()
+	errc := make(chan error, 1)
+	go func() {
+		if srv.TLSConfig != nil {
+			errc <- srv.ServeTLS(listener, "", "")
+			return
+		}
+		errc <- srv.Serve(listener)
+	}()
+	slog.InfoContext(ctx, "app: serving", slog.String("addr", srv.Addr))
+
+	select {
+	case err := <-errc:
+		a.opt.checker.Shutdown()
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.InfoContext(ctx, "app: shutting down", slog.Duration("drain_delay", a.opt.drainDelay), slog.Duration("timeout", a.opt.shutdownTimeout))
+	a.opt.checker.Shutdown()
+	if a.opt.drainDelay > 0 {
+		time.Sleep(a.opt.drainDelay)
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), a.opt.shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		_ = srv.Close()
+		return fmt.Errorf("app: failed to drain connections: %w", err)
+	}
+	if err := <-errc; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// newServer creates a server with the settings of the template, which is left unchanged
+// Parameters:
+//   - template: The HTTP server template, nil for the default settings
+//
+// Returns:
+//   - *http.Server: The server, without Addr and Handler
+func newServer(template *http.Server) *http.Server {
+	if template == nil {
+		return &http.Server{}
+	}
+	return &http.Server{
+		TLSConfig:                    template.TLSConfig.Clone(),
+		ReadTimeout:                  template.ReadTimeout,
+		ReadHeaderTimeout:            template.ReadHeaderTimeout,
+		WriteTimeout:                 template.WriteTimeout,
+		IdleTimeout:                  template.IdleTimeout,
+		MaxHeaderBytes:               template.MaxHeaderBytes,
+		TLSNextProto:                 template.TLSNextProto,
+		ConnState:                    template.ConnState,
+		ErrorLog:                     template.ErrorLog,
+		BaseContext:                  template.BaseContext,
+		ConnContext:                  template.ConnContext,
+		DisableGeneralOptionsHandler: template.DisableGeneralOptionsHandler,
+	}
+}
@@ -0,0 +1,252 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-leo/goose"
+	"github.com/go-leo/goose/health"
+	"github.com/go-leo/goose/server"
+)
+
+// greeter is a service registered like the generated services
+type greeter interface {
+	Greet(name string) string
+}
+
+type mockGreeter struct{}
+
+func (mockGreeter) Greet(name string) string {
+	return "hello " + name
+}
+
+var greetEndpoint = &goose.Endpoint{FullName: "/leo.example.greeter.v1.Greeter/Greet", Method: "GET", Pattern: "/v1/greeting/{name}"}
+
+// appendGreeterRoute registers the route like the generated AppendXxxGooseRoute functions
+func appendGreeterRoute(router *http.ServeMux, service greeter, opts ...server.Option) *http.ServeMux {
+	options := server.NewOptions(opts...)
+	options.RouteRegistry().Handle(router, greetEndpoint, "GET "+options.PathPrefix()+"/v1/greeting/{name}", http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		_, _ = io.WriteString(response, service.Greet(request.PathValue("name")))
+	}))
+	return router
+}
+
+func listen(t *testing.T) net.Listener {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return listener
+}
+
+func get(t *testing.T, url string) (int, string) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+// waitStatus waits for the checker to set the status of the server, when Run starts or shuts down
+func waitStatus(t *testing.T, checker *health.Checker, want health.Status) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for checker.ServingStatus("") != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the status %s, got %s", want, checker.ServingStatus(""))
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestRun(t *testing.T) {
+	listener := listen(t)
+	base := "http://" + listener.Addr().String()
+	checker := health.NewChecker()
+	var calls atomic.Int32
+	counter := func(response http.ResponseWriter, request *http.Request, invoker http.HandlerFunc) {
+		calls.Add(1)
+		invoker(response, request)
+	}
+	started, released := make(chan struct{}), make(chan struct{})
+	a := New(
+		Listener(listener),
+		Signals(),
+		Health(checker),
+		Middlewares(counter),
+		Service(appendGreeterRoute, greeter(mockGreeter{}), server.PathPrefix("/api")),
+		Routes(func(router *http.ServeMux) *http.ServeMux {
+			router.HandleFunc("GET /slow", func(w http.ResponseWriter, r *http.Request) {
+				close(started)
+				<-released
+				_, _ = w.Write([]byte("done"))
+			})
+			return router
+		}),
+		DrainDelay(200*time.Millisecond),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() { errc <- a.Run(ctx) }()
+
+	if code, body := get(t, base+"/api/v1/greeting/bob"); code != http.StatusOK || body != "hello bob" {
+		t.Fatalf("unexpected response %d %s", code, body)
+	}
+	if code, _ := get(t, base+"/health/ready"); code != http.StatusOK {
+		t.Fatalf("expected ready, got %d", code)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("expected the middleware to wrap all the routes, got %d calls", calls.Load())
+	}
+
+	slow := make(chan string, 1)
+	go func() {
+		_, body := get(t, base+"/slow")
+		slow <- body
+	}()
+	<-started
+	cancel()
+	waitStatus(t, checker, health.NotServing)
+
+	// during the drain delay, the server still answers but is not ready
+	if code, _ := get(t, base+"/health/ready"); code != http.StatusServiceUnavailable {
+		t.Fatalf("expected not ready while draining, got %d", code)
+	}
+	if code, _ := get(t, base+"/health/live"); code != http.StatusOK {
+		t.Fatalf("expected alive while draining, got %d", code)
+	}
+
+	close(released)
+	if body := <-slow; body != "done" {
+		t.Fatalf("expected the request in flight to complete, got %q", body)
+	}
+	select {
+	case err := <-errc:
+		if err != nil {
+			t.Fatalf("expected graceful shutdown, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return")
+	}
+}
+
+func TestRunShutdownTimeout(t *testing.T) {
+	listener := listen(t)
+	base := "http://" + listener.Addr().String()
+	started, released := make(chan struct{}), make(chan struct{})
+	defer close(released)
+	a := New(
+		Listener(listener),
+		Signals(),
+		Health(health.NewChecker()),
+		Routes(func(router *http.ServeMux) *http.ServeMux {
+			router.HandleFunc("GET /stuck", func(w http.ResponseWriter, r *http.Request) {
+				close(started)
+				<-released
+			})
+			return router
+		}),
+		ShutdownTimeout(50*time.Millisecond),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() { errc <- a.Run(ctx) }()
+
+	go func() {
+		if resp, err := http.Get(base + "/stuck"); err == nil {
+			resp.Body.Close()
+		}
+	}()
+	<-started
+	cancel()
+	select {
+	case err := <-errc:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected a drain timeout, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return")
+	}
+}
+
+func TestRunKeepsServerTemplate(t *testing.T) {
+	template := &http.Server{ReadHeaderTimeout: time.Second}
+	for range 2 {
+		listener := listen(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		errc := make(chan error, 1)
+		go func() { errc <- New(Listener(listener), Signals(), Health(health.NewChecker()), HttpServer(template)).Run(ctx) }()
+		if code, _ := get(t, "http://"+listener.Addr().String()+"/health/live"); code != http.StatusOK {
+			t.Fatalf("expected alive, got %d", code)
+		}
+		cancel()
+		if err := <-errc; err != nil {
+			t.Fatalf("expected graceful shutdown, got %v", err)
+		}
+	}
+	if template.Addr != "" || template.Handler != nil || template.ReadHeaderTimeout != time.Second {
+		t.Fatalf("expected the template unchanged, got Addr %q and Handler %v", template.Addr, template.Handler)
+	}
+}
+
+func TestRunListenError(t *testing.T) {
+	listener := listen(t)
+	defer listener.Close()
+	err := New(Addr(listener.Addr().String()), Signals(), Health(health.NewChecker())).Run(context.Background())
+	if err == nil {
+		t.Fatal("expected a listen error")
+	}
+}
+
+func TestRunKeepsServiceStatus(t *testing.T) {
+	listener := listen(t)
+	base := "http://" + listener.Addr().String()
+	checker := health.NewChecker()
+	checker.SetServingStatus("leo.example.greeter.v1.Greeter", health.NotServing)
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() { errc <- New(Listener(listener), Signals(), Health(checker)).Run(ctx) }()
+	defer func() {
+		cancel()
+		<-errc
+	}()
+
+	if code, _ := get(t, base+"/health/ready"); code != http.StatusOK {
+		t.Fatalf("expected the server ready, got %d", code)
+	}
+	if code, _ := get(t, base+"/health/ready?service=leo.example.greeter.v1.Greeter"); code != http.StatusServiceUnavailable {
+		t.Fatalf("expected the service to keep its status, got %d", code)
+	}
+}
+
+func TestDefaultChecker(t *testing.T) {
+	first, second := New(), New()
+	if first.opt.checker == health.DefaultChecker || first.opt.checker == second.opt.checker {
+		t.Fatal("expected each application to have a checker of its own")
+	}
+}
+
+func TestHandlerRouteConflict(t *testing.T) {
+	application := New(Service(appendGreeterRoute, greeter(mockGreeter{})), Service(appendGreeterRoute, greeter(mockGreeter{})))
+	defer func() {
+		err, _ := recover().(error)
+		var conflict *goose.RouteConflictError
+		if !errors.As(err, &conflict) {
+			t.Fatalf("expected a route conflict, got %v", err)
+		}
+		if conflict.Route.RPC != greetEndpoint.FullName || conflict.Existing.RPC != greetEndpoint.FullName {
+			t.Fatalf("unexpected conflict %v", conflict)
+		}
+	}()
+	application.Handler()
+}
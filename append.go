@@ -1,20 +1,10 @@
 package goose
 
-import (
-	"net/http"
-	"net/http/pprof"
-)
+import "github.com/go-leo/goose/health"
 
-func AppendPProf(router *http.ServeMux) *http.ServeMux {
-	http.HandleFunc("GET /debug/pprof/", pprof.Index)
-	http.HandleFunc("GET /debug/pprof/cmdline", pprof.Cmdline)
-	http.HandleFunc("GET /debug/pprof/profile", pprof.Profile)
-	http.HandleFunc("GET /debug/pprof/symbol", pprof.Symbol)
-	http.HandleFunc("GET /debug/pprof/trace", pprof.Trace)
-	return router
-}
-
-func AppendHealth(router *http.ServeMux) *http.ServeMux {
-	router.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {})
-	return router
+// AppendHealth registers the health endpoints of health.DefaultChecker on the router,
+// "GET /health" and "GET /health/ready" for readiness and "GET /health/live" for liveness.
+// Use health.Append to register the endpoints of another checker.
+func AppendHealth[R Router](router R) R {
+	return health.Append(router, health.DefaultChecker)
 }
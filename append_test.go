@@ -0,0 +1,39 @@
+package goose
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+// patternRouter is a Router other than *http.ServeMux, recording the registered patterns
+type patternRouter struct {
+	patterns []string
+}
+
+func (r *patternRouter) Handle(pattern string, handler http.Handler) {
+	r.patterns = append(r.patterns, pattern)
+}
+
+func TestAppendHealth(t *testing.T) {
+	router := &patternRouter{}
+	if got := AppendHealth(router); got != router {
+		t.Fatal("expected the router to be returned")
+	}
+	want := []string{"GET /health", "GET /health/ready", "GET /health/live"}
+	if !slices.Equal(router.patterns, want) {
+		t.Fatalf("expected patterns %v, got %v", want, router.patterns)
+	}
+}
+
+func TestNoDebugEndpointsOnDefaultServeMux(t *testing.T) {
+	// importing expvar or net/http/pprof registers their handlers on http.DefaultServeMux
+	for _, target := range []string{"/debug/vars", "/debug/pprof/"} {
+		rr := httptest.NewRecorder()
+		http.DefaultServeMux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, target, nil))
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("%s: expected 404 on the default mux, got %d", target, rr.Code)
+		}
+	}
+}
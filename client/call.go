@@ -0,0 +1,49 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// CallInfo receives the status code, header and trailer of the HTTP response of a call.
+// The trailer is only complete once the response body has been read,
+// that is after the client method has returned.
+type CallInfo struct {
+	// StatusCode is the HTTP status code of the response
+	StatusCode int
+	// Header is the HTTP header of the response
+	Header http.Header
+	// Trailer is the HTTP trailer of the response
+	Trailer http.Header
+}
+
+// callInfoKey is the context key of the CallInfo
+type callInfoKey struct{}
+
+// WithCallInfo returns a new context that makes the client fill info with the response of the call.
+// Use a new context for each call.
+//
+// Parameters:
+//   - ctx: The parent context
+//   - info: The CallInfo to fill
+//
+// Returns:
+//   - context.Context: The context to pass to the client method
+func WithCallInfo(ctx context.Context, info *CallInfo) context.Context {
+	return context.WithValue(ctx, callInfoKey{}, info)
+}
+
+// setCallInfo fills the CallInfo carried by ctx, if any, with the response.
+//
+// Parameters:
+//   - ctx: The context of the request
+//   - response: The HTTP response of the call
+func setCallInfo(ctx context.Context, response *http.Response) {
+	info, ok := ctx.Value(callInfoKey{}).(*CallInfo)
+	if !ok || info == nil || response == nil {
+		return
+	}
+	info.StatusCode = response.StatusCode
+	info.Header = response.Header
+	info.Trailer = response.Trailer
+}
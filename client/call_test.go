@@ -0,0 +1,60 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithCallInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", "/v1/users/1")
+		w.Header().Set("Trailer", "X-Checksum")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("OK"))
+		w.Header().Set("X-Checksum", "abc")
+	}))
+	defer server.Close()
+
+	var info CallInfo
+	ctx := WithCallInfo(context.Background(), &info)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL, nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	response, err := Invoke(nil, &http.Client{}, request)
+	if err != nil {
+		t.Fatalf("Invoke returned error: %v", err)
+	}
+	_, _ = io.ReadAll(response.Body)
+	_ = response.Body.Close()
+
+	if info.StatusCode != http.StatusCreated {
+		t.Errorf("StatusCode = %d, want 201", info.StatusCode)
+	}
+	if got := info.Header.Get("Location"); got != "/v1/users/1" {
+		t.Errorf("Location = %q, want /v1/users/1", got)
+	}
+	if got := info.Trailer.Get("X-Checksum"); got != "abc" {
+		t.Errorf("X-Checksum = %q, want abc", got)
+	}
+}
+
+func TestWithoutCallInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	request, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	response, err := Invoke(nil, &http.Client{}, request)
+	if err != nil {
+		t.Fatalf("Invoke returned error: %v", err)
+	}
+	_ = response.Body.Close()
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"slices"
 
 	"github.com/go-leo/goose"
 	"google.golang.org/genproto/googleapis/api/httpbody"
@@ -13,9 +14,39 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
+// CheckStatus checks that the HTTP response has a success status code.
+// Any 2xx status code is a success, as are the given statuses, such as the
+// status declared with the (leo.goose.response) option.
+// Otherwise the response body is read and closed, and an error carrying the
+// status code and body is returned.
+//
+// Parameters:
+//   - response: The HTTP response to check
+//   - statuses: Additional status codes accepted as success
+//
+// Returns:
+//   - error: nil if the status code is a success, otherwise an error implementing goose.StatusCodeGetter
+func CheckStatus(response *http.Response, statuses ...int) error {
+	if response.StatusCode >= 200 && response.StatusCode <= 299 {
+		return nil
+	}
+	if slices.Contains(statuses, response.StatusCode) {
+		return nil
+	}
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return errors.Join(err, response.Body.Close())
+	}
+	if err := response.Body.Close(); err != nil {
+		return err
+	}
+	return goose.NewError(response.StatusCode, string(body))
+}
+
 // DecodeMessage decodes an HTTP response into a protobuf message.
 // It reads the response body, unmarshals the JSON data into the provided protobuf message,
-// and properly closes the response body.
+// and properly closes the response body. An empty body, such as the body of a
+// 204 No Content response, leaves the message unchanged.
 //
 // Parameters:
 //   - ctx: The context.Context for the request
@@ -30,6 +61,9 @@ func DecodeMessage(ctx context.Context, response *http.Response, resp proto.Mess
 	if err != nil {
 		return errors.Join(err, response.Body.Close())
 	}
+	if len(data) == 0 {
+		return response.Body.Close()
+	}
 	if err := unmarshalOptions.Unmarshal(data, resp); err != nil {
 		return errors.Join(err, response.Body.Close())
 	}
@@ -56,6 +90,22 @@ func DecodeHttpBody(ctx context.Context, response *http.Response, resp *httpbody
 	return response.Body.Close()
 }
 
+// DecodeHttpBodyStream decodes an HTTP response into a goose.HttpBodyStream without reading the body.
+// The caller reads the body and must close it.
+//
+// Parameters:
+//   - ctx: The context.Context for the request
+//   - response: The HTTP response to decode
+//   - resp: The HttpBodyStream to populate with response data
+//
+// Returns:
+//   - error: Always nil
+func DecodeHttpBodyStream(ctx context.Context, response *http.Response, resp *goose.HttpBodyStream) error {
+	resp.ContentType = response.Header.Get(goose.ContentTypeKey)
+	resp.Body = response.Body
+	return nil
+}
+
 // DecodeHttpResponse decodes an HTTP response into an HttpResponse message.
 // It extracts the status code, reason phrase, headers, and body from the HTTP response.
 //
@@ -176,3 +176,48 @@ func (e *errorReader) Read(p []byte) (n int, err error) {
 func (e *errorReader) Close() error {
 	return nil
 }
+
+func TestCheckStatus(t *testing.T) {
+	newResponse := func(code int) *http.Response {
+		return &http.Response{StatusCode: code, Body: io.NopCloser(bytes.NewBufferString("not found"))}
+	}
+	if err := CheckStatus(newResponse(http.StatusCreated)); err != nil {
+		t.Errorf("CheckStatus(201) error = %v, want nil", err)
+	}
+	if err := CheckStatus(newResponse(http.StatusNotModified), http.StatusNotModified); err != nil {
+		t.Errorf("CheckStatus(304, 304) error = %v, want nil", err)
+	}
+	err := CheckStatus(newResponse(http.StatusNotFound))
+	statusCodeGetter, ok := err.(goose.StatusCodeGetter)
+	if !ok {
+		t.Fatalf("CheckStatus(404) error = %v, want a goose.StatusCodeGetter", err)
+	}
+	if statusCodeGetter.StatusCode() != http.StatusNotFound {
+		t.Errorf("StatusCode() = %d, want 404", statusCodeGetter.StatusCode())
+	}
+}
+
+func TestDecodeMessageEmptyBody(t *testing.T) {
+	response := &http.Response{StatusCode: http.StatusNoContent, Body: io.NopCloser(bytes.NewReader(nil))}
+	msg := &structpb.Struct{}
+	if err := DecodeMessage(context.Background(), response, msg, protojson.UnmarshalOptions{}); err != nil {
+		t.Errorf("DecodeMessage() error = %v, want nil", err)
+	}
+}
+
+func TestDecodeHttpBodyStream(t *testing.T) {
+	response := &http.Response{
+		Header: http.Header{goose.ContentTypeKey: []string{"text/plain"}},
+		Body:   io.NopCloser(bytes.NewBufferString("hello")),
+	}
+	resp := &goose.HttpBodyStream{}
+	if err := DecodeHttpBodyStream(context.Background(), response, resp); err != nil {
+		t.Fatalf("DecodeHttpBodyStream error: %v", err)
+	}
+	if resp.ContentType != "text/plain" {
+		t.Errorf("ContentType = %q, want text/plain", resp.ContentType)
+	}
+	if resp.Body != response.Body {
+		t.Error("Body is not the response body")
+	}
+}
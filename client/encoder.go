@@ -78,3 +78,22 @@ func EncodeHttpRequest(ctx context.Context, req *rpchttp.HttpRequest, header htt
 	}
 	return nil
 }
+
+// EncodeHttpBodyStream prepares a goose.HttpBodyStream to be sent as the HTTP request body.
+// It sets the content type header and returns the body, which is streamed by the
+// HTTP client without being buffered, and closed once sent.
+//
+// Parameters:
+//   - ctx: The context.Context for the request
+//   - req: The HttpBodyStream to send
+//   - header: The HTTP header to set the content type in
+//
+// Returns:
+//   - io.Reader: The request body, nil if req has no body
+func EncodeHttpBodyStream(ctx context.Context, req *goose.HttpBodyStream, header http.Header) io.Reader {
+	header.Set(goose.ContentTypeKey, req.ContentType)
+	if req.Body == nil {
+		return nil
+	}
+	return req.Body
+}
@@ -147,3 +147,18 @@ type errorWriter struct{}
 func (e *errorWriter) Write(p []byte) (n int, err error) {
 	return 0, io.ErrUnexpectedEOF
 }
+
+func TestEncodeHttpBodyStream(t *testing.T) {
+	header := http.Header{}
+	body := io.NopCloser(bytes.NewBufferString("hello"))
+	got := EncodeHttpBodyStream(context.Background(), &goose.HttpBodyStream{ContentType: "text/plain", Body: body}, header)
+	if got != body {
+		t.Error("EncodeHttpBodyStream should return the stream body")
+	}
+	if ct := header.Get(goose.ContentTypeKey); ct != "text/plain" {
+		t.Errorf("Content-Type = %q, want text/plain", ct)
+	}
+	if got := EncodeHttpBodyStream(context.Background(), &goose.HttpBodyStream{}, http.Header{}); got != nil {
+		t.Errorf("EncodeHttpBodyStream without body = %v, want nil", got)
+	}
+}
@@ -0,0 +1,111 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/go-leo/goose"
+	"google.golang.org/genproto/googleapis/api/httpbody"
+)
+
+// FormFiles holds the file parts of a multipart/form-data request body by form field key
+type FormFiles map[string][]*httpbody.HttpBody
+
+// AddBytes adds files with the application/octet-stream content type, empty data is skipped
+// Parameters:
+//   - key: Form field key
+//   - data: File contents
+func (f FormFiles) AddBytes(key string, data ...[]byte) {
+	for _, d := range data {
+		if len(d) == 0 {
+			continue
+		}
+		f[key] = append(f[key], &httpbody.HttpBody{ContentType: "application/octet-stream", Data: d})
+	}
+}
+
+// AddHttpBody adds files with the content type of the HttpBody, nil bodies are skipped
+// Parameters:
+//   - key: Form field key
+//   - bodies: File contents and content types
+func (f FormFiles) AddHttpBody(key string, bodies ...*httpbody.HttpBody) {
+	for _, body := range bodies {
+		if body == nil {
+			continue
+		}
+		f[key] = append(f[key], body)
+	}
+}
+
+// EncodeForm encodes form values into an application/x-www-form-urlencoded HTTP request body
+// Parameters:
+//   - ctx: The context.Context for the request
+//   - form: The form values to encode
+//   - header: The HTTP headers to set content type information
+//   - body: The io.Writer to write the encoded form
+//
+// Returns:
+//   - error: Any error that occurred during encoding, or nil if successful
+func EncodeForm(ctx context.Context, form url.Values, header http.Header, body io.Writer) error {
+	if _, err := io.WriteString(body, form.Encode()); err != nil {
+		return err
+	}
+	header.Set(goose.ContentTypeKey, goose.FormContentType)
+	return nil
+}
+
+// EncodeMultipartForm encodes form values and files into a multipart/form-data HTTP request body
+// Parameters:
+//   - ctx: The context.Context for the request
+//   - form: The form values to encode
+//   - files: The files to encode, each named after its form field key
+//   - header: The HTTP headers to set content type information
+//   - body: The io.Writer to write the encoded form
+//
+// Returns:
+//   - error: Any error that occurred during encoding, or nil if successful
+//
+// Behavior:
+//  1. Writes the form values as parts, sorted by key
+//  2. Writes the files as file parts, sorted by key
+//  3. Sets the Content-Type header with the multipart boundary
+func EncodeMultipartForm(ctx context.Context, form url.Values, files FormFiles, header http.Header, body io.Writer) error {
+	writer := multipart.NewWriter(body)
+	for _, key := range slices.Sorted(maps.Keys(form)) {
+		for _, value := range form[key] {
+			if err := writer.WriteField(key, value); err != nil {
+				return err
+			}
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(files)) {
+		for _, file := range files[key] {
+			partHeader := textproto.MIMEHeader{}
+			partHeader.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, quoteEscaper.Replace(key), quoteEscaper.Replace(key)))
+			partHeader.Set(goose.ContentTypeKey, file.GetContentType())
+			part, err := writer.CreatePart(partHeader)
+			if err != nil {
+				return err
+			}
+			if _, err := part.Write(file.GetData()); err != nil {
+				return err
+			}
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	header.Set(goose.ContentTypeKey, writer.FormDataContentType())
+	return nil
+}
+
+// quoteEscaper escapes the quoted strings of a Content-Disposition header
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/go-leo/goose"
+	"google.golang.org/genproto/googleapis/api/httpbody"
+)
+
+func TestEncodeForm(t *testing.T) {
+	var body bytes.Buffer
+	header := http.Header{}
+	if err := EncodeForm(context.Background(), url.Values{"name": {"jax"}, "tags": {"a", "b"}}, header, &body); err != nil {
+		t.Fatal(err)
+	}
+	if got := header.Get(goose.ContentTypeKey); got != goose.FormContentType {
+		t.Errorf("content type = %q, want %q", got, goose.FormContentType)
+	}
+	if got := body.String(); got != "name=jax&tags=a&tags=b" {
+		t.Errorf("body = %q, want name=jax&tags=a&tags=b", got)
+	}
+}
+
+func TestFormFiles(t *testing.T) {
+	files := FormFiles{}
+	files.AddBytes("docs", []byte("a"), nil, []byte("b"))
+	files.AddHttpBody("avatar", nil, &httpbody.HttpBody{ContentType: "image/png", Data: []byte("png")})
+	if len(files["docs"]) != 2 || files["docs"][0].GetContentType() != "application/octet-stream" {
+		t.Errorf("docs = %v, want 2 octet-stream files", files["docs"])
+	}
+	if len(files["avatar"]) != 1 {
+		t.Errorf("avatar = %v, want 1 file", files["avatar"])
+	}
+}
+
+func TestEncodeMultipartForm(t *testing.T) {
+	var body bytes.Buffer
+	header := http.Header{}
+	files := FormFiles{}
+	files.AddHttpBody(`av"atar`, &httpbody.HttpBody{ContentType: "image/png", Data: []byte("png")})
+	if err := EncodeMultipartForm(context.Background(), url.Values{"name": {"jax"}}, files, header, &body); err != nil {
+		t.Fatal(err)
+	}
+	mediaType, params, err := mime.ParseMediaType(header.Get(goose.ContentTypeKey))
+	if err != nil || mediaType != goose.MultipartFormContentType {
+		t.Fatalf("content type = %q, %v", header.Get(goose.ContentTypeKey), err)
+	}
+	reader := multipart.NewReader(&body, params["boundary"])
+	part, err := reader.NextPart()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(part)
+	if part.FormName() != "name" || part.FileName() != "" || string(data) != "jax" {
+		t.Errorf("part = %q %q %q, want the name value", part.FormName(), part.FileName(), data)
+	}
+	part, err = reader.NextPart()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ = io.ReadAll(part)
+	if part.FormName() != `av"atar` || part.FileName() == "" || part.Header.Get("Content-Type") != "image/png" || string(data) != "png" {
+		t.Errorf("part = %q %q %q, want the avatar file", part.FormName(), part.FileName(), data)
+	}
+	if _, err := reader.NextPart(); err != io.EOF {
+		t.Errorf("err = %v, want EOF", err)
+	}
+}
@@ -0,0 +1,76 @@
+// Package grpcx adapts goose clients to the calling conventions of gRPC clients,
+// so that generated goose clients can satisfy the interfaces generated by protoc-gen-go-grpc.
+package grpcx
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// AppendOutgoingHeader copies the outgoing gRPC metadata of the context into the HTTP request header.
+// Pseudo headers (":authority") and reserved gRPC keys ("grpc-*") are skipped.
+//
+// Parameters:
+//   - ctx: The context.Context that may carry outgoing gRPC metadata
+//   - header: The HTTP request header to append the metadata to
+func AppendOutgoingHeader(ctx context.Context, header http.Header) {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if !ok {
+		return
+	}
+	for key, values := range md {
+		if !isForwardable(key) {
+			continue
+		}
+		for _, value := range values {
+			header.Add(key, value)
+		}
+	}
+}
+
+// AfterCall applies the gRPC call options once a call has finished.
+// It supports the following options, all others are ignored:
+//   - grpc.Header: receives the HTTP response header
+//   - grpc.Trailer: receives the HTTP response trailer
+//   - grpc.OnFinish: receives the error of the call
+//
+// Parameters:
+//   - response: The HTTP response of the call, nil if no response was received
+//   - err: The error of the call, nil if successful
+//   - opts: The gRPC call options passed to the client method
+func AfterCall(response *http.Response, err error, opts ...grpc.CallOption) {
+	for _, opt := range opts {
+		switch o := opt.(type) {
+		case grpc.HeaderCallOption:
+			if response != nil && o.HeaderAddr != nil {
+				*o.HeaderAddr = toMetadata(response.Header)
+			}
+		case grpc.TrailerCallOption:
+			if response != nil && o.TrailerAddr != nil {
+				*o.TrailerAddr = toMetadata(response.Trailer)
+			}
+		case grpc.OnFinishCallOption:
+			if o.OnFinish != nil {
+				o.OnFinish(err)
+			}
+		}
+	}
+}
+
+// toMetadata converts an HTTP header into gRPC metadata with lower-cased keys.
+func toMetadata(header http.Header) metadata.MD {
+	md := make(metadata.MD, len(header))
+	for key, values := range header {
+		md.Append(key, values...)
+	}
+	return md
+}
+
+// isForwardable reports whether a gRPC metadata key can be sent as an HTTP header.
+func isForwardable(key string) bool {
+	return !strings.HasPrefix(key, ":") && !strings.HasPrefix(key, "grpc-")
+}
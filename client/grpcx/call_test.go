@@ -0,0 +1,87 @@
+package grpcx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestAppendOutgoingHeader(t *testing.T) {
+	ctx := metadata.AppendToOutgoingContext(context.Background(),
+		"x-request-id", "abc",
+		"x-tag", "a",
+		"x-tag", "b",
+		"grpc-timeout", "1S",
+		":authority", "example.com",
+	)
+	header := http.Header{}
+	AppendOutgoingHeader(ctx, header)
+
+	if got := header.Get("X-Request-Id"); got != "abc" {
+		t.Errorf("X-Request-Id = %q, want abc", got)
+	}
+	if got := header.Values("X-Tag"); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("X-Tag = %v, want [a b]", got)
+	}
+	if got := header.Get("Grpc-Timeout"); got != "" {
+		t.Errorf("Grpc-Timeout = %q, want empty", got)
+	}
+	if len(header) != 2 {
+		t.Errorf("header length = %d, want 2", len(header))
+	}
+}
+
+func TestAppendOutgoingHeaderWithoutMetadata(t *testing.T) {
+	header := http.Header{}
+	AppendOutgoingHeader(context.Background(), header)
+	if len(header) != 0 {
+		t.Errorf("header = %v, want empty", header)
+	}
+}
+
+func TestAfterCall(t *testing.T) {
+	response := &http.Response{
+		Header:  http.Header{"X-Foo": []string{"bar"}},
+		Trailer: http.Header{"X-Checksum": []string{"123"}},
+	}
+	var header, trailer metadata.MD
+	var finished error
+	callErr := errors.New("call error")
+
+	AfterCall(response, callErr,
+		grpc.Header(&header),
+		grpc.Trailer(&trailer),
+		grpc.OnFinish(func(err error) { finished = err }),
+		grpc.WaitForReady(true),
+	)
+
+	if got := header.Get("x-foo"); !reflect.DeepEqual(got, []string{"bar"}) {
+		t.Errorf("header x-foo = %v, want [bar]", got)
+	}
+	if got := trailer.Get("x-checksum"); !reflect.DeepEqual(got, []string{"123"}) {
+		t.Errorf("trailer x-checksum = %v, want [123]", got)
+	}
+	if !errors.Is(finished, callErr) {
+		t.Errorf("OnFinish error = %v, want %v", finished, callErr)
+	}
+}
+
+func TestAfterCallWithoutResponse(t *testing.T) {
+	var header metadata.MD
+	var called bool
+	AfterCall(nil, errors.New("dial error"),
+		grpc.Header(&header),
+		grpc.OnFinish(func(err error) { called = true }),
+	)
+	if header != nil {
+		t.Errorf("header = %v, want nil", header)
+	}
+	if !called {
+		t.Error("OnFinish was not called")
+	}
+}
@@ -73,6 +73,7 @@ func getInvoker(interceptors []Middleware, curr int, finalInvoker Invoker) Invok
 
 // Invoke executes an HTTP request with the given middleware.
 // If no middleware is provided, it directly executes the request using the HTTP client.
+// If the request context was created by WithCallInfo, the CallInfo is filled with the response.
 //
 // Parameters:
 //   - ctx: The context.Context for the request
@@ -87,8 +88,13 @@ func Invoke(middleware Middleware, cli *http.Client, request *http.Request) (*ht
 	invoke := func(cli *http.Client, request *http.Request) (*http.Response, error) {
 		return cli.Do(request)
 	}
+	var response *http.Response
+	var err error
 	if middleware == nil {
-		return invoke(cli, request)
+		response, err = invoke(cli, request)
+	} else {
+		response, err = middleware(cli, request, invoke)
 	}
-	return middleware(cli, request, invoke)
+	setCallInfo(request.Context(), response)
+	return response, err
 }
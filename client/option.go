@@ -9,7 +9,9 @@ import (
 	"google.golang.org/protobuf/encoding/protojson"
 )
 
-// Options interface defines methods to access all configurable options for the client
+// Options interface defines methods to access all configurable options for the client.
+// It is implemented by NewOptions and gains methods as options are added, such as ResolverRegistry;
+// implementations outside of this package should embed the Options returned by NewOptions.
 type Options interface {
 	// Client returns the HTTP client used for making requests
 	Client() *http.Client
@@ -37,6 +39,12 @@ type Options interface {
 
 	// Resolver returns the resolver used for resolving URLs
 	Resolver() resolver.Resolver
+
+	// ResolverRegistry returns the registry of the resolvers looked up by scheme
+	ResolverRegistry() *resolver.Registry
+
+	// PathPrefix returns the prefix of the paths of the requests
+	PathPrefix() string
 }
 
 // options holds the configuration options for the client
@@ -50,6 +58,8 @@ type options struct {
 	shouldFailFast          bool                          // Flag indicating if fail-fast mode is enabled
 	onValidationErrCallback goose.OnValidationErrCallback // Callback for validation errors
 	resolver                resolver.Resolver             // Resolver used for resolving URLs
+	resolverRegistry        *resolver.Registry            // Registry of the resolvers looked up by scheme
+	pathPrefix              string                        // Prefix of the paths of the requests
 }
 
 // Option defines a function type for modifying client options
@@ -82,6 +92,9 @@ func (o *options) Correct() *options {
 	if o.onValidationErrCallback == nil {
 		o.onValidationErrCallback = func(ctx context.Context, err error) {}
 	}
+	if o.resolverRegistry == nil {
+		o.resolverRegistry = resolver.DefaultRegistry
+	}
 	return o
 }
 
@@ -157,6 +170,22 @@ func (o *options) Resolver() resolver.Resolver {
 	return o.resolver
 }
 
+// ResolverRegistry returns the registry of the resolvers looked up by scheme
+//
+// Returns:
+//   - *resolver.Registry: The resolver registry, resolver.DefaultRegistry by default
+func (o *options) ResolverRegistry() *resolver.Registry {
+	return o.resolverRegistry
+}
+
+// PathPrefix returns the prefix of the paths of the requests
+//
+// Returns:
+//   - string: The prefix, such as "/api/internal", empty by default
+func (o *options) PathPrefix() string {
+	return o.pathPrefix
+}
+
 // Client sets the HTTP client to be used for making requests
 //
 // Parameters:
@@ -264,6 +293,34 @@ func Resolvers(resolver resolver.Resolver) Option {
 	}
 }
 
+// ResolverRegistry sets the registry of the resolvers looked up by scheme,
+// scoping them to the client instead of the global resolver.DefaultRegistry
+//
+// Parameters:
+//   - registry: The resolver registry, such as one created with resolver.NewRegistry
+//
+// Returns:
+//   - Option: A function that sets the resolver registry
+func ResolverRegistry(registry *resolver.Registry) Option {
+	return func(o *options) {
+		o.resolverRegistry = registry
+	}
+}
+
+// PathPrefix prefixes the paths of the requests, such as "/api/internal",
+// to call services mounted with server.PathPrefix. It is joined after the path of the target.
+//
+// Parameters:
+//   - prefix: The prefix, slashes around it are normalized
+//
+// Returns:
+//   - Option: A function that sets the path prefix
+func PathPrefix(prefix string) Option {
+	return func(o *options) {
+		o.pathPrefix = goose.CleanPathPrefix(prefix)
+	}
+}
+
 // NewOptions creates a new Options instance with default values and applies the provided options
 //
 // Parameters:
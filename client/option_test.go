@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/go-leo/goose"
+	"github.com/go-leo/goose/client/resolver"
 	"google.golang.org/protobuf/encoding/protojson"
 )
 
@@ -220,6 +221,33 @@ func TestOnValidationErrCallbackOption(t *testing.T) {
 	}
 }
 
+func TestResolverRegistryOption(t *testing.T) {
+	opts := &options{}
+	registry := resolver.NewRegistry()
+
+	// Apply ResolverRegistry option
+	option := ResolverRegistry(registry)
+	option(opts)
+
+	// Verify the registry was set
+	if opts.resolverRegistry != registry {
+		t.Error("ResolverRegistry option did not set the registry correctly")
+	}
+}
+
+func TestPathPrefixOption(t *testing.T) {
+	opts := &options{}
+
+	// Apply PathPrefix option
+	option := PathPrefix("/api/internal/")
+	option(opts)
+
+	// Verify the prefix was normalized
+	if opts.PathPrefix() != "/api/internal" {
+		t.Errorf("PathPrefix option did not normalize the prefix, got %q", opts.PathPrefix())
+	}
+}
+
 func TestNewOptions(t *testing.T) {
 	// Test NewOptions with no options
 	opts := NewOptions()
@@ -252,6 +280,10 @@ func TestNewOptions(t *testing.T) {
 		t.Error("Default shouldFailFast should be false")
 	}
 
+	if opts.ResolverRegistry() != resolver.DefaultRegistry {
+		t.Error("Default resolver registry should be resolver.DefaultRegistry")
+	}
+
 	// Test NewOptions with custom options
 	testClient := &http.Client{}
 	customOpts := NewOptions(Client(testClient), FailFast())
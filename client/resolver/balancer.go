@@ -0,0 +1,219 @@
+// Package resolver provides URL resolution functionality for the goose client
+package resolver
+
+import (
+	"cmp"
+	"context"
+	"hash/fnv"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Instance is an instance of a service, tracked by a BalancingResolver
+type Instance struct {
+	address      string       // Address in host:port form
+	inFlight     atomic.Int64 // Number of requests in flight
+	failures     int          // Consecutive failures, guarded by the resolver
+	ejectedUntil time.Time    // End of the ejection, guarded by the resolver
+}
+
+// Address returns the address of the instance
+// Returns:
+//   - string: Address in host:port form
+func (i *Instance) Address() string {
+	return i.address
+}
+
+// InFlight returns the number of requests in flight to the instance,
+// counted by the transport of the BalancingResolver
+// Returns:
+//   - int64: Number of requests in flight
+func (i *Instance) InFlight() int64 {
+	return i.inFlight.Load()
+}
+
+// Balancer is an interface for picking the instance a request is sent to
+type Balancer interface {
+	// Pick picks an instance
+	// Parameters:
+	//   - ctx: Context of the request
+	//   - instances: Available instances sorted by address, never empty
+	// Returns:
+	//   - *Instance: The picked instance
+	Pick(ctx context.Context, instances []*Instance) *Instance
+}
+
+// roundRobin picks the instances in turn
+type roundRobin struct {
+	next atomic.Uint64
+}
+
+// RoundRobin creates a balancer picking the instances in turn
+// Returns:
+//   - Balancer: A round-robin balancer
+func RoundRobin() Balancer {
+	return &roundRobin{}
+}
+
+// Pick picks the next instance
+func (b *roundRobin) Pick(ctx context.Context, instances []*Instance) *Instance {
+	return instances[(b.next.Add(1)-1)%uint64(len(instances))]
+}
+
+// random picks instances at random
+type random struct{}
+
+// Random creates a balancer picking instances at random
+// Returns:
+//   - Balancer: A random balancer
+func Random() Balancer {
+	return random{}
+}
+
+// Pick picks a random instance
+func (random) Pick(ctx context.Context, instances []*Instance) *Instance {
+	return instances[rand.N(len(instances))]
+}
+
+// leastRequests picks the least loaded of two random instances
+type leastRequests struct{}
+
+// LeastRequests creates a balancer picking, of two random instances, the one with fewer requests in flight.
+// The requests in flight are counted by the transport of the BalancingResolver.
+// Returns:
+//   - Balancer: A least-requests balancer
+func LeastRequests() Balancer {
+	return leastRequests{}
+}
+
+// Pick picks the least loaded of two random instances
+func (leastRequests) Pick(ctx context.Context, instances []*Instance) *Instance {
+	if len(instances) == 1 {
+		return instances[0]
+	}
+	i := rand.N(len(instances))
+	j := rand.N(len(instances) - 1)
+	if j >= i {
+		j++
+	}
+	if instances[j].InFlight() < instances[i].InFlight() {
+		return instances[j]
+	}
+	return instances[i]
+}
+
+// hashKeyCtxKey is the context key of the consistent hash key
+type hashKeyCtxKey struct{}
+
+// WithHashKey returns a context carrying the key a ConsistentHash balancer picks instances by
+// Parameters:
+//   - ctx: Parent context
+//   - key: The hash key, such as a user ID
+//
+// Returns:
+//   - context.Context: Context with the hash key
+func WithHashKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, hashKeyCtxKey{}, key)
+}
+
+// HashKeyFromContext retrieves the consistent hash key from the context
+// Parameters:
+//   - ctx: Context that may contain a hash key
+//
+// Returns:
+//   - string: The hash key
+//   - bool: True if a hash key was found, false otherwise
+func HashKeyFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(hashKeyCtxKey{}).(string)
+	return key, ok
+}
+
+// ringPoint is a virtual node of a hash ring
+type ringPoint struct {
+	hash     uint64
+	instance int
+}
+
+// consistentHash picks instances on a hash ring
+type consistentHash struct {
+	replicas int
+	mu       sync.Mutex
+	key      string      // Addresses the ring was built for
+	ring     []ringPoint // Virtual nodes sorted by hash
+}
+
+// ConsistentHash creates a balancer picking instances on a hash ring by the key set with WithHashKey,
+// so that requests with the same key go to the same instance while the instances do not change.
+// Requests without key are sent to a random instance.
+// Parameters:
+//   - replicas: Number of virtual nodes per instance, 100 if not positive
+//
+// Returns:
+//   - Balancer: A consistent hash balancer
+func ConsistentHash(replicas int) Balancer {
+	if replicas <= 0 {
+		replicas = 100
+	}
+	return &consistentHash{replicas: replicas}
+}
+
+// Pick picks the first instance after the hash of the key on the ring
+func (b *consistentHash) Pick(ctx context.Context, instances []*Instance) *Instance {
+	key, ok := HashKeyFromContext(ctx)
+	if !ok {
+		return instances[rand.N(len(instances))]
+	}
+	ring := b.buildRing(instances)
+	hash := hashString(key)
+	i, _ := slices.BinarySearchFunc(ring, hash, func(point ringPoint, hash uint64) int {
+		return cmp.Compare(point.hash, hash)
+	})
+	if i == len(ring) {
+		i = 0
+	}
+	return instances[ring[i].instance]
+}
+
+// buildRing returns the ring of the instances, rebuilt when they change
+func (b *consistentHash) buildRing(instances []*Instance) []ringPoint {
+	addresses := make([]string, 0, len(instances))
+	for _, instance := range instances {
+		addresses = append(addresses, instance.address)
+	}
+	key := strings.Join(addresses, ",")
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.key == key && b.ring != nil {
+		return b.ring
+	}
+	ring := make([]ringPoint, 0, len(instances)*b.replicas)
+	for i, address := range addresses {
+		for replica := 0; replica < b.replicas; replica++ {
+			ring = append(ring, ringPoint{hash: hashString(address + "#" + strconv.Itoa(replica)), instance: i})
+		}
+	}
+	slices.SortFunc(ring, func(a, b ringPoint) int {
+		return cmp.Compare(a.hash, b.hash)
+	})
+	b.key, b.ring = key, ring
+	return ring
+}
+
+// hashString returns the FNV-1a hash of a string, mixed with the SplitMix64 finalizer
+// to spread similar strings, such as the virtual nodes of an instance, over the ring
+func hashString(s string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(s))
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,121 @@
+package resolver
+
+import (
+	"context"
+	"strconv"
+	"testing"
+)
+
+// newInstances creates instances of the addresses
+func newInstances(addresses ...string) []*Instance {
+	instances := make([]*Instance, 0, len(addresses))
+	for _, address := range addresses {
+		instances = append(instances, &Instance{address: address})
+	}
+	return instances
+}
+
+// pickAll returns the addresses picked by n calls of the balancer
+func pickAll(ctx context.Context, balancer Balancer, instances []*Instance, n int) []string {
+	picks := make([]string, 0, n)
+	for range n {
+		picks = append(picks, balancer.Pick(ctx, instances).Address())
+	}
+	return picks
+}
+
+func TestRoundRobin(t *testing.T) {
+	instances := newInstances("a:80", "b:80", "c:80")
+	got := pickAll(context.Background(), RoundRobin(), instances, 7)
+	want := []string{"a:80", "b:80", "c:80", "a:80", "b:80", "c:80", "a:80"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("picks = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestRandom(t *testing.T) {
+	instances := newInstances("a:80", "b:80", "c:80")
+	counts := map[string]int{}
+	for _, address := range pickAll(context.Background(), Random(), instances, 300) {
+		counts[address]++
+	}
+	if len(counts) != 3 {
+		t.Errorf("picks = %v, want every instance picked", counts)
+	}
+	if got := Random().Pick(context.Background(), instances[:1]); got != instances[0] {
+		t.Errorf("single instance: picked %s", got.Address())
+	}
+}
+
+func TestLeastRequests(t *testing.T) {
+	instances := newInstances("a:80", "b:80")
+	instances[0].inFlight.Store(5)
+	// with two instances, both are compared on every pick
+	for _, address := range pickAll(context.Background(), LeastRequests(), instances, 50) {
+		if address != "b:80" {
+			t.Fatalf("picked %s, want the least loaded b:80", address)
+		}
+	}
+	instances[1].inFlight.Store(10)
+	for _, address := range pickAll(context.Background(), LeastRequests(), instances, 50) {
+		if address != "a:80" {
+			t.Fatalf("picked %s, want the least loaded a:80", address)
+		}
+	}
+	if got := LeastRequests().Pick(context.Background(), instances[1:]); got != instances[1] {
+		t.Errorf("single instance: picked %s", got.Address())
+	}
+}
+
+func TestConsistentHash(t *testing.T) {
+	balancer := ConsistentHash(0)
+	instances := newInstances("a:80", "b:80", "c:80")
+
+	// the same key goes to the same instance
+	picks := map[string]string{}
+	counts := map[string]int{}
+	for i := range 300 {
+		key := "user-" + strconv.Itoa(i)
+		ctx := WithHashKey(context.Background(), key)
+		address := balancer.Pick(ctx, instances).Address()
+		if again := balancer.Pick(ctx, instances).Address(); again != address {
+			t.Fatalf("key %s: picked %s then %s", key, address, again)
+		}
+		picks[key] = address
+		counts[address]++
+	}
+	// the keys are spread over the instances
+	for _, instance := range instances {
+		if counts[instance.Address()] < 50 {
+			t.Errorf("picks = %v, want the keys spread over the instances", counts)
+		}
+	}
+
+	// removing an instance only moves its keys
+	remaining := []*Instance{instances[0], instances[2]}
+	for key, address := range picks {
+		got := balancer.Pick(WithHashKey(context.Background(), key), remaining).Address()
+		if address != "b:80" && got != address {
+			t.Errorf("key %s moved from %s to %s", key, address, got)
+		}
+		if got == "b:80" {
+			t.Errorf("key %s picked the removed instance", key)
+		}
+	}
+
+	// requests without key go to any instance
+	if got := balancer.Pick(context.Background(), instances); got == nil {
+		t.Error("no instance picked without key")
+	}
+}
+
+func TestHashKeyFromContext(t *testing.T) {
+	if _, ok := HashKeyFromContext(context.Background()); ok {
+		t.Error("hash key found in an empty context")
+	}
+	if key, ok := HashKeyFromContext(WithHashKey(context.Background(), "user-1")); !ok || key != "user-1" {
+		t.Errorf("HashKeyFromContext = %q, %t", key, ok)
+	}
+}
@@ -0,0 +1,269 @@
+// Package resolver provides URL resolution functionality for the goose client
+package resolver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Ensure BalancingResolver implements the Resolver interface
+var _ Resolver = (*BalancingResolver)(nil)
+
+// ErrNoInstance is returned when a target resolves to no address
+var ErrNoInstance = errors.New("resolver: no instance available")
+
+// options holds configuration options for the balancing resolver
+type options struct {
+	httpScheme   string           // Scheme of the resolved URLs
+	maxFailures  int              // Consecutive failures ejecting an instance
+	ejectionTime time.Duration    // Duration of an ejection
+	now          func() time.Time // Clock, replaced in tests
+}
+
+// apply applies the given options to the options struct
+// Parameters:
+//   - opts: Variable number of Option functions
+//
+// Returns:
+//   - *options: Pointer to the updated options struct
+func (o *options) apply(opts ...Option) *options {
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Option is a function type for configuring the balancing resolver
+type Option func(o *options)
+
+// defaultOptions returns the default configuration options
+// Returns:
+//   - *options: Default options, URLs resolved with DefaultHttpScheme and
+//     instances ejected for 30s after 5 consecutive failures
+func defaultOptions() *options {
+	return &options{
+		maxFailures:  5,
+		ejectionTime: 30 * time.Second,
+		now:          time.Now,
+	}
+}
+
+// HttpScheme sets the scheme of the resolved URLs, DefaultHttpScheme by default
+// Parameters:
+//   - scheme: "http" or "https"
+//
+// Returns:
+//   - Option: Function to set the HTTP scheme option
+func HttpScheme(scheme string) Option {
+	return func(o *options) {
+		o.httpScheme = scheme
+	}
+}
+
+// MaxFailures sets the number of consecutive failures ejecting an instance
+// Parameters:
+//   - n: Number of consecutive failures, 0 disables ejection
+//
+// Returns:
+//   - Option: Function to set the max failures option
+func MaxFailures(n int) Option {
+	return func(o *options) {
+		o.maxFailures = n
+	}
+}
+
+// EjectionTime sets how long an instance is ejected
+// Parameters:
+//   - d: Ejection duration
+//
+// Returns:
+//   - Option: Function to set the ejection time option
+func EjectionTime(d time.Duration) Option {
+	return func(o *options) {
+		o.ejectionTime = d
+	}
+}
+
+// BalancingResolver is a resolver resolving a target to the addresses of its instances with a MultiResolver,
+// and picking one per request with a Balancer.
+// Failing instances are ejected passively, from the outcomes observed by its Transport.
+type BalancingResolver struct {
+	scheme    string
+	resolver  MultiResolver
+	balancer  Balancer
+	opt       *options
+	mu        sync.Mutex
+	instances map[string]*Instance // Instances by address
+}
+
+// NewBalancingResolver creates a balancing resolver
+// Parameters:
+//   - scheme: The scheme of the targets handled by the resolver, such as "lb"
+//   - resolver: The resolver of the addresses, such as a StaticResolver or a DNSResolver
+//   - balancer: The balancer picking an instance, RoundRobin if nil
+//   - opts: Variable number of Option functions for configuration
+//
+// Returns:
+//   - *BalancingResolver: The balancing resolver
+func NewBalancingResolver(scheme string, resolver MultiResolver, balancer Balancer, opts ...Option) *BalancingResolver {
+	if balancer == nil {
+		balancer = RoundRobin()
+	}
+	return &BalancingResolver{
+		scheme:    scheme,
+		resolver:  resolver,
+		balancer:  balancer,
+		opt:       defaultOptions().apply(opts...),
+		instances: map[string]*Instance{},
+	}
+}
+
+// Resolve resolves the target to the URL of an instance picked by the balancer
+// Parameters:
+//   - ctx: Context for the resolution operation
+//   - target: Target URL to resolve
+//
+// Returns:
+//   - *url.URL: URL of the target with the scheme of the instance and its address as host
+//   - error: Error if the target scheme doesn't match, if resolution fails or if there is no address
+func (r *BalancingResolver) Resolve(ctx context.Context, target *url.URL) (*url.URL, error) {
+	// Check if the target URL scheme matches this resolver's scheme (case-insensitive)
+	if !strings.EqualFold(target.Scheme, r.Scheme()) {
+		return nil, &ResolverError{target: target}
+	}
+
+	addresses, err := r.resolver.ResolveAll(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	instances := r.available(addresses, r.opt.now())
+	if len(instances) == 0 {
+		return nil, ErrNoInstance
+	}
+	instance := r.balancer.Pick(ctx, instances)
+
+	// Create a new URL with the address of the instance and copy the other components from the target
+	resolved := &url.URL{}
+	if r.opt.httpScheme != "" {
+		resolved.Scheme = r.opt.httpScheme
+	} else {
+		resolved.Scheme = DefaultHttpScheme
+	}
+	resolved.User = target.User
+	resolved.Host = instance.address
+	resolved.Path = target.Path
+	resolved.RawPath = target.RawPath
+	resolved.ForceQuery = target.ForceQuery
+	resolved.RawQuery = target.RawQuery
+	resolved.Fragment = target.Fragment
+	resolved.RawFragment = target.RawFragment
+
+	return resolved, nil
+}
+
+// Scheme returns the scheme that this resolver handles
+// Returns:
+//   - string: The scheme given to NewBalancingResolver
+func (r *BalancingResolver) Scheme() string {
+	return r.scheme
+}
+
+// available returns the instances of the addresses sorted by address, excluding the ejected ones
+// unless they are all ejected, and forgets the instances of the other addresses
+func (r *BalancingResolver) available(addresses []string, now time.Time) []*Instance {
+	addresses = slices.Compact(slices.Sorted(slices.Values(addresses)))
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	all := make([]*Instance, 0, len(addresses))
+	for _, address := range addresses {
+		instance, ok := r.instances[address]
+		if !ok {
+			instance = &Instance{address: address}
+			r.instances[address] = instance
+		}
+		all = append(all, instance)
+	}
+	if len(r.instances) > len(all) {
+		for address := range r.instances {
+			if _, found := slices.BinarySearch(addresses, address); !found {
+				delete(r.instances, address)
+			}
+		}
+	}
+	available := make([]*Instance, 0, len(all))
+	for _, instance := range all {
+		if now.After(instance.ejectedUntil) {
+			available = append(available, instance)
+		}
+	}
+	if len(available) == 0 {
+		// rather than failing, send requests to the ejected instances
+		return all
+	}
+	return available
+}
+
+// record records the outcome of a request sent to an instance, ejecting it after too many failures
+func (r *BalancingResolver) record(instance *Instance, failed bool, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !failed {
+		instance.failures = 0
+		return
+	}
+	instance.failures++
+	if r.opt.maxFailures > 0 && instance.failures >= r.opt.maxFailures {
+		instance.failures = 0
+		instance.ejectedUntil = now.Add(r.opt.ejectionTime)
+	}
+}
+
+// Transport wraps a transport to count the requests in flight to the instances and to eject failing instances
+// Parameters:
+//   - base: The wrapped transport, http.DefaultTransport if nil
+//
+// Returns:
+//   - http.RoundTripper: A transport to use as the Transport of the client of the service
+//
+// Behavior:
+//  1. Sends the requests to hosts that are not instances of the resolver unchanged
+//  2. Counts the requests in flight to the instances, for the LeastRequests balancer
+//  3. Counts transport errors, but canceled contexts, and 5xx responses as failures;
+//     an instance is ejected after MaxFailures consecutive failures
+func (r *BalancingResolver) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &balancingTransport{resolver: r, base: base}
+}
+
+// balancingTransport observes the requests sent to the instances of a BalancingResolver
+type balancingTransport struct {
+	resolver *BalancingResolver
+	base     http.RoundTripper
+}
+
+// RoundTrip sends the request and records its outcome
+func (t *balancingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	t.resolver.mu.Lock()
+	instance, ok := t.resolver.instances[request.URL.Host]
+	t.resolver.mu.Unlock()
+	if !ok {
+		return t.base.RoundTrip(request)
+	}
+	instance.inFlight.Add(1)
+	defer instance.inFlight.Add(-1)
+	response, err := t.base.RoundTrip(request)
+	if err != nil {
+		t.resolver.record(instance, !errors.Is(err, context.Canceled), t.resolver.opt.now())
+		return response, err
+	}
+	t.resolver.record(instance, response.StatusCode >= http.StatusInternalServerError, t.resolver.opt.now())
+	return response, err
+}
@@ -0,0 +1,227 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+)
+
+// fakeAddresses is a MultiResolver returning addresses set by the tests
+type fakeAddresses struct {
+	addresses []string
+	err       error
+}
+
+func (f *fakeAddresses) ResolveAll(ctx context.Context, target *url.URL) ([]string, error) {
+	return f.addresses, f.err
+}
+
+// fakeClock is a clock moved forward by the tests
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+// withClock replaces the clock of the resolver
+func withClock(clock *fakeClock) Option {
+	return func(o *options) {
+		o.now = clock.Now
+	}
+}
+
+// roundTripFunc is a transport answering with a function
+type roundTripFunc func(request *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
+
+// statusByHost answers each host with a status code, 200 by default
+func statusByHost(codes map[string]int) roundTripFunc {
+	return func(request *http.Request) (*http.Response, error) {
+		code, ok := codes[request.URL.Host]
+		if !ok {
+			code = http.StatusOK
+		}
+		return &http.Response{StatusCode: code, Body: http.NoBody, Request: request}, nil
+	}
+}
+
+// resolveHosts returns the hosts resolved by n calls
+func resolveHosts(t *testing.T, r *BalancingResolver, n int) []string {
+	t.Helper()
+	target, _ := url.Parse("lb://user")
+	hosts := make([]string, 0, n)
+	for range n {
+		resolved, err := r.Resolve(context.Background(), target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hosts = append(hosts, resolved.Host)
+	}
+	return hosts
+}
+
+func TestBalancingResolverResolve(t *testing.T) {
+	addresses := &fakeAddresses{addresses: []string{"10.0.0.2:80", "10.0.0.1:80", "10.0.0.2:80"}}
+	r := NewBalancingResolver("lb", addresses, nil)
+	if r.Scheme() != "lb" {
+		t.Errorf("Scheme() = %q", r.Scheme())
+	}
+
+	target, _ := url.Parse("LB://alice@user/v1/users?page=2#top")
+	resolved, err := r.Resolve(context.Background(), target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resolved.String(); got != "http://alice@10.0.0.1:80/v1/users?page=2#top" {
+		t.Errorf("resolved = %s", got)
+	}
+
+	// the addresses are deduplicated and sorted, picked in turn by default
+	if got := resolveHosts(t, r, 3); !slices.Equal(got, []string{"10.0.0.2:80", "10.0.0.1:80", "10.0.0.2:80"}) {
+		t.Errorf("hosts = %v", got)
+	}
+
+	r = NewBalancingResolver("lb", addresses, nil, HttpScheme("https"))
+	if resolved, _ := r.Resolve(context.Background(), target); resolved.Scheme != "https" {
+		t.Errorf("scheme = %q, want https", resolved.Scheme)
+	}
+}
+
+func TestBalancingResolverErrors(t *testing.T) {
+	target, _ := url.Parse("lb://user")
+	r := NewBalancingResolver("other", &fakeAddresses{addresses: []string{"10.0.0.1:80"}}, nil)
+	var resolverErr *ResolverError
+	if _, err := r.Resolve(context.Background(), target); !errors.As(err, &resolverErr) {
+		t.Errorf("scheme mismatch: err = %v, want a ResolverError", err)
+	}
+
+	r = NewBalancingResolver("lb", &fakeAddresses{}, nil)
+	if _, err := r.Resolve(context.Background(), target); !errors.Is(err, ErrNoInstance) {
+		t.Errorf("no address: err = %v, want ErrNoInstance", err)
+	}
+
+	lookupErr := errors.New("lookup failed")
+	r = NewBalancingResolver("lb", &fakeAddresses{err: lookupErr}, nil)
+	if _, err := r.Resolve(context.Background(), target); !errors.Is(err, lookupErr) {
+		t.Errorf("lookup error: err = %v", err)
+	}
+}
+
+func TestBalancingResolverForgetsInstances(t *testing.T) {
+	addresses := &fakeAddresses{addresses: []string{"10.0.0.1:80", "10.0.0.2:80"}}
+	r := NewBalancingResolver("lb", addresses, nil)
+	resolveHosts(t, r, 1)
+	addresses.addresses = []string{"10.0.0.2:80"}
+	resolveHosts(t, r, 1)
+	if _, ok := r.instances["10.0.0.1:80"]; ok || len(r.instances) != 1 {
+		t.Errorf("instances = %v, want the removed address forgotten", r.instances)
+	}
+}
+
+func TestBalancingResolverEjection(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	addresses := &fakeAddresses{addresses: []string{"10.0.0.1:80", "10.0.0.2:80"}}
+	r := NewBalancingResolver("lb", addresses, nil, MaxFailures(2), EjectionTime(10*time.Second), withClock(clock))
+	codes := map[string]int{"10.0.0.1:80": http.StatusServiceUnavailable}
+	cli := &http.Client{Transport: r.Transport(statusByHost(codes))}
+	send := func(host string) {
+		t.Helper()
+		response, err := cli.Get("http://" + host + "/")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = response.Body.Close()
+	}
+
+	// the instances are tracked once resolved
+	resolveHosts(t, r, 1)
+
+	// a success resets the consecutive failures
+	send("10.0.0.1:80")
+	codes["10.0.0.1:80"] = http.StatusOK
+	send("10.0.0.1:80")
+	codes["10.0.0.1:80"] = http.StatusServiceUnavailable
+	send("10.0.0.1:80")
+	if got := resolveHosts(t, r, 2); !slices.Contains(got, "10.0.0.1:80") {
+		t.Fatalf("hosts = %v, the instance was ejected after non-consecutive failures", got)
+	}
+
+	// the instance is ejected after MaxFailures consecutive failures
+	send("10.0.0.1:80")
+	if got := resolveHosts(t, r, 4); slices.Contains(got, "10.0.0.1:80") {
+		t.Fatalf("hosts = %v, want the failing instance ejected", got)
+	}
+
+	// it recovers once the ejection time elapsed
+	clock.now = clock.now.Add(10*time.Second + time.Nanosecond)
+	if got := resolveHosts(t, r, 4); !slices.Contains(got, "10.0.0.1:80") {
+		t.Errorf("hosts = %v, want the instance back after the ejection", got)
+	}
+}
+
+func TestBalancingResolverAllEjected(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	r := NewBalancingResolver("lb", &fakeAddresses{addresses: []string{"10.0.0.1:80"}}, nil, MaxFailures(1), withClock(clock))
+	resolveHosts(t, r, 1)
+	cli := &http.Client{Transport: r.Transport(statusByHost(map[string]int{"10.0.0.1:80": http.StatusInternalServerError}))}
+	response, err := cli.Get("http://10.0.0.1:80/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = response.Body.Close()
+	if r.instances["10.0.0.1:80"].ejectedUntil.IsZero() {
+		t.Fatal("the instance was not ejected")
+	}
+	// rather than failing, the ejected instances are used
+	if got := resolveHosts(t, r, 1); got[0] != "10.0.0.1:80" {
+		t.Errorf("hosts = %v", got)
+	}
+}
+
+func TestBalancingTransport(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	r := NewBalancingResolver("lb", &fakeAddresses{addresses: []string{"10.0.0.1:80", "10.0.0.2:80"}}, nil, MaxFailures(1), withClock(clock))
+	resolveHosts(t, r, 1)
+	instance := r.instances["10.0.0.1:80"]
+
+	// the requests in flight are counted
+	var inFlight int64
+	var err error
+	transport := r.Transport(roundTripFunc(func(request *http.Request) (*http.Response, error) {
+		inFlight = instance.InFlight()
+		return nil, err
+	}))
+	request, _ := http.NewRequest(http.MethodGet, "http://10.0.0.1:80/", nil)
+	err = context.Canceled
+	_, _ = transport.RoundTrip(request)
+	if inFlight != 1 || instance.InFlight() != 0 {
+		t.Errorf("in flight = %d during the request and %d after, want 1 and 0", inFlight, instance.InFlight())
+	}
+	// canceled requests are not failures
+	if !instance.ejectedUntil.IsZero() {
+		t.Error("the instance was ejected after a canceled request")
+	}
+	// transport errors are
+	err = errors.New("connection refused")
+	_, _ = transport.RoundTrip(request)
+	if instance.ejectedUntil.IsZero() {
+		t.Error("the instance was not ejected after a transport error")
+	}
+
+	// requests to other hosts are sent unchanged and not tracked
+	err = nil
+	request, _ = http.NewRequest(http.MethodGet, "http://example.com/", nil)
+	_, _ = transport.RoundTrip(request)
+	if len(r.instances) != 2 {
+		t.Errorf("instances = %v, want the other host not tracked", r.instances)
+	}
+}
@@ -0,0 +1,159 @@
+// Package resolver provides URL resolution functionality for the goose client
+package resolver
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Ensure CachingResolver implements the MultiResolver interface
+var _ MultiResolver = (*CachingResolver)(nil)
+
+// cacheEntry is the cached resolution of a target
+type cacheEntry struct {
+	addresses []string
+	expiresAt time.Time
+}
+
+// watchEntry is the watched resolution of a target
+type watchEntry struct {
+	ready     chan struct{} // Closed when the first addresses, or the error, are known
+	addresses []string
+	err       error
+}
+
+// CachingResolver is a MultiResolver caching the addresses resolved by another MultiResolver,
+// so that targets are not resolved on every request.
+// The targets of a WatchResolver are watched, and their addresses updated when they change;
+// the addresses of other resolvers are cached for a TTL.
+type CachingResolver struct {
+	resolver MultiResolver
+	ttl      time.Duration
+	now      func() time.Time // Clock, replaced in tests
+	ctx      context.Context
+	cancel   context.CancelFunc
+	mu       sync.Mutex
+	entries  map[string]*cacheEntry // Cached resolutions by target
+	watches  map[string]*watchEntry // Watched resolutions by target
+}
+
+// NewCachingResolver creates a caching resolver
+// Parameters:
+//   - resolver: The cached resolver, such as a DNSResolver or a fileresolver.Resolver
+//   - ttl: How long the addresses of a resolver that cannot be watched are cached, 30s if not positive
+//
+// Returns:
+//   - *CachingResolver: The caching resolver, to Close when it is no longer used
+func NewCachingResolver(resolver MultiResolver, ttl time.Duration) *CachingResolver {
+	if ttl <= 0 {
+		ttl = 30 * time.Second
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &CachingResolver{
+		resolver: resolver,
+		ttl:      ttl,
+		now:      time.Now,
+		ctx:      ctx,
+		cancel:   cancel,
+		entries:  map[string]*cacheEntry{},
+		watches:  map[string]*watchEntry{},
+	}
+}
+
+// ResolveAll returns the cached addresses of the target, resolving them when they are missing or expired.
+// When the resolution of expired addresses fails, the stale addresses are served for another TTL.
+// Parameters:
+//   - ctx: Context for the resolution operation
+//   - target: Target URL to resolve
+//
+// Returns:
+//   - []string: The addresses of the target
+//   - error: Error if the target cannot be resolved and no addresses are cached
+func (r *CachingResolver) ResolveAll(ctx context.Context, target *url.URL) ([]string, error) {
+	if watcher, ok := r.resolver.(WatchResolver); ok {
+		return r.watched(ctx, watcher, target)
+	}
+	return r.cached(ctx, target)
+}
+
+// Close stops the watches
+func (r *CachingResolver) Close() error {
+	r.cancel()
+	return nil
+}
+
+// cached returns the addresses of the target cached for a TTL
+func (r *CachingResolver) cached(ctx context.Context, target *url.URL) ([]string, error) {
+	key := target.String()
+	now := r.now()
+	r.mu.Lock()
+	entry, ok := r.entries[key]
+	r.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.addresses, nil
+	}
+	addresses, err := r.resolver.ResolveAll(ctx, target)
+	if err != nil {
+		if !ok {
+			return nil, err
+		}
+		addresses = entry.addresses
+	}
+	r.mu.Lock()
+	r.entries[key] = &cacheEntry{addresses: addresses, expiresAt: now.Add(r.ttl)}
+	r.mu.Unlock()
+	return addresses, nil
+}
+
+// watched returns the addresses of the target, starting a watch on the first call
+func (r *CachingResolver) watched(ctx context.Context, watcher WatchResolver, target *url.URL) ([]string, error) {
+	key := target.String()
+	r.mu.Lock()
+	entry, ok := r.watches[key]
+	if !ok {
+		entry = &watchEntry{ready: make(chan struct{})}
+		r.watches[key] = entry
+		go r.watch(watcher, target, key, entry)
+	}
+	r.mu.Unlock()
+	select {
+	case <-entry.ready:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return entry.addresses, entry.err
+}
+
+// watch updates the entry of the target until the watch ends, the next call then watches the target again
+func (r *CachingResolver) watch(watcher WatchResolver, target *url.URL, key string, entry *watchEntry) {
+	defer func() {
+		r.mu.Lock()
+		if r.watches[key] == entry {
+			delete(r.watches, key)
+		}
+		r.mu.Unlock()
+	}()
+	updates, err := watcher.Watch(r.ctx, target)
+	if err == nil {
+		var ok bool
+		if entry.addresses, ok = <-updates; !ok {
+			if err = r.ctx.Err(); err == nil {
+				err = ErrNoInstance
+			}
+		}
+	}
+	entry.err = err
+	close(entry.ready)
+	if err != nil {
+		return
+	}
+	for addresses := range updates {
+		r.mu.Lock()
+		entry.addresses = addresses
+		r.mu.Unlock()
+	}
+}
@@ -0,0 +1,184 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+)
+
+// countingAddresses is a MultiResolver counting its resolutions
+type countingAddresses struct {
+	fakeAddresses
+	calls int
+}
+
+func (c *countingAddresses) ResolveAll(ctx context.Context, target *url.URL) ([]string, error) {
+	c.calls++
+	return c.fakeAddresses.ResolveAll(ctx, target)
+}
+
+// fakeWatcher is a WatchResolver pushing the addresses sent by the tests
+type fakeWatcher struct {
+	fakeAddresses
+	err     error
+	watches chan chan []string // Receives the channel of each watch
+}
+
+func (f *fakeWatcher) Watch(ctx context.Context, target *url.URL) (<-chan []string, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	updates := make(chan []string)
+	f.watches <- updates
+	return updates, nil
+}
+
+func TestCachingResolverTTL(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	addresses := &countingAddresses{fakeAddresses: fakeAddresses{addresses: []string{"10.0.0.1:80"}}}
+	r := NewCachingResolver(addresses, 10*time.Second)
+	r.now = clock.Now
+	defer r.Close()
+	target, _ := url.Parse("lb://user")
+	other, _ := url.Parse("lb://order")
+
+	tests := []struct {
+		name    string
+		advance time.Duration
+		target  *url.URL
+		set     []string
+		err     error
+		want    []string
+		calls   int
+	}{
+		{name: "first", target: target, want: []string{"10.0.0.1:80"}, calls: 1},
+		{name: "cached", advance: 9 * time.Second, target: target, set: []string{"10.0.0.2:80"}, want: []string{"10.0.0.1:80"}, calls: 1},
+		{name: "other target", target: other, want: []string{"10.0.0.2:80"}, calls: 2},
+		{name: "refreshed", advance: time.Second, target: target, want: []string{"10.0.0.2:80"}, calls: 3},
+		{name: "stale on error", advance: 10 * time.Second, target: target, err: errors.New("lookup failed"), want: []string{"10.0.0.2:80"}, calls: 4},
+		// the stale addresses are served for another TTL
+		{name: "stale cached", advance: 9 * time.Second, target: target, want: []string{"10.0.0.2:80"}, calls: 4},
+	}
+	for _, tt := range tests {
+		clock.now = clock.now.Add(tt.advance)
+		if tt.set != nil {
+			addresses.addresses = tt.set
+		}
+		addresses.err = tt.err
+		got, err := r.ResolveAll(context.Background(), tt.target)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !slices.Equal(got, tt.want) || addresses.calls != tt.calls {
+			t.Errorf("%s: got %v after %d calls, want %v after %d calls", tt.name, got, addresses.calls, tt.want, tt.calls)
+		}
+	}
+
+	// without cached addresses, the error is returned
+	addresses.err = errors.New("lookup failed")
+	missing, _ := url.Parse("lb://missing")
+	if _, err := r.ResolveAll(context.Background(), missing); !errors.Is(err, addresses.err) {
+		t.Errorf("missing: err = %v", err)
+	}
+}
+
+func TestCachingResolverDefaultTTL(t *testing.T) {
+	r := NewCachingResolver(&fakeAddresses{}, 0)
+	defer r.Close()
+	if r.ttl != 30*time.Second {
+		t.Errorf("ttl = %v, want 30s", r.ttl)
+	}
+}
+
+// waitAddresses waits for the resolver to return the addresses
+func waitAddresses(t *testing.T, r *CachingResolver, target *url.URL, want []string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		got, err := r.ResolveAll(context.Background(), target)
+		if err == nil && slices.Equal(got, want) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("ResolveAll = %v, %v, want %v", got, err, want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCachingResolverWatch(t *testing.T) {
+	watcher := &fakeWatcher{watches: make(chan chan []string, 1)}
+	r := NewCachingResolver(watcher, time.Second)
+	defer r.Close()
+	target, _ := url.Parse("lb://user")
+
+	// the first call waits for the first addresses
+	done := make(chan []string)
+	go func() {
+		addresses, _ := r.ResolveAll(context.Background(), target)
+		done <- addresses
+	}()
+	updates := <-watcher.watches
+	updates <- []string{"10.0.0.1:80"}
+	if got := <-done; !slices.Equal(got, []string{"10.0.0.1:80"}) {
+		t.Fatalf("first = %v", got)
+	}
+
+	// the addresses are updated without another watch
+	updates <- []string{"10.0.0.1:80", "10.0.0.2:80"}
+	waitAddresses(t, r, target, []string{"10.0.0.1:80", "10.0.0.2:80"})
+	select {
+	case <-watcher.watches:
+		t.Fatal("the target was watched twice")
+	default:
+	}
+
+	// the next call watches the target again once the watch ended
+	close(updates)
+	for watching := true; watching; time.Sleep(time.Millisecond) {
+		r.mu.Lock()
+		_, watching = r.watches[target.String()]
+		r.mu.Unlock()
+	}
+	go func() {
+		addresses, _ := r.ResolveAll(context.Background(), target)
+		done <- addresses
+	}()
+	updates = <-watcher.watches
+	updates <- []string{"10.0.0.3:80"}
+	if got := <-done; !slices.Equal(got, []string{"10.0.0.3:80"}) {
+		t.Errorf("after the watch ended = %v", got)
+	}
+}
+
+func TestCachingResolverWatchErrors(t *testing.T) {
+	target, _ := url.Parse("lb://user")
+
+	watchErr := errors.New("watch failed")
+	r := NewCachingResolver(&fakeWatcher{err: watchErr}, time.Second)
+	defer r.Close()
+	if _, err := r.ResolveAll(context.Background(), target); !errors.Is(err, watchErr) {
+		t.Errorf("watch error: err = %v", err)
+	}
+
+	// a watch closed before the first addresses has no instance
+	watcher := &fakeWatcher{watches: make(chan chan []string, 1)}
+	r = NewCachingResolver(watcher, time.Second)
+	defer r.Close()
+	go func() { close(<-watcher.watches) }()
+	if _, err := r.ResolveAll(context.Background(), target); !errors.Is(err, ErrNoInstance) {
+		t.Errorf("closed watch: err = %v, want ErrNoInstance", err)
+	}
+
+	// the call gives up with its context
+	r = NewCachingResolver(&fakeWatcher{watches: make(chan chan []string, 1)}, time.Second)
+	defer r.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := r.ResolveAll(ctx, target); !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled: err = %v", err)
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/go-leo/goose/client/resolver/fileresolver
+
+go 1.23.0
+
+require (
+	github.com/go-leo/goose v1.6.11
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+replace github.com/go-leo/goose => ../../../
@@ -0,0 +1,124 @@
+// Package fileresolver provides a resolver watching a file listing the addresses of a service.
+// It is a module of its own, so that the goose module does not depend on a YAML parser.
+package fileresolver
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/go-leo/goose/client/resolver"
+	"gopkg.in/yaml.v3"
+)
+
+// Ensure Resolver implements the WatchResolver interface
+var _ resolver.WatchResolver = (*Resolver)(nil)
+
+// Resolver resolves any target to the addresses listed in a JSON or YAML file, such as
+// ["10.0.0.1:8080", "10.0.0.2:8080"] or a YAML sequence, and watches the file for changes
+type Resolver struct {
+	Path     string        // Path of the file
+	Interval time.Duration // Interval between checks for changes, 1s if zero
+}
+
+// ResolveAll reads the addresses of the file
+// Parameters:
+//   - ctx: Context for the resolution operation
+//   - target: Target URL, ignored
+//
+// Returns:
+//   - []string: The addresses of the file
+//   - error: Error if the file cannot be read or parsed
+func (r *Resolver) ResolveAll(ctx context.Context, target *url.URL) ([]string, error) {
+	data, err := os.ReadFile(r.Path)
+	if err != nil {
+		return nil, err
+	}
+	// an empty file is usually being written, an empty list must be explicit
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, fmt.Errorf("fileresolver: %s is empty", r.Path)
+	}
+	var addresses []string
+	if err := yaml.Unmarshal(data, &addresses); err != nil {
+		return nil, fmt.Errorf("fileresolver: failed to parse %s: %w", r.Path, err)
+	}
+	return addresses, nil
+}
+
+// Watch checks the modification time and size of the file every Interval and pushes its addresses
+// when they change. Read or parse errors are logged, the last valid addresses stay in effect.
+// Parameters:
+//   - ctx: Context bounding the watch
+//   - target: Target URL, ignored
+//
+// Returns:
+//   - <-chan []string: Channel receiving the addresses
+//   - error: Error if the file cannot be read or parsed initially
+func (r *Resolver) Watch(ctx context.Context, target *url.URL) (<-chan []string, error) {
+	info, err := os.Stat(r.Path)
+	if err != nil {
+		return nil, err
+	}
+	addresses, err := r.ResolveAll(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	interval := r.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	updates := make(chan []string, 1)
+	updates <- addresses
+	go func() {
+		defer close(updates)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			latest, err := os.Stat(r.Path)
+			if err != nil {
+				slog.ErrorContext(ctx, "fileresolver: failed to stat file", slog.String("path", r.Path), slog.String("error", err.Error()))
+				continue
+			}
+			if latest.ModTime().Equal(info.ModTime()) && latest.Size() == info.Size() {
+				continue
+			}
+			info = latest
+			reloaded, err := r.ResolveAll(ctx, target)
+			if err != nil {
+				slog.ErrorContext(ctx, "fileresolver: failed to reload file", slog.String("path", r.Path), slog.String("error", err.Error()))
+				continue
+			}
+			if slices.Equal(reloaded, addresses) {
+				continue
+			}
+			addresses = reloaded
+			push(updates, addresses)
+		}
+	}()
+	return updates, nil
+}
+
+// push sends the addresses to a channel with a buffer of 1, replacing the addresses not received yet
+func push(updates chan []string, addresses []string) {
+	for {
+		select {
+		case updates <- addresses:
+			return
+		default:
+		}
+		select {
+		case <-updates:
+		default:
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package fileresolver
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// writeFile writes the file and moves its modification time forward, so that the change is seen
+// even within the resolution of the file system clock
+func writeFile(t *testing.T, path string, content string, modTime time.Time) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResolveAll(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+		wantErr bool
+	}{
+		{name: "json", content: `["10.0.0.1:80", "10.0.0.2:80"]`, want: []string{"10.0.0.1:80", "10.0.0.2:80"}},
+		{name: "yaml", content: "- 10.0.0.1:80\n- 10.0.0.2:80\n", want: []string{"10.0.0.1:80", "10.0.0.2:80"}},
+		{name: "explicit empty list", content: "[]", want: []string{}},
+		{name: "empty file", content: " \n", wantErr: true},
+		{name: "invalid", content: "{address: 10.0.0.1:80}", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "addresses.yaml")
+			writeFile(t, path, tt.content, time.Now())
+			got, err := (&Resolver{Path: path}).ResolveAll(context.Background(), nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %t", err, tt.wantErr)
+			}
+			if !tt.wantErr && !slices.Equal(got, tt.want) {
+				t.Errorf("ResolveAll = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := (&Resolver{Path: filepath.Join(t.TempDir(), "missing")}).ResolveAll(context.Background(), nil); err == nil {
+		t.Error("missing file: no error")
+	}
+}
+
+// receive returns the next addresses of the watch
+func receive(t *testing.T, updates <-chan []string) []string {
+	t.Helper()
+	select {
+	case addresses := <-updates:
+		return addresses
+	case <-time.After(5 * time.Second):
+		t.Fatal("no addresses received")
+		return nil
+	}
+}
+
+// expectNone checks that no addresses are received for a few intervals
+func expectNone(t *testing.T, updates <-chan []string) {
+	t.Helper()
+	select {
+	case addresses := <-updates:
+		t.Fatalf("received %v, want no update", addresses)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "addresses.json")
+	modTime := time.Now().Add(-time.Hour)
+	writeFile(t, path, `["10.0.0.1:80"]`, modTime)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates, err := (&Resolver{Path: path, Interval: 5 * time.Millisecond}).Watch(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := receive(t, updates); !slices.Equal(got, []string{"10.0.0.1:80"}) {
+		t.Fatalf("initial = %v", got)
+	}
+
+	// a change is reloaded
+	modTime = modTime.Add(time.Second)
+	writeFile(t, path, `["10.0.0.1:80", "10.0.0.2:80"]`, modTime)
+	if got := receive(t, updates); !slices.Equal(got, []string{"10.0.0.1:80", "10.0.0.2:80"}) {
+		t.Fatalf("reloaded = %v", got)
+	}
+
+	// the same addresses are not pushed again
+	modTime = modTime.Add(time.Second)
+	writeFile(t, path, "- 10.0.0.1:80\n- 10.0.0.2:80\n", modTime)
+	expectNone(t, updates)
+
+	// an invalid file keeps the last valid addresses
+	modTime = modTime.Add(time.Second)
+	writeFile(t, path, "", modTime)
+	expectNone(t, updates)
+	modTime = modTime.Add(time.Second)
+	writeFile(t, path, `["10.0.0.3:80"]`, modTime)
+	if got := receive(t, updates); !slices.Equal(got, []string{"10.0.0.3:80"}) {
+		t.Fatalf("after an invalid file = %v", got)
+	}
+
+	// the channel is closed with the context
+	cancel()
+	for range updates {
+	}
+}
+
+func TestWatchErrors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := (&Resolver{Path: filepath.Join(dir, "missing")}).Watch(context.Background(), nil); err == nil {
+		t.Error("missing file: no error")
+	}
+	path := filepath.Join(dir, "invalid")
+	writeFile(t, path, "{", time.Now())
+	if _, err := (&Resolver{Path: path}).Watch(context.Background(), nil); err == nil {
+		t.Error("invalid file: no error")
+	}
+}
+
+func TestPush(t *testing.T) {
+	updates := make(chan []string, 1)
+	push(updates, []string{"10.0.0.1:80"})
+	push(updates, []string{"10.0.0.2:80"})
+	if got := <-updates; !slices.Equal(got, []string{"10.0.0.2:80"}) {
+		t.Errorf("received %v, want only the latest addresses", got)
+	}
+}
@@ -0,0 +1,103 @@
+// Package resolver provides URL resolution functionality for the goose client
+package resolver
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Ensure StaticResolver and DNSResolver implement the MultiResolver interface
+var (
+	_ MultiResolver = StaticResolver{}
+	_ MultiResolver = (*DNSResolver)(nil)
+)
+
+// MultiResolver is an interface for resolving a target URL to the addresses of all the instances of a service.
+// It is used with a BalancingResolver picking an address per request.
+type MultiResolver interface {
+	// ResolveAll takes a target URL and returns the addresses of its instances
+	// Parameters:
+	//   - ctx: Context for the resolution operation
+	//   - target: Target URL to resolve
+	// Returns:
+	//   - []string: Addresses in host:port form
+	//   - error: Error if resolution fails
+	ResolveAll(ctx context.Context, target *url.URL) ([]string, error)
+}
+
+// StaticResolver resolves any target to a fixed list of addresses
+type StaticResolver struct {
+	Addresses []string // Addresses in host:port form
+}
+
+// ResolveAll returns the static addresses
+// Parameters:
+//   - ctx: Context for the resolution operation
+//   - target: Target URL, ignored
+//
+// Returns:
+//   - []string: The static addresses
+//   - error: Always nil
+func (r StaticResolver) ResolveAll(ctx context.Context, target *url.URL) ([]string, error) {
+	return r.Addresses, nil
+}
+
+// DNSResolver resolves the host of a target with DNS.
+// Without Service, it looks up the A and AAAA records of the host and keeps the port of the target;
+// with Service, it looks up the SRV records of _service._proto.host, which give the ports.
+type DNSResolver struct {
+	Resolver *net.Resolver // DNS resolver, net.DefaultResolver if nil
+	Service  string        // SRV service, such as "http"
+	Proto    string        // SRV protocol, "tcp" if empty
+}
+
+// ResolveAll looks up the addresses of the host of the target
+// Parameters:
+//   - ctx: Context for the resolution operation
+//   - target: Target URL whose host is looked up
+//
+// Returns:
+//   - []string: Addresses in host:port form, or host form if the target has no port and Service is empty
+//   - error: Error if the lookup fails
+func (r *DNSResolver) ResolveAll(ctx context.Context, target *url.URL) ([]string, error) {
+	dns := r.Resolver
+	if dns == nil {
+		dns = net.DefaultResolver
+	}
+	host := target.Hostname()
+	if r.Service != "" {
+		proto := r.Proto
+		if proto == "" {
+			proto = "tcp"
+		}
+		_, records, err := dns.LookupSRV(ctx, r.Service, proto, host)
+		if err != nil {
+			return nil, err
+		}
+		addresses := make([]string, 0, len(records))
+		for _, record := range records {
+			addresses = append(addresses, net.JoinHostPort(strings.TrimSuffix(record.Target, "."), strconv.Itoa(int(record.Port))))
+		}
+		return addresses, nil
+	}
+	ips, err := dns.LookupHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	port := target.Port()
+	addresses := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		switch {
+		case port != "":
+			addresses = append(addresses, net.JoinHostPort(ip, port))
+		case strings.Contains(ip, ":"):
+			addresses = append(addresses, "["+ip+"]")
+		default:
+			addresses = append(addresses, ip)
+		}
+	}
+	return addresses, nil
+}
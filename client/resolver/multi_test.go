@@ -0,0 +1,145 @@
+package resolver
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"testing"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func TestStaticResolver(t *testing.T) {
+	r := StaticResolver{Addresses: []string{"10.0.0.1:80", "10.0.0.2:80"}}
+	target, _ := url.Parse("lb://user")
+	got, err := r.ResolveAll(context.Background(), target)
+	if err != nil || !slices.Equal(got, r.Addresses) {
+		t.Errorf("ResolveAll = %v, %v", got, err)
+	}
+}
+
+// serveDNS starts a DNS server answering the queries of the test zone, and returns a resolver querying it
+func serveDNS(t *testing.T) *net.Resolver {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			answer, err := answerDNS(buf[:n])
+			if err != nil {
+				continue
+			}
+			_, _ = conn.WriteTo(answer, addr)
+		}
+	}()
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "udp", conn.LocalAddr().String())
+		},
+	}
+}
+
+// answerDNS answers a query of the test zone:
+// user.test. has A 10.0.0.1 and AAAA ::1, _http._tcp.user.test. has SRV records on ports 8081 and 8082
+func answerDNS(query []byte) ([]byte, error) {
+	var parser dnsmessage.Parser
+	header, err := parser.Start(query)
+	if err != nil {
+		return nil, err
+	}
+	question, err := parser.Question()
+	if err != nil {
+		return nil, err
+	}
+	builder := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: header.ID, Response: true, Authoritative: true})
+	builder.EnableCompression()
+	if err := builder.StartQuestions(); err != nil {
+		return nil, err
+	}
+	if err := builder.Question(question); err != nil {
+		return nil, err
+	}
+	if err := builder.StartAnswers(); err != nil {
+		return nil, err
+	}
+	resource := dnsmessage.ResourceHeader{Name: question.Name, Class: dnsmessage.ClassINET, TTL: 60}
+	switch {
+	case question.Name.String() == "user.test." && question.Type == dnsmessage.TypeA:
+		err = builder.AResource(resource, dnsmessage.AResource{A: [4]byte{10, 0, 0, 1}})
+	case question.Name.String() == "user.test." && question.Type == dnsmessage.TypeAAAA:
+		err = builder.AAAAResource(resource, dnsmessage.AAAAResource{AAAA: [16]byte{15: 1}})
+	case question.Name.String() == "_http._tcp.user.test." && question.Type == dnsmessage.TypeSRV:
+		for _, port := range []uint16{8081, 8082} {
+			target := dnsmessage.MustNewName("node" + strconv.Itoa(int(port-8080)) + ".user.test.")
+			if err = builder.SRVResource(resource, dnsmessage.SRVResource{Priority: 1, Weight: 1, Port: port, Target: target}); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return builder.Finish()
+}
+
+func TestDNSResolver(t *testing.T) {
+	dns := serveDNS(t)
+	tests := []struct {
+		name     string
+		resolver *DNSResolver
+		target   string
+		want     []string
+	}{
+		{
+			name:     "host with port",
+			resolver: &DNSResolver{Resolver: dns},
+			target:   "lb://user.test:8080",
+			want:     []string{"10.0.0.1:8080", "[::1]:8080"},
+		},
+		{
+			name:     "host without port",
+			resolver: &DNSResolver{Resolver: dns},
+			target:   "lb://user.test",
+			want:     []string{"10.0.0.1", "[::1]"},
+		},
+		{
+			name:     "SRV",
+			resolver: &DNSResolver{Resolver: dns, Service: "http"},
+			target:   "lb://user.test",
+			want:     []string{"node1.user.test:8081", "node2.user.test:8082"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, _ := url.Parse(tt.target)
+			got, err := tt.resolver.ResolveAll(context.Background(), target)
+			if err != nil {
+				t.Fatal(err)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ResolveAll = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDNSResolverNotFound(t *testing.T) {
+	r := &DNSResolver{Resolver: serveDNS(t), Service: "grpc"}
+	target, _ := url.Parse("lb://user.test")
+	if got, err := r.ResolveAll(context.Background(), target); err == nil {
+		t.Errorf("ResolveAll = %v, want an error for a missing SRV record", got)
+	}
+}
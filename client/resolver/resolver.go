@@ -25,20 +25,59 @@ type Resolver interface {
 	Scheme() string
 }
 
-// registered is a thread-safe map storing registered resolvers by their scheme
-var registered = sync.Map{}
+// Registry is a thread-safe set of resolvers by scheme.
+// DefaultRegistry is shared by the clients without their own registry;
+// a client can be given its own with client.ResolverRegistry, so that its resolvers do not leak to other clients.
+type Registry struct {
+	resolvers sync.Map // Resolvers by scheme
+}
 
-// RegisterResolver registers a resolver for its scheme
-// This allows the resolver to be automatically used when resolving URLs with matching schemes
+// DefaultRegistry is the global registry, holding the DefaultResolver, HttpResolver and HttpsResolver
+// and the resolvers registered with RegisterResolver
+var DefaultRegistry = &Registry{}
+
+// NewRegistry creates a registry holding the DefaultResolver, HttpResolver and HttpsResolver, and the given resolvers
+// Parameters:
+//   - resolvers: Resolvers to register, replacing the built-in resolver of the same scheme
+//
+// Returns:
+//   - *Registry: The registry
+func NewRegistry(resolvers ...Resolver) *Registry {
+	registry := &Registry{}
+	registry.Register(&DefaultResolver{})
+	registry.Register(&HttpResolver{})
+	registry.Register(&HttpsResolver{})
+	for _, resolver := range resolvers {
+		registry.Register(resolver)
+	}
+	return registry
+}
+
+// Register registers a resolver for its scheme, replacing the resolver registered for the same scheme
 // Parameters:
 //   - resolver: Resolver to register
-func RegisterResolver(resolver Resolver) {
-	registered.Store(resolver.Scheme(), resolver)
+func (r *Registry) Register(resolver Resolver) {
+	r.resolvers.Store(resolver.Scheme(), resolver)
+}
+
+// Lookup returns the resolver registered for a scheme
+// Parameters:
+//   - scheme: URL scheme
+//
+// Returns:
+//   - Resolver: The registered resolver
+//   - bool: True if a resolver is registered for the scheme, false otherwise
+func (r *Registry) Lookup(scheme string) (Resolver, bool) {
+	resolver, ok := r.resolvers.Load(scheme)
+	if !ok {
+		return nil, false
+	}
+	return resolver.(Resolver), true
 }
 
 // Resolve resolves a target URL string using the appropriate resolver
 // It first tries to use the provided resolver if its scheme matches the target,
-// otherwise it looks up a registered resolver by the target's scheme
+// otherwise it looks up a resolver registered by the target's scheme
 // Parameters:
 //   - ctx: Context for the resolution operation
 //   - resolver: Optional resolver to try first (can be nil)
@@ -47,7 +86,7 @@ func RegisterResolver(resolver Resolver) {
 // Returns:
 //   - *url.URL: Resolved URL
 //   - error: Error if parsing or resolution fails
-func Resolve(ctx context.Context, resolver Resolver, targetStr string) (*url.URL, error) {
+func (r *Registry) Resolve(ctx context.Context, resolver Resolver, targetStr string) (*url.URL, error) {
 	// Parse the target string into a URL
 	target, err := url.Parse(targetStr)
 	if err != nil {
@@ -60,10 +99,31 @@ func Resolve(ctx context.Context, resolver Resolver, targetStr string) (*url.URL
 	}
 
 	// Look up a registered resolver by the target's scheme
-	if resolver, ok := registered.Load(target.Scheme); ok {
-		return resolver.(Resolver).Resolve(ctx, target)
+	if resolver, ok := r.Lookup(target.Scheme); ok {
+		return resolver.Resolve(ctx, target)
 	}
 
 	// No resolver found for the scheme, return an error
 	return nil, &ResolverError{target: target}
 }
+
+// RegisterResolver registers a resolver for its scheme in the DefaultRegistry
+// This allows the resolver to be automatically used when resolving URLs with matching schemes
+// Parameters:
+//   - resolver: Resolver to register
+func RegisterResolver(resolver Resolver) {
+	DefaultRegistry.Register(resolver)
+}
+
+// Resolve resolves a target URL string using the provided resolver or the DefaultRegistry, see Registry.Resolve
+// Parameters:
+//   - ctx: Context for the resolution operation
+//   - resolver: Optional resolver to try first (can be nil)
+//   - targetStr: Target URL string to resolve
+//
+// Returns:
+//   - *url.URL: Resolved URL
+//   - error: Error if parsing or resolution fails
+func Resolve(ctx context.Context, resolver Resolver, targetStr string) (*url.URL, error) {
+	return DefaultRegistry.Resolve(ctx, resolver, targetStr)
+}
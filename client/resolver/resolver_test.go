@@ -0,0 +1,104 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+)
+
+// hostResolver resolves the targets of its scheme to a fixed http host
+type hostResolver struct {
+	scheme string
+	host   string
+}
+
+func (r hostResolver) Resolve(ctx context.Context, target *url.URL) (*url.URL, error) {
+	return &url.URL{Scheme: "http", Host: r.host, Path: target.Path}, nil
+}
+
+func (r hostResolver) Scheme() string {
+	return r.scheme
+}
+
+func TestRegistryResolve(t *testing.T) {
+	tests := []struct {
+		name      string
+		resolvers []Resolver // Resolvers given to NewRegistry
+		resolver  Resolver   // Resolver given to Resolve
+		target    string
+		want      string
+		wantErr   bool
+	}{
+		{name: "empty scheme", target: "//users:8080/v1/user", want: "http://users:8080/v1/user"},
+		{name: "http", target: "http://users/v1/user", want: "http://users/v1/user"},
+		{name: "https", target: "https://users/v1/user", want: "https://users/v1/user"},
+		{name: "unknown scheme", target: "lb://users/v1/user", wantErr: true},
+		{
+			name:      "custom scheme",
+			resolvers: []Resolver{hostResolver{scheme: "lb", host: "10.0.0.1:80"}},
+			target:    "lb://users/v1/user",
+			want:      "http://10.0.0.1:80/v1/user",
+		},
+		{
+			name:      "custom resolver replaces a built-in",
+			resolvers: []Resolver{hostResolver{scheme: "http", host: "10.0.0.1:80"}},
+			target:    "http://users/v1/user",
+			want:      "http://10.0.0.1:80/v1/user",
+		},
+		{
+			name:     "resolver of the call first",
+			resolver: hostResolver{scheme: "lb", host: "10.0.0.2:80"},
+			target:   "lb://users/v1/user",
+			want:     "http://10.0.0.2:80/v1/user",
+		},
+		{
+			name:     "resolver of the call for another scheme",
+			resolver: hostResolver{scheme: "lb", host: "10.0.0.2:80"},
+			target:   "http://users/v1/user",
+			want:     "http://users/v1/user",
+		},
+		{name: "invalid target", target: "http://users/%zz", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewRegistry(tt.resolvers...).Resolve(context.Background(), tt.resolver, tt.target)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Resolve() = %v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("Resolve() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistryUnknownSchemeError(t *testing.T) {
+	_, err := NewRegistry().Resolve(context.Background(), nil, "lb://users/v1/user")
+	var resolverErr *ResolverError
+	if !errors.As(err, &resolverErr) || resolverErr.Target().Scheme != "lb" {
+		t.Fatalf("err = %v, want a *ResolverError of the lb scheme", err)
+	}
+}
+
+func TestRegistryIsolation(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(hostResolver{scheme: "isolated", host: "10.0.0.1:80"})
+	if _, ok := DefaultRegistry.Lookup("isolated"); ok {
+		t.Error("a resolver registered in a registry must not leak to the DefaultRegistry")
+	}
+	if _, ok := NewRegistry().Lookup("isolated"); ok {
+		t.Error("a resolver registered in a registry must not leak to other registries")
+	}
+	for _, scheme := range []string{"", "http", "https"} {
+		if _, ok := DefaultRegistry.Lookup(scheme); !ok {
+			t.Errorf("DefaultRegistry has no resolver for %q", scheme)
+		}
+	}
+}
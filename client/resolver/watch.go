@@ -0,0 +1,23 @@
+// Package resolver provides URL resolution functionality for the goose client
+package resolver
+
+import (
+	"context"
+	"net/url"
+)
+
+// WatchResolver is a MultiResolver pushing the addresses of a target when they change,
+// such as the fileresolver.Resolver of the github.com/go-leo/goose/client/resolver/fileresolver module
+type WatchResolver interface {
+	MultiResolver
+
+	// Watch watches the addresses of a target
+	// Parameters:
+	//   - ctx: Context bounding the watch, the channel is closed when it is done
+	//   - target: Target URL to watch
+	// Returns:
+	//   - <-chan []string: Channel receiving the current addresses, then the addresses after each change;
+	//     a slow receiver only gets the latest addresses
+	//   - error: Error if the watch cannot start
+	Watch(ctx context.Context, target *url.URL) (<-chan []string, error)
+}
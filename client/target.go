@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+	"net/url"
+)
+
+// TargetResolver resolves the target of a client to the URL of an instance,
+// with the resolver and the resolver registry of the client.
+//
+// Parameters:
+//   - ctx: The context of the request
+//
+// Returns:
+//   - *url.URL: The resolved URL
+//   - error: If the target cannot be resolved
+type TargetResolver func(ctx context.Context) (*url.URL, error)
+
+// targetResolverKey is the context key of the TargetResolver
+type targetResolverKey struct{}
+
+// WithTargetResolver returns a context carrying the target resolver of a client.
+// The generated clients put it in the context of their requests, so that middlewares,
+// such as hedge, can send a request to another instance of the target.
+//
+// Parameters:
+//   - ctx: The parent context
+//   - resolve: The target resolver of the client
+//
+// Returns:
+//   - context.Context: The context with the target resolver
+func WithTargetResolver(ctx context.Context, resolve TargetResolver) context.Context {
+	return context.WithValue(ctx, targetResolverKey{}, resolve)
+}
+
+// TargetResolverFromContext retrieves the target resolver of the client sending a request
+//
+// Parameters:
+//   - ctx: The context of the request
+//
+// Returns:
+//   - TargetResolver: The target resolver
+//   - bool: True if the context carries a target resolver
+func TargetResolverFromContext(ctx context.Context) (TargetResolver, bool) {
+	resolve, ok := ctx.Value(targetResolverKey{}).(TargetResolver)
+	return resolve, ok && resolve != nil
+}
@@ -0,0 +1,23 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"testing"
+)
+
+func TestTargetResolverFromContext(t *testing.T) {
+	if _, ok := TargetResolverFromContext(context.Background()); ok {
+		t.Fatal("a context without target resolver must not carry one")
+	}
+	resolve := func(ctx context.Context) (*url.URL, error) {
+		return url.Parse("http://10.0.0.1:80")
+	}
+	got, ok := TargetResolverFromContext(WithTargetResolver(context.Background(), resolve))
+	if !ok {
+		t.Fatal("the target resolver is missing")
+	}
+	if target, err := got(context.Background()); err != nil || target.Host != "10.0.0.1:80" {
+		t.Errorf("resolved %v, %v, want 10.0.0.1:80", target, err)
+	}
+}
@@ -12,11 +12,22 @@ func (f *Generator) GenerateNewClient(service *parser.Service, g *protogen.Gener
 	g.P("func ", service.NewClientName(), "(target string, opts ...", constant.ClientOptionIdent, ") ", service.ServiceName(), " {")
 	g.P("options := ", constant.ClientNewOptionsIdent, "(opts...)")
 	g.P("client :=  &", service.Unexported(service.ClientName()), "{")
+	f.PrintClientFields(service, g)
+	g.P("}")
+	g.P("return client")
+	g.P("}")
+	g.P()
+	return nil
+}
+
+func (f *Generator) PrintClientFields(service *parser.Service, g *protogen.GeneratedFile) {
 	g.P("client: options.Client(),")
 	g.P("encoder: ", service.Unexported(service.RequestEncoderName()), "{")
 	g.P("target: target,")
 	g.P("marshalOptions: options.MarshalOptions(),")
 	g.P("resolver: options.Resolver(),")
+	g.P("registry: options.ResolverRegistry(),")
+	g.P("pathPrefix: options.PathPrefix(),")
 	g.P("},")
 	g.P("decoder: ", service.Unexported(service.ResponseDecoderName()), "{")
 	g.P("unmarshalOptions: options.UnmarshalOptions(),")
@@ -26,11 +37,6 @@ func (f *Generator) GenerateNewClient(service *parser.Service, g *protogen.Gener
 	g.P("shouldFailFast: options.ShouldFailFast(),")
 	g.P("onValidationErrCallback: options.OnValidationErrCallback(),")
 	g.P("middleware: ", constant.ClientChainIdent, "(options.Middlewares()...),")
-	g.P("}")
-	g.P("return client")
-	g.P("}")
-	g.P()
-	return nil
 }
 
 func (f *Generator) GenerateClient(service *parser.Service, g *protogen.GeneratedFile) error {
@@ -45,9 +51,12 @@ func (f *Generator) GenerateClient(service *parser.Service, g *protogen.Generate
 	g.P()
 	for _, endpoint := range service.Endpoints {
 		g.P("func (c *", service.Unexported(service.ClientName()), ") ", endpoint.Name(), "(ctx ", constant.ContextIdent, ", req *", endpoint.InputGoIdent(), ") (*", endpoint.OutputGoIdent(), ", error){")
-		g.P("if err := ", constant.ValidateRequestIdent, "(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {")
-		g.P("return nil, err")
-		g.P("}")
+		g.P("ctx = ", constant.WithEndpointIdent, "(ctx, ", service.EndpointName(endpoint), ")")
+		if !endpoint.IsStreamingRequest() {
+			g.P("if err := ", constant.ValidateRequestIdent, "(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {")
+			g.P("return nil, err")
+			g.P("}")
+		}
 		g.P("request, err := c.encoder.", endpoint.Name(), "(ctx, req)")
 		g.P("if err != nil {")
 		g.P("return nil, err")
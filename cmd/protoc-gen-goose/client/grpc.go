@@ -0,0 +1,56 @@
+package client
+
+import (
+	"github.com/go-leo/goose/cmd/protoc-gen-goose/constant"
+	"github.com/go-leo/goose/cmd/protoc-gen-goose/parser"
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+func (f *Generator) GenerateGrpcClient(service *parser.Service, g *protogen.GeneratedFile) error {
+	g.P("type ", service.GrpcClientName(), " interface {")
+	for _, endpoint := range service.Endpoints {
+		g.P(endpoint.Name(), "(ctx ", constant.ContextIdent, ", req *", endpoint.InputGoIdent(), ", opts ...", constant.GrpcCallOptionIdent, ") (*", endpoint.OutputGoIdent(), ", error)")
+	}
+	g.P("}")
+	g.P()
+
+	g.P("func ", service.NewGrpcClientName(), "(target string, opts ...", constant.ClientOptionIdent, ") ", service.GrpcClientName(), " {")
+	g.P("options := ", constant.ClientNewOptionsIdent, "(opts...)")
+	g.P("client :=  &", service.Unexported(service.GrpcClientName()), "{")
+	g.P(service.Unexported(service.ClientName()), ": &", service.Unexported(service.ClientName()), "{")
+	f.PrintClientFields(service, g)
+	g.P("},")
+	g.P("}")
+	g.P("return client")
+	g.P("}")
+	g.P()
+
+	g.P("type ", service.Unexported(service.GrpcClientName()), " struct {")
+	g.P("*", service.Unexported(service.ClientName()))
+	g.P("}")
+	g.P()
+	for _, endpoint := range service.Endpoints {
+		g.P("func (c *", service.Unexported(service.GrpcClientName()), ") ", endpoint.Name(), "(ctx ", constant.ContextIdent, ", req *", endpoint.InputGoIdent(), ", opts ...", constant.GrpcCallOptionIdent, ") (resp *", endpoint.OutputGoIdent(), ", err error){")
+		g.P("ctx = ", constant.WithEndpointIdent, "(ctx, ", service.EndpointName(endpoint), ")")
+		g.P("var response *", constant.ResponseIdent)
+		g.P("defer func() {")
+		g.P(constant.AfterCallIdent, "(response, err, opts...)")
+		g.P("}()")
+		g.P("if err := ", constant.ValidateRequestIdent, "(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {")
+		g.P("return nil, err")
+		g.P("}")
+		g.P("request, err := c.encoder.", endpoint.Name(), "(ctx, req)")
+		g.P("if err != nil {")
+		g.P("return nil, err")
+		g.P("}")
+		g.P(constant.AppendOutgoingHeaderIdent, "(ctx, request.Header)")
+		g.P("response, err = ", constant.ClientInvokeIdent, "(c.middleware, c.client, request)")
+		g.P("if err != nil {")
+		g.P("return nil, err")
+		g.P("}")
+		g.P("return c.decoder.", endpoint.Name(), "(ctx, response)")
+		g.P("}")
+		g.P()
+	}
+	return nil
+}
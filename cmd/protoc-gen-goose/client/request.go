@@ -3,6 +3,7 @@ package client
 import (
 	"strconv"
 
+	gooseannotations "github.com/go-leo/goose/annotations"
 	"github.com/go-leo/goose/cmd/protoc-gen-goose/constant"
 	"github.com/go-leo/goose/cmd/protoc-gen-goose/parser"
 	"google.golang.org/protobuf/compiler/protogen"
@@ -14,18 +15,40 @@ func (f *Generator) GenerateRequestEncoder(service *parser.Service, g *protogen.
 	g.P("target string")
 	g.P("marshalOptions ", constant.ProtoJsonMarshalOptionsIdent)
 	g.P("resolver ", constant.ResolverIdent)
+	g.P("registry *", constant.RegistryIdent)
+	g.P("pathPrefix string")
 	g.P("}")
+	g.P()
+	g.P("func (encoder *", service.Unexported(service.RequestEncoderName()), ") resolve(ctx ", constant.ContextIdent, ") (*", constant.URLIndent, ", error) {")
+	g.P("return encoder.registry.Resolve(ctx, encoder.resolver, encoder.target)")
+	g.P("}")
+	g.P()
 	for _, endpoint := range service.Endpoints {
 		g.P("func (encoder *", service.Unexported(service.RequestEncoderName()), ") ", endpoint.Name(), "(ctx ", constant.ContextIdent, ", req *", endpoint.InputGoIdent(), ") (*", constant.RequestIdent, ", error){")
 		g.P("if req == nil {")
 		g.P("return nil, ", constant.NewErrorIdent, "(", strconv.Quote("request is nil"), ")")
 		g.P("}")
-		g.P("target, err := ", constant.ResolveIdent, "(ctx, encoder.resolver, encoder.target)")
+		g.P("ctx = ", constant.WithTargetResolverIdent, "(ctx, encoder.resolve)")
+		g.P("target, err := encoder.resolve(ctx)")
 		g.P("if err != nil {")
 		g.P("return nil, err")
 		g.P("}")
 		g.P("method := ", strconv.Quote(endpoint.Method()))
 		g.P("header := ", constant.Header, "{}")
+		if endpoint.IsStreamingRequest() {
+			g.P("body := ", constant.EncodeHttpBodyStreamToRequestIdent, "(ctx, req, header)")
+			g.P("path := ", strconv.Quote(endpoint.Path()))
+			f.PrintJoinPath(g)
+			g.P("request, err := ", constant.NewRequestWithContextIndent, "(ctx, method, target.String(), body)")
+			g.P("if err != nil {")
+			g.P("return nil, err")
+			g.P("}")
+			g.P(constant.CopyHeaderIdent, "(request.Header, header)")
+			g.P("return request, nil")
+			g.P("}")
+			g.P()
+			continue
+		}
 		g.P("var body ", constant.Buffer)
 		bodyMessage, bodyField, pathFields, queryFields, err := endpoint.ParseParameters()
 		if err != nil {
@@ -41,7 +64,13 @@ func (f *Generator) GenerateRequestEncoder(service *parser.Service, g *protogen.
 			case "google.rpc.HttpRequest":
 				f.PrintEncodeHttpRequestToRequest(g, srcValue)
 			default:
-				f.PrintEncodeMessageToRequest(g, srcValue)
+				if endpoint.IsFormBody() {
+					if err := f.PrintEncodeFormToRequest(g, endpoint, srcValue); err != nil {
+						return err
+					}
+				} else {
+					f.PrintEncodeMessageToRequest(g, srcValue)
+				}
 			}
 		} else if bodyField != nil {
 			switch bodyField.Desc.Kind() {
@@ -51,14 +80,20 @@ func (f *Generator) GenerateRequestEncoder(service *parser.Service, g *protogen.
 				case "google.api.HttpBody":
 					f.PrintEncodeHttpBodyToRequest(g, srcValue)
 				default:
-					f.PrintEncodeMessageToRequest(g, srcValue)
+					if endpoint.IsFormBody() {
+						if err := f.PrintEncodeFormToRequest(g, endpoint, srcValue); err != nil {
+							return err
+						}
+					} else {
+						f.PrintEncodeMessageToRequest(g, srcValue)
+					}
 				}
 			}
 		}
 
 		g.P("path := ", strconv.Quote(endpoint.Path()))
 		f.PrintPathField(g, pathFields)
-		g.P("target.Path = path")
+		f.PrintJoinPath(g)
 
 		f.PrintQueryField(g, queryFields)
 
@@ -93,6 +128,50 @@ func (f *Generator) PrintEncodeMessageToRequest(g *protogen.GeneratedFile, srcVa
 	g.P("}")
 }
 
+// PrintEncodeFormToRequest prints the encoding of the body as a URL-encoded or multipart form,
+// in multipart forms bytes and google.api.HttpBody fields are sent as files.
+func (f *Generator) PrintEncodeFormToRequest(g *protogen.GeneratedFile, endpoint *parser.Endpoint, srcValue []any) error {
+	formFields, err := endpoint.FormFields()
+	if err != nil {
+		return err
+	}
+	multipart := endpoint.BodyEncoding() == gooseannotations.BodyEncoding_BODY_ENCODING_MULTIPART
+	var valueFields, fileFields []*protogen.Field
+	for _, field := range formFields {
+		isFile := field.Desc.Kind() == protoreflect.BytesKind ||
+			(field.Desc.Kind() == protoreflect.MessageKind && field.Message.Desc.FullName() == "google.api.HttpBody")
+		if multipart && isFile {
+			fileFields = append(fileFields, field)
+		} else {
+			valueFields = append(valueFields, field)
+		}
+	}
+	g.P("form := ", constant.URLValuesIndent, "{}")
+	f.PrintFormField(g, valueFields, srcValue, "form")
+	if !multipart {
+		g.P("if err := ", constant.EncodeFormIdent, "(ctx, form, header, &body); err != nil {")
+		g.P("return nil, err")
+		g.P("}")
+		return nil
+	}
+	g.P("files := ", constant.FormFilesIdent, "{}")
+	for _, field := range fileFields {
+		adder := "AddBytes"
+		if field.Desc.Kind() == protoreflect.MessageKind {
+			adder = "AddHttpBody"
+		}
+		value := append(append([]any{}, srcValue...), ".Get", field.GoName, "()")
+		if field.Desc.IsList() {
+			value = append(value, "...")
+		}
+		g.P(append(append([]any{"files.", adder, "(", strconv.Quote(string(field.Desc.Name())), ", "}, value...), ")")...)
+	}
+	g.P("if err := ", constant.EncodeMultipartFormIdent, "(ctx, form, files, header, &body); err != nil {")
+	g.P("return nil, err")
+	g.P("}")
+	return nil
+}
+
 func (f *Generator) PrintPathField(g *protogen.GeneratedFile, pathFields []*protogen.Field) {
 	if len(pathFields) <= 0 {
 		return
@@ -103,10 +182,16 @@ func (f *Generator) PrintPathField(g *protogen.GeneratedFile, pathFields []*prot
 	}
 	g.P("}")
 	g.P("path = ", constant.URLPathIdent, "(path, pairs)")
-	g.P("path, err = ", constant.JoinPathIndent, "(target.Path, path)")
+}
+
+// PrintJoinPath prints the join of the path of the target, the path prefix and the path of the endpoint,
+// applied to every endpoint, with or without path fields.
+func (f *Generator) PrintJoinPath(g *protogen.GeneratedFile) {
+	g.P("path, err = ", constant.JoinPathIndent, "(target.Path, encoder.pathPrefix, path)")
 	g.P("if err != nil {")
 	g.P("return nil, err")
 	g.P("}")
+	g.P("target.Path = path")
 }
 
 func (f *Generator) PathFieldFormat(field *protogen.Field) []any {
@@ -158,123 +243,141 @@ func (f *Generator) PrintQueryField(g *protogen.GeneratedFile, queryFields []*pr
 		return
 	}
 	g.P("queries := ", constant.URLValuesIndent, "{}")
-	for _, field := range queryFields {
-		srcValue := []any{"req.Get", field.GoName, "()"}
+	f.PrintFormField(g, queryFields, []any{"req"}, "queries")
+	g.P("target.RawQuery = queries.Encode()")
+}
+
+// PrintFormField prints the formatting of the fields of srcPrefix into the url.Values named form,
+// bytes fields are formatted as values and google.api.HttpBody fields are skipped.
+func (f *Generator) PrintFormField(g *protogen.GeneratedFile, fields []*protogen.Field, srcPrefix []any, form string) {
+	for _, field := range fields {
+		srcValue := append(append([]any{}, srcPrefix...), ".Get", field.GoName, "()")
 		fieldName := string(field.Desc.Name())
 		switch field.Desc.Kind() {
 		case protoreflect.BoolKind: // bool
 			if field.Desc.IsList() {
-				f.PrintQuery(g, fieldName, append(f.BoolSliceFormat(srcValue), []any{"..."}...))
+				f.PrintFormValue(g, form, fieldName, append(f.BoolSliceFormat(srcValue), []any{"..."}...))
 			} else {
-				f.PrintQuery(g, fieldName, f.BoolValueFormat(srcValue))
+				f.PrintFormValue(g, form, fieldName, f.BoolValueFormat(srcValue))
 			}
 		case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind: // int32
 			if field.Desc.IsList() {
-				f.PrintQuery(g, fieldName, append(f.IntSliceFormat(srcValue), []any{"..."}...))
+				f.PrintFormValue(g, form, fieldName, append(f.IntSliceFormat(srcValue), []any{"..."}...))
 			} else {
-				f.PrintQuery(g, fieldName, f.IntValueFormat(srcValue))
+				f.PrintFormValue(g, form, fieldName, f.IntValueFormat(srcValue))
 			}
 		case protoreflect.Uint32Kind, protoreflect.Fixed32Kind: // uint32
 			if field.Desc.IsList() {
-				f.PrintQuery(g, fieldName, append(f.UintSliceFormat(srcValue), []any{"..."}...))
+				f.PrintFormValue(g, form, fieldName, append(f.UintSliceFormat(srcValue), []any{"..."}...))
 			} else {
-				f.PrintQuery(g, fieldName, f.UintValueFormat(srcValue))
+				f.PrintFormValue(g, form, fieldName, f.UintValueFormat(srcValue))
 			}
 		case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind: // int64
 			if field.Desc.IsList() {
-				f.PrintQuery(g, fieldName, append(f.IntSliceFormat(srcValue), []any{"..."}...))
+				f.PrintFormValue(g, form, fieldName, append(f.IntSliceFormat(srcValue), []any{"..."}...))
 			} else {
-				f.PrintQuery(g, fieldName, f.IntValueFormat(srcValue))
+				f.PrintFormValue(g, form, fieldName, f.IntValueFormat(srcValue))
 			}
 		case protoreflect.Uint64Kind, protoreflect.Fixed64Kind: // uint64
 			if field.Desc.IsList() {
-				f.PrintQuery(g, fieldName, append(f.UintSliceFormat(srcValue), []any{"..."}...))
+				f.PrintFormValue(g, form, fieldName, append(f.UintSliceFormat(srcValue), []any{"..."}...))
 			} else {
-				f.PrintQuery(g, fieldName, f.UintValueFormat(srcValue))
+				f.PrintFormValue(g, form, fieldName, f.UintValueFormat(srcValue))
 			}
 		case protoreflect.FloatKind: // float32
 			if field.Desc.IsList() {
-				f.PrintQuery(g, fieldName, append(f.FloatSliceFormat(srcValue, "32"), []any{"..."}...))
+				f.PrintFormValue(g, form, fieldName, append(f.FloatSliceFormat(srcValue, "32"), []any{"..."}...))
 			} else {
-				f.PrintQuery(g, fieldName, f.FloatValueFormat(srcValue, "32"))
+				f.PrintFormValue(g, form, fieldName, f.FloatValueFormat(srcValue, "32"))
 			}
 		case protoreflect.DoubleKind: // float64
 			if field.Desc.IsList() {
-				f.PrintQuery(g, fieldName, append(f.FloatSliceFormat(srcValue, "64"), []any{"..."}...))
+				f.PrintFormValue(g, form, fieldName, append(f.FloatSliceFormat(srcValue, "64"), []any{"..."}...))
 			} else {
-				f.PrintQuery(g, fieldName, f.FloatValueFormat(srcValue, "64"))
+				f.PrintFormValue(g, form, fieldName, f.FloatValueFormat(srcValue, "64"))
 			}
 		case protoreflect.StringKind: // string
 			if field.Desc.IsList() {
-				f.PrintQuery(g, fieldName, append(f.StringKindFormat(srcValue), []any{"..."}...))
+				f.PrintFormValue(g, form, fieldName, append(f.StringKindFormat(srcValue), []any{"..."}...))
 			} else {
-				f.PrintQuery(g, fieldName, f.StringKindFormat(srcValue))
+				f.PrintFormValue(g, form, fieldName, f.StringKindFormat(srcValue))
 			}
 		case protoreflect.EnumKind: // enum int32
 			if field.Desc.IsList() {
-				f.PrintQuery(g, fieldName, append(f.IntSliceFormat(srcValue), []any{"..."}...))
+				f.PrintFormValue(g, form, fieldName, append(f.IntSliceFormat(srcValue), []any{"..."}...))
+			} else {
+				f.PrintFormValue(g, form, fieldName, f.IntValueFormat(srcValue))
+			}
+		case protoreflect.BytesKind: // bytes
+			if field.Desc.IsList() {
+				g.P(append(append([]any{"for _, value := range "}, srcValue...), " {")...)
+				f.PrintFormValue(g, form, fieldName, []any{"string(value)"})
+				g.P("}")
 			} else {
-				f.PrintQuery(g, fieldName, f.IntValueFormat(srcValue))
+				f.PrintFormValue(g, form, fieldName, append(append([]any{"string("}, srcValue...), ")"))
 			}
 		case protoreflect.MessageKind:
 			switch field.Message.Desc.FullName() {
 			case "google.protobuf.BoolValue":
 				if field.Desc.IsList() {
-					f.PrintQuery(g, fieldName, append(f.UnwrapBoolSliceFormat(srcValue), []any{"..."}...))
+					f.PrintFormValue(g, form, fieldName, append(f.UnwrapBoolSliceFormat(srcValue), []any{"..."}...))
 				} else {
-					f.PrintQuery(g, fieldName, f.UnwrapBoolValueFormat(srcValue))
+					f.PrintFormValue(g, form, fieldName, f.UnwrapBoolValueFormat(srcValue))
 				}
 			case "google.protobuf.Int32Value":
 				if field.Desc.IsList() {
-					f.PrintQuery(g, fieldName, append(f.UnwrapIntSliceFormat(srcValue, constant.UnwrapInt32SliceIdent, "32"), []any{"..."}...))
+					f.PrintFormValue(g, form, fieldName, append(f.UnwrapIntSliceFormat(srcValue, constant.UnwrapInt32SliceIdent, "32"), []any{"..."}...))
 				} else {
-					f.PrintQuery(g, fieldName, f.UnwrapIntValueFormat(srcValue))
+					f.PrintFormValue(g, form, fieldName, f.UnwrapIntValueFormat(srcValue))
 				}
 			case "google.protobuf.UInt32Value":
 				if field.Desc.IsList() {
-					f.PrintQuery(g, fieldName, append(f.UnwrapUintSliceFormat(srcValue, constant.UnwrapUint32SliceIdent, "32"), []any{"..."}...))
+					f.PrintFormValue(g, form, fieldName, append(f.UnwrapUintSliceFormat(srcValue, constant.UnwrapUint32SliceIdent, "32"), []any{"..."}...))
 				} else {
-					f.PrintQuery(g, fieldName, f.UnwrapUintValueFormat(srcValue))
+					f.PrintFormValue(g, form, fieldName, f.UnwrapUintValueFormat(srcValue))
 				}
 			case "google.protobuf.Int64Value":
 				if field.Desc.IsList() {
-					f.PrintQuery(g, fieldName, append(f.UnwrapIntSliceFormat(srcValue, constant.UnwrapInt64SliceIdent, "64"), []any{"..."}...))
+					f.PrintFormValue(g, form, fieldName, append(f.UnwrapIntSliceFormat(srcValue, constant.UnwrapInt64SliceIdent, "64"), []any{"..."}...))
 				} else {
-					f.PrintQuery(g, fieldName, f.UnwrapIntValueFormat(srcValue))
+					f.PrintFormValue(g, form, fieldName, f.UnwrapIntValueFormat(srcValue))
 				}
 			case "google.protobuf.UInt64Value":
 				if field.Desc.IsList() {
-					f.PrintQuery(g, fieldName, append(f.UnwrapUintSliceFormat(srcValue, constant.UnwrapUint64SliceIdent, "64"), []any{"..."}...))
+					f.PrintFormValue(g, form, fieldName, append(f.UnwrapUintSliceFormat(srcValue, constant.UnwrapUint64SliceIdent, "64"), []any{"..."}...))
 				} else {
-					f.PrintQuery(g, fieldName, f.UnwrapUintValueFormat(srcValue))
+					f.PrintFormValue(g, form, fieldName, f.UnwrapUintValueFormat(srcValue))
 				}
 
 			case "google.protobuf.FloatValue":
 				if field.Desc.IsList() {
-					f.PrintQuery(g, fieldName, append(f.UnwrapFloatSliceFormat(srcValue, constant.UnwrapFloat32SliceIdent, "32"), []any{"..."}...))
+					f.PrintFormValue(g, form, fieldName, append(f.UnwrapFloatSliceFormat(srcValue, constant.UnwrapFloat32SliceIdent, "32"), []any{"..."}...))
 				} else {
-					f.PrintQuery(g, fieldName, f.UnwrapFloatValueFormat(srcValue, "32"))
+					f.PrintFormValue(g, form, fieldName, f.UnwrapFloatValueFormat(srcValue, "32"))
 				}
 			case "google.protobuf.DoubleValue":
 				if field.Desc.IsList() {
-					f.PrintQuery(g, fieldName, append(f.UnwrapFloatSliceFormat(srcValue, constant.UnwrapFloat64SliceIdent, "64"), []any{"..."}...))
+					f.PrintFormValue(g, form, fieldName, append(f.UnwrapFloatSliceFormat(srcValue, constant.UnwrapFloat64SliceIdent, "64"), []any{"..."}...))
 				} else {
-					f.PrintQuery(g, fieldName, f.UnwrapFloatValueFormat(srcValue, "64"))
+					f.PrintFormValue(g, form, fieldName, f.UnwrapFloatValueFormat(srcValue, "64"))
 				}
 			case "google.protobuf.StringValue":
 				if field.Desc.IsList() {
-					f.PrintQuery(g, fieldName, append(f.UnwrapStringSliceFormat(srcValue), []any{"..."}...))
+					f.PrintFormValue(g, form, fieldName, append(f.UnwrapStringSliceFormat(srcValue), []any{"..."}...))
 				} else {
-					f.PrintQuery(g, fieldName, f.UnwrapStringValueFormat(srcValue))
+					f.PrintFormValue(g, form, fieldName, f.UnwrapStringValueFormat(srcValue))
 				}
 			}
 		}
 	}
-	g.P("target.RawQuery = queries.Encode()")
 }
 
 func (f *Generator) PrintQuery(g *protogen.GeneratedFile, fieldName string, srcValue []any) {
-	g.P(append(append([]any{"queries[", strconv.Quote(fieldName), "] = append(queries[", strconv.Quote(fieldName), "], "}, srcValue...), []any{")"}...)...)
+	f.PrintFormValue(g, "queries", fieldName, srcValue)
+}
+
+func (f *Generator) PrintFormValue(g *protogen.GeneratedFile, form string, fieldName string, srcValue []any) {
+	g.P(append(append([]any{form, "[", strconv.Quote(fieldName), "] = append(", form, "[", strconv.Quote(fieldName), "], "}, srcValue...), []any{")"}...)...)
 }
 
 func (f *Generator) BoolValueFormat(srcValue []any) []any {
@@ -20,7 +20,17 @@ func (f *Generator) GenerateResponseDecoder(service *parser.Service, g *protogen
 		g.P("if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {")
 		g.P("return nil, respErr")
 		g.P("}")
-		g.P("resp := &", endpoint.Output().GoIdent, "{}")
+		if endpoint.Output().Desc.FullName() != "google.rpc.HttpResponse" {
+			f.PrintCheckStatus(g, endpoint.Status())
+		}
+		g.P("resp := &", endpoint.OutputGoIdent(), "{}")
+		if endpoint.IsStreamingResponse() {
+			f.DecodeHttpBodyStream(g, []any{"resp"})
+			g.P("return resp, nil")
+			g.P("}")
+			g.P()
+			continue
+		}
 		bodyParameter := endpoint.ResponseBody()
 		switch bodyParameter {
 		case "", "*":
@@ -63,6 +73,16 @@ func (f *Generator) GenerateResponseDecoder(service *parser.Service, g *protogen
 	return nil
 }
 
+func (f *Generator) PrintCheckStatus(g *protogen.GeneratedFile, status int) {
+	if status != 0 {
+		g.P("if err := ", constant.CheckStatusIdent, "(response, ", status, "); err != nil {")
+	} else {
+		g.P("if err := ", constant.CheckStatusIdent, "(response); err != nil {")
+	}
+	g.P("return nil, err")
+	g.P("}")
+}
+
 func (f *Generator) PrintDecodeMessage(g *protogen.GeneratedFile, srcValue []any) {
 	g.P(append(append([]any{"if err := ", constant.DecodeMessageIdent, "(ctx, response, "}, srcValue...), ", decoder.unmarshalOptions); err != nil {")...)
 	g.P("return nil, err")
@@ -75,6 +95,12 @@ func (f *Generator) DecodeHttpBody(g *protogen.GeneratedFile, srcValue []any) {
 	g.P("}")
 }
 
+func (f *Generator) DecodeHttpBodyStream(g *protogen.GeneratedFile, srcValue []any) {
+	g.P(append(append([]any{"if err := ", constant.DecodeHttpBodyStreamFromResponseIdent, "(ctx, response, "}, srcValue...), "); err != nil {")...)
+	g.P("return nil, err")
+	g.P("}")
+}
+
 func (f *Generator) DecodeHttpResponse(g *protogen.GeneratedFile, srcValue []any) {
 	g.P(append(append([]any{"if err := ", constant.DecodeHttpResponseIdent, "(ctx, response, "}, srcValue...), "); err != nil {")...)
 	g.P("return nil, err")
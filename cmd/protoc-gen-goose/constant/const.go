@@ -40,7 +40,6 @@ var (
 
 var (
 	HttpPackage                 = protogen.GoImportPath("net/http")
-	RouterIdent                 = HttpPackage.Ident("ServeMux")
 	ClientIdent                 = HttpPackage.Ident("Client")
 	HttpHandlerIdent            = HttpPackage.Ident("Handler")
 	HttpHandlerFuncIdent        = HttpPackage.Ident("HandlerFunc")
@@ -65,6 +64,10 @@ var (
 var (
 	GoosePackage = protogen.GoImportPath("github.com/go-leo/goose")
 
+	HttpBodyStreamIdent  = GoosePackage.Ident("HttpBodyStream")
+	RouterIdent          = GoosePackage.Ident("Router")
+	EndpointIdent        = GoosePackage.Ident("Endpoint")
+	WithEndpointIdent    = GoosePackage.Ident("WithEndpoint")
 	ValidateRequestIdent = GoosePackage.Ident("ValidateRequest")
 	OnErrCallbackIdent   = GoosePackage.Ident("OnValidationErrCallback")
 
@@ -150,15 +153,31 @@ func GetEnumSliceIdent(g *protogen.GeneratedFile, ident protogen.GoIdent) protog
 }
 
 var (
-	GooseServerPackage       = protogen.GoImportPath("github.com/go-leo/goose/server")
-	EncodeResponseIdent      = GooseServerPackage.Ident("EncodeResponse")
-	EncodeHttpBodyIdent      = GooseServerPackage.Ident("EncodeHttpBody")
-	EncodeHttpResponseIdent  = GooseServerPackage.Ident("EncodeHttpResponse")
+	GooseServerPackage      = protogen.GoImportPath("github.com/go-leo/goose/server")
+	EncodeResponseIdent     = GooseServerPackage.Ident("EncodeResponse")
+	EncodeHttpBodyIdent     = GooseServerPackage.Ident("EncodeHttpBody")
+	EncodeHttpResponseIdent = GooseServerPackage.Ident("EncodeHttpResponse")
+
+	EncodeResponseWithStatusIdent = GooseServerPackage.Ident("EncodeResponseWithStatus")
+	EncodeHttpBodyWithStatusIdent = GooseServerPackage.Ident("EncodeHttpBodyWithStatus")
+
+	EncodeHttpBodyStreamIdent           = GooseServerPackage.Ident("EncodeHttpBodyStream")
+	EncodeHttpBodyStreamWithStatusIdent = GooseServerPackage.Ident("EncodeHttpBodyStreamWithStatus")
+	DecodeHttpBodyStreamIdent           = GooseServerPackage.Ident("DecodeHttpBodyStream")
+
 	DecodeRequestIdent       = GooseServerPackage.Ident("DecodeRequest")
 	DecodeHttpBodyIdent      = GooseServerPackage.Ident("DecodeHttpBody")
 	DecodeHttpRequestIdent   = GooseServerPackage.Ident("DecodeHttpRequest")
 	CustomDecodeRequestIdent = GooseServerPackage.Ident("CustomDecodeRequest")
 
+	IsFormRequestIdent     = GooseServerPackage.Ident("IsFormRequest")
+	DecodeFormIdent        = GooseServerPackage.Ident("DecodeForm")
+	RemoveFormIdent        = GooseServerPackage.Ident("RemoveForm")
+	GetFormFileIdent       = GooseServerPackage.Ident("GetFormFile")
+	GetFormFilesIdent      = GooseServerPackage.Ident("GetFormFiles")
+	GetFormHttpBodyIdent   = GooseServerPackage.Ident("GetFormHttpBody")
+	GetFormHttpBodiesIdent = GooseServerPackage.Ident("GetFormHttpBodies")
+
 	ServerOptionIdent     = GooseServerPackage.Ident("Option")
 	ServerNewOptionsIdent = GooseServerPackage.Ident("NewOptions")
 
@@ -170,12 +189,18 @@ var (
 var (
 	GooseClientPackage = protogen.GoImportPath("github.com/go-leo/goose/client")
 
-	DecodeMessageIdent              = GooseClientPackage.Ident("DecodeMessage")
-	DecodeHttpBodyFromResponseIdent = GooseClientPackage.Ident("DecodeHttpBody")
-	DecodeHttpResponseIdent         = GooseClientPackage.Ident("DecodeHttpResponse")
-	EncodeHttpBodyToRequestIdent    = GooseClientPackage.Ident("EncodeHttpBody")
-	EncodeHttpRequestIdent          = GooseClientPackage.Ident("EncodeHttpRequest")
-	EncodeMessageIdent              = GooseClientPackage.Ident("EncodeMessage")
+	CheckStatusIdent                      = GooseClientPackage.Ident("CheckStatus")
+	DecodeHttpBodyStreamFromResponseIdent = GooseClientPackage.Ident("DecodeHttpBodyStream")
+	EncodeHttpBodyStreamToRequestIdent    = GooseClientPackage.Ident("EncodeHttpBodyStream")
+	EncodeFormIdent                       = GooseClientPackage.Ident("EncodeForm")
+	EncodeMultipartFormIdent              = GooseClientPackage.Ident("EncodeMultipartForm")
+	FormFilesIdent                        = GooseClientPackage.Ident("FormFiles")
+	DecodeMessageIdent                    = GooseClientPackage.Ident("DecodeMessage")
+	DecodeHttpBodyFromResponseIdent       = GooseClientPackage.Ident("DecodeHttpBody")
+	DecodeHttpResponseIdent               = GooseClientPackage.Ident("DecodeHttpResponse")
+	EncodeHttpBodyToRequestIdent          = GooseClientPackage.Ident("EncodeHttpBody")
+	EncodeHttpRequestIdent                = GooseClientPackage.Ident("EncodeHttpRequest")
+	EncodeMessageIdent                    = GooseClientPackage.Ident("EncodeMessage")
 
 	ClientOptionIdent     = GooseClientPackage.Ident("Option")
 	ClientNewOptionsIdent = GooseClientPackage.Ident("NewOptions")
@@ -183,13 +208,27 @@ var (
 	ClientChainIdent      = GooseClientPackage.Ident("Chain")
 	ClientMiddlewareIdent = GooseClientPackage.Ident("Middleware")
 	ClientInvokeIdent     = GooseClientPackage.Ident("Invoke")
+
+	WithTargetResolverIdent = GooseClientPackage.Ident("WithTargetResolver")
 )
 
 var (
 	GooseClientResolverPackage = protogen.GoImportPath("github.com/go-leo/goose/client/resolver")
 
 	ResolverIdent = GooseClientResolverPackage.Ident("Resolver")
-	ResolveIdent  = GooseClientResolverPackage.Ident("Resolve")
+	RegistryIdent = GooseClientResolverPackage.Ident("Registry")
+)
+
+var (
+	GooseClientGrpcxPackage = protogen.GoImportPath("github.com/go-leo/goose/client/grpcx")
+
+	AppendOutgoingHeaderIdent = GooseClientGrpcxPackage.Ident("AppendOutgoingHeader")
+	AfterCallIdent            = GooseClientGrpcxPackage.Ident("AfterCall")
+)
+
+var (
+	GrpcPackage         = protogen.GoImportPath("google.golang.org/grpc")
+	GrpcCallOptionIdent = GrpcPackage.Ident("CallOption")
 )
 
 var (
@@ -15,6 +15,10 @@ import (
 
 var flags flag.FlagSet
 
+var grpcClient = flags.Bool("grpc_client", false, "generate a client that implements the gRPC client interface")
+
+var routeConflicts = flags.String("route_conflicts", string(parser.FileScope), "detect the route conflicts between the services of a file (file) or within each service (service)")
+
 func main() {
 	if len(os.Args) == 2 && os.Args[1] == "--version" {
 		fmt.Fprintf(os.Stdout, "%v %v\n", filepath.Base(os.Args[0]), "v1.6.8")
@@ -28,6 +32,10 @@ func main() {
 }
 
 func generate(plugin *protogen.Plugin) error {
+	scope, err := parser.ParseRouteConflictScope(*routeConflicts)
+	if err != nil {
+		return err
+	}
 	for _, file := range plugin.Files {
 		if !file.Generate {
 			continue
@@ -35,7 +43,7 @@ func generate(plugin *protogen.Plugin) error {
 		if len(file.Services) <= 0 {
 			continue
 		}
-		services, err := parser.NewServices(file)
+		services, err := parser.NewServices(file, scope)
 		if err != nil {
 			return err
 		}
@@ -51,6 +59,9 @@ func generate(plugin *protogen.Plugin) error {
 			if err := srvGen.GenerateServices(service, g); err != nil {
 				return err
 			}
+			if err := srvGen.GenerateEndpoints(service, g); err != nil {
+				return err
+			}
 			if err := srvGen.GenerateAppendServerFunc(service, g); err != nil {
 				return err
 			}
@@ -77,6 +88,13 @@ func generate(plugin *protogen.Plugin) error {
 			if err := cliGen.GenerateResponseDecoder(service, g); err != nil {
 				return err
 			}
+			// gRPC clients stream with their own interfaces, so services with
+			// streaming methods have no gRPC compatible goose client.
+			if *grpcClient && !service.HasStreaming() {
+				if err := cliGen.GenerateGrpcClient(service, g); err != nil {
+					return err
+				}
+			}
 		}
 
 	}
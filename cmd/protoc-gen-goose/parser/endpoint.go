@@ -5,6 +5,9 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/go-leo/goose"
+	gooseannotations "github.com/go-leo/goose/annotations"
+	"github.com/go-leo/goose/cmd/protoc-gen-goose/constant"
 	"golang.org/x/exp/slices"
 	"google.golang.org/genproto/googleapis/api/annotations"
 	"google.golang.org/protobuf/compiler/protogen"
@@ -15,7 +18,7 @@ import (
 type Endpoint struct {
 	protoMethod *protogen.Method
 	httpRule    *annotations.HttpRule
-	pattern     *Pattern
+	pattern     *goose.RoutePattern
 }
 
 func (e *Endpoint) Name() string {
@@ -34,6 +37,41 @@ func (e *Endpoint) IsStreaming() bool {
 	return e.protoMethod.Desc.IsStreamingServer() || e.protoMethod.Desc.IsStreamingClient()
 }
 
+// IsStreamingRequest reports whether the request is declared as `stream google.api.HttpBody`.
+func (e *Endpoint) IsStreamingRequest() bool {
+	return e.protoMethod.Desc.IsStreamingClient()
+}
+
+// IsStreamingResponse reports whether the response is declared as `stream google.api.HttpBody`.
+func (e *Endpoint) IsStreamingResponse() bool {
+	return e.protoMethod.Desc.IsStreamingServer()
+}
+
+// CheckStreaming checks that a streaming method only streams google.api.HttpBody,
+// which is mapped to goose.HttpBodyStream.
+func (e *Endpoint) CheckStreaming() error {
+	if e.IsStreamingRequest() {
+		if e.Input().Desc.FullName() != "google.api.HttpBody" {
+			return fmt.Errorf("%s, stream request must be google.api.HttpBody", e.FullName())
+		}
+		if e.Body() != "*" {
+			return fmt.Errorf("%s, stream request body must be *", e.FullName())
+		}
+		if pathParameters, _ := e.PathParameters(); len(pathParameters) > 0 {
+			return fmt.Errorf("%s, stream request does not support path parameters", e.FullName())
+		}
+	}
+	if e.IsStreamingResponse() {
+		if e.Output().Desc.FullName() != "google.api.HttpBody" {
+			return fmt.Errorf("%s, stream response must be google.api.HttpBody", e.FullName())
+		}
+		if e.ResponseBody() != "" && e.ResponseBody() != "*" {
+			return fmt.Errorf("%s, stream response body must be *", e.FullName())
+		}
+	}
+	return nil
+}
+
 func (e *Endpoint) Input() *protogen.Message {
 	return e.protoMethod.Input
 }
@@ -43,10 +81,16 @@ func (e *Endpoint) Output() *protogen.Message {
 }
 
 func (e *Endpoint) InputGoIdent() protogen.GoIdent {
+	if e.IsStreamingRequest() {
+		return constant.HttpBodyStreamIdent
+	}
 	return e.Input().GoIdent
 }
 
 func (e *Endpoint) OutputGoIdent() protogen.GoIdent {
+	if e.IsStreamingResponse() {
+		return constant.HttpBodyStreamIdent
+	}
 	return e.Output().GoIdent
 }
 
@@ -156,20 +200,14 @@ func (e *Endpoint) ParseParameters() (*protogen.Message, *protogen.Field, []*pro
 }
 
 func (e *Endpoint) PathParameters() ([]string, error) {
-	values := make([]string, 0, len(e.pattern.segments))
-	for _, segment := range e.pattern.segments {
-		if segment.wild {
-			values = append(values, segment.s)
-		}
-	}
-	return values, nil
+	return e.pattern.Wildcards(), nil
 }
 
-func (e *Endpoint) SetPattern(pattern *Pattern) {
+func (e *Endpoint) SetPattern(pattern *goose.RoutePattern) {
 	e.pattern = pattern
 }
 
-func (e *Endpoint) Pattern() *Pattern {
+func (e *Endpoint) Pattern() *goose.RoutePattern {
 	return e.pattern
 }
 
@@ -235,3 +273,90 @@ func (e *Endpoint) Body() string {
 func (e *Endpoint) ResponseBody() string {
 	return e.httpRule.GetResponseBody()
 }
+
+// Status returns the success status code declared with the (leo.goose.response) option, 0 if unset.
+func (e *Endpoint) Status() int {
+	response, ok := proto.GetExtension(e.protoMethod.Desc.Options(), gooseannotations.E_Response).(*gooseannotations.Response)
+	if !ok || response == nil {
+		return 0
+	}
+	return int(response.GetStatus())
+}
+
+// BodyEncoding returns the request body encoding declared with the (leo.goose.request) option, JSON if unset.
+func (e *Endpoint) BodyEncoding() gooseannotations.BodyEncoding {
+	request, ok := proto.GetExtension(e.protoMethod.Desc.Options(), gooseannotations.E_Request).(*gooseannotations.Request)
+	if !ok || request == nil {
+		return gooseannotations.BodyEncoding_BODY_ENCODING_JSON
+	}
+	return request.GetBodyEncoding()
+}
+
+// IsFormBody reports whether the request body is declared as a URL-encoded or multipart form.
+func (e *Endpoint) IsFormBody() bool {
+	return e.BodyEncoding() != gooseannotations.BodyEncoding_BODY_ENCODING_JSON
+}
+
+// FormFields returns the fields of the body message bound to form values and files:
+// scalar, enum, wrapper, bytes and google.api.HttpBody fields, except path parameters.
+func (e *Endpoint) FormFields() ([]*protogen.Field, error) {
+	bodyMessage, bodyField, pathFields, _, err := e.ParseParameters()
+	if err != nil {
+		return nil, err
+	}
+	var message *protogen.Message
+	switch {
+	case bodyMessage != nil:
+		message = bodyMessage
+	case bodyField != nil && bodyField.Message != nil && !bodyField.Desc.IsList() && !bodyField.Desc.IsMap():
+		message = bodyField.Message
+	default:
+		return nil, fmt.Errorf("%s, form body requires a message body", e.FullName())
+	}
+	switch message.Desc.FullName() {
+	case "google.api.HttpBody", "google.rpc.HttpRequest":
+		return nil, fmt.Errorf("%s, form body does not support %s", e.FullName(), message.Desc.FullName())
+	}
+	var formFields []*protogen.Field
+	for _, field := range message.Fields {
+		if bodyMessage != nil && slices.Contains(pathFields, field) {
+			continue
+		}
+		if field.Desc.IsMap() {
+			continue
+		}
+		switch field.Desc.Kind() {
+		case protoreflect.BoolKind: // bool
+		case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind: // int32
+		case protoreflect.Uint32Kind, protoreflect.Fixed32Kind: // uint32
+		case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind: // int64
+		case protoreflect.Uint64Kind, protoreflect.Fixed64Kind: // uint64
+		case protoreflect.FloatKind: // float32
+		case protoreflect.DoubleKind: // float64
+		case protoreflect.StringKind: // string
+		case protoreflect.EnumKind: // enum
+		case protoreflect.BytesKind: // file
+		case protoreflect.MessageKind:
+			switch field.Message.Desc.FullName() {
+			case "google.protobuf.DoubleValue":
+			case "google.protobuf.FloatValue":
+			case "google.protobuf.Int64Value":
+			case "google.protobuf.UInt64Value":
+			case "google.protobuf.Int32Value":
+			case "google.protobuf.UInt32Value":
+			case "google.protobuf.BoolValue":
+			case "google.protobuf.StringValue":
+			case "google.api.HttpBody": // file
+				if e.BodyEncoding() == gooseannotations.BodyEncoding_BODY_ENCODING_FORM {
+					return nil, fmt.Errorf("%s, google.api.HttpBody field %s requires multipart body encoding", e.FullName(), field.Desc.Name())
+				}
+			default:
+				continue
+			}
+		default:
+			continue
+		}
+		formFields = append(formFields, field)
+	}
+	return formFields, nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/go-leo/goose"
 	"google.golang.org/protobuf/compiler/protogen"
 )
 
@@ -32,6 +33,10 @@ func (s *Service) ServiceName() string {
 	return s.GooseName() + "Service"
 }
 
+func (s *Service) EndpointName(endpoint *Endpoint) string {
+	return s.Unexported(s.GooseName()) + endpoint.Name() + "Endpoint"
+}
+
 func (s *Service) AppendRouteName() string {
 	return "Append" + s.GooseName() + "Route"
 }
@@ -56,6 +61,24 @@ func (s *Service) ClientName() string {
 	return s.GooseName() + "Client"
 }
 
+func (s *Service) NewGrpcClientName() string {
+	return "New" + s.GrpcClientName()
+}
+
+func (s *Service) GrpcClientName() string {
+	return s.GooseName() + "GrpcClient"
+}
+
+// HasStreaming reports whether any method of the service streams its request or response.
+func (s *Service) HasStreaming() bool {
+	for _, endpoint := range s.Endpoints {
+		if endpoint.IsStreaming() {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *Service) RequestEncoderName() string {
 	return s.GooseName() + "RequestEncoder"
 }
@@ -64,27 +87,63 @@ func (s *Service) ResponseDecoderName() string {
 	return s.GooseName() + "ResponseDecoder"
 }
 
-func NewServices(file *protogen.File) ([]*Service, error) {
+// RouteConflictScope is the set of routes the routes of an endpoint must not conflict with
+type RouteConflictScope string
+
+const (
+	// FileScope checks the routes of all the services of a file, registered on the same router
+	FileScope RouteConflictScope = "file"
+	// ServiceScope checks the routes of each service, the services of a file being registered on distinct routers
+	ServiceScope RouteConflictScope = "service"
+)
+
+// ParseRouteConflictScope parses the value of the route_conflicts option
+func ParseRouteConflictScope(value string) (RouteConflictScope, error) {
+	switch scope := RouteConflictScope(value); scope {
+	case FileScope, ServiceScope:
+		return scope, nil
+	default:
+		return "", fmt.Errorf("goose: invalid route_conflicts %q, want %q or %q", value, FileScope, ServiceScope)
+	}
+}
+
+func NewServices(file *protogen.File, scope RouteConflictScope) ([]*Service, error) {
 	var services []*Service
+	// the routes are registered on a router which panics on conflicts
+	var registered []*Endpoint
 	for _, pbService := range file.Services {
 		service := &Service{
 			ProtoService: pbService,
 		}
+		if scope == ServiceScope {
+			registered = nil
+		}
 		var endpoints []*Endpoint
 		for _, pbMethod := range pbService.Methods {
 			endpoint := &Endpoint{
 				protoMethod: pbMethod,
 			}
-			if endpoint.IsStreaming() {
-				return nil, fmt.Errorf("goose: unsupport stream method, %s", endpoint.FullName())
-			}
 			endpoint.SetHttpRule()
-			pattern, err := ParsePattern(endpoint.Path())
+			// the pattern is parsed as registered on http.ServeMux, which panics on unclean paths
+			// with empty, "." or ".." segments, so such paths fail the generation
+			pattern, err := goose.ParseRoutePattern(endpoint.Method() + " " + endpoint.Path())
 			if err != nil {
-				return nil, fmt.Errorf("goose: %s", err)
+				return nil, err
 			}
 			endpoint.SetPattern(pattern)
+			if err := endpoint.CheckStreaming(); err != nil {
+				return nil, fmt.Errorf("goose: unsupport stream method, %s", err)
+			}
+			for _, existing := range registered {
+				if pattern.ConflictsWith(existing.Pattern()) {
+					return nil, &goose.RouteConflictError{
+						Route:    goose.Route{Method: endpoint.Method(), Path: endpoint.Path(), RPC: endpoint.FullName()},
+						Existing: goose.Route{Method: existing.Method(), Path: existing.Path(), RPC: existing.FullName()},
+					}
+				}
+			}
 			endpoints = append(endpoints, endpoint)
+			registered = append(registered, endpoint)
 		}
 		service.Endpoints = endpoints
 		services = append(services, service)
@@ -0,0 +1,217 @@
+package parser
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/go-leo/goose"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// rpc is an RPC method of the service of the test file with its HTTP rule
+type rpc struct {
+	name string
+	rule *annotations.HttpRule
+}
+
+func get(path string) *annotations.HttpRule {
+	return &annotations.HttpRule{Pattern: &annotations.HttpRule_Get{Get: path}}
+}
+
+func post(path string) *annotations.HttpRule {
+	return &annotations.HttpRule{Pattern: &annotations.HttpRule_Post{Post: path}, Body: "*"}
+}
+
+func custom(kind, path string) *annotations.HttpRule {
+	return &annotations.HttpRule{Pattern: &annotations.HttpRule_Custom{Custom: &annotations.CustomHttpPattern{Kind: kind, Path: path}}}
+}
+
+// newFile builds a file with a "leo.example.test.v1.Test" service of the RPC methods
+func newFile(t *testing.T, rpcs ...rpc) *protogen.File {
+	t.Helper()
+	return newFileServices(t, map[string][]rpc{"Test": rpcs})
+}
+
+// newFileServices builds a file with the "leo.example.test.v1" services of the RPC methods, sorted by name
+func newFileServices(t *testing.T, rpcsByService map[string][]rpc) *protogen.File {
+	t.Helper()
+	var services []*descriptorpb.ServiceDescriptorProto
+	for _, name := range slices.Sorted(maps.Keys(rpcsByService)) {
+		service := &descriptorpb.ServiceDescriptorProto{Name: proto.String(name)}
+		for _, r := range rpcsByService[name] {
+			options := &descriptorpb.MethodOptions{}
+			proto.SetExtension(options, annotations.E_Http, r.rule)
+			service.Method = append(service.Method, &descriptorpb.MethodDescriptorProto{
+				Name:       proto.String(r.name),
+				InputType:  proto.String(".leo.example.test.v1.Request"),
+				OutputType: proto.String(".leo.example.test.v1.Response"),
+				Options:    options,
+			})
+		}
+		services = append(services, service)
+	}
+	file := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("example/test/test.proto"),
+		Package: proto.String("leo.example.test.v1"),
+		Syntax:  proto.String("proto3"),
+		Options: &descriptorpb.FileOptions{GoPackage: proto.String("example/test;test")},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{Name: proto.String("Request"), Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: proto.String("id"), Number: proto.Int32(1), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), JsonName: proto.String("id")},
+				{Name: proto.String("name"), Number: proto.Int32(2), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), JsonName: proto.String("name")},
+			}},
+			{Name: proto.String("Response")},
+		},
+		Service: services,
+	}
+	plugin, err := protogen.Options{}.New(&pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{file.GetName()},
+		ProtoFile:      []*descriptorpb.FileDescriptorProto{file},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return plugin.FilesByPath[file.GetName()]
+}
+
+func TestNewServicesConflicts(t *testing.T) {
+	tests := []struct {
+		name     string
+		rpcs     []rpc
+		conflict bool
+	}{
+		{
+			name: "literal segment more specific than a wildcard",
+			rpcs: []rpc{{"GetUser", get("/v1/user/{id}")}, {"GetMe", get("/v1/user/me")}},
+		},
+		{
+			name: "GET and HEAD",
+			rpcs: []rpc{{"GetUser", get("/v1/user/{id}")}, {"HeadUser", custom("HEAD", "/v1/user/{id}")}},
+		},
+		{
+			name: "different methods",
+			rpcs: []rpc{{"GetUser", get("/v1/user/{id}")}, {"CreateUser", post("/v1/user/{id}")}},
+		},
+		{
+			name: "multi wildcard less specific than a single wildcard",
+			rpcs: []rpc{{"GetUser", get("/v1/user/{id}")}, {"GetFile", get("/v1/user/{name...}")}},
+		},
+		{
+			name:     "same path with different wildcard names",
+			rpcs:     []rpc{{"GetUser", get("/v1/user/{id}")}, {"GetUserByName", get("/v1/user/{name}")}},
+			conflict: true,
+		},
+		{
+			name:     "overlapping wildcards with neither more specific",
+			rpcs:     []rpc{{"GetUserName", get("/v1/{id}/name")}, {"GetMeField", get("/v1/me/{name}")}},
+			conflict: true,
+		},
+		{
+			name:     "same path",
+			rpcs:     []rpc{{"CreateUser", post("/v1/user")}, {"AddUser", post("/v1/user")}},
+			conflict: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services, err := NewServices(newFile(t, tt.rpcs...), FileScope)
+			if !tt.conflict {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(services) != 1 || len(services[0].Endpoints) != len(tt.rpcs) {
+					t.Fatalf("services = %v, want one service with %d endpoints", services, len(tt.rpcs))
+				}
+				return
+			}
+			var conflict *goose.RouteConflictError
+			if !errors.As(err, &conflict) {
+				t.Fatalf("expected a route conflict, got %v", err)
+			}
+			// the message names both RPC methods
+			for _, r := range tt.rpcs {
+				if fullName := "/leo.example.test.v1.Test/" + r.name; !strings.Contains(err.Error(), fullName) {
+					t.Fatalf("error %q does not name %s", err, fullName)
+				}
+			}
+		})
+	}
+}
+
+func TestNewServicesConflictMessage(t *testing.T) {
+	_, err := NewServices(newFile(t, rpc{"GetUser", get("/v1/user/{id}")}, rpc{"GetUserByName", get("/v1/user/{name}")}), FileScope)
+	want := `goose: route "GET /v1/user/{name}" of /leo.example.test.v1.Test/GetUserByName conflicts with route "GET /v1/user/{id}" of /leo.example.test.v1.Test/GetUser`
+	if err == nil || err.Error() != want {
+		t.Fatalf("error = %v, want %s", err, want)
+	}
+}
+
+func TestNewServicesConflictScope(t *testing.T) {
+	conflicting := map[string][]rpc{
+		"Account": {{"GetAccount", get("/v1/user/{name}")}},
+		"User":    {{"GetUser", get("/v1/user/{id}")}},
+	}
+	distinct := map[string][]rpc{
+		"Account": {{"GetAccount", get("/v1/account/{name}")}},
+		"User":    {{"GetUser", get("/v1/user/{id}")}},
+	}
+	tests := []struct {
+		name    string
+		rpcs    map[string][]rpc
+		scope   RouteConflictScope
+		wantErr string
+	}{
+		{
+			name:    "conflict between the services of a file",
+			rpcs:    conflicting,
+			scope:   FileScope,
+			wantErr: `goose: route "GET /v1/user/{id}" of /leo.example.test.v1.User/GetUser conflicts with route "GET /v1/user/{name}" of /leo.example.test.v1.Account/GetAccount`,
+		},
+		{name: "distinct routes of the services of a file", rpcs: distinct, scope: FileScope},
+		{name: "services on distinct routers", rpcs: conflicting, scope: ServiceScope},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services, err := NewServices(newFileServices(t, tt.rpcs), tt.scope)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || len(services) != 2 {
+				t.Fatalf("NewServices() = %v, %v, want 2 services", services, err)
+			}
+		})
+	}
+}
+
+func TestParseRouteConflictScope(t *testing.T) {
+	for _, value := range []string{"file", "service"} {
+		if scope, err := ParseRouteConflictScope(value); err != nil || string(scope) != value {
+			t.Errorf("ParseRouteConflictScope(%q) = %q, %v", value, scope, err)
+		}
+	}
+	if _, err := ParseRouteConflictScope("package"); err == nil {
+		t.Error("expected an error for an unknown scope")
+	}
+}
+
+// http.ServeMux panics on the patterns with unclean paths, so they fail the generation
+func TestNewServicesUncleanPath(t *testing.T) {
+	for _, path := range []string{"/v1//user", "/v1/./user", "/v1/user/..", "/v1/user/{id}/./name"} {
+		t.Run(path, func(t *testing.T) {
+			_, err := NewServices(newFile(t, rpc{"GetUser", get(path)}), FileScope)
+			if err == nil || !strings.Contains(err.Error(), "non-CONNECT pattern with unclean path") {
+				t.Fatalf("error = %v, want an unclean path error", err)
+			}
+		})
+	}
+}
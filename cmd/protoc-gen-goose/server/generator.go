@@ -21,7 +21,7 @@ func (generator *Generator) GenerateServices(service *parser.Service, g *protoge
 }
 
 func (generator *Generator) GenerateAppendServerFunc(service *parser.Service, g *protogen.GeneratedFile) error {
-	g.P("func ", service.AppendRouteName(), "(router *", constant.RouterIdent, ", service ", service.ServiceName(), ", opts ...", constant.ServerOptionIdent, ") ", "*", constant.RouterIdent, " {")
+	g.P("func ", service.AppendRouteName(), "[R ", constant.RouterIdent, "](router R, service ", service.ServiceName(), ", opts ...", constant.ServerOptionIdent, ") R {")
 	g.P("options := ", constant.ServerNewOptionsIdent, "(opts...)")
 	g.P("handler :=  ", service.Unexported(service.HandlerName()), "{")
 	g.P("service: service,")
@@ -37,8 +37,9 @@ func (generator *Generator) GenerateAppendServerFunc(service *parser.Service, g
 	g.P("onValidationErrCallback: options.OnValidationErrCallback(),")
 	g.P("middleware: ", constant.ServerChainIdent, "(options.Middlewares()...),")
 	g.P("}")
+	g.P("registry := options.RouteRegistry()")
 	for _, endpoint := range service.Endpoints {
-		g.P("router.Handle(", strconv.Quote(endpoint.Method()+" "+endpoint.Path()), ", ", constant.HttpHandlerFuncIdent, "(handler.", endpoint.Name(), "))")
+		g.P("registry.Handle(router, ", service.EndpointName(endpoint), ", ", strconv.Quote(endpoint.Method()+" "), "+options.PathPrefix()+", strconv.Quote(endpoint.Path()), ", ", constant.HttpHandlerFuncIdent, "(handler.", endpoint.Name(), "))")
 	}
 	g.P("return router")
 	g.P("}")
@@ -46,6 +47,26 @@ func (generator *Generator) GenerateAppendServerFunc(service *parser.Service, g
 	return nil
 }
 
+// GenerateEndpoints generates the endpoint descriptions put in the context by the route registry and the clients
+func (generator *Generator) GenerateEndpoints(service *parser.Service, g *protogen.GeneratedFile) error {
+	g.P("var (")
+	for _, endpoint := range service.Endpoints {
+		g.P(service.EndpointName(endpoint), " = &", constant.EndpointIdent, "{")
+		g.P("FullName: ", strconv.Quote(endpoint.FullName()), ",")
+		g.P("Method: ", strconv.Quote(endpoint.Method()), ",")
+		g.P("Pattern: ", strconv.Quote(endpoint.Path()), ",")
+		g.P("Request: ", strconv.Quote(string(endpoint.Input().Desc.FullName())), ",")
+		g.P("Response: ", strconv.Quote(string(endpoint.Output().Desc.FullName())), ",")
+		if endpoint.IsStreamingRequest() {
+			g.P("StreamingRequest: true,")
+		}
+		g.P("}")
+	}
+	g.P(")")
+	g.P()
+	return nil
+}
+
 func (generator *Generator) GenerateHandlers(service *parser.Service, g *protogen.GeneratedFile) error {
 	g.P("type ", service.Unexported(service.HandlerName()), " struct {")
 	g.P("service ", service.ServiceName())
@@ -66,10 +87,12 @@ func (generator *Generator) GenerateHandlers(service *parser.Service, g *protoge
 		g.P("h.errorEncoder(ctx, err, response)")
 		g.P("return")
 		g.P("}")
-		g.P("if err := ", constant.ValidateRequestIdent, "(ctx, req, h.shouldFailFast, h.onValidationErrCallback)", "; err != nil {")
-		g.P("h.errorEncoder(ctx, err, response)")
-		g.P("return")
-		g.P("}")
+		if !endpoint.IsStreamingRequest() {
+			g.P("if err := ", constant.ValidateRequestIdent, "(ctx, req, h.shouldFailFast, h.onValidationErrCallback)", "; err != nil {")
+			g.P("h.errorEncoder(ctx, err, response)")
+			g.P("return")
+			g.P("}")
+		}
 		g.P("resp, err := h.service.", endpoint.Name(), "(ctx, req)")
 		g.P("if err != nil {")
 		g.P("h.errorEncoder(ctx, err, response)")
@@ -17,6 +17,12 @@ func (generator *Generator) GenerateDecodeRequest(service *parser.Service, g *pr
 	for _, endpoint := range service.Endpoints {
 		g.P("func (decoder ", service.Unexported(service.RequestDecoderName()), ")", endpoint.Name(), "(ctx ", constant.ContextIdent, ", request *", constant.RequestIdent, ") (*", endpoint.InputGoIdent(), ", error){")
 		g.P("req := &", endpoint.InputGoIdent(), "{}")
+		if endpoint.IsStreamingRequest() {
+			generator.PrintHttpBodyStreamDecodeBlock(g, []any{"req"})
+			g.P("return req, nil")
+			g.P("}")
+			continue
+		}
 		g.P("ok, err := ", constant.CustomDecodeRequestIdent, "(ctx, request, req)")
 		g.P("if err != nil {")
 		g.P("return nil, err")
@@ -37,7 +43,13 @@ func (generator *Generator) GenerateDecodeRequest(service *parser.Service, g *pr
 			case "google.rpc.HttpRequest":
 				generator.PrintHttpRequestEncodeBlock(g, []any{"req"})
 			default:
-				generator.PrintRequestDecodeBlock(g, []any{"req"})
+				if endpoint.IsFormBody() {
+					if err := generator.PrintFormDecodeBlock(g, endpoint, []any{"req."}, []any{"req"}); err != nil {
+						return err
+					}
+				} else {
+					generator.PrintRequestDecodeBlock(g, []any{"req"})
+				}
 			}
 		} else if bodyField != nil {
 			tgtValue := []any{"req.", bodyField.GoName}
@@ -50,7 +62,13 @@ func (generator *Generator) GenerateDecodeRequest(service *parser.Service, g *pr
 				case "google.api.HttpBody":
 					generator.PrintHttpBodyDecodeBlock(g, tgtValue)
 				default:
-					generator.PrintRequestDecodeBlock(g, []any{"req.", bodyField.GoName})
+					if endpoint.IsFormBody() {
+						if err := generator.PrintFormDecodeBlock(g, endpoint, []any{"req.", bodyField.GoName, "."}, []any{"req.", bodyField.GoName}); err != nil {
+							return err
+						}
+					} else {
+						generator.PrintRequestDecodeBlock(g, []any{"req.", bodyField.GoName})
+					}
 				}
 			}
 		}
@@ -86,6 +104,12 @@ func (generator *Generator) PrintHttpBodyDecodeBlock(g *protogen.GeneratedFile,
 	g.P("}")
 }
 
+func (generator *Generator) PrintHttpBodyStreamDecodeBlock(g *protogen.GeneratedFile, tgtValue []any) {
+	g.P(append(append([]any{"if err := ", constant.DecodeHttpBodyStreamIdent, "(ctx, request, "}, tgtValue...), "); err != nil {")...)
+	g.P("return nil, err")
+	g.P("}")
+}
+
 func (generator *Generator) PrintHttpRequestEncodeBlock(g *protogen.GeneratedFile, tgtValue []any) {
 	g.P(append(append([]any{"if err := ", constant.DecodeHttpRequestIdent, "(ctx, request, "}, tgtValue...), "); err != nil {")...)
 	g.P("return nil, err")
@@ -98,6 +122,35 @@ func (generator *Generator) PrintRequestDecodeBlock(g *protogen.GeneratedFile, t
 	g.P("}")
 }
 
+// PrintFormDecodeBlock prints the decoding of a body that is a form or JSON, depending on the request Content-Type.
+func (generator *Generator) PrintFormDecodeBlock(g *protogen.GeneratedFile, endpoint *parser.Endpoint, tgtPrefix []any, tgtValue []any) error {
+	formFields, err := endpoint.FormFields()
+	if err != nil {
+		return err
+	}
+	g.P("if ", constant.IsFormRequestIdent, "(request) {")
+	if len(formFields) > 0 {
+		g.P("form, err := ", constant.DecodeFormIdent, "(ctx, request)")
+	} else {
+		g.P("_, err := ", constant.DecodeFormIdent, "(ctx, request)")
+	}
+	g.P("if err != nil {")
+	g.P("return nil, err")
+	g.P("}")
+	g.P("defer ", constant.RemoveFormIdent, "(request)")
+	if len(formFields) > 0 {
+		g.P("var formErr error")
+		generator.PrintFormField(g, formFields, tgtPrefix, "form", "formErr")
+		g.P("if formErr != nil {")
+		g.P("return nil, formErr")
+		g.P("}")
+	}
+	g.P("} else {")
+	generator.PrintRequestDecodeBlock(g, tgtValue)
+	g.P("}")
+	return nil
+}
+
 func (generator *Generator) PrintPathField(g *protogen.GeneratedFile, pathFields []*protogen.Field) {
 	if len(pathFields) <= 0 {
 		return
@@ -195,14 +248,20 @@ func (generator *Generator) PrintPathField(g *protogen.GeneratedFile, pathFields
 }
 
 func (generator *Generator) PrintQueryField(g *protogen.GeneratedFile, queryFields []*protogen.Field) {
-	for _, field := range queryFields {
+	generator.PrintFormField(g, queryFields, []any{"req."}, "queries", "queryErr")
+}
+
+// PrintFormField prints the binding of url.Values named form to the fields of tgtPrefix,
+// bytes and google.api.HttpBody fields are bound to the files of the request form.
+func (generator *Generator) PrintFormField(g *protogen.GeneratedFile, fields []*protogen.Field, tgtPrefix []any, form string, errName string) {
+	for _, field := range fields {
 		fieldName := string(field.Desc.Name())
 
-		tgtValue := []any{"req.", field.GoName, " = "}
-		tgtErrValue := []any{"req.", field.GoName, ", queryErr = "}
-		srcValue := []any{"queries.Get(", strconv.Quote(fieldName), ")"}
+		tgtValue := append(append([]any{}, tgtPrefix...), field.GoName, " = ")
+		tgtErrValue := append(append([]any{}, tgtPrefix...), field.GoName, ", ", errName, " = ")
+		srcValue := []any{form, ".Get(", strconv.Quote(fieldName), ")"}
 		if field.Desc.IsList() {
-			srcValue = []any{"queries[", strconv.Quote(fieldName), "]"}
+			srcValue = []any{form, "[", strconv.Quote(fieldName), "]"}
 		}
 
 		goType, pointer := parser.FieldGoType(g, field)
@@ -210,9 +269,6 @@ func (generator *Generator) PrintQueryField(g *protogen.GeneratedFile, queryFiel
 			goType = append([]any{"*"}, goType...)
 		}
 
-		form := "queries"
-		errName := "queryErr"
-
 		switch field.Desc.Kind() {
 		case protoreflect.BoolKind: // bool
 			if field.Desc.IsList() {
@@ -300,8 +356,20 @@ func (generator *Generator) PrintQueryField(g *protogen.GeneratedFile, queryFiel
 					generator.PrintFieldAssign(g, tgtErrValue, goType, constant.GetEnumIdent(g, goType[0].(protogen.GoIdent)), fieldName, form, errName)
 				}
 			}
+		case protoreflect.BytesKind: // file
+			if field.Desc.IsList() {
+				generator.PrintFileAssign(g, tgtErrValue, constant.GetFormFilesIdent, fieldName, errName)
+			} else {
+				generator.PrintFileAssign(g, tgtErrValue, constant.GetFormFileIdent, fieldName, errName)
+			}
 		case protoreflect.MessageKind:
 			switch field.Message.Desc.FullName() {
+			case "google.api.HttpBody": // file
+				if field.Desc.IsList() {
+					generator.PrintFileAssign(g, tgtErrValue, constant.GetFormHttpBodiesIdent, fieldName, errName)
+				} else {
+					generator.PrintFileAssign(g, tgtErrValue, constant.GetFormHttpBodyIdent, fieldName, errName)
+				}
 			case "google.protobuf.BoolValue":
 				if field.Desc.IsList() {
 					generator.PrintFieldAssign(g, tgtErrValue, goType, constant.GetBoolValueSliceIdent, fieldName, form, errName)
@@ -360,6 +428,10 @@ func (generator *Generator) PrintFieldAssign(g *protogen.GeneratedFile, tgtValue
 	g.P(append(append([]any{}, tgtValue...), append(append([]any{constant.GetFormIdent, "["}, goType...), append([]any{"](", errName, ", ", form, ", ", strconv.Quote(key), ", ", getter}, ")")...)...)...)
 }
 
+func (generator *Generator) PrintFileAssign(g *protogen.GeneratedFile, tgtValue []any, getter protogen.GoIdent, key string, errName string) {
+	g.P(append(append([]any{}, tgtValue...), getter, "(", errName, ", request, ", strconv.Quote(key), ")")...)
+}
+
 func (generator *Generator) PrintStringValueAssign(g *protogen.GeneratedFile, tgtValue []any, srcValue []any, hasPresence bool) {
 	if hasPresence {
 		g.P(append(tgtValue, append(append([]any{constant.ProtoStringIdent, "("}, srcValue...), ")")...)...)
@@ -15,7 +15,16 @@ func (generator *Generator) GenerateEncodeResponse(service *parser.Service, g *p
 	g.P("unmarshalOptions ", constant.ProtoJsonUnmarshalOptionsIdent)
 	g.P("}")
 	for _, endpoint := range service.Endpoints {
+		status := endpoint.Status()
+		if status != 0 && (status < 100 || status > 599) {
+			return fmt.Errorf("%s, invalid response status %d", endpoint.FullName(), status)
+		}
 		g.P("func (encoder ", service.Unexported(service.ResponseEncoderName()), ")", endpoint.Name(), "(ctx ", constant.ContextIdent, ", w ", constant.ResponseWriterIdent, ", resp *", endpoint.OutputGoIdent(), ") error {")
+		if endpoint.IsStreamingResponse() {
+			generator.PrintHttpBodyStreamEncodeBlock(g, []any{"resp"}, status)
+			g.P("}")
+			continue
+		}
 		bodyParameter := endpoint.ResponseBody()
 		switch bodyParameter {
 		case "", "*":
@@ -23,13 +32,13 @@ func (generator *Generator) GenerateEncodeResponse(service *parser.Service, g *p
 			switch message.Desc.FullName() {
 			case "google.api.HttpBody":
 				srcValue := []any{"resp"}
-				generator.PrintHttpBodyEncodeBlock(g, srcValue)
+				generator.PrintHttpBodyEncodeBlock(g, srcValue, status)
 			case "google.rpc.HttpResponse":
 				srcValue := []any{"resp"}
 				generator.PrintHttpResponseEncodeBlock(g, srcValue)
 			default:
 				srcValue := []any{"resp"}
-				generator.PrintResponseEncodeBlock(g, srcValue)
+				generator.PrintResponseEncodeBlock(g, srcValue, status)
 			}
 		default:
 			bodyField := parser.FindField(bodyParameter, endpoint.Output())
@@ -41,10 +50,10 @@ func (generator *Generator) GenerateEncodeResponse(service *parser.Service, g *p
 				switch bodyField.Message.Desc.FullName() {
 				case "google.api.HttpBody":
 					srcValue := []any{"resp.Get", bodyField.GoName, "()"}
-					generator.PrintHttpBodyEncodeBlock(g, srcValue)
+					generator.PrintHttpBodyEncodeBlock(g, srcValue, status)
 				default:
 					srcValue := []any{"resp.Get", bodyField.GoName, "()"}
-					generator.PrintResponseEncodeBlock(g, srcValue)
+					generator.PrintResponseEncodeBlock(g, srcValue, status)
 				}
 			}
 		}
@@ -54,14 +63,30 @@ func (generator *Generator) GenerateEncodeResponse(service *parser.Service, g *p
 	return nil
 }
 
-func (generator *Generator) PrintHttpBodyEncodeBlock(g *protogen.GeneratedFile, srcValue []any) {
+func (generator *Generator) PrintHttpBodyEncodeBlock(g *protogen.GeneratedFile, srcValue []any, status int) {
+	if status != 0 {
+		g.P(append(append([]any{"return ", constant.EncodeHttpBodyWithStatusIdent, "(ctx, w, "}, srcValue...), ", ", status, ")")...)
+		return
+	}
 	g.P(append(append([]any{"return ", constant.EncodeHttpBodyIdent, "(ctx, w, "}, srcValue...), ")")...)
 }
 
+func (generator *Generator) PrintHttpBodyStreamEncodeBlock(g *protogen.GeneratedFile, srcValue []any, status int) {
+	if status != 0 {
+		g.P(append(append([]any{"return ", constant.EncodeHttpBodyStreamWithStatusIdent, "(ctx, w, "}, srcValue...), ", ", status, ")")...)
+		return
+	}
+	g.P(append(append([]any{"return ", constant.EncodeHttpBodyStreamIdent, "(ctx, w, "}, srcValue...), ")")...)
+}
+
 func (generator *Generator) PrintHttpResponseEncodeBlock(g *protogen.GeneratedFile, srcValue []any) {
 	g.P(append(append([]any{"return ", constant.EncodeHttpResponseIdent, "(ctx, w, "}, srcValue...), ")")...)
 }
 
-func (generator *Generator) PrintResponseEncodeBlock(g *protogen.GeneratedFile, srcValue []any) {
+func (generator *Generator) PrintResponseEncodeBlock(g *protogen.GeneratedFile, srcValue []any, status int) {
+	if status != 0 {
+		g.P(append(append([]any{"return ", constant.EncodeResponseWithStatusIdent, "(ctx, w, "}, srcValue...), ", ", status, ", encoder.marshalOptions)")...)
+		return
+	}
 	g.P(append(append([]any{"return ", constant.EncodeResponseIdent, "(ctx, w, "}, srcValue...), ", encoder.marshalOptions)")...)
 }
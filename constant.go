@@ -10,6 +10,12 @@ const (
 	// PlainContentType is the content type for plain text.
 	PlainContentType = "text/plain; charset=utf-8"
 
+	// FormContentType is the content type for URL-encoded forms.
+	FormContentType = "application/x-www-form-urlencoded"
+
+	// MultipartFormContentType is the content type for multipart forms.
+	MultipartFormContentType = "multipart/form-data"
+
 	// ErrorKey is the key for the error header.
 	ErrorKey = "X-Goose-Error"
 )
@@ -0,0 +1,163 @@
+// Package debug registers the debug endpoints of pprof, expvar and runtime/metrics on a router.
+// It is a separate package because importing net/http/pprof and expvar registers their handlers
+// on http.DefaultServeMux, which the goose package must not do.
+package debug
+
+import (
+	"encoding/json"
+	"expvar"
+	"math"
+	"net/http"
+	"net/http/pprof"
+	"runtime/metrics"
+	"strings"
+
+	"github.com/go-leo/goose"
+	"github.com/go-leo/goose/server"
+)
+
+// options holds configuration options for the debug endpoints
+type options struct {
+	prefix     string            // Prefix of the endpoints
+	middleware server.Middleware // Middleware protecting the endpoints
+}
+
+// apply applies the given options to the options struct
+// Parameters:
+//   - opts: Variable number of Option functions
+//
+// Returns:
+//   - *options: Pointer to the updated options struct
+func (o *options) apply(opts ...Option) *options {
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Option is a function type for configuring the debug endpoints
+type Option func(o *options)
+
+// defaultOptions returns the default configuration options
+// Returns:
+//   - *options: Default options, the "/debug" prefix and no middleware
+func defaultOptions() *options {
+	return &options{
+		prefix: "/debug",
+	}
+}
+
+// Prefix sets the prefix of the debug endpoints, "/debug" by default
+// Parameters:
+//   - prefix: Prefix, such as "/internal/debug"
+//
+// Returns:
+//   - Option: Function to set the prefix option
+func Prefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = strings.TrimSuffix(prefix, "/")
+	}
+}
+
+// Middleware sets a middleware protecting the debug endpoints, such as basicauth.Server or an IP allowlist.
+// Parameters:
+//   - middleware: The middleware
+//
+// Returns:
+//   - Option: Function to set the middleware option
+func Middleware(middleware server.Middleware) Option {
+	return func(o *options) {
+		o.middleware = middleware
+	}
+}
+
+// profiles are the named runtime profiles, registered explicitly so they do not depend on the index
+var profiles = []string{"allocs", "block", "goroutine", "heap", "mutex", "threadcreate"}
+
+// Append registers the debug endpoints on the router, under a prefix "/debug" by default:
+//   - {prefix}/pprof/ with the index, cmdline, profile, symbol, trace and the named profiles of net/http/pprof
+//   - {prefix}/vars with the variables of expvar
+//   - {prefix}/runtime/metrics with the samples of runtime/metrics as JSON
+//
+// Parameters:
+//   - router: The router to register on, such as an *http.ServeMux or a router adapter
+//   - opts: Variable number of Option functions for configuration
+//
+// Returns:
+//   - R: The router
+func Append[R goose.Router](router R, opts ...Option) R {
+	opt := defaultOptions().apply(opts...)
+	handle := func(pattern string, handler http.Handler) {
+		if opt.middleware != nil {
+			next := handler.ServeHTTP
+			handler = http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+				opt.middleware(response, request, next)
+			})
+		}
+		router.Handle(pattern, handler)
+	}
+	prefix := opt.prefix + "/pprof/"
+	handle("GET "+prefix, pprofIndex(prefix))
+	handle("GET "+prefix+"cmdline", http.HandlerFunc(pprof.Cmdline))
+	handle("GET "+prefix+"profile", http.HandlerFunc(pprof.Profile))
+	handle("GET "+prefix+"symbol", http.HandlerFunc(pprof.Symbol))
+	handle("POST "+prefix+"symbol", http.HandlerFunc(pprof.Symbol))
+	handle("GET "+prefix+"trace", http.HandlerFunc(pprof.Trace))
+	for _, profile := range profiles {
+		handle("GET "+prefix+profile, pprof.Handler(profile))
+	}
+	handle("GET "+opt.prefix+"/vars", expvar.Handler())
+	handle("GET "+opt.prefix+"/runtime/metrics", http.HandlerFunc(runtimeMetrics))
+	return router
+}
+
+// pprofIndex serves the pprof index under any prefix, pprof.Index expecting the "/debug/pprof/" prefix
+func pprofIndex(prefix string) http.Handler {
+	return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		request = request.Clone(request.Context())
+		request.URL.Path = "/debug/pprof/" + strings.TrimPrefix(request.URL.Path, prefix)
+		pprof.Index(response, request)
+	})
+}
+
+// runtimeMetrics writes the samples of all the supported runtime metrics as a JSON object keyed by metric name.
+// Histograms are written as their counts and bucket boundaries, infinite boundaries as "-Inf" and "+Inf".
+func runtimeMetrics(response http.ResponseWriter, request *http.Request) {
+	descriptions := metrics.All()
+	samples := make([]metrics.Sample, len(descriptions))
+	for i, description := range descriptions {
+		samples[i].Name = description.Name
+	}
+	metrics.Read(samples)
+	values := make(map[string]any, len(samples))
+	for _, sample := range samples {
+		switch sample.Value.Kind() {
+		case metrics.KindUint64:
+			values[sample.Name] = sample.Value.Uint64()
+		case metrics.KindFloat64:
+			values[sample.Name] = jsonFloat(sample.Value.Float64())
+		case metrics.KindFloat64Histogram:
+			histogram := sample.Value.Float64Histogram()
+			buckets := make([]any, len(histogram.Buckets))
+			for i, bucket := range histogram.Buckets {
+				buckets[i] = jsonFloat(bucket)
+			}
+			values[sample.Name] = map[string]any{"counts": histogram.Counts, "buckets": buckets}
+		}
+	}
+	response.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(response).Encode(values)
+}
+
+// jsonFloat returns a float encodable as JSON, infinities and NaN as strings
+func jsonFloat(f float64) any {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	case math.IsNaN(f):
+		return "NaN"
+	}
+	return f
+}
@@ -0,0 +1,114 @@
+package debug
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestAppend(t *testing.T) {
+	router := Append(http.NewServeMux())
+	tests := []struct {
+		target      string
+		contains    string
+		contentType string
+	}{
+		{target: "/debug/pprof/", contains: "goroutine"},
+		{target: "/debug/pprof/goroutine?debug=1", contains: "goroutine profile"},
+		{target: "/debug/pprof/heap?debug=1", contains: "heap profile"},
+		{target: "/debug/pprof/cmdline", contains: ""},
+		{target: "/debug/vars", contains: "memstats", contentType: "application/json; charset=utf-8"},
+		{target: "/debug/runtime/metrics", contains: "/gc/cycles/total:gc-cycles", contentType: "application/json; charset=utf-8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			if rr.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+			}
+			if !strings.Contains(rr.Body.String(), tt.contains) {
+				t.Fatalf("expected body to contain %q, got %q", tt.contains, rr.Body.String())
+			}
+			if tt.contentType != "" && rr.Header().Get("Content-Type") != tt.contentType {
+				t.Fatalf("expected content type %q, got %q", tt.contentType, rr.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestAppendNotOnDefaultServeMux(t *testing.T) {
+	Append(http.NewServeMux(), Prefix("/isolated/debug"))
+	rr := httptest.NewRecorder()
+	http.DefaultServeMux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/isolated/debug/pprof/", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 on the default mux, got %d", rr.Code)
+	}
+}
+
+func TestAppendPrefixAndMiddleware(t *testing.T) {
+	guard := func(response http.ResponseWriter, request *http.Request, invoker http.HandlerFunc) {
+		if request.Header.Get("X-Debug-Token") != "secret" {
+			http.Error(response, "forbidden", http.StatusForbidden)
+			return
+		}
+		invoker(response, request)
+	}
+	router := Append(http.NewServeMux(), Prefix("/internal/debug/"), Middleware(guard))
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/internal/debug/pprof/", nil))
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 without token, got %d", rr.Code)
+	}
+
+	for _, target := range []string{"/internal/debug/pprof/", "/internal/debug/pprof/allocs?debug=1", "/internal/debug/runtime/metrics"} {
+		request := httptest.NewRequest(http.MethodGet, target, nil)
+		request.Header.Set("X-Debug-Token", "secret")
+		rr = httptest.NewRecorder()
+		router.ServeHTTP(rr, request)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200 with token, got %d: %s", target, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestRuntimeMetricsJSON(t *testing.T) {
+	rr := httptest.NewRecorder()
+	runtimeMetrics(rr, httptest.NewRequest(http.MethodGet, "/debug/runtime/metrics", nil))
+	var values map[string]any
+	if err := json.Unmarshal(rr.Body.Bytes(), &values); err != nil {
+		t.Fatal(err)
+	}
+	histogram, ok := values["/gc/pauses:seconds"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected histogram, got %T", values["/gc/pauses:seconds"])
+	}
+	if _, ok := histogram["buckets"].([]any); !ok {
+		t.Fatalf("expected buckets, got %v", histogram)
+	}
+}
+
+func TestAppendRouter(t *testing.T) {
+	router := &patternRouter{}
+	if got := Append(router, Prefix("/internal")); got != router {
+		t.Fatal("expected the router to be returned")
+	}
+	for _, pattern := range []string{"GET /internal/pprof/", "GET /internal/pprof/heap", "POST /internal/pprof/symbol", "GET /internal/vars", "GET /internal/runtime/metrics"} {
+		if !slices.Contains(router.patterns, pattern) {
+			t.Errorf("expected pattern %q, got %v", pattern, router.patterns)
+		}
+	}
+}
+
+// patternRouter is a Router other than *http.ServeMux, recording the registered patterns
+type patternRouter struct {
+	patterns []string
+}
+
+func (r *patternRouter) Handle(pattern string, handler http.Handler) {
+	r.patterns = append(r.patterns, pattern)
+}
@@ -0,0 +1,72 @@
+package goose
+
+import (
+	"context"
+	"strings"
+)
+
+// Endpoint describes the goose endpoint of a request.
+// The generated server handlers and clients put it in the context of the requests,
+// so that middlewares such as tracing and metrics can name them.
+type Endpoint struct {
+	FullName         string // Full name of the RPC method, such as "/leo.example.user.v1.User/CreateUser"
+	Method           string // HTTP method of the route, such as "GET"
+	Pattern          string // Path pattern of the route, such as "/v1/user/{id}", with the path prefix in the server requests
+	Request          string // Full name of the request message, such as "leo.example.user.v1.CreateUserRequest"
+	Response         string // Full name of the response message, such as "leo.example.user.v1.CreateUserResponse"
+	StreamingRequest bool   // Whether the request body is streamed to the service as a goose.HttpBodyStream
+}
+
+// Service returns the full name of the service of the RPC method
+//
+// Returns:
+//   - string: The service name, such as "leo.example.user.v1.User"
+func (e *Endpoint) Service() string {
+	service, _, _ := strings.Cut(strings.TrimPrefix(e.FullName, "/"), "/")
+	return service
+}
+
+// RPCMethod returns the name of the RPC method
+//
+// Returns:
+//   - string: The method name, such as "CreateUser"
+func (e *Endpoint) RPCMethod() string {
+	_, method, _ := strings.Cut(strings.TrimPrefix(e.FullName, "/"), "/")
+	return method
+}
+
+// Route returns the route of the endpoint, in the syntax of http.ServeMux patterns
+//
+// Returns:
+//   - string: The route, such as "GET /v1/user/{id}"
+func (e *Endpoint) Route() string {
+	return e.Method + " " + e.Pattern
+}
+
+// endpointKey is the context key of the endpoint
+type endpointKey struct{}
+
+// WithEndpoint returns a context carrying the endpoint of a request
+//
+// Parameters:
+//   - ctx: The parent context
+//   - endpoint: The endpoint of the request
+//
+// Returns:
+//   - context.Context: The context with the endpoint
+func WithEndpoint(ctx context.Context, endpoint *Endpoint) context.Context {
+	return context.WithValue(ctx, endpointKey{}, endpoint)
+}
+
+// EndpointFromContext retrieves the endpoint of a request from the context
+//
+// Parameters:
+//   - ctx: The context that may contain an endpoint
+//
+// Returns:
+//   - *Endpoint: The endpoint if found
+//   - bool: True if an endpoint was found, false otherwise
+func EndpointFromContext(ctx context.Context) (*Endpoint, bool) {
+	endpoint, ok := ctx.Value(endpointKey{}).(*Endpoint)
+	return endpoint, ok
+}
@@ -0,0 +1,30 @@
+package goose
+
+import (
+	"context"
+	"testing"
+)
+
+func TestEndpointFromContext(t *testing.T) {
+	if _, ok := EndpointFromContext(context.Background()); ok {
+		t.Fatal("expected no endpoint")
+	}
+	endpoint := &Endpoint{FullName: "/leo.example.user.v1.User/GetUser", Method: "GET", Pattern: "/v1/user/{id}"}
+	got, ok := EndpointFromContext(WithEndpoint(context.Background(), endpoint))
+	if !ok || got != endpoint {
+		t.Fatalf("EndpointFromContext() = %v, %v, want %v, true", got, ok, endpoint)
+	}
+}
+
+func TestEndpointNames(t *testing.T) {
+	endpoint := &Endpoint{FullName: "/leo.example.user.v1.User/GetUser", Method: "GET", Pattern: "/v1/user/{id}"}
+	if got := endpoint.Service(); got != "leo.example.user.v1.User" {
+		t.Errorf("Service() = %q", got)
+	}
+	if got := endpoint.RPCMethod(); got != "GetUser" {
+		t.Errorf("RPCMethod() = %q", got)
+	}
+	if got := endpoint.Route(); got != "GET /v1/user/{id}" {
+		t.Errorf("Route() = %q", got)
+	}
+}
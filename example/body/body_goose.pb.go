@@ -8,13 +8,16 @@ import (
 	errors "errors"
 	goose "github.com/go-leo/goose"
 	client "github.com/go-leo/goose/client"
+	grpcx "github.com/go-leo/goose/client/grpcx"
 	resolver "github.com/go-leo/goose/client/resolver"
 	server "github.com/go-leo/goose/server"
 	httpbody "google.golang.org/genproto/googleapis/api/httpbody"
 	http "google.golang.org/genproto/googleapis/rpc/http"
+	grpc "google.golang.org/grpc"
 	protojson "google.golang.org/protobuf/encoding/protojson"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
 	http1 "net/http"
+	url "net/url"
 )
 
 type BodyGooseService interface {
@@ -26,7 +29,52 @@ type BodyGooseService interface {
 	HttpRequest(ctx context.Context, req *http.HttpRequest) (*Response, error)
 }
 
-func AppendBodyGooseRoute(router *http1.ServeMux, service BodyGooseService, opts ...server.Option) *http1.ServeMux {
+var (
+	bodyGooseStarBodyEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.body.v1.Body/StarBody",
+		Method:   "POST",
+		Pattern:  "/v1/star/body",
+		Request:  "leo.goose.example.body.v1.BodyRequest",
+		Response: "leo.goose.example.body.v1.Response",
+	}
+	bodyGooseNamedBodyEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.body.v1.Body/NamedBody",
+		Method:   "POST",
+		Pattern:  "/v1/named/body",
+		Request:  "leo.goose.example.body.v1.NamedBodyRequest",
+		Response: "leo.goose.example.body.v1.Response",
+	}
+	bodyGooseNonBodyEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.body.v1.Body/NonBody",
+		Method:   "GET",
+		Pattern:  "/v1/user_body",
+		Request:  "google.protobuf.Empty",
+		Response: "leo.goose.example.body.v1.Response",
+	}
+	bodyGooseHttpBodyStarBodyEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.body.v1.Body/HttpBodyStarBody",
+		Method:   "PUT",
+		Pattern:  "/v1/http/body/star/body",
+		Request:  "google.api.HttpBody",
+		Response: "leo.goose.example.body.v1.Response",
+	}
+	bodyGooseHttpBodyNamedBodyEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.body.v1.Body/HttpBodyNamedBody",
+		Method:   "PUT",
+		Pattern:  "/v1/http/body/named/body",
+		Request:  "leo.goose.example.body.v1.HttpBodyRequest",
+		Response: "leo.goose.example.body.v1.Response",
+	}
+	bodyGooseHttpRequestEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.body.v1.Body/HttpRequest",
+		Method:   "PUT",
+		Pattern:  "/v1/http/request",
+		Request:  "google.rpc.HttpRequest",
+		Response: "leo.goose.example.body.v1.Response",
+	}
+)
+
+func AppendBodyGooseRoute[R goose.Router](router R, service BodyGooseService, opts ...server.Option) R {
 	options := server.NewOptions(opts...)
 	handler := bodyGooseHandler{
 		service: service,
@@ -42,12 +90,13 @@ func AppendBodyGooseRoute(router *http1.ServeMux, service BodyGooseService, opts
 		onValidationErrCallback: options.OnValidationErrCallback(),
 		middleware:              server.Chain(options.Middlewares()...),
 	}
-	router.Handle("POST /v1/star/body", http1.HandlerFunc(handler.StarBody))
-	router.Handle("POST /v1/named/body", http1.HandlerFunc(handler.NamedBody))
-	router.Handle("GET /v1/user_body", http1.HandlerFunc(handler.NonBody))
-	router.Handle("PUT /v1/http/body/star/body", http1.HandlerFunc(handler.HttpBodyStarBody))
-	router.Handle("PUT /v1/http/body/named/body", http1.HandlerFunc(handler.HttpBodyNamedBody))
-	router.Handle("PUT /v1/http/request", http1.HandlerFunc(handler.HttpRequest))
+	registry := options.RouteRegistry()
+	registry.Handle(router, bodyGooseStarBodyEndpoint, "POST "+options.PathPrefix()+"/v1/star/body", http1.HandlerFunc(handler.StarBody))
+	registry.Handle(router, bodyGooseNamedBodyEndpoint, "POST "+options.PathPrefix()+"/v1/named/body", http1.HandlerFunc(handler.NamedBody))
+	registry.Handle(router, bodyGooseNonBodyEndpoint, "GET "+options.PathPrefix()+"/v1/user_body", http1.HandlerFunc(handler.NonBody))
+	registry.Handle(router, bodyGooseHttpBodyStarBodyEndpoint, "PUT "+options.PathPrefix()+"/v1/http/body/star/body", http1.HandlerFunc(handler.HttpBodyStarBody))
+	registry.Handle(router, bodyGooseHttpBodyNamedBodyEndpoint, "PUT "+options.PathPrefix()+"/v1/http/body/named/body", http1.HandlerFunc(handler.HttpBodyNamedBody))
+	registry.Handle(router, bodyGooseHttpRequestEndpoint, "PUT "+options.PathPrefix()+"/v1/http/request", http1.HandlerFunc(handler.HttpRequest))
 	return router
 }
 
@@ -335,6 +384,8 @@ func NewBodyGooseClient(target string, opts ...client.Option) BodyGooseService {
 			target:         target,
 			marshalOptions: options.MarshalOptions(),
 			resolver:       options.Resolver(),
+			registry:       options.ResolverRegistry(),
+			pathPrefix:     options.PathPrefix(),
 		},
 		decoder: bodyGooseResponseDecoder{
 			unmarshalOptions: options.UnmarshalOptions(),
@@ -358,6 +409,7 @@ type bodyGooseClient struct {
 }
 
 func (c *bodyGooseClient) StarBody(ctx context.Context, req *BodyRequest) (*Response, error) {
+	ctx = goose.WithEndpoint(ctx, bodyGooseStarBodyEndpoint)
 	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
 		return nil, err
 	}
@@ -377,6 +429,7 @@ func (c *bodyGooseClient) StarBody(ctx context.Context, req *BodyRequest) (*Resp
 }
 
 func (c *bodyGooseClient) NamedBody(ctx context.Context, req *NamedBodyRequest) (*Response, error) {
+	ctx = goose.WithEndpoint(ctx, bodyGooseNamedBodyEndpoint)
 	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
 		return nil, err
 	}
@@ -396,6 +449,7 @@ func (c *bodyGooseClient) NamedBody(ctx context.Context, req *NamedBodyRequest)
 }
 
 func (c *bodyGooseClient) NonBody(ctx context.Context, req *emptypb.Empty) (*Response, error) {
+	ctx = goose.WithEndpoint(ctx, bodyGooseNonBodyEndpoint)
 	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
 		return nil, err
 	}
@@ -415,6 +469,7 @@ func (c *bodyGooseClient) NonBody(ctx context.Context, req *emptypb.Empty) (*Res
 }
 
 func (c *bodyGooseClient) HttpBodyStarBody(ctx context.Context, req *httpbody.HttpBody) (*Response, error) {
+	ctx = goose.WithEndpoint(ctx, bodyGooseHttpBodyStarBodyEndpoint)
 	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
 		return nil, err
 	}
@@ -434,6 +489,7 @@ func (c *bodyGooseClient) HttpBodyStarBody(ctx context.Context, req *httpbody.Ht
 }
 
 func (c *bodyGooseClient) HttpBodyNamedBody(ctx context.Context, req *HttpBodyRequest) (*Response, error) {
+	ctx = goose.WithEndpoint(ctx, bodyGooseHttpBodyNamedBodyEndpoint)
 	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
 		return nil, err
 	}
@@ -453,6 +509,7 @@ func (c *bodyGooseClient) HttpBodyNamedBody(ctx context.Context, req *HttpBodyRe
 }
 
 func (c *bodyGooseClient) HttpRequest(ctx context.Context, req *http.HttpRequest) (*Response, error) {
+	ctx = goose.WithEndpoint(ctx, bodyGooseHttpRequestEndpoint)
 	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
 		return nil, err
 	}
@@ -475,13 +532,20 @@ type bodyGooseRequestEncoder struct {
 	target         string
 	marshalOptions protojson.MarshalOptions
 	resolver       resolver.Resolver
+	registry       *resolver.Registry
+	pathPrefix     string
+}
+
+func (encoder *bodyGooseRequestEncoder) resolve(ctx context.Context) (*url.URL, error) {
+	return encoder.registry.Resolve(ctx, encoder.resolver, encoder.target)
 }
 
 func (encoder *bodyGooseRequestEncoder) StarBody(ctx context.Context, req *BodyRequest) (*http1.Request, error) {
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
-	target, err := resolver.Resolve(ctx, encoder.resolver, encoder.target)
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -492,6 +556,10 @@ func (encoder *bodyGooseRequestEncoder) StarBody(ctx context.Context, req *BodyR
 		return nil, err
 	}
 	path := "/v1/star/body"
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
+	if err != nil {
+		return nil, err
+	}
 	target.Path = path
 	request, err := http1.NewRequestWithContext(ctx, method, target.String(), &body)
 	if err != nil {
@@ -505,7 +573,8 @@ func (encoder *bodyGooseRequestEncoder) NamedBody(ctx context.Context, req *Name
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
-	target, err := resolver.Resolve(ctx, encoder.resolver, encoder.target)
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -516,6 +585,10 @@ func (encoder *bodyGooseRequestEncoder) NamedBody(ctx context.Context, req *Name
 		return nil, err
 	}
 	path := "/v1/named/body"
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
+	if err != nil {
+		return nil, err
+	}
 	target.Path = path
 	request, err := http1.NewRequestWithContext(ctx, method, target.String(), &body)
 	if err != nil {
@@ -529,7 +602,8 @@ func (encoder *bodyGooseRequestEncoder) NonBody(ctx context.Context, req *emptyp
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
-	target, err := resolver.Resolve(ctx, encoder.resolver, encoder.target)
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -537,6 +611,10 @@ func (encoder *bodyGooseRequestEncoder) NonBody(ctx context.Context, req *emptyp
 	header := http1.Header{}
 	var body bytes.Buffer
 	path := "/v1/user_body"
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
+	if err != nil {
+		return nil, err
+	}
 	target.Path = path
 	request, err := http1.NewRequestWithContext(ctx, method, target.String(), &body)
 	if err != nil {
@@ -550,7 +628,8 @@ func (encoder *bodyGooseRequestEncoder) HttpBodyStarBody(ctx context.Context, re
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
-	target, err := resolver.Resolve(ctx, encoder.resolver, encoder.target)
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -561,6 +640,10 @@ func (encoder *bodyGooseRequestEncoder) HttpBodyStarBody(ctx context.Context, re
 		return nil, err
 	}
 	path := "/v1/http/body/star/body"
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
+	if err != nil {
+		return nil, err
+	}
 	target.Path = path
 	request, err := http1.NewRequestWithContext(ctx, method, target.String(), &body)
 	if err != nil {
@@ -574,7 +657,8 @@ func (encoder *bodyGooseRequestEncoder) HttpBodyNamedBody(ctx context.Context, r
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
-	target, err := resolver.Resolve(ctx, encoder.resolver, encoder.target)
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -585,6 +669,10 @@ func (encoder *bodyGooseRequestEncoder) HttpBodyNamedBody(ctx context.Context, r
 		return nil, err
 	}
 	path := "/v1/http/body/named/body"
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
+	if err != nil {
+		return nil, err
+	}
 	target.Path = path
 	request, err := http1.NewRequestWithContext(ctx, method, target.String(), &body)
 	if err != nil {
@@ -598,7 +686,8 @@ func (encoder *bodyGooseRequestEncoder) HttpRequest(ctx context.Context, req *ht
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
-	target, err := resolver.Resolve(ctx, encoder.resolver, encoder.target)
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -609,6 +698,10 @@ func (encoder *bodyGooseRequestEncoder) HttpRequest(ctx context.Context, req *ht
 		return nil, err
 	}
 	path := "/v1/http/request"
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
+	if err != nil {
+		return nil, err
+	}
 	target.Path = path
 	request, err := http1.NewRequestWithContext(ctx, method, target.String(), &body)
 	if err != nil {
@@ -628,6 +721,9 @@ func (decoder *bodyGooseResponseDecoder) StarBody(ctx context.Context, response
 	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
 		return nil, respErr
 	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
 	resp := &Response{}
 	if err := client.DecodeMessage(ctx, response, resp, decoder.unmarshalOptions); err != nil {
 		return nil, err
@@ -639,6 +735,9 @@ func (decoder *bodyGooseResponseDecoder) NamedBody(ctx context.Context, response
 	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
 		return nil, respErr
 	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
 	resp := &Response{}
 	if err := client.DecodeMessage(ctx, response, resp, decoder.unmarshalOptions); err != nil {
 		return nil, err
@@ -650,6 +749,9 @@ func (decoder *bodyGooseResponseDecoder) NonBody(ctx context.Context, response *
 	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
 		return nil, respErr
 	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
 	resp := &Response{}
 	if err := client.DecodeMessage(ctx, response, resp, decoder.unmarshalOptions); err != nil {
 		return nil, err
@@ -661,6 +763,9 @@ func (decoder *bodyGooseResponseDecoder) HttpBodyStarBody(ctx context.Context, r
 	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
 		return nil, respErr
 	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
 	resp := &Response{}
 	if err := client.DecodeMessage(ctx, response, resp, decoder.unmarshalOptions); err != nil {
 		return nil, err
@@ -672,6 +777,9 @@ func (decoder *bodyGooseResponseDecoder) HttpBodyNamedBody(ctx context.Context,
 	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
 		return nil, respErr
 	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
 	resp := &Response{}
 	if err := client.DecodeMessage(ctx, response, resp, decoder.unmarshalOptions); err != nil {
 		return nil, err
@@ -683,9 +791,176 @@ func (decoder *bodyGooseResponseDecoder) HttpRequest(ctx context.Context, respon
 	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
 		return nil, respErr
 	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
 	resp := &Response{}
 	if err := client.DecodeMessage(ctx, response, resp, decoder.unmarshalOptions); err != nil {
 		return nil, err
 	}
 	return resp, nil
 }
+
+type BodyGooseGrpcClient interface {
+	StarBody(ctx context.Context, req *BodyRequest, opts ...grpc.CallOption) (*Response, error)
+	NamedBody(ctx context.Context, req *NamedBodyRequest, opts ...grpc.CallOption) (*Response, error)
+	NonBody(ctx context.Context, req *emptypb.Empty, opts ...grpc.CallOption) (*Response, error)
+	HttpBodyStarBody(ctx context.Context, req *httpbody.HttpBody, opts ...grpc.CallOption) (*Response, error)
+	HttpBodyNamedBody(ctx context.Context, req *HttpBodyRequest, opts ...grpc.CallOption) (*Response, error)
+	HttpRequest(ctx context.Context, req *http.HttpRequest, opts ...grpc.CallOption) (*Response, error)
+}
+
+func NewBodyGooseGrpcClient(target string, opts ...client.Option) BodyGooseGrpcClient {
+	options := client.NewOptions(opts...)
+	client := &bodyGooseGrpcClient{
+		bodyGooseClient: &bodyGooseClient{
+			client: options.Client(),
+			encoder: bodyGooseRequestEncoder{
+				target:         target,
+				marshalOptions: options.MarshalOptions(),
+				resolver:       options.Resolver(),
+				registry:       options.ResolverRegistry(),
+				pathPrefix:     options.PathPrefix(),
+			},
+			decoder: bodyGooseResponseDecoder{
+				unmarshalOptions: options.UnmarshalOptions(),
+				errorDecoder:     options.ErrorDecoder(),
+				errorFactory:     options.ErrorFactory(),
+			},
+			shouldFailFast:          options.ShouldFailFast(),
+			onValidationErrCallback: options.OnValidationErrCallback(),
+			middleware:              client.Chain(options.Middlewares()...),
+		},
+	}
+	return client
+}
+
+type bodyGooseGrpcClient struct {
+	*bodyGooseClient
+}
+
+func (c *bodyGooseGrpcClient) StarBody(ctx context.Context, req *BodyRequest, opts ...grpc.CallOption) (resp *Response, err error) {
+	ctx = goose.WithEndpoint(ctx, bodyGooseStarBodyEndpoint)
+	var response *http1.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.StarBody(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.StarBody(ctx, response)
+}
+
+func (c *bodyGooseGrpcClient) NamedBody(ctx context.Context, req *NamedBodyRequest, opts ...grpc.CallOption) (resp *Response, err error) {
+	ctx = goose.WithEndpoint(ctx, bodyGooseNamedBodyEndpoint)
+	var response *http1.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.NamedBody(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.NamedBody(ctx, response)
+}
+
+func (c *bodyGooseGrpcClient) NonBody(ctx context.Context, req *emptypb.Empty, opts ...grpc.CallOption) (resp *Response, err error) {
+	ctx = goose.WithEndpoint(ctx, bodyGooseNonBodyEndpoint)
+	var response *http1.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.NonBody(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.NonBody(ctx, response)
+}
+
+func (c *bodyGooseGrpcClient) HttpBodyStarBody(ctx context.Context, req *httpbody.HttpBody, opts ...grpc.CallOption) (resp *Response, err error) {
+	ctx = goose.WithEndpoint(ctx, bodyGooseHttpBodyStarBodyEndpoint)
+	var response *http1.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.HttpBodyStarBody(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.HttpBodyStarBody(ctx, response)
+}
+
+func (c *bodyGooseGrpcClient) HttpBodyNamedBody(ctx context.Context, req *HttpBodyRequest, opts ...grpc.CallOption) (resp *Response, err error) {
+	ctx = goose.WithEndpoint(ctx, bodyGooseHttpBodyNamedBodyEndpoint)
+	var response *http1.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.HttpBodyNamedBody(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.HttpBodyNamedBody(ctx, response)
+}
+
+func (c *bodyGooseGrpcClient) HttpRequest(ctx context.Context, req *http.HttpRequest, opts ...grpc.CallOption) (resp *Response, err error) {
+	ctx = goose.WithEndpoint(ctx, bodyGooseHttpRequestEndpoint)
+	var response *http1.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.HttpRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.HttpRequest(ctx, response)
+}
@@ -0,0 +1,654 @@
+// Code generated by protoc-gen-goose. DO NOT EDIT.
+
+package form
+
+import (
+	bytes "bytes"
+	context "context"
+	errors "errors"
+	goose "github.com/go-leo/goose"
+	client "github.com/go-leo/goose/client"
+	grpcx "github.com/go-leo/goose/client/grpcx"
+	resolver "github.com/go-leo/goose/client/resolver"
+	server "github.com/go-leo/goose/server"
+	grpc "google.golang.org/grpc"
+	protojson "google.golang.org/protobuf/encoding/protojson"
+	wrapperspb "google.golang.org/protobuf/types/known/wrapperspb"
+	http "net/http"
+	url "net/url"
+)
+
+type FormGooseService interface {
+	Register(ctx context.Context, req *RegisterRequest) (*RegisterResponse, error)
+	UploadAvatar(ctx context.Context, req *UploadAvatarRequest) (*UploadAvatarResponse, error)
+	UpdateProfile(ctx context.Context, req *UpdateProfileRequest) (*UpdateProfileResponse, error)
+}
+
+var (
+	formGooseRegisterEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.form.v1.Form/Register",
+		Method:   "POST",
+		Pattern:  "/v1/register",
+		Request:  "leo.goose.example.form.v1.RegisterRequest",
+		Response: "leo.goose.example.form.v1.RegisterResponse",
+	}
+	formGooseUploadAvatarEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.form.v1.Form/UploadAvatar",
+		Method:   "POST",
+		Pattern:  "/v1/users/{id}/avatar",
+		Request:  "leo.goose.example.form.v1.UploadAvatarRequest",
+		Response: "leo.goose.example.form.v1.UploadAvatarResponse",
+	}
+	formGooseUpdateProfileEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.form.v1.Form/UpdateProfile",
+		Method:   "PUT",
+		Pattern:  "/v1/users/{id}/profile",
+		Request:  "leo.goose.example.form.v1.UpdateProfileRequest",
+		Response: "leo.goose.example.form.v1.UpdateProfileResponse",
+	}
+)
+
+func AppendFormGooseRoute[R goose.Router](router R, service FormGooseService, opts ...server.Option) R {
+	options := server.NewOptions(opts...)
+	handler := formGooseHandler{
+		service: service,
+		decoder: formGooseRequestDecoder{
+			unmarshalOptions: options.UnmarshalOptions(),
+		},
+		encoder: formGooseResponseEncoder{
+			marshalOptions:   options.MarshalOptions(),
+			unmarshalOptions: options.UnmarshalOptions(),
+		},
+		errorEncoder:            options.ErrorEncoder(),
+		shouldFailFast:          options.ShouldFailFast(),
+		onValidationErrCallback: options.OnValidationErrCallback(),
+		middleware:              server.Chain(options.Middlewares()...),
+	}
+	registry := options.RouteRegistry()
+	registry.Handle(router, formGooseRegisterEndpoint, "POST "+options.PathPrefix()+"/v1/register", http.HandlerFunc(handler.Register))
+	registry.Handle(router, formGooseUploadAvatarEndpoint, "POST "+options.PathPrefix()+"/v1/users/{id}/avatar", http.HandlerFunc(handler.UploadAvatar))
+	registry.Handle(router, formGooseUpdateProfileEndpoint, "PUT "+options.PathPrefix()+"/v1/users/{id}/profile", http.HandlerFunc(handler.UpdateProfile))
+	return router
+}
+
+type formGooseHandler struct {
+	service                 FormGooseService
+	decoder                 formGooseRequestDecoder
+	encoder                 formGooseResponseEncoder
+	errorEncoder            goose.ErrorEncoder
+	shouldFailFast          bool
+	onValidationErrCallback goose.OnValidationErrCallback
+	middleware              server.Middleware
+}
+
+func (h formGooseHandler) Register(response http.ResponseWriter, request *http.Request) {
+	invoke := func(response http.ResponseWriter, request *http.Request) {
+		ctx := request.Context()
+		req, err := h.decoder.Register(ctx, request)
+		if err != nil {
+			h.errorEncoder(ctx, err, response)
+			return
+		}
+		if err := goose.ValidateRequest(ctx, req, h.shouldFailFast, h.onValidationErrCallback); err != nil {
+			h.errorEncoder(ctx, err, response)
+			return
+		}
+		resp, err := h.service.Register(ctx, req)
+		if err != nil {
+			h.errorEncoder(ctx, err, response)
+			return
+		}
+		if err := h.encoder.Register(ctx, response, resp); err != nil {
+			h.errorEncoder(ctx, err, response)
+			return
+		}
+	}
+	server.Invoke(h.middleware, response, request, invoke)
+}
+
+func (h formGooseHandler) UploadAvatar(response http.ResponseWriter, request *http.Request) {
+	invoke := func(response http.ResponseWriter, request *http.Request) {
+		ctx := request.Context()
+		req, err := h.decoder.UploadAvatar(ctx, request)
+		if err != nil {
+			h.errorEncoder(ctx, err, response)
+			return
+		}
+		if err := goose.ValidateRequest(ctx, req, h.shouldFailFast, h.onValidationErrCallback); err != nil {
+			h.errorEncoder(ctx, err, response)
+			return
+		}
+		resp, err := h.service.UploadAvatar(ctx, req)
+		if err != nil {
+			h.errorEncoder(ctx, err, response)
+			return
+		}
+		if err := h.encoder.UploadAvatar(ctx, response, resp); err != nil {
+			h.errorEncoder(ctx, err, response)
+			return
+		}
+	}
+	server.Invoke(h.middleware, response, request, invoke)
+}
+
+func (h formGooseHandler) UpdateProfile(response http.ResponseWriter, request *http.Request) {
+	invoke := func(response http.ResponseWriter, request *http.Request) {
+		ctx := request.Context()
+		req, err := h.decoder.UpdateProfile(ctx, request)
+		if err != nil {
+			h.errorEncoder(ctx, err, response)
+			return
+		}
+		if err := goose.ValidateRequest(ctx, req, h.shouldFailFast, h.onValidationErrCallback); err != nil {
+			h.errorEncoder(ctx, err, response)
+			return
+		}
+		resp, err := h.service.UpdateProfile(ctx, req)
+		if err != nil {
+			h.errorEncoder(ctx, err, response)
+			return
+		}
+		if err := h.encoder.UpdateProfile(ctx, response, resp); err != nil {
+			h.errorEncoder(ctx, err, response)
+			return
+		}
+	}
+	server.Invoke(h.middleware, response, request, invoke)
+}
+
+type formGooseRequestDecoder struct {
+	unmarshalOptions protojson.UnmarshalOptions
+}
+
+func (decoder formGooseRequestDecoder) Register(ctx context.Context, request *http.Request) (*RegisterRequest, error) {
+	req := &RegisterRequest{}
+	ok, err := server.CustomDecodeRequest(ctx, request, req)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return req, nil
+	}
+	if server.IsFormRequest(request) {
+		form, err := server.DecodeForm(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		defer server.RemoveForm(request)
+		var formErr error
+		req.Name = form.Get("name")
+		req.Age, formErr = goose.GetForm[int32](formErr, form, "age", goose.GetInt)
+		req.Subscribe, formErr = goose.GetForm[*bool](formErr, form, "subscribe", goose.GetBoolPtr)
+		req.Tags = form["tags"]
+		req.Gender, formErr = goose.GetForm[Gender](formErr, form, "gender", goose.GetInt[Gender])
+		req.Score, formErr = goose.GetForm[*wrapperspb.Int64Value](formErr, form, "score", goose.GetInt64Value)
+		req.Note, formErr = server.GetFormFile(formErr, request, "note")
+		if formErr != nil {
+			return nil, formErr
+		}
+	} else {
+		if err := server.DecodeRequest(ctx, request, req, decoder.unmarshalOptions); err != nil {
+			return nil, err
+		}
+	}
+	return req, nil
+}
+func (decoder formGooseRequestDecoder) UploadAvatar(ctx context.Context, request *http.Request) (*UploadAvatarRequest, error) {
+	req := &UploadAvatarRequest{}
+	ok, err := server.CustomDecodeRequest(ctx, request, req)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return req, nil
+	}
+	if server.IsFormRequest(request) {
+		form, err := server.DecodeForm(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		defer server.RemoveForm(request)
+		var formErr error
+		req.Name = form.Get("name")
+		req.Avatar, formErr = server.GetFormHttpBody(formErr, request, "avatar")
+		req.Thumbnail, formErr = server.GetFormFile(formErr, request, "thumbnail")
+		req.Attachments, formErr = server.GetFormFiles(formErr, request, "attachments")
+		if formErr != nil {
+			return nil, formErr
+		}
+	} else {
+		if err := server.DecodeRequest(ctx, request, req, decoder.unmarshalOptions); err != nil {
+			return nil, err
+		}
+	}
+	vars := goose.FormFromPath(request, "id")
+	var varErr error
+	req.Id, varErr = goose.GetForm[int64](varErr, vars, "id", goose.GetInt)
+	if varErr != nil {
+		return nil, varErr
+	}
+	return req, nil
+}
+func (decoder formGooseRequestDecoder) UpdateProfile(ctx context.Context, request *http.Request) (*UpdateProfileRequest, error) {
+	req := &UpdateProfileRequest{}
+	ok, err := server.CustomDecodeRequest(ctx, request, req)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return req, nil
+	}
+	if req.Profile == nil {
+		req.Profile = &Profile{}
+	}
+	if server.IsFormRequest(request) {
+		form, err := server.DecodeForm(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		defer server.RemoveForm(request)
+		var formErr error
+		req.Profile.Nickname = form.Get("nickname")
+		req.Profile.Age, formErr = goose.GetForm[int32](formErr, form, "age", goose.GetInt)
+		if formErr != nil {
+			return nil, formErr
+		}
+	} else {
+		if err := server.DecodeRequest(ctx, request, req.Profile, decoder.unmarshalOptions); err != nil {
+			return nil, err
+		}
+	}
+	vars := goose.FormFromPath(request, "id")
+	var varErr error
+	req.Id, varErr = goose.GetForm[int64](varErr, vars, "id", goose.GetInt)
+	if varErr != nil {
+		return nil, varErr
+	}
+	queries := request.URL.Query()
+	var queryErr error
+	req.Notify, queryErr = goose.GetForm[bool](queryErr, queries, "notify", goose.GetBool)
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	return req, nil
+}
+
+type formGooseResponseEncoder struct {
+	marshalOptions   protojson.MarshalOptions
+	unmarshalOptions protojson.UnmarshalOptions
+}
+
+func (encoder formGooseResponseEncoder) Register(ctx context.Context, w http.ResponseWriter, resp *RegisterResponse) error {
+	return server.EncodeResponse(ctx, w, resp, encoder.marshalOptions)
+}
+func (encoder formGooseResponseEncoder) UploadAvatar(ctx context.Context, w http.ResponseWriter, resp *UploadAvatarResponse) error {
+	return server.EncodeResponse(ctx, w, resp, encoder.marshalOptions)
+}
+func (encoder formGooseResponseEncoder) UpdateProfile(ctx context.Context, w http.ResponseWriter, resp *UpdateProfileResponse) error {
+	return server.EncodeResponse(ctx, w, resp, encoder.marshalOptions)
+}
+
+func NewFormGooseClient(target string, opts ...client.Option) FormGooseService {
+	options := client.NewOptions(opts...)
+	client := &formGooseClient{
+		client: options.Client(),
+		encoder: formGooseRequestEncoder{
+			target:         target,
+			marshalOptions: options.MarshalOptions(),
+			resolver:       options.Resolver(),
+			registry:       options.ResolverRegistry(),
+			pathPrefix:     options.PathPrefix(),
+		},
+		decoder: formGooseResponseDecoder{
+			unmarshalOptions: options.UnmarshalOptions(),
+			errorDecoder:     options.ErrorDecoder(),
+			errorFactory:     options.ErrorFactory(),
+		},
+		shouldFailFast:          options.ShouldFailFast(),
+		onValidationErrCallback: options.OnValidationErrCallback(),
+		middleware:              client.Chain(options.Middlewares()...),
+	}
+	return client
+}
+
+type formGooseClient struct {
+	client                  *http.Client
+	encoder                 formGooseRequestEncoder
+	decoder                 formGooseResponseDecoder
+	shouldFailFast          bool
+	onValidationErrCallback goose.OnValidationErrCallback
+	middleware              client.Middleware
+}
+
+func (c *formGooseClient) Register(ctx context.Context, req *RegisterRequest) (*RegisterResponse, error) {
+	ctx = goose.WithEndpoint(ctx, formGooseRegisterEndpoint)
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.Register(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	response, err := client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.decoder.Register(ctx, response)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *formGooseClient) UploadAvatar(ctx context.Context, req *UploadAvatarRequest) (*UploadAvatarResponse, error) {
+	ctx = goose.WithEndpoint(ctx, formGooseUploadAvatarEndpoint)
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.UploadAvatar(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	response, err := client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.decoder.UploadAvatar(ctx, response)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *formGooseClient) UpdateProfile(ctx context.Context, req *UpdateProfileRequest) (*UpdateProfileResponse, error) {
+	ctx = goose.WithEndpoint(ctx, formGooseUpdateProfileEndpoint)
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.UpdateProfile(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	response, err := client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.decoder.UpdateProfile(ctx, response)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+type formGooseRequestEncoder struct {
+	target         string
+	marshalOptions protojson.MarshalOptions
+	resolver       resolver.Resolver
+	registry       *resolver.Registry
+	pathPrefix     string
+}
+
+func (encoder *formGooseRequestEncoder) resolve(ctx context.Context) (*url.URL, error) {
+	return encoder.registry.Resolve(ctx, encoder.resolver, encoder.target)
+}
+
+func (encoder *formGooseRequestEncoder) Register(ctx context.Context, req *RegisterRequest) (*http.Request, error) {
+	if req == nil {
+		return nil, errors.New("request is nil")
+	}
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	method := "POST"
+	header := http.Header{}
+	var body bytes.Buffer
+	form := url.Values{}
+	form["name"] = append(form["name"], req.GetName())
+	form["age"] = append(form["age"], goose.FormatInt(req.GetAge(), 10))
+	form["subscribe"] = append(form["subscribe"], goose.FormatBool(req.GetSubscribe()))
+	form["tags"] = append(form["tags"], req.GetTags()...)
+	form["gender"] = append(form["gender"], goose.FormatInt(req.GetGender(), 10))
+	form["score"] = append(form["score"], goose.FormatInt(req.GetScore().GetValue(), 10))
+	form["note"] = append(form["note"], string(req.GetNote()))
+	if err := client.EncodeForm(ctx, form, header, &body); err != nil {
+		return nil, err
+	}
+	path := "/v1/register"
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
+	if err != nil {
+		return nil, err
+	}
+	target.Path = path
+	request, err := http.NewRequestWithContext(ctx, method, target.String(), &body)
+	if err != nil {
+		return nil, err
+	}
+	goose.CopyHeader(request.Header, header)
+	return request, nil
+}
+
+func (encoder *formGooseRequestEncoder) UploadAvatar(ctx context.Context, req *UploadAvatarRequest) (*http.Request, error) {
+	if req == nil {
+		return nil, errors.New("request is nil")
+	}
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	method := "POST"
+	header := http.Header{}
+	var body bytes.Buffer
+	form := url.Values{}
+	form["name"] = append(form["name"], req.GetName())
+	files := client.FormFiles{}
+	files.AddHttpBody("avatar", req.GetAvatar())
+	files.AddBytes("thumbnail", req.GetThumbnail())
+	files.AddBytes("attachments", req.GetAttachments()...)
+	if err := client.EncodeMultipartForm(ctx, form, files, header, &body); err != nil {
+		return nil, err
+	}
+	path := "/v1/users/{id}/avatar"
+	pairs := map[string]string{
+		"id": goose.FormatInt(req.GetId(), 10),
+	}
+	path = goose.URLPath(path, pairs)
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
+	if err != nil {
+		return nil, err
+	}
+	target.Path = path
+	request, err := http.NewRequestWithContext(ctx, method, target.String(), &body)
+	if err != nil {
+		return nil, err
+	}
+	goose.CopyHeader(request.Header, header)
+	return request, nil
+}
+
+func (encoder *formGooseRequestEncoder) UpdateProfile(ctx context.Context, req *UpdateProfileRequest) (*http.Request, error) {
+	if req == nil {
+		return nil, errors.New("request is nil")
+	}
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	method := "PUT"
+	header := http.Header{}
+	var body bytes.Buffer
+	form := url.Values{}
+	form["nickname"] = append(form["nickname"], req.GetProfile().GetNickname())
+	form["age"] = append(form["age"], goose.FormatInt(req.GetProfile().GetAge(), 10))
+	if err := client.EncodeForm(ctx, form, header, &body); err != nil {
+		return nil, err
+	}
+	path := "/v1/users/{id}/profile"
+	pairs := map[string]string{
+		"id": goose.FormatInt(req.GetId(), 10),
+	}
+	path = goose.URLPath(path, pairs)
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
+	if err != nil {
+		return nil, err
+	}
+	target.Path = path
+	queries := url.Values{}
+	queries["notify"] = append(queries["notify"], goose.FormatBool(req.GetNotify()))
+	target.RawQuery = queries.Encode()
+	request, err := http.NewRequestWithContext(ctx, method, target.String(), &body)
+	if err != nil {
+		return nil, err
+	}
+	goose.CopyHeader(request.Header, header)
+	return request, nil
+}
+
+type formGooseResponseDecoder struct {
+	unmarshalOptions protojson.UnmarshalOptions
+	errorDecoder     goose.ErrorDecoder
+	errorFactory     goose.ErrorFactory
+}
+
+func (decoder *formGooseResponseDecoder) Register(ctx context.Context, response *http.Response) (*RegisterResponse, error) {
+	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
+		return nil, respErr
+	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
+	resp := &RegisterResponse{}
+	if err := client.DecodeMessage(ctx, response, resp, decoder.unmarshalOptions); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (decoder *formGooseResponseDecoder) UploadAvatar(ctx context.Context, response *http.Response) (*UploadAvatarResponse, error) {
+	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
+		return nil, respErr
+	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
+	resp := &UploadAvatarResponse{}
+	if err := client.DecodeMessage(ctx, response, resp, decoder.unmarshalOptions); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (decoder *formGooseResponseDecoder) UpdateProfile(ctx context.Context, response *http.Response) (*UpdateProfileResponse, error) {
+	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
+		return nil, respErr
+	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
+	resp := &UpdateProfileResponse{}
+	if err := client.DecodeMessage(ctx, response, resp, decoder.unmarshalOptions); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+type FormGooseGrpcClient interface {
+	Register(ctx context.Context, req *RegisterRequest, opts ...grpc.CallOption) (*RegisterResponse, error)
+	UploadAvatar(ctx context.Context, req *UploadAvatarRequest, opts ...grpc.CallOption) (*UploadAvatarResponse, error)
+	UpdateProfile(ctx context.Context, req *UpdateProfileRequest, opts ...grpc.CallOption) (*UpdateProfileResponse, error)
+}
+
+func NewFormGooseGrpcClient(target string, opts ...client.Option) FormGooseGrpcClient {
+	options := client.NewOptions(opts...)
+	client := &formGooseGrpcClient{
+		formGooseClient: &formGooseClient{
+			client: options.Client(),
+			encoder: formGooseRequestEncoder{
+				target:         target,
+				marshalOptions: options.MarshalOptions(),
+				resolver:       options.Resolver(),
+				registry:       options.ResolverRegistry(),
+				pathPrefix:     options.PathPrefix(),
+			},
+			decoder: formGooseResponseDecoder{
+				unmarshalOptions: options.UnmarshalOptions(),
+				errorDecoder:     options.ErrorDecoder(),
+				errorFactory:     options.ErrorFactory(),
+			},
+			shouldFailFast:          options.ShouldFailFast(),
+			onValidationErrCallback: options.OnValidationErrCallback(),
+			middleware:              client.Chain(options.Middlewares()...),
+		},
+	}
+	return client
+}
+
+type formGooseGrpcClient struct {
+	*formGooseClient
+}
+
+func (c *formGooseGrpcClient) Register(ctx context.Context, req *RegisterRequest, opts ...grpc.CallOption) (resp *RegisterResponse, err error) {
+	ctx = goose.WithEndpoint(ctx, formGooseRegisterEndpoint)
+	var response *http.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.Register(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.Register(ctx, response)
+}
+
+func (c *formGooseGrpcClient) UploadAvatar(ctx context.Context, req *UploadAvatarRequest, opts ...grpc.CallOption) (resp *UploadAvatarResponse, err error) {
+	ctx = goose.WithEndpoint(ctx, formGooseUploadAvatarEndpoint)
+	var response *http.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.UploadAvatar(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.UploadAvatar(ctx, response)
+}
+
+func (c *formGooseGrpcClient) UpdateProfile(ctx context.Context, req *UpdateProfileRequest, opts ...grpc.CallOption) (resp *UpdateProfileResponse, err error) {
+	ctx = goose.WithEndpoint(ctx, formGooseUpdateProfileEndpoint)
+	var response *http.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.UpdateProfile(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.UpdateProfile(ctx, response)
+}
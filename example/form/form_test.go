@@ -0,0 +1,215 @@
+package form
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/api/httpbody"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// ---- Mock Service ----
+
+type MockFormService struct{}
+
+func (m *MockFormService) Register(ctx context.Context, req *RegisterRequest) (*RegisterResponse, error) {
+	return &RegisterResponse{Request: req}, nil
+}
+
+func (m *MockFormService) UploadAvatar(ctx context.Context, req *UploadAvatarRequest) (*UploadAvatarResponse, error) {
+	return &UploadAvatarResponse{
+		Id:                req.GetId(),
+		Name:              req.GetName(),
+		AvatarContentType: req.GetAvatar().GetContentType(),
+		AvatarSize:        int64(len(req.GetAvatar().GetData())),
+		ThumbnailSize:     int64(len(req.GetThumbnail())),
+		AttachmentCount:   int32(len(req.GetAttachments())),
+	}, nil
+}
+
+func (m *MockFormService) UpdateProfile(ctx context.Context, req *UpdateProfileRequest) (*UpdateProfileResponse, error) {
+	return &UpdateProfileResponse{Id: req.GetId(), Profile: req.GetProfile(), Notify: req.GetNotify()}, nil
+}
+
+func runServer(server *http.Server, port int) {
+	router := http.NewServeMux()
+	router = AppendFormGooseRoute(router, &MockFormService{})
+	server.Addr = fmt.Sprintf(":%d", port)
+	server.Handler = router
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		panic(err)
+	}
+}
+
+func newClient(port int) FormGooseService {
+	return NewFormGooseClient(fmt.Sprintf("http://localhost:%d", port))
+}
+
+// ---- Test Cases ----
+
+func TestRegister(t *testing.T) {
+	server := new(http.Server)
+	defer server.Shutdown(context.Background())
+	go runServer(server, 58084)
+	time.Sleep(1 * time.Second)
+
+	req := &RegisterRequest{
+		Name:      "jax",
+		Age:       18,
+		Subscribe: proto.Bool(true),
+		Tags:      []string{"a", "b"},
+		Gender:    Gender_GENDER_FEMALE,
+		Score:     wrapperspb.Int64(99),
+		Note:      []byte("hello"),
+	}
+	resp, err := newClient(58084).Register(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(resp.GetRequest(), req) {
+		t.Fatalf("request = %v, want %v", resp.GetRequest(), req)
+	}
+}
+
+func TestRegisterBrowserForm(t *testing.T) {
+	server := new(http.Server)
+	defer server.Shutdown(context.Background())
+	go runServer(server, 58085)
+	time.Sleep(1 * time.Second)
+
+	// a browser form only sends the fields it has
+	response, err := http.PostForm("http://localhost:58085/v1/register", url.Values{
+		"name": {"jax"},
+		"tags": {"a", "b"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	resp := &RegisterResponse{}
+	if err := decodeResponse(response, resp); err != nil {
+		t.Fatal(err)
+	}
+	// like queries, absent optional and wrapper fields are set to their zero value
+	want := &RegisterRequest{Name: "jax", Tags: []string{"a", "b"}, Subscribe: proto.Bool(false), Score: wrapperspb.Int64(0)}
+	if !proto.Equal(resp.GetRequest(), want) {
+		t.Fatalf("request = %v, want %v", resp.GetRequest(), want)
+	}
+
+	// JSON is still accepted
+	response, err = http.Post("http://localhost:58085/v1/register", "application/json", strings.NewReader(`{"name":"jax","age":18}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	resp = &RegisterResponse{}
+	if err := decodeResponse(response, resp); err != nil {
+		t.Fatal(err)
+	}
+	want = &RegisterRequest{Name: "jax", Age: 18}
+	if !proto.Equal(resp.GetRequest(), want) {
+		t.Fatalf("request = %v, want %v", resp.GetRequest(), want)
+	}
+
+	// invalid values are rejected
+	response, err = http.PostForm("http://localhost:58085/v1/register", url.Values{"age": {"old"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode == http.StatusOK {
+		t.Fatalf("status = %d, want an error", response.StatusCode)
+	}
+}
+
+func TestUploadAvatar(t *testing.T) {
+	server := new(http.Server)
+	defer server.Shutdown(context.Background())
+	go runServer(server, 58086)
+	time.Sleep(1 * time.Second)
+
+	resp, err := newClient(58086).UploadAvatar(context.Background(), &UploadAvatarRequest{
+		Id:          1,
+		Name:        "avatar.png",
+		Avatar:      &httpbody.HttpBody{ContentType: "image/png", Data: bytes.Repeat([]byte{0x89}, 1024)},
+		Thumbnail:   bytes.Repeat([]byte{0x89}, 64),
+		Attachments: [][]byte{[]byte("a"), []byte("b"), []byte("c")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &UploadAvatarResponse{
+		Id:                1,
+		Name:              "avatar.png",
+		AvatarContentType: "image/png",
+		AvatarSize:        1024,
+		ThumbnailSize:     64,
+		AttachmentCount:   3,
+	}
+	if !proto.Equal(resp, want) {
+		t.Fatalf("response = %v, want %v", resp, want)
+	}
+
+	// a browser upload
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	_ = writer.WriteField("name", "browser")
+	part, _ := writer.CreateFormFile("thumbnail", "thumbnail.png")
+	_, _ = part.Write([]byte("png"))
+	_ = writer.Close()
+	response, err := http.Post("http://localhost:58086/v1/users/2/avatar", writer.FormDataContentType(), &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	resp = &UploadAvatarResponse{}
+	if err := decodeResponse(response, resp); err != nil {
+		t.Fatal(err)
+	}
+	want = &UploadAvatarResponse{Id: 2, Name: "browser", ThumbnailSize: 3}
+	if !proto.Equal(resp, want) {
+		t.Fatalf("response = %v, want %v", resp, want)
+	}
+}
+
+func TestUpdateProfile(t *testing.T) {
+	server := new(http.Server)
+	defer server.Shutdown(context.Background())
+	go runServer(server, 58087)
+	time.Sleep(1 * time.Second)
+
+	resp, err := newClient(58087).UpdateProfile(context.Background(), &UpdateProfileRequest{
+		Id:      3,
+		Profile: &Profile{Nickname: "jax", Age: 18},
+		Notify:  true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &UpdateProfileResponse{Id: 3, Profile: &Profile{Nickname: "jax", Age: 18}, Notify: true}
+	if !proto.Equal(resp, want) {
+		t.Fatalf("response = %v, want %v", resp, want)
+	}
+}
+
+func decodeResponse(response *http.Response, resp proto.Message) error {
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("status = %d, body = %s", response.StatusCode, data)
+	}
+	return protojson.Unmarshal(data, resp)
+}
@@ -0,0 +1,24 @@
+module github.com/go-leo/goose/example
+
+go 1.23.0
+
+require (
+	github.com/go-leo/goose v1.6.11
+	github.com/go-leo/goose/client/grpcx v1.6.11
+	google.golang.org/genproto/googleapis/api v0.0.0-20250825161204-c5933d9347a5
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250825161204-c5933d9347a5
+	google.golang.org/grpc v1.75.1
+	google.golang.org/protobuf v1.36.10
+)
+
+require (
+	golang.org/x/exp v0.0.0-20250811191247-51f88131bc50 // indirect
+	golang.org/x/net v0.43.0 // indirect
+	golang.org/x/sys v0.35.0 // indirect
+	golang.org/x/text v0.28.0 // indirect
+)
+
+replace (
+	github.com/go-leo/goose => ../
+	github.com/go-leo/goose/client/grpcx => ../client/grpcx
+)
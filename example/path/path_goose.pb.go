@@ -8,9 +8,11 @@ import (
 	errors "errors"
 	goose "github.com/go-leo/goose"
 	client "github.com/go-leo/goose/client"
+	grpcx "github.com/go-leo/goose/client/grpcx"
 	resolver "github.com/go-leo/goose/client/resolver"
 	server "github.com/go-leo/goose/server"
 	httpbody "google.golang.org/genproto/googleapis/api/httpbody"
+	grpc "google.golang.org/grpc"
 	protojson "google.golang.org/protobuf/encoding/protojson"
 	proto "google.golang.org/protobuf/proto"
 	wrapperspb "google.golang.org/protobuf/types/known/wrapperspb"
@@ -22,7 +24,17 @@ type BoolPathGooseService interface {
 	BoolPath(ctx context.Context, req *BoolPathRequest) (*httpbody.HttpBody, error)
 }
 
-func AppendBoolPathGooseRoute(router *http.ServeMux, service BoolPathGooseService, opts ...server.Option) *http.ServeMux {
+var (
+	boolPathGooseBoolPathEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.path.v1.BoolPath/BoolPath",
+		Method:   "GET",
+		Pattern:  "/v1/{bool}/{opt_bool}/{wrap_bool}",
+		Request:  "leo.goose.example.path.v1.BoolPathRequest",
+		Response: "google.api.HttpBody",
+	}
+)
+
+func AppendBoolPathGooseRoute[R goose.Router](router R, service BoolPathGooseService, opts ...server.Option) R {
 	options := server.NewOptions(opts...)
 	handler := boolPathGooseHandler{
 		service: service,
@@ -38,7 +50,8 @@ func AppendBoolPathGooseRoute(router *http.ServeMux, service BoolPathGooseServic
 		onValidationErrCallback: options.OnValidationErrCallback(),
 		middleware:              server.Chain(options.Middlewares()...),
 	}
-	router.Handle("GET /v1/{bool}/{opt_bool}/{wrap_bool}", http.HandlerFunc(handler.BoolPath))
+	registry := options.RouteRegistry()
+	registry.Handle(router, boolPathGooseBoolPathEndpoint, "GET "+options.PathPrefix()+"/v1/{bool}/{opt_bool}/{wrap_bool}", http.HandlerFunc(handler.BoolPath))
 	return router
 }
 
@@ -118,6 +131,8 @@ func NewBoolPathGooseClient(target string, opts ...client.Option) BoolPathGooseS
 			target:         target,
 			marshalOptions: options.MarshalOptions(),
 			resolver:       options.Resolver(),
+			registry:       options.ResolverRegistry(),
+			pathPrefix:     options.PathPrefix(),
 		},
 		decoder: boolPathGooseResponseDecoder{
 			unmarshalOptions: options.UnmarshalOptions(),
@@ -141,6 +156,7 @@ type boolPathGooseClient struct {
 }
 
 func (c *boolPathGooseClient) BoolPath(ctx context.Context, req *BoolPathRequest) (*httpbody.HttpBody, error) {
+	ctx = goose.WithEndpoint(ctx, boolPathGooseBoolPathEndpoint)
 	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
 		return nil, err
 	}
@@ -163,13 +179,20 @@ type boolPathGooseRequestEncoder struct {
 	target         string
 	marshalOptions protojson.MarshalOptions
 	resolver       resolver.Resolver
+	registry       *resolver.Registry
+	pathPrefix     string
+}
+
+func (encoder *boolPathGooseRequestEncoder) resolve(ctx context.Context) (*url.URL, error) {
+	return encoder.registry.Resolve(ctx, encoder.resolver, encoder.target)
 }
 
 func (encoder *boolPathGooseRequestEncoder) BoolPath(ctx context.Context, req *BoolPathRequest) (*http.Request, error) {
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
-	target, err := resolver.Resolve(ctx, encoder.resolver, encoder.target)
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -183,7 +206,7 @@ func (encoder *boolPathGooseRequestEncoder) BoolPath(ctx context.Context, req *B
 		"wrap_bool": goose.FormatBool(req.GetWrapBool().GetValue()),
 	}
 	path = goose.URLPath(path, pairs)
-	path, err = url.JoinPath(target.Path, path)
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
 	if err != nil {
 		return nil, err
 	}
@@ -206,6 +229,9 @@ func (decoder *boolPathGooseResponseDecoder) BoolPath(ctx context.Context, respo
 	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
 		return nil, respErr
 	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
 	resp := &httpbody.HttpBody{}
 	if err := client.DecodeHttpBody(ctx, response, resp); err != nil {
 		return nil, err
@@ -213,11 +239,75 @@ func (decoder *boolPathGooseResponseDecoder) BoolPath(ctx context.Context, respo
 	return resp, nil
 }
 
+type BoolPathGooseGrpcClient interface {
+	BoolPath(ctx context.Context, req *BoolPathRequest, opts ...grpc.CallOption) (*httpbody.HttpBody, error)
+}
+
+func NewBoolPathGooseGrpcClient(target string, opts ...client.Option) BoolPathGooseGrpcClient {
+	options := client.NewOptions(opts...)
+	client := &boolPathGooseGrpcClient{
+		boolPathGooseClient: &boolPathGooseClient{
+			client: options.Client(),
+			encoder: boolPathGooseRequestEncoder{
+				target:         target,
+				marshalOptions: options.MarshalOptions(),
+				resolver:       options.Resolver(),
+				registry:       options.ResolverRegistry(),
+				pathPrefix:     options.PathPrefix(),
+			},
+			decoder: boolPathGooseResponseDecoder{
+				unmarshalOptions: options.UnmarshalOptions(),
+				errorDecoder:     options.ErrorDecoder(),
+				errorFactory:     options.ErrorFactory(),
+			},
+			shouldFailFast:          options.ShouldFailFast(),
+			onValidationErrCallback: options.OnValidationErrCallback(),
+			middleware:              client.Chain(options.Middlewares()...),
+		},
+	}
+	return client
+}
+
+type boolPathGooseGrpcClient struct {
+	*boolPathGooseClient
+}
+
+func (c *boolPathGooseGrpcClient) BoolPath(ctx context.Context, req *BoolPathRequest, opts ...grpc.CallOption) (resp *httpbody.HttpBody, err error) {
+	ctx = goose.WithEndpoint(ctx, boolPathGooseBoolPathEndpoint)
+	var response *http.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.BoolPath(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.BoolPath(ctx, response)
+}
+
 type Int32PathGooseService interface {
 	Int32Path(ctx context.Context, req *Int32PathRequest) (*httpbody.HttpBody, error)
 }
 
-func AppendInt32PathGooseRoute(router *http.ServeMux, service Int32PathGooseService, opts ...server.Option) *http.ServeMux {
+var (
+	int32PathGooseInt32PathEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.path.v1.Int32Path/Int32Path",
+		Method:   "GET",
+		Pattern:  "/v1/{int32}/{sint32}/{sfixed32}/{opt_int32}/{opt_sint32}/{opt_sfixed32}/{wrap_int32}",
+		Request:  "leo.goose.example.path.v1.Int32PathRequest",
+		Response: "google.api.HttpBody",
+	}
+)
+
+func AppendInt32PathGooseRoute[R goose.Router](router R, service Int32PathGooseService, opts ...server.Option) R {
 	options := server.NewOptions(opts...)
 	handler := int32PathGooseHandler{
 		service: service,
@@ -233,7 +323,8 @@ func AppendInt32PathGooseRoute(router *http.ServeMux, service Int32PathGooseServ
 		onValidationErrCallback: options.OnValidationErrCallback(),
 		middleware:              server.Chain(options.Middlewares()...),
 	}
-	router.Handle("GET /v1/{int32}/{sint32}/{sfixed32}/{opt_int32}/{opt_sint32}/{opt_sfixed32}/{wrap_int32}", http.HandlerFunc(handler.Int32Path))
+	registry := options.RouteRegistry()
+	registry.Handle(router, int32PathGooseInt32PathEndpoint, "GET "+options.PathPrefix()+"/v1/{int32}/{sint32}/{sfixed32}/{opt_int32}/{opt_sint32}/{opt_sfixed32}/{wrap_int32}", http.HandlerFunc(handler.Int32Path))
 	return router
 }
 
@@ -317,6 +408,8 @@ func NewInt32PathGooseClient(target string, opts ...client.Option) Int32PathGoos
 			target:         target,
 			marshalOptions: options.MarshalOptions(),
 			resolver:       options.Resolver(),
+			registry:       options.ResolverRegistry(),
+			pathPrefix:     options.PathPrefix(),
 		},
 		decoder: int32PathGooseResponseDecoder{
 			unmarshalOptions: options.UnmarshalOptions(),
@@ -340,6 +433,7 @@ type int32PathGooseClient struct {
 }
 
 func (c *int32PathGooseClient) Int32Path(ctx context.Context, req *Int32PathRequest) (*httpbody.HttpBody, error) {
+	ctx = goose.WithEndpoint(ctx, int32PathGooseInt32PathEndpoint)
 	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
 		return nil, err
 	}
@@ -362,13 +456,20 @@ type int32PathGooseRequestEncoder struct {
 	target         string
 	marshalOptions protojson.MarshalOptions
 	resolver       resolver.Resolver
+	registry       *resolver.Registry
+	pathPrefix     string
+}
+
+func (encoder *int32PathGooseRequestEncoder) resolve(ctx context.Context) (*url.URL, error) {
+	return encoder.registry.Resolve(ctx, encoder.resolver, encoder.target)
 }
 
 func (encoder *int32PathGooseRequestEncoder) Int32Path(ctx context.Context, req *Int32PathRequest) (*http.Request, error) {
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
-	target, err := resolver.Resolve(ctx, encoder.resolver, encoder.target)
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -386,7 +487,7 @@ func (encoder *int32PathGooseRequestEncoder) Int32Path(ctx context.Context, req
 		"wrap_int32":   goose.FormatInt(req.GetWrapInt32().GetValue(), 10),
 	}
 	path = goose.URLPath(path, pairs)
-	path, err = url.JoinPath(target.Path, path)
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
 	if err != nil {
 		return nil, err
 	}
@@ -409,6 +510,9 @@ func (decoder *int32PathGooseResponseDecoder) Int32Path(ctx context.Context, res
 	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
 		return nil, respErr
 	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
 	resp := &httpbody.HttpBody{}
 	if err := client.DecodeHttpBody(ctx, response, resp); err != nil {
 		return nil, err
@@ -416,11 +520,75 @@ func (decoder *int32PathGooseResponseDecoder) Int32Path(ctx context.Context, res
 	return resp, nil
 }
 
+type Int32PathGooseGrpcClient interface {
+	Int32Path(ctx context.Context, req *Int32PathRequest, opts ...grpc.CallOption) (*httpbody.HttpBody, error)
+}
+
+func NewInt32PathGooseGrpcClient(target string, opts ...client.Option) Int32PathGooseGrpcClient {
+	options := client.NewOptions(opts...)
+	client := &int32PathGooseGrpcClient{
+		int32PathGooseClient: &int32PathGooseClient{
+			client: options.Client(),
+			encoder: int32PathGooseRequestEncoder{
+				target:         target,
+				marshalOptions: options.MarshalOptions(),
+				resolver:       options.Resolver(),
+				registry:       options.ResolverRegistry(),
+				pathPrefix:     options.PathPrefix(),
+			},
+			decoder: int32PathGooseResponseDecoder{
+				unmarshalOptions: options.UnmarshalOptions(),
+				errorDecoder:     options.ErrorDecoder(),
+				errorFactory:     options.ErrorFactory(),
+			},
+			shouldFailFast:          options.ShouldFailFast(),
+			onValidationErrCallback: options.OnValidationErrCallback(),
+			middleware:              client.Chain(options.Middlewares()...),
+		},
+	}
+	return client
+}
+
+type int32PathGooseGrpcClient struct {
+	*int32PathGooseClient
+}
+
+func (c *int32PathGooseGrpcClient) Int32Path(ctx context.Context, req *Int32PathRequest, opts ...grpc.CallOption) (resp *httpbody.HttpBody, err error) {
+	ctx = goose.WithEndpoint(ctx, int32PathGooseInt32PathEndpoint)
+	var response *http.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.Int32Path(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.Int32Path(ctx, response)
+}
+
 type Int64PathGooseService interface {
 	Int64Path(ctx context.Context, req *Int64PathRequest) (*httpbody.HttpBody, error)
 }
 
-func AppendInt64PathGooseRoute(router *http.ServeMux, service Int64PathGooseService, opts ...server.Option) *http.ServeMux {
+var (
+	int64PathGooseInt64PathEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.path.v1.Int64Path/Int64Path",
+		Method:   "GET",
+		Pattern:  "/v1/{int64}/{sint64}/{sfixed64}/{opt_int64}/{opt_sint64}/{opt_sfixed64}/{wrap_int64}",
+		Request:  "leo.goose.example.path.v1.Int64PathRequest",
+		Response: "google.api.HttpBody",
+	}
+)
+
+func AppendInt64PathGooseRoute[R goose.Router](router R, service Int64PathGooseService, opts ...server.Option) R {
 	options := server.NewOptions(opts...)
 	handler := int64PathGooseHandler{
 		service: service,
@@ -436,7 +604,8 @@ func AppendInt64PathGooseRoute(router *http.ServeMux, service Int64PathGooseServ
 		onValidationErrCallback: options.OnValidationErrCallback(),
 		middleware:              server.Chain(options.Middlewares()...),
 	}
-	router.Handle("GET /v1/{int64}/{sint64}/{sfixed64}/{opt_int64}/{opt_sint64}/{opt_sfixed64}/{wrap_int64}", http.HandlerFunc(handler.Int64Path))
+	registry := options.RouteRegistry()
+	registry.Handle(router, int64PathGooseInt64PathEndpoint, "GET "+options.PathPrefix()+"/v1/{int64}/{sint64}/{sfixed64}/{opt_int64}/{opt_sint64}/{opt_sfixed64}/{wrap_int64}", http.HandlerFunc(handler.Int64Path))
 	return router
 }
 
@@ -520,6 +689,8 @@ func NewInt64PathGooseClient(target string, opts ...client.Option) Int64PathGoos
 			target:         target,
 			marshalOptions: options.MarshalOptions(),
 			resolver:       options.Resolver(),
+			registry:       options.ResolverRegistry(),
+			pathPrefix:     options.PathPrefix(),
 		},
 		decoder: int64PathGooseResponseDecoder{
 			unmarshalOptions: options.UnmarshalOptions(),
@@ -543,6 +714,7 @@ type int64PathGooseClient struct {
 }
 
 func (c *int64PathGooseClient) Int64Path(ctx context.Context, req *Int64PathRequest) (*httpbody.HttpBody, error) {
+	ctx = goose.WithEndpoint(ctx, int64PathGooseInt64PathEndpoint)
 	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
 		return nil, err
 	}
@@ -565,13 +737,20 @@ type int64PathGooseRequestEncoder struct {
 	target         string
 	marshalOptions protojson.MarshalOptions
 	resolver       resolver.Resolver
+	registry       *resolver.Registry
+	pathPrefix     string
+}
+
+func (encoder *int64PathGooseRequestEncoder) resolve(ctx context.Context) (*url.URL, error) {
+	return encoder.registry.Resolve(ctx, encoder.resolver, encoder.target)
 }
 
 func (encoder *int64PathGooseRequestEncoder) Int64Path(ctx context.Context, req *Int64PathRequest) (*http.Request, error) {
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
-	target, err := resolver.Resolve(ctx, encoder.resolver, encoder.target)
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -589,7 +768,7 @@ func (encoder *int64PathGooseRequestEncoder) Int64Path(ctx context.Context, req
 		"wrap_int64":   goose.FormatInt(req.GetWrapInt64().GetValue(), 10),
 	}
 	path = goose.URLPath(path, pairs)
-	path, err = url.JoinPath(target.Path, path)
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
 	if err != nil {
 		return nil, err
 	}
@@ -612,6 +791,9 @@ func (decoder *int64PathGooseResponseDecoder) Int64Path(ctx context.Context, res
 	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
 		return nil, respErr
 	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
 	resp := &httpbody.HttpBody{}
 	if err := client.DecodeHttpBody(ctx, response, resp); err != nil {
 		return nil, err
@@ -619,11 +801,75 @@ func (decoder *int64PathGooseResponseDecoder) Int64Path(ctx context.Context, res
 	return resp, nil
 }
 
+type Int64PathGooseGrpcClient interface {
+	Int64Path(ctx context.Context, req *Int64PathRequest, opts ...grpc.CallOption) (*httpbody.HttpBody, error)
+}
+
+func NewInt64PathGooseGrpcClient(target string, opts ...client.Option) Int64PathGooseGrpcClient {
+	options := client.NewOptions(opts...)
+	client := &int64PathGooseGrpcClient{
+		int64PathGooseClient: &int64PathGooseClient{
+			client: options.Client(),
+			encoder: int64PathGooseRequestEncoder{
+				target:         target,
+				marshalOptions: options.MarshalOptions(),
+				resolver:       options.Resolver(),
+				registry:       options.ResolverRegistry(),
+				pathPrefix:     options.PathPrefix(),
+			},
+			decoder: int64PathGooseResponseDecoder{
+				unmarshalOptions: options.UnmarshalOptions(),
+				errorDecoder:     options.ErrorDecoder(),
+				errorFactory:     options.ErrorFactory(),
+			},
+			shouldFailFast:          options.ShouldFailFast(),
+			onValidationErrCallback: options.OnValidationErrCallback(),
+			middleware:              client.Chain(options.Middlewares()...),
+		},
+	}
+	return client
+}
+
+type int64PathGooseGrpcClient struct {
+	*int64PathGooseClient
+}
+
+func (c *int64PathGooseGrpcClient) Int64Path(ctx context.Context, req *Int64PathRequest, opts ...grpc.CallOption) (resp *httpbody.HttpBody, err error) {
+	ctx = goose.WithEndpoint(ctx, int64PathGooseInt64PathEndpoint)
+	var response *http.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.Int64Path(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.Int64Path(ctx, response)
+}
+
 type Uint32PathGooseService interface {
 	Uint32Path(ctx context.Context, req *Uint32PathRequest) (*httpbody.HttpBody, error)
 }
 
-func AppendUint32PathGooseRoute(router *http.ServeMux, service Uint32PathGooseService, opts ...server.Option) *http.ServeMux {
+var (
+	uint32PathGooseUint32PathEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.path.v1.Uint32Path/Uint32Path",
+		Method:   "GET",
+		Pattern:  "/v1/{uint32}/{fixed32}/{opt_uint32}/{opt_fixed32}/{wrap_uint32}",
+		Request:  "leo.goose.example.path.v1.Uint32PathRequest",
+		Response: "google.api.HttpBody",
+	}
+)
+
+func AppendUint32PathGooseRoute[R goose.Router](router R, service Uint32PathGooseService, opts ...server.Option) R {
 	options := server.NewOptions(opts...)
 	handler := uint32PathGooseHandler{
 		service: service,
@@ -639,7 +885,8 @@ func AppendUint32PathGooseRoute(router *http.ServeMux, service Uint32PathGooseSe
 		onValidationErrCallback: options.OnValidationErrCallback(),
 		middleware:              server.Chain(options.Middlewares()...),
 	}
-	router.Handle("GET /v1/{uint32}/{fixed32}/{opt_uint32}/{opt_fixed32}/{wrap_uint32}", http.HandlerFunc(handler.Uint32Path))
+	registry := options.RouteRegistry()
+	registry.Handle(router, uint32PathGooseUint32PathEndpoint, "GET "+options.PathPrefix()+"/v1/{uint32}/{fixed32}/{opt_uint32}/{opt_fixed32}/{wrap_uint32}", http.HandlerFunc(handler.Uint32Path))
 	return router
 }
 
@@ -721,6 +968,8 @@ func NewUint32PathGooseClient(target string, opts ...client.Option) Uint32PathGo
 			target:         target,
 			marshalOptions: options.MarshalOptions(),
 			resolver:       options.Resolver(),
+			registry:       options.ResolverRegistry(),
+			pathPrefix:     options.PathPrefix(),
 		},
 		decoder: uint32PathGooseResponseDecoder{
 			unmarshalOptions: options.UnmarshalOptions(),
@@ -744,6 +993,7 @@ type uint32PathGooseClient struct {
 }
 
 func (c *uint32PathGooseClient) Uint32Path(ctx context.Context, req *Uint32PathRequest) (*httpbody.HttpBody, error) {
+	ctx = goose.WithEndpoint(ctx, uint32PathGooseUint32PathEndpoint)
 	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
 		return nil, err
 	}
@@ -766,13 +1016,20 @@ type uint32PathGooseRequestEncoder struct {
 	target         string
 	marshalOptions protojson.MarshalOptions
 	resolver       resolver.Resolver
+	registry       *resolver.Registry
+	pathPrefix     string
+}
+
+func (encoder *uint32PathGooseRequestEncoder) resolve(ctx context.Context) (*url.URL, error) {
+	return encoder.registry.Resolve(ctx, encoder.resolver, encoder.target)
 }
 
 func (encoder *uint32PathGooseRequestEncoder) Uint32Path(ctx context.Context, req *Uint32PathRequest) (*http.Request, error) {
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
-	target, err := resolver.Resolve(ctx, encoder.resolver, encoder.target)
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -788,7 +1045,7 @@ func (encoder *uint32PathGooseRequestEncoder) Uint32Path(ctx context.Context, re
 		"wrap_uint32": goose.FormatUint(req.GetWrapUint32().GetValue(), 10),
 	}
 	path = goose.URLPath(path, pairs)
-	path, err = url.JoinPath(target.Path, path)
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
 	if err != nil {
 		return nil, err
 	}
@@ -811,6 +1068,9 @@ func (decoder *uint32PathGooseResponseDecoder) Uint32Path(ctx context.Context, r
 	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
 		return nil, respErr
 	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
 	resp := &httpbody.HttpBody{}
 	if err := client.DecodeHttpBody(ctx, response, resp); err != nil {
 		return nil, err
@@ -818,11 +1078,75 @@ func (decoder *uint32PathGooseResponseDecoder) Uint32Path(ctx context.Context, r
 	return resp, nil
 }
 
+type Uint32PathGooseGrpcClient interface {
+	Uint32Path(ctx context.Context, req *Uint32PathRequest, opts ...grpc.CallOption) (*httpbody.HttpBody, error)
+}
+
+func NewUint32PathGooseGrpcClient(target string, opts ...client.Option) Uint32PathGooseGrpcClient {
+	options := client.NewOptions(opts...)
+	client := &uint32PathGooseGrpcClient{
+		uint32PathGooseClient: &uint32PathGooseClient{
+			client: options.Client(),
+			encoder: uint32PathGooseRequestEncoder{
+				target:         target,
+				marshalOptions: options.MarshalOptions(),
+				resolver:       options.Resolver(),
+				registry:       options.ResolverRegistry(),
+				pathPrefix:     options.PathPrefix(),
+			},
+			decoder: uint32PathGooseResponseDecoder{
+				unmarshalOptions: options.UnmarshalOptions(),
+				errorDecoder:     options.ErrorDecoder(),
+				errorFactory:     options.ErrorFactory(),
+			},
+			shouldFailFast:          options.ShouldFailFast(),
+			onValidationErrCallback: options.OnValidationErrCallback(),
+			middleware:              client.Chain(options.Middlewares()...),
+		},
+	}
+	return client
+}
+
+type uint32PathGooseGrpcClient struct {
+	*uint32PathGooseClient
+}
+
+func (c *uint32PathGooseGrpcClient) Uint32Path(ctx context.Context, req *Uint32PathRequest, opts ...grpc.CallOption) (resp *httpbody.HttpBody, err error) {
+	ctx = goose.WithEndpoint(ctx, uint32PathGooseUint32PathEndpoint)
+	var response *http.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.Uint32Path(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.Uint32Path(ctx, response)
+}
+
 type Uint64PathGooseService interface {
 	Uint64Path(ctx context.Context, req *Uint64PathRequest) (*httpbody.HttpBody, error)
 }
 
-func AppendUint64PathGooseRoute(router *http.ServeMux, service Uint64PathGooseService, opts ...server.Option) *http.ServeMux {
+var (
+	uint64PathGooseUint64PathEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.path.v1.Uint64Path/Uint64Path",
+		Method:   "GET",
+		Pattern:  "/v1/{uint64}/{fixed64}/{opt_uint64}/{opt_fixed64}/{wrap_uint64}",
+		Request:  "leo.goose.example.path.v1.Uint64PathRequest",
+		Response: "google.api.HttpBody",
+	}
+)
+
+func AppendUint64PathGooseRoute[R goose.Router](router R, service Uint64PathGooseService, opts ...server.Option) R {
 	options := server.NewOptions(opts...)
 	handler := uint64PathGooseHandler{
 		service: service,
@@ -838,7 +1162,8 @@ func AppendUint64PathGooseRoute(router *http.ServeMux, service Uint64PathGooseSe
 		onValidationErrCallback: options.OnValidationErrCallback(),
 		middleware:              server.Chain(options.Middlewares()...),
 	}
-	router.Handle("GET /v1/{uint64}/{fixed64}/{opt_uint64}/{opt_fixed64}/{wrap_uint64}", http.HandlerFunc(handler.Uint64Path))
+	registry := options.RouteRegistry()
+	registry.Handle(router, uint64PathGooseUint64PathEndpoint, "GET "+options.PathPrefix()+"/v1/{uint64}/{fixed64}/{opt_uint64}/{opt_fixed64}/{wrap_uint64}", http.HandlerFunc(handler.Uint64Path))
 	return router
 }
 
@@ -920,6 +1245,8 @@ func NewUint64PathGooseClient(target string, opts ...client.Option) Uint64PathGo
 			target:         target,
 			marshalOptions: options.MarshalOptions(),
 			resolver:       options.Resolver(),
+			registry:       options.ResolverRegistry(),
+			pathPrefix:     options.PathPrefix(),
 		},
 		decoder: uint64PathGooseResponseDecoder{
 			unmarshalOptions: options.UnmarshalOptions(),
@@ -943,6 +1270,7 @@ type uint64PathGooseClient struct {
 }
 
 func (c *uint64PathGooseClient) Uint64Path(ctx context.Context, req *Uint64PathRequest) (*httpbody.HttpBody, error) {
+	ctx = goose.WithEndpoint(ctx, uint64PathGooseUint64PathEndpoint)
 	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
 		return nil, err
 	}
@@ -965,13 +1293,20 @@ type uint64PathGooseRequestEncoder struct {
 	target         string
 	marshalOptions protojson.MarshalOptions
 	resolver       resolver.Resolver
+	registry       *resolver.Registry
+	pathPrefix     string
+}
+
+func (encoder *uint64PathGooseRequestEncoder) resolve(ctx context.Context) (*url.URL, error) {
+	return encoder.registry.Resolve(ctx, encoder.resolver, encoder.target)
 }
 
 func (encoder *uint64PathGooseRequestEncoder) Uint64Path(ctx context.Context, req *Uint64PathRequest) (*http.Request, error) {
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
-	target, err := resolver.Resolve(ctx, encoder.resolver, encoder.target)
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -987,7 +1322,7 @@ func (encoder *uint64PathGooseRequestEncoder) Uint64Path(ctx context.Context, re
 		"wrap_uint64": goose.FormatUint(req.GetWrapUint64().GetValue(), 10),
 	}
 	path = goose.URLPath(path, pairs)
-	path, err = url.JoinPath(target.Path, path)
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
 	if err != nil {
 		return nil, err
 	}
@@ -1010,6 +1345,9 @@ func (decoder *uint64PathGooseResponseDecoder) Uint64Path(ctx context.Context, r
 	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
 		return nil, respErr
 	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
 	resp := &httpbody.HttpBody{}
 	if err := client.DecodeHttpBody(ctx, response, resp); err != nil {
 		return nil, err
@@ -1017,11 +1355,75 @@ func (decoder *uint64PathGooseResponseDecoder) Uint64Path(ctx context.Context, r
 	return resp, nil
 }
 
+type Uint64PathGooseGrpcClient interface {
+	Uint64Path(ctx context.Context, req *Uint64PathRequest, opts ...grpc.CallOption) (*httpbody.HttpBody, error)
+}
+
+func NewUint64PathGooseGrpcClient(target string, opts ...client.Option) Uint64PathGooseGrpcClient {
+	options := client.NewOptions(opts...)
+	client := &uint64PathGooseGrpcClient{
+		uint64PathGooseClient: &uint64PathGooseClient{
+			client: options.Client(),
+			encoder: uint64PathGooseRequestEncoder{
+				target:         target,
+				marshalOptions: options.MarshalOptions(),
+				resolver:       options.Resolver(),
+				registry:       options.ResolverRegistry(),
+				pathPrefix:     options.PathPrefix(),
+			},
+			decoder: uint64PathGooseResponseDecoder{
+				unmarshalOptions: options.UnmarshalOptions(),
+				errorDecoder:     options.ErrorDecoder(),
+				errorFactory:     options.ErrorFactory(),
+			},
+			shouldFailFast:          options.ShouldFailFast(),
+			onValidationErrCallback: options.OnValidationErrCallback(),
+			middleware:              client.Chain(options.Middlewares()...),
+		},
+	}
+	return client
+}
+
+type uint64PathGooseGrpcClient struct {
+	*uint64PathGooseClient
+}
+
+func (c *uint64PathGooseGrpcClient) Uint64Path(ctx context.Context, req *Uint64PathRequest, opts ...grpc.CallOption) (resp *httpbody.HttpBody, err error) {
+	ctx = goose.WithEndpoint(ctx, uint64PathGooseUint64PathEndpoint)
+	var response *http.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.Uint64Path(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.Uint64Path(ctx, response)
+}
+
 type FloatPathGooseService interface {
 	FloatPath(ctx context.Context, req *FloatPathRequest) (*httpbody.HttpBody, error)
 }
 
-func AppendFloatPathGooseRoute(router *http.ServeMux, service FloatPathGooseService, opts ...server.Option) *http.ServeMux {
+var (
+	floatPathGooseFloatPathEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.path.v1.FloatPath/FloatPath",
+		Method:   "GET",
+		Pattern:  "/v1/{float}/{opt_float}/{wrap_float}",
+		Request:  "leo.goose.example.path.v1.FloatPathRequest",
+		Response: "google.api.HttpBody",
+	}
+)
+
+func AppendFloatPathGooseRoute[R goose.Router](router R, service FloatPathGooseService, opts ...server.Option) R {
 	options := server.NewOptions(opts...)
 	handler := floatPathGooseHandler{
 		service: service,
@@ -1037,7 +1439,8 @@ func AppendFloatPathGooseRoute(router *http.ServeMux, service FloatPathGooseServ
 		onValidationErrCallback: options.OnValidationErrCallback(),
 		middleware:              server.Chain(options.Middlewares()...),
 	}
-	router.Handle("GET /v1/{float}/{opt_float}/{wrap_float}", http.HandlerFunc(handler.FloatPath))
+	registry := options.RouteRegistry()
+	registry.Handle(router, floatPathGooseFloatPathEndpoint, "GET "+options.PathPrefix()+"/v1/{float}/{opt_float}/{wrap_float}", http.HandlerFunc(handler.FloatPath))
 	return router
 }
 
@@ -1117,6 +1520,8 @@ func NewFloatPathGooseClient(target string, opts ...client.Option) FloatPathGoos
 			target:         target,
 			marshalOptions: options.MarshalOptions(),
 			resolver:       options.Resolver(),
+			registry:       options.ResolverRegistry(),
+			pathPrefix:     options.PathPrefix(),
 		},
 		decoder: floatPathGooseResponseDecoder{
 			unmarshalOptions: options.UnmarshalOptions(),
@@ -1140,6 +1545,7 @@ type floatPathGooseClient struct {
 }
 
 func (c *floatPathGooseClient) FloatPath(ctx context.Context, req *FloatPathRequest) (*httpbody.HttpBody, error) {
+	ctx = goose.WithEndpoint(ctx, floatPathGooseFloatPathEndpoint)
 	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
 		return nil, err
 	}
@@ -1162,13 +1568,20 @@ type floatPathGooseRequestEncoder struct {
 	target         string
 	marshalOptions protojson.MarshalOptions
 	resolver       resolver.Resolver
+	registry       *resolver.Registry
+	pathPrefix     string
+}
+
+func (encoder *floatPathGooseRequestEncoder) resolve(ctx context.Context) (*url.URL, error) {
+	return encoder.registry.Resolve(ctx, encoder.resolver, encoder.target)
 }
 
 func (encoder *floatPathGooseRequestEncoder) FloatPath(ctx context.Context, req *FloatPathRequest) (*http.Request, error) {
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
-	target, err := resolver.Resolve(ctx, encoder.resolver, encoder.target)
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -1182,7 +1595,7 @@ func (encoder *floatPathGooseRequestEncoder) FloatPath(ctx context.Context, req
 		"wrap_float": goose.FormatFloat(req.GetWrapFloat().GetValue(), 'f', -1, 32),
 	}
 	path = goose.URLPath(path, pairs)
-	path, err = url.JoinPath(target.Path, path)
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
 	if err != nil {
 		return nil, err
 	}
@@ -1205,6 +1618,9 @@ func (decoder *floatPathGooseResponseDecoder) FloatPath(ctx context.Context, res
 	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
 		return nil, respErr
 	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
 	resp := &httpbody.HttpBody{}
 	if err := client.DecodeHttpBody(ctx, response, resp); err != nil {
 		return nil, err
@@ -1212,11 +1628,75 @@ func (decoder *floatPathGooseResponseDecoder) FloatPath(ctx context.Context, res
 	return resp, nil
 }
 
+type FloatPathGooseGrpcClient interface {
+	FloatPath(ctx context.Context, req *FloatPathRequest, opts ...grpc.CallOption) (*httpbody.HttpBody, error)
+}
+
+func NewFloatPathGooseGrpcClient(target string, opts ...client.Option) FloatPathGooseGrpcClient {
+	options := client.NewOptions(opts...)
+	client := &floatPathGooseGrpcClient{
+		floatPathGooseClient: &floatPathGooseClient{
+			client: options.Client(),
+			encoder: floatPathGooseRequestEncoder{
+				target:         target,
+				marshalOptions: options.MarshalOptions(),
+				resolver:       options.Resolver(),
+				registry:       options.ResolverRegistry(),
+				pathPrefix:     options.PathPrefix(),
+			},
+			decoder: floatPathGooseResponseDecoder{
+				unmarshalOptions: options.UnmarshalOptions(),
+				errorDecoder:     options.ErrorDecoder(),
+				errorFactory:     options.ErrorFactory(),
+			},
+			shouldFailFast:          options.ShouldFailFast(),
+			onValidationErrCallback: options.OnValidationErrCallback(),
+			middleware:              client.Chain(options.Middlewares()...),
+		},
+	}
+	return client
+}
+
+type floatPathGooseGrpcClient struct {
+	*floatPathGooseClient
+}
+
+func (c *floatPathGooseGrpcClient) FloatPath(ctx context.Context, req *FloatPathRequest, opts ...grpc.CallOption) (resp *httpbody.HttpBody, err error) {
+	ctx = goose.WithEndpoint(ctx, floatPathGooseFloatPathEndpoint)
+	var response *http.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.FloatPath(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.FloatPath(ctx, response)
+}
+
 type DoublePathGooseService interface {
 	DoublePath(ctx context.Context, req *DoublePathRequest) (*httpbody.HttpBody, error)
 }
 
-func AppendDoublePathGooseRoute(router *http.ServeMux, service DoublePathGooseService, opts ...server.Option) *http.ServeMux {
+var (
+	doublePathGooseDoublePathEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.path.v1.DoublePath/DoublePath",
+		Method:   "GET",
+		Pattern:  "/v1/{double}/{opt_double}/{wrap_double}",
+		Request:  "leo.goose.example.path.v1.DoublePathRequest",
+		Response: "google.api.HttpBody",
+	}
+)
+
+func AppendDoublePathGooseRoute[R goose.Router](router R, service DoublePathGooseService, opts ...server.Option) R {
 	options := server.NewOptions(opts...)
 	handler := doublePathGooseHandler{
 		service: service,
@@ -1232,7 +1712,8 @@ func AppendDoublePathGooseRoute(router *http.ServeMux, service DoublePathGooseSe
 		onValidationErrCallback: options.OnValidationErrCallback(),
 		middleware:              server.Chain(options.Middlewares()...),
 	}
-	router.Handle("GET /v1/{double}/{opt_double}/{wrap_double}", http.HandlerFunc(handler.DoublePath))
+	registry := options.RouteRegistry()
+	registry.Handle(router, doublePathGooseDoublePathEndpoint, "GET "+options.PathPrefix()+"/v1/{double}/{opt_double}/{wrap_double}", http.HandlerFunc(handler.DoublePath))
 	return router
 }
 
@@ -1312,6 +1793,8 @@ func NewDoublePathGooseClient(target string, opts ...client.Option) DoublePathGo
 			target:         target,
 			marshalOptions: options.MarshalOptions(),
 			resolver:       options.Resolver(),
+			registry:       options.ResolverRegistry(),
+			pathPrefix:     options.PathPrefix(),
 		},
 		decoder: doublePathGooseResponseDecoder{
 			unmarshalOptions: options.UnmarshalOptions(),
@@ -1335,6 +1818,7 @@ type doublePathGooseClient struct {
 }
 
 func (c *doublePathGooseClient) DoublePath(ctx context.Context, req *DoublePathRequest) (*httpbody.HttpBody, error) {
+	ctx = goose.WithEndpoint(ctx, doublePathGooseDoublePathEndpoint)
 	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
 		return nil, err
 	}
@@ -1357,13 +1841,20 @@ type doublePathGooseRequestEncoder struct {
 	target         string
 	marshalOptions protojson.MarshalOptions
 	resolver       resolver.Resolver
+	registry       *resolver.Registry
+	pathPrefix     string
+}
+
+func (encoder *doublePathGooseRequestEncoder) resolve(ctx context.Context) (*url.URL, error) {
+	return encoder.registry.Resolve(ctx, encoder.resolver, encoder.target)
 }
 
 func (encoder *doublePathGooseRequestEncoder) DoublePath(ctx context.Context, req *DoublePathRequest) (*http.Request, error) {
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
-	target, err := resolver.Resolve(ctx, encoder.resolver, encoder.target)
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -1377,7 +1868,7 @@ func (encoder *doublePathGooseRequestEncoder) DoublePath(ctx context.Context, re
 		"wrap_double": goose.FormatFloat(req.GetWrapDouble().GetValue(), 'f', -1, 64),
 	}
 	path = goose.URLPath(path, pairs)
-	path, err = url.JoinPath(target.Path, path)
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
 	if err != nil {
 		return nil, err
 	}
@@ -1400,6 +1891,9 @@ func (decoder *doublePathGooseResponseDecoder) DoublePath(ctx context.Context, r
 	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
 		return nil, respErr
 	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
 	resp := &httpbody.HttpBody{}
 	if err := client.DecodeHttpBody(ctx, response, resp); err != nil {
 		return nil, err
@@ -1407,11 +1901,75 @@ func (decoder *doublePathGooseResponseDecoder) DoublePath(ctx context.Context, r
 	return resp, nil
 }
 
+type DoublePathGooseGrpcClient interface {
+	DoublePath(ctx context.Context, req *DoublePathRequest, opts ...grpc.CallOption) (*httpbody.HttpBody, error)
+}
+
+func NewDoublePathGooseGrpcClient(target string, opts ...client.Option) DoublePathGooseGrpcClient {
+	options := client.NewOptions(opts...)
+	client := &doublePathGooseGrpcClient{
+		doublePathGooseClient: &doublePathGooseClient{
+			client: options.Client(),
+			encoder: doublePathGooseRequestEncoder{
+				target:         target,
+				marshalOptions: options.MarshalOptions(),
+				resolver:       options.Resolver(),
+				registry:       options.ResolverRegistry(),
+				pathPrefix:     options.PathPrefix(),
+			},
+			decoder: doublePathGooseResponseDecoder{
+				unmarshalOptions: options.UnmarshalOptions(),
+				errorDecoder:     options.ErrorDecoder(),
+				errorFactory:     options.ErrorFactory(),
+			},
+			shouldFailFast:          options.ShouldFailFast(),
+			onValidationErrCallback: options.OnValidationErrCallback(),
+			middleware:              client.Chain(options.Middlewares()...),
+		},
+	}
+	return client
+}
+
+type doublePathGooseGrpcClient struct {
+	*doublePathGooseClient
+}
+
+func (c *doublePathGooseGrpcClient) DoublePath(ctx context.Context, req *DoublePathRequest, opts ...grpc.CallOption) (resp *httpbody.HttpBody, err error) {
+	ctx = goose.WithEndpoint(ctx, doublePathGooseDoublePathEndpoint)
+	var response *http.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.DoublePath(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.DoublePath(ctx, response)
+}
+
 type StringPathGooseService interface {
 	StringPath(ctx context.Context, req *StringPathRequest) (*httpbody.HttpBody, error)
 }
 
-func AppendStringPathGooseRoute(router *http.ServeMux, service StringPathGooseService, opts ...server.Option) *http.ServeMux {
+var (
+	stringPathGooseStringPathEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.path.v1.StringPath/StringPath",
+		Method:   "GET",
+		Pattern:  "/v1/{string}/{opt_string}/{wrap_string}/{multi_string...}",
+		Request:  "leo.goose.example.path.v1.StringPathRequest",
+		Response: "google.api.HttpBody",
+	}
+)
+
+func AppendStringPathGooseRoute[R goose.Router](router R, service StringPathGooseService, opts ...server.Option) R {
 	options := server.NewOptions(opts...)
 	handler := stringPathGooseHandler{
 		service: service,
@@ -1427,7 +1985,8 @@ func AppendStringPathGooseRoute(router *http.ServeMux, service StringPathGooseSe
 		onValidationErrCallback: options.OnValidationErrCallback(),
 		middleware:              server.Chain(options.Middlewares()...),
 	}
-	router.Handle("GET /v1/{string}/{opt_string}/{wrap_string}/{multi_string...}", http.HandlerFunc(handler.StringPath))
+	registry := options.RouteRegistry()
+	registry.Handle(router, stringPathGooseStringPathEndpoint, "GET "+options.PathPrefix()+"/v1/{string}/{opt_string}/{wrap_string}/{multi_string...}", http.HandlerFunc(handler.StringPath))
 	return router
 }
 
@@ -1508,6 +2067,8 @@ func NewStringPathGooseClient(target string, opts ...client.Option) StringPathGo
 			target:         target,
 			marshalOptions: options.MarshalOptions(),
 			resolver:       options.Resolver(),
+			registry:       options.ResolverRegistry(),
+			pathPrefix:     options.PathPrefix(),
 		},
 		decoder: stringPathGooseResponseDecoder{
 			unmarshalOptions: options.UnmarshalOptions(),
@@ -1531,6 +2092,7 @@ type stringPathGooseClient struct {
 }
 
 func (c *stringPathGooseClient) StringPath(ctx context.Context, req *StringPathRequest) (*httpbody.HttpBody, error) {
+	ctx = goose.WithEndpoint(ctx, stringPathGooseStringPathEndpoint)
 	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
 		return nil, err
 	}
@@ -1553,13 +2115,20 @@ type stringPathGooseRequestEncoder struct {
 	target         string
 	marshalOptions protojson.MarshalOptions
 	resolver       resolver.Resolver
+	registry       *resolver.Registry
+	pathPrefix     string
+}
+
+func (encoder *stringPathGooseRequestEncoder) resolve(ctx context.Context) (*url.URL, error) {
+	return encoder.registry.Resolve(ctx, encoder.resolver, encoder.target)
 }
 
 func (encoder *stringPathGooseRequestEncoder) StringPath(ctx context.Context, req *StringPathRequest) (*http.Request, error) {
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
-	target, err := resolver.Resolve(ctx, encoder.resolver, encoder.target)
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -1574,7 +2143,7 @@ func (encoder *stringPathGooseRequestEncoder) StringPath(ctx context.Context, re
 		"multi_string": req.GetMultiString(),
 	}
 	path = goose.URLPath(path, pairs)
-	path, err = url.JoinPath(target.Path, path)
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
 	if err != nil {
 		return nil, err
 	}
@@ -1597,6 +2166,9 @@ func (decoder *stringPathGooseResponseDecoder) StringPath(ctx context.Context, r
 	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
 		return nil, respErr
 	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
 	resp := &httpbody.HttpBody{}
 	if err := client.DecodeHttpBody(ctx, response, resp); err != nil {
 		return nil, err
@@ -1604,11 +2176,75 @@ func (decoder *stringPathGooseResponseDecoder) StringPath(ctx context.Context, r
 	return resp, nil
 }
 
+type StringPathGooseGrpcClient interface {
+	StringPath(ctx context.Context, req *StringPathRequest, opts ...grpc.CallOption) (*httpbody.HttpBody, error)
+}
+
+func NewStringPathGooseGrpcClient(target string, opts ...client.Option) StringPathGooseGrpcClient {
+	options := client.NewOptions(opts...)
+	client := &stringPathGooseGrpcClient{
+		stringPathGooseClient: &stringPathGooseClient{
+			client: options.Client(),
+			encoder: stringPathGooseRequestEncoder{
+				target:         target,
+				marshalOptions: options.MarshalOptions(),
+				resolver:       options.Resolver(),
+				registry:       options.ResolverRegistry(),
+				pathPrefix:     options.PathPrefix(),
+			},
+			decoder: stringPathGooseResponseDecoder{
+				unmarshalOptions: options.UnmarshalOptions(),
+				errorDecoder:     options.ErrorDecoder(),
+				errorFactory:     options.ErrorFactory(),
+			},
+			shouldFailFast:          options.ShouldFailFast(),
+			onValidationErrCallback: options.OnValidationErrCallback(),
+			middleware:              client.Chain(options.Middlewares()...),
+		},
+	}
+	return client
+}
+
+type stringPathGooseGrpcClient struct {
+	*stringPathGooseClient
+}
+
+func (c *stringPathGooseGrpcClient) StringPath(ctx context.Context, req *StringPathRequest, opts ...grpc.CallOption) (resp *httpbody.HttpBody, err error) {
+	ctx = goose.WithEndpoint(ctx, stringPathGooseStringPathEndpoint)
+	var response *http.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.StringPath(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.StringPath(ctx, response)
+}
+
 type EnumPathGooseService interface {
 	EnumPath(ctx context.Context, req *EnumPathRequest) (*httpbody.HttpBody, error)
 }
 
-func AppendEnumPathGooseRoute(router *http.ServeMux, service EnumPathGooseService, opts ...server.Option) *http.ServeMux {
+var (
+	enumPathGooseEnumPathEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.path.v1.EnumPath/EnumPath",
+		Method:   "GET",
+		Pattern:  "/v1/{status}/{opt_status}",
+		Request:  "leo.goose.example.path.v1.EnumPathRequest",
+		Response: "google.api.HttpBody",
+	}
+)
+
+func AppendEnumPathGooseRoute[R goose.Router](router R, service EnumPathGooseService, opts ...server.Option) R {
 	options := server.NewOptions(opts...)
 	handler := enumPathGooseHandler{
 		service: service,
@@ -1624,7 +2260,8 @@ func AppendEnumPathGooseRoute(router *http.ServeMux, service EnumPathGooseServic
 		onValidationErrCallback: options.OnValidationErrCallback(),
 		middleware:              server.Chain(options.Middlewares()...),
 	}
-	router.Handle("GET /v1/{status}/{opt_status}", http.HandlerFunc(handler.EnumPath))
+	registry := options.RouteRegistry()
+	registry.Handle(router, enumPathGooseEnumPathEndpoint, "GET "+options.PathPrefix()+"/v1/{status}/{opt_status}", http.HandlerFunc(handler.EnumPath))
 	return router
 }
 
@@ -1703,6 +2340,8 @@ func NewEnumPathGooseClient(target string, opts ...client.Option) EnumPathGooseS
 			target:         target,
 			marshalOptions: options.MarshalOptions(),
 			resolver:       options.Resolver(),
+			registry:       options.ResolverRegistry(),
+			pathPrefix:     options.PathPrefix(),
 		},
 		decoder: enumPathGooseResponseDecoder{
 			unmarshalOptions: options.UnmarshalOptions(),
@@ -1726,6 +2365,7 @@ type enumPathGooseClient struct {
 }
 
 func (c *enumPathGooseClient) EnumPath(ctx context.Context, req *EnumPathRequest) (*httpbody.HttpBody, error) {
+	ctx = goose.WithEndpoint(ctx, enumPathGooseEnumPathEndpoint)
 	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
 		return nil, err
 	}
@@ -1748,13 +2388,20 @@ type enumPathGooseRequestEncoder struct {
 	target         string
 	marshalOptions protojson.MarshalOptions
 	resolver       resolver.Resolver
+	registry       *resolver.Registry
+	pathPrefix     string
+}
+
+func (encoder *enumPathGooseRequestEncoder) resolve(ctx context.Context) (*url.URL, error) {
+	return encoder.registry.Resolve(ctx, encoder.resolver, encoder.target)
 }
 
 func (encoder *enumPathGooseRequestEncoder) EnumPath(ctx context.Context, req *EnumPathRequest) (*http.Request, error) {
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
-	target, err := resolver.Resolve(ctx, encoder.resolver, encoder.target)
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -1767,7 +2414,7 @@ func (encoder *enumPathGooseRequestEncoder) EnumPath(ctx context.Context, req *E
 		"opt_status": goose.FormatInt(req.GetOptStatus(), 10),
 	}
 	path = goose.URLPath(path, pairs)
-	path, err = url.JoinPath(target.Path, path)
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
 	if err != nil {
 		return nil, err
 	}
@@ -1790,9 +2437,66 @@ func (decoder *enumPathGooseResponseDecoder) EnumPath(ctx context.Context, respo
 	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
 		return nil, respErr
 	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
 	resp := &httpbody.HttpBody{}
 	if err := client.DecodeHttpBody(ctx, response, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
 }
+
+type EnumPathGooseGrpcClient interface {
+	EnumPath(ctx context.Context, req *EnumPathRequest, opts ...grpc.CallOption) (*httpbody.HttpBody, error)
+}
+
+func NewEnumPathGooseGrpcClient(target string, opts ...client.Option) EnumPathGooseGrpcClient {
+	options := client.NewOptions(opts...)
+	client := &enumPathGooseGrpcClient{
+		enumPathGooseClient: &enumPathGooseClient{
+			client: options.Client(),
+			encoder: enumPathGooseRequestEncoder{
+				target:         target,
+				marshalOptions: options.MarshalOptions(),
+				resolver:       options.Resolver(),
+				registry:       options.ResolverRegistry(),
+				pathPrefix:     options.PathPrefix(),
+			},
+			decoder: enumPathGooseResponseDecoder{
+				unmarshalOptions: options.UnmarshalOptions(),
+				errorDecoder:     options.ErrorDecoder(),
+				errorFactory:     options.ErrorFactory(),
+			},
+			shouldFailFast:          options.ShouldFailFast(),
+			onValidationErrCallback: options.OnValidationErrCallback(),
+			middleware:              client.Chain(options.Middlewares()...),
+		},
+	}
+	return client
+}
+
+type enumPathGooseGrpcClient struct {
+	*enumPathGooseClient
+}
+
+func (c *enumPathGooseGrpcClient) EnumPath(ctx context.Context, req *EnumPathRequest, opts ...grpc.CallOption) (resp *httpbody.HttpBody, err error) {
+	ctx = goose.WithEndpoint(ctx, enumPathGooseEnumPathEndpoint)
+	var response *http.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.EnumPath(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.EnumPath(ctx, response)
+}
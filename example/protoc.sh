@@ -8,4 +8,18 @@ protoc \
 --go_opt=paths=source_relative \
 --goose_out=. \
 --goose_opt=paths=source_relative \
-*/*.proto
+--goose_opt=grpc_client=true \
+$(ls */*.proto | grep -v '^path/')
+
+# the services of path are served on distinct routers, so their routes may overlap
+protoc \
+--proto_path=. \
+--proto_path=../third_party \
+--proto_path=../../ \
+--go_out=. \
+--go_opt=paths=source_relative \
+--goose_out=. \
+--goose_opt=paths=source_relative \
+--goose_opt=grpc_client=true \
+--goose_opt=route_conflicts=service \
+path/*.proto
@@ -8,9 +8,11 @@ import (
 	errors "errors"
 	goose "github.com/go-leo/goose"
 	client "github.com/go-leo/goose/client"
+	grpcx "github.com/go-leo/goose/client/grpcx"
 	resolver "github.com/go-leo/goose/client/resolver"
 	server "github.com/go-leo/goose/server"
 	httpbody "google.golang.org/genproto/googleapis/api/httpbody"
+	grpc "google.golang.org/grpc"
 	protojson "google.golang.org/protobuf/encoding/protojson"
 	proto "google.golang.org/protobuf/proto"
 	wrapperspb "google.golang.org/protobuf/types/known/wrapperspb"
@@ -22,7 +24,17 @@ type BoolQueryGooseService interface {
 	BoolQuery(ctx context.Context, req *BoolQueryRequest) (*httpbody.HttpBody, error)
 }
 
-func AppendBoolQueryGooseRoute(router *http.ServeMux, service BoolQueryGooseService, opts ...server.Option) *http.ServeMux {
+var (
+	boolQueryGooseBoolQueryEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.query.v1.BoolQuery/BoolQuery",
+		Method:   "GET",
+		Pattern:  "/v1/bool",
+		Request:  "leo.goose.example.query.v1.BoolQueryRequest",
+		Response: "google.api.HttpBody",
+	}
+)
+
+func AppendBoolQueryGooseRoute[R goose.Router](router R, service BoolQueryGooseService, opts ...server.Option) R {
 	options := server.NewOptions(opts...)
 	handler := boolQueryGooseHandler{
 		service: service,
@@ -38,7 +50,8 @@ func AppendBoolQueryGooseRoute(router *http.ServeMux, service BoolQueryGooseServ
 		onValidationErrCallback: options.OnValidationErrCallback(),
 		middleware:              server.Chain(options.Middlewares()...),
 	}
-	router.Handle("GET /v1/bool", http.HandlerFunc(handler.BoolQuery))
+	registry := options.RouteRegistry()
+	registry.Handle(router, boolQueryGooseBoolQueryEndpoint, "GET "+options.PathPrefix()+"/v1/bool", http.HandlerFunc(handler.BoolQuery))
 	return router
 }
 
@@ -120,6 +133,8 @@ func NewBoolQueryGooseClient(target string, opts ...client.Option) BoolQueryGoos
 			target:         target,
 			marshalOptions: options.MarshalOptions(),
 			resolver:       options.Resolver(),
+			registry:       options.ResolverRegistry(),
+			pathPrefix:     options.PathPrefix(),
 		},
 		decoder: boolQueryGooseResponseDecoder{
 			unmarshalOptions: options.UnmarshalOptions(),
@@ -143,6 +158,7 @@ type boolQueryGooseClient struct {
 }
 
 func (c *boolQueryGooseClient) BoolQuery(ctx context.Context, req *BoolQueryRequest) (*httpbody.HttpBody, error) {
+	ctx = goose.WithEndpoint(ctx, boolQueryGooseBoolQueryEndpoint)
 	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
 		return nil, err
 	}
@@ -165,13 +181,20 @@ type boolQueryGooseRequestEncoder struct {
 	target         string
 	marshalOptions protojson.MarshalOptions
 	resolver       resolver.Resolver
+	registry       *resolver.Registry
+	pathPrefix     string
+}
+
+func (encoder *boolQueryGooseRequestEncoder) resolve(ctx context.Context) (*url.URL, error) {
+	return encoder.registry.Resolve(ctx, encoder.resolver, encoder.target)
 }
 
 func (encoder *boolQueryGooseRequestEncoder) BoolQuery(ctx context.Context, req *BoolQueryRequest) (*http.Request, error) {
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
-	target, err := resolver.Resolve(ctx, encoder.resolver, encoder.target)
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -179,6 +202,10 @@ func (encoder *boolQueryGooseRequestEncoder) BoolQuery(ctx context.Context, req
 	header := http.Header{}
 	var body bytes.Buffer
 	path := "/v1/bool"
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
+	if err != nil {
+		return nil, err
+	}
 	target.Path = path
 	queries := url.Values{}
 	queries["bool"] = append(queries["bool"], goose.FormatBool(req.GetBool()))
@@ -205,6 +232,9 @@ func (decoder *boolQueryGooseResponseDecoder) BoolQuery(ctx context.Context, res
 	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
 		return nil, respErr
 	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
 	resp := &httpbody.HttpBody{}
 	if err := client.DecodeHttpBody(ctx, response, resp); err != nil {
 		return nil, err
@@ -212,11 +242,75 @@ func (decoder *boolQueryGooseResponseDecoder) BoolQuery(ctx context.Context, res
 	return resp, nil
 }
 
+type BoolQueryGooseGrpcClient interface {
+	BoolQuery(ctx context.Context, req *BoolQueryRequest, opts ...grpc.CallOption) (*httpbody.HttpBody, error)
+}
+
+func NewBoolQueryGooseGrpcClient(target string, opts ...client.Option) BoolQueryGooseGrpcClient {
+	options := client.NewOptions(opts...)
+	client := &boolQueryGooseGrpcClient{
+		boolQueryGooseClient: &boolQueryGooseClient{
+			client: options.Client(),
+			encoder: boolQueryGooseRequestEncoder{
+				target:         target,
+				marshalOptions: options.MarshalOptions(),
+				resolver:       options.Resolver(),
+				registry:       options.ResolverRegistry(),
+				pathPrefix:     options.PathPrefix(),
+			},
+			decoder: boolQueryGooseResponseDecoder{
+				unmarshalOptions: options.UnmarshalOptions(),
+				errorDecoder:     options.ErrorDecoder(),
+				errorFactory:     options.ErrorFactory(),
+			},
+			shouldFailFast:          options.ShouldFailFast(),
+			onValidationErrCallback: options.OnValidationErrCallback(),
+			middleware:              client.Chain(options.Middlewares()...),
+		},
+	}
+	return client
+}
+
+type boolQueryGooseGrpcClient struct {
+	*boolQueryGooseClient
+}
+
+func (c *boolQueryGooseGrpcClient) BoolQuery(ctx context.Context, req *BoolQueryRequest, opts ...grpc.CallOption) (resp *httpbody.HttpBody, err error) {
+	ctx = goose.WithEndpoint(ctx, boolQueryGooseBoolQueryEndpoint)
+	var response *http.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.BoolQuery(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.BoolQuery(ctx, response)
+}
+
 type Int32QueryGooseService interface {
 	Int32Query(ctx context.Context, req *Int32QueryRequest) (*httpbody.HttpBody, error)
 }
 
-func AppendInt32QueryGooseRoute(router *http.ServeMux, service Int32QueryGooseService, opts ...server.Option) *http.ServeMux {
+var (
+	int32QueryGooseInt32QueryEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.query.v1.Int32Query/Int32Query",
+		Method:   "GET",
+		Pattern:  "/v1/int32",
+		Request:  "leo.goose.example.query.v1.Int32QueryRequest",
+		Response: "google.api.HttpBody",
+	}
+)
+
+func AppendInt32QueryGooseRoute[R goose.Router](router R, service Int32QueryGooseService, opts ...server.Option) R {
 	options := server.NewOptions(opts...)
 	handler := int32QueryGooseHandler{
 		service: service,
@@ -232,7 +326,8 @@ func AppendInt32QueryGooseRoute(router *http.ServeMux, service Int32QueryGooseSe
 		onValidationErrCallback: options.OnValidationErrCallback(),
 		middleware:              server.Chain(options.Middlewares()...),
 	}
-	router.Handle("GET /v1/int32", http.HandlerFunc(handler.Int32Query))
+	registry := options.RouteRegistry()
+	registry.Handle(router, int32QueryGooseInt32QueryEndpoint, "GET "+options.PathPrefix()+"/v1/int32", http.HandlerFunc(handler.Int32Query))
 	return router
 }
 
@@ -320,6 +415,8 @@ func NewInt32QueryGooseClient(target string, opts ...client.Option) Int32QueryGo
 			target:         target,
 			marshalOptions: options.MarshalOptions(),
 			resolver:       options.Resolver(),
+			registry:       options.ResolverRegistry(),
+			pathPrefix:     options.PathPrefix(),
 		},
 		decoder: int32QueryGooseResponseDecoder{
 			unmarshalOptions: options.UnmarshalOptions(),
@@ -343,6 +440,7 @@ type int32QueryGooseClient struct {
 }
 
 func (c *int32QueryGooseClient) Int32Query(ctx context.Context, req *Int32QueryRequest) (*httpbody.HttpBody, error) {
+	ctx = goose.WithEndpoint(ctx, int32QueryGooseInt32QueryEndpoint)
 	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
 		return nil, err
 	}
@@ -365,13 +463,20 @@ type int32QueryGooseRequestEncoder struct {
 	target         string
 	marshalOptions protojson.MarshalOptions
 	resolver       resolver.Resolver
+	registry       *resolver.Registry
+	pathPrefix     string
+}
+
+func (encoder *int32QueryGooseRequestEncoder) resolve(ctx context.Context) (*url.URL, error) {
+	return encoder.registry.Resolve(ctx, encoder.resolver, encoder.target)
 }
 
 func (encoder *int32QueryGooseRequestEncoder) Int32Query(ctx context.Context, req *Int32QueryRequest) (*http.Request, error) {
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
-	target, err := resolver.Resolve(ctx, encoder.resolver, encoder.target)
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -379,6 +484,10 @@ func (encoder *int32QueryGooseRequestEncoder) Int32Query(ctx context.Context, re
 	header := http.Header{}
 	var body bytes.Buffer
 	path := "/v1/int32"
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
+	if err != nil {
+		return nil, err
+	}
 	target.Path = path
 	queries := url.Values{}
 	queries["int32"] = append(queries["int32"], goose.FormatInt(req.GetInt32(), 10))
@@ -411,6 +520,9 @@ func (decoder *int32QueryGooseResponseDecoder) Int32Query(ctx context.Context, r
 	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
 		return nil, respErr
 	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
 	resp := &httpbody.HttpBody{}
 	if err := client.DecodeHttpBody(ctx, response, resp); err != nil {
 		return nil, err
@@ -418,11 +530,75 @@ func (decoder *int32QueryGooseResponseDecoder) Int32Query(ctx context.Context, r
 	return resp, nil
 }
 
+type Int32QueryGooseGrpcClient interface {
+	Int32Query(ctx context.Context, req *Int32QueryRequest, opts ...grpc.CallOption) (*httpbody.HttpBody, error)
+}
+
+func NewInt32QueryGooseGrpcClient(target string, opts ...client.Option) Int32QueryGooseGrpcClient {
+	options := client.NewOptions(opts...)
+	client := &int32QueryGooseGrpcClient{
+		int32QueryGooseClient: &int32QueryGooseClient{
+			client: options.Client(),
+			encoder: int32QueryGooseRequestEncoder{
+				target:         target,
+				marshalOptions: options.MarshalOptions(),
+				resolver:       options.Resolver(),
+				registry:       options.ResolverRegistry(),
+				pathPrefix:     options.PathPrefix(),
+			},
+			decoder: int32QueryGooseResponseDecoder{
+				unmarshalOptions: options.UnmarshalOptions(),
+				errorDecoder:     options.ErrorDecoder(),
+				errorFactory:     options.ErrorFactory(),
+			},
+			shouldFailFast:          options.ShouldFailFast(),
+			onValidationErrCallback: options.OnValidationErrCallback(),
+			middleware:              client.Chain(options.Middlewares()...),
+		},
+	}
+	return client
+}
+
+type int32QueryGooseGrpcClient struct {
+	*int32QueryGooseClient
+}
+
+func (c *int32QueryGooseGrpcClient) Int32Query(ctx context.Context, req *Int32QueryRequest, opts ...grpc.CallOption) (resp *httpbody.HttpBody, err error) {
+	ctx = goose.WithEndpoint(ctx, int32QueryGooseInt32QueryEndpoint)
+	var response *http.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.Int32Query(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.Int32Query(ctx, response)
+}
+
 type Int64QueryGooseService interface {
 	Int64Query(ctx context.Context, req *Int64QueryRequest) (*httpbody.HttpBody, error)
 }
 
-func AppendInt64QueryGooseRoute(router *http.ServeMux, service Int64QueryGooseService, opts ...server.Option) *http.ServeMux {
+var (
+	int64QueryGooseInt64QueryEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.query.v1.Int64Query/Int64Query",
+		Method:   "GET",
+		Pattern:  "/v1/int64",
+		Request:  "leo.goose.example.query.v1.Int64QueryRequest",
+		Response: "google.api.HttpBody",
+	}
+)
+
+func AppendInt64QueryGooseRoute[R goose.Router](router R, service Int64QueryGooseService, opts ...server.Option) R {
 	options := server.NewOptions(opts...)
 	handler := int64QueryGooseHandler{
 		service: service,
@@ -438,7 +614,8 @@ func AppendInt64QueryGooseRoute(router *http.ServeMux, service Int64QueryGooseSe
 		onValidationErrCallback: options.OnValidationErrCallback(),
 		middleware:              server.Chain(options.Middlewares()...),
 	}
-	router.Handle("GET /v1/int64", http.HandlerFunc(handler.Int64Query))
+	registry := options.RouteRegistry()
+	registry.Handle(router, int64QueryGooseInt64QueryEndpoint, "GET "+options.PathPrefix()+"/v1/int64", http.HandlerFunc(handler.Int64Query))
 	return router
 }
 
@@ -526,6 +703,8 @@ func NewInt64QueryGooseClient(target string, opts ...client.Option) Int64QueryGo
 			target:         target,
 			marshalOptions: options.MarshalOptions(),
 			resolver:       options.Resolver(),
+			registry:       options.ResolverRegistry(),
+			pathPrefix:     options.PathPrefix(),
 		},
 		decoder: int64QueryGooseResponseDecoder{
 			unmarshalOptions: options.UnmarshalOptions(),
@@ -549,6 +728,7 @@ type int64QueryGooseClient struct {
 }
 
 func (c *int64QueryGooseClient) Int64Query(ctx context.Context, req *Int64QueryRequest) (*httpbody.HttpBody, error) {
+	ctx = goose.WithEndpoint(ctx, int64QueryGooseInt64QueryEndpoint)
 	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
 		return nil, err
 	}
@@ -571,13 +751,20 @@ type int64QueryGooseRequestEncoder struct {
 	target         string
 	marshalOptions protojson.MarshalOptions
 	resolver       resolver.Resolver
+	registry       *resolver.Registry
+	pathPrefix     string
+}
+
+func (encoder *int64QueryGooseRequestEncoder) resolve(ctx context.Context) (*url.URL, error) {
+	return encoder.registry.Resolve(ctx, encoder.resolver, encoder.target)
 }
 
 func (encoder *int64QueryGooseRequestEncoder) Int64Query(ctx context.Context, req *Int64QueryRequest) (*http.Request, error) {
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
-	target, err := resolver.Resolve(ctx, encoder.resolver, encoder.target)
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -585,6 +772,10 @@ func (encoder *int64QueryGooseRequestEncoder) Int64Query(ctx context.Context, re
 	header := http.Header{}
 	var body bytes.Buffer
 	path := "/v1/int64"
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
+	if err != nil {
+		return nil, err
+	}
 	target.Path = path
 	queries := url.Values{}
 	queries["int64"] = append(queries["int64"], goose.FormatInt(req.GetInt64(), 10))
@@ -617,6 +808,9 @@ func (decoder *int64QueryGooseResponseDecoder) Int64Query(ctx context.Context, r
 	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
 		return nil, respErr
 	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
 	resp := &httpbody.HttpBody{}
 	if err := client.DecodeHttpBody(ctx, response, resp); err != nil {
 		return nil, err
@@ -624,11 +818,75 @@ func (decoder *int64QueryGooseResponseDecoder) Int64Query(ctx context.Context, r
 	return resp, nil
 }
 
+type Int64QueryGooseGrpcClient interface {
+	Int64Query(ctx context.Context, req *Int64QueryRequest, opts ...grpc.CallOption) (*httpbody.HttpBody, error)
+}
+
+func NewInt64QueryGooseGrpcClient(target string, opts ...client.Option) Int64QueryGooseGrpcClient {
+	options := client.NewOptions(opts...)
+	client := &int64QueryGooseGrpcClient{
+		int64QueryGooseClient: &int64QueryGooseClient{
+			client: options.Client(),
+			encoder: int64QueryGooseRequestEncoder{
+				target:         target,
+				marshalOptions: options.MarshalOptions(),
+				resolver:       options.Resolver(),
+				registry:       options.ResolverRegistry(),
+				pathPrefix:     options.PathPrefix(),
+			},
+			decoder: int64QueryGooseResponseDecoder{
+				unmarshalOptions: options.UnmarshalOptions(),
+				errorDecoder:     options.ErrorDecoder(),
+				errorFactory:     options.ErrorFactory(),
+			},
+			shouldFailFast:          options.ShouldFailFast(),
+			onValidationErrCallback: options.OnValidationErrCallback(),
+			middleware:              client.Chain(options.Middlewares()...),
+		},
+	}
+	return client
+}
+
+type int64QueryGooseGrpcClient struct {
+	*int64QueryGooseClient
+}
+
+func (c *int64QueryGooseGrpcClient) Int64Query(ctx context.Context, req *Int64QueryRequest, opts ...grpc.CallOption) (resp *httpbody.HttpBody, err error) {
+	ctx = goose.WithEndpoint(ctx, int64QueryGooseInt64QueryEndpoint)
+	var response *http.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.Int64Query(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.Int64Query(ctx, response)
+}
+
 type Uint32QueryGooseService interface {
 	Uint32Query(ctx context.Context, req *Uint32QueryRequest) (*httpbody.HttpBody, error)
 }
 
-func AppendUint32QueryGooseRoute(router *http.ServeMux, service Uint32QueryGooseService, opts ...server.Option) *http.ServeMux {
+var (
+	uint32QueryGooseUint32QueryEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.query.v1.Uint32Query/Uint32Query",
+		Method:   "GET",
+		Pattern:  "/v1/uint32",
+		Request:  "leo.goose.example.query.v1.Uint32QueryRequest",
+		Response: "google.api.HttpBody",
+	}
+)
+
+func AppendUint32QueryGooseRoute[R goose.Router](router R, service Uint32QueryGooseService, opts ...server.Option) R {
 	options := server.NewOptions(opts...)
 	handler := uint32QueryGooseHandler{
 		service: service,
@@ -644,7 +902,8 @@ func AppendUint32QueryGooseRoute(router *http.ServeMux, service Uint32QueryGoose
 		onValidationErrCallback: options.OnValidationErrCallback(),
 		middleware:              server.Chain(options.Middlewares()...),
 	}
-	router.Handle("GET /v1/uint32", http.HandlerFunc(handler.Uint32Query))
+	registry := options.RouteRegistry()
+	registry.Handle(router, uint32QueryGooseUint32QueryEndpoint, "GET "+options.PathPrefix()+"/v1/uint32", http.HandlerFunc(handler.Uint32Query))
 	return router
 }
 
@@ -729,6 +988,8 @@ func NewUint32QueryGooseClient(target string, opts ...client.Option) Uint32Query
 			target:         target,
 			marshalOptions: options.MarshalOptions(),
 			resolver:       options.Resolver(),
+			registry:       options.ResolverRegistry(),
+			pathPrefix:     options.PathPrefix(),
 		},
 		decoder: uint32QueryGooseResponseDecoder{
 			unmarshalOptions: options.UnmarshalOptions(),
@@ -752,6 +1013,7 @@ type uint32QueryGooseClient struct {
 }
 
 func (c *uint32QueryGooseClient) Uint32Query(ctx context.Context, req *Uint32QueryRequest) (*httpbody.HttpBody, error) {
+	ctx = goose.WithEndpoint(ctx, uint32QueryGooseUint32QueryEndpoint)
 	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
 		return nil, err
 	}
@@ -774,13 +1036,20 @@ type uint32QueryGooseRequestEncoder struct {
 	target         string
 	marshalOptions protojson.MarshalOptions
 	resolver       resolver.Resolver
+	registry       *resolver.Registry
+	pathPrefix     string
+}
+
+func (encoder *uint32QueryGooseRequestEncoder) resolve(ctx context.Context) (*url.URL, error) {
+	return encoder.registry.Resolve(ctx, encoder.resolver, encoder.target)
 }
 
 func (encoder *uint32QueryGooseRequestEncoder) Uint32Query(ctx context.Context, req *Uint32QueryRequest) (*http.Request, error) {
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
-	target, err := resolver.Resolve(ctx, encoder.resolver, encoder.target)
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -788,6 +1057,10 @@ func (encoder *uint32QueryGooseRequestEncoder) Uint32Query(ctx context.Context,
 	header := http.Header{}
 	var body bytes.Buffer
 	path := "/v1/uint32"
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
+	if err != nil {
+		return nil, err
+	}
 	target.Path = path
 	queries := url.Values{}
 	queries["uint32"] = append(queries["uint32"], goose.FormatUint(req.GetUint32(), 10))
@@ -817,6 +1090,9 @@ func (decoder *uint32QueryGooseResponseDecoder) Uint32Query(ctx context.Context,
 	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
 		return nil, respErr
 	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
 	resp := &httpbody.HttpBody{}
 	if err := client.DecodeHttpBody(ctx, response, resp); err != nil {
 		return nil, err
@@ -824,11 +1100,75 @@ func (decoder *uint32QueryGooseResponseDecoder) Uint32Query(ctx context.Context,
 	return resp, nil
 }
 
+type Uint32QueryGooseGrpcClient interface {
+	Uint32Query(ctx context.Context, req *Uint32QueryRequest, opts ...grpc.CallOption) (*httpbody.HttpBody, error)
+}
+
+func NewUint32QueryGooseGrpcClient(target string, opts ...client.Option) Uint32QueryGooseGrpcClient {
+	options := client.NewOptions(opts...)
+	client := &uint32QueryGooseGrpcClient{
+		uint32QueryGooseClient: &uint32QueryGooseClient{
+			client: options.Client(),
+			encoder: uint32QueryGooseRequestEncoder{
+				target:         target,
+				marshalOptions: options.MarshalOptions(),
+				resolver:       options.Resolver(),
+				registry:       options.ResolverRegistry(),
+				pathPrefix:     options.PathPrefix(),
+			},
+			decoder: uint32QueryGooseResponseDecoder{
+				unmarshalOptions: options.UnmarshalOptions(),
+				errorDecoder:     options.ErrorDecoder(),
+				errorFactory:     options.ErrorFactory(),
+			},
+			shouldFailFast:          options.ShouldFailFast(),
+			onValidationErrCallback: options.OnValidationErrCallback(),
+			middleware:              client.Chain(options.Middlewares()...),
+		},
+	}
+	return client
+}
+
+type uint32QueryGooseGrpcClient struct {
+	*uint32QueryGooseClient
+}
+
+func (c *uint32QueryGooseGrpcClient) Uint32Query(ctx context.Context, req *Uint32QueryRequest, opts ...grpc.CallOption) (resp *httpbody.HttpBody, err error) {
+	ctx = goose.WithEndpoint(ctx, uint32QueryGooseUint32QueryEndpoint)
+	var response *http.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.Uint32Query(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.Uint32Query(ctx, response)
+}
+
 type Uint64QueryGooseService interface {
 	Uint64Query(ctx context.Context, req *Uint64QueryRequest) (*httpbody.HttpBody, error)
 }
 
-func AppendUint64QueryGooseRoute(router *http.ServeMux, service Uint64QueryGooseService, opts ...server.Option) *http.ServeMux {
+var (
+	uint64QueryGooseUint64QueryEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.query.v1.Uint64Query/Uint64Query",
+		Method:   "GET",
+		Pattern:  "/v1/uint64",
+		Request:  "leo.goose.example.query.v1.Uint64QueryRequest",
+		Response: "google.api.HttpBody",
+	}
+)
+
+func AppendUint64QueryGooseRoute[R goose.Router](router R, service Uint64QueryGooseService, opts ...server.Option) R {
 	options := server.NewOptions(opts...)
 	handler := uint64QueryGooseHandler{
 		service: service,
@@ -844,7 +1184,8 @@ func AppendUint64QueryGooseRoute(router *http.ServeMux, service Uint64QueryGoose
 		onValidationErrCallback: options.OnValidationErrCallback(),
 		middleware:              server.Chain(options.Middlewares()...),
 	}
-	router.Handle("GET /v1/uint64", http.HandlerFunc(handler.Uint64Query))
+	registry := options.RouteRegistry()
+	registry.Handle(router, uint64QueryGooseUint64QueryEndpoint, "GET "+options.PathPrefix()+"/v1/uint64", http.HandlerFunc(handler.Uint64Query))
 	return router
 }
 
@@ -929,6 +1270,8 @@ func NewUint64QueryGooseClient(target string, opts ...client.Option) Uint64Query
 			target:         target,
 			marshalOptions: options.MarshalOptions(),
 			resolver:       options.Resolver(),
+			registry:       options.ResolverRegistry(),
+			pathPrefix:     options.PathPrefix(),
 		},
 		decoder: uint64QueryGooseResponseDecoder{
 			unmarshalOptions: options.UnmarshalOptions(),
@@ -952,6 +1295,7 @@ type uint64QueryGooseClient struct {
 }
 
 func (c *uint64QueryGooseClient) Uint64Query(ctx context.Context, req *Uint64QueryRequest) (*httpbody.HttpBody, error) {
+	ctx = goose.WithEndpoint(ctx, uint64QueryGooseUint64QueryEndpoint)
 	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
 		return nil, err
 	}
@@ -974,13 +1318,20 @@ type uint64QueryGooseRequestEncoder struct {
 	target         string
 	marshalOptions protojson.MarshalOptions
 	resolver       resolver.Resolver
+	registry       *resolver.Registry
+	pathPrefix     string
+}
+
+func (encoder *uint64QueryGooseRequestEncoder) resolve(ctx context.Context) (*url.URL, error) {
+	return encoder.registry.Resolve(ctx, encoder.resolver, encoder.target)
 }
 
 func (encoder *uint64QueryGooseRequestEncoder) Uint64Query(ctx context.Context, req *Uint64QueryRequest) (*http.Request, error) {
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
-	target, err := resolver.Resolve(ctx, encoder.resolver, encoder.target)
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -988,6 +1339,10 @@ func (encoder *uint64QueryGooseRequestEncoder) Uint64Query(ctx context.Context,
 	header := http.Header{}
 	var body bytes.Buffer
 	path := "/v1/uint64"
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
+	if err != nil {
+		return nil, err
+	}
 	target.Path = path
 	queries := url.Values{}
 	queries["uint64"] = append(queries["uint64"], goose.FormatUint(req.GetUint64(), 10))
@@ -1017,6 +1372,9 @@ func (decoder *uint64QueryGooseResponseDecoder) Uint64Query(ctx context.Context,
 	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
 		return nil, respErr
 	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
 	resp := &httpbody.HttpBody{}
 	if err := client.DecodeHttpBody(ctx, response, resp); err != nil {
 		return nil, err
@@ -1024,11 +1382,75 @@ func (decoder *uint64QueryGooseResponseDecoder) Uint64Query(ctx context.Context,
 	return resp, nil
 }
 
+type Uint64QueryGooseGrpcClient interface {
+	Uint64Query(ctx context.Context, req *Uint64QueryRequest, opts ...grpc.CallOption) (*httpbody.HttpBody, error)
+}
+
+func NewUint64QueryGooseGrpcClient(target string, opts ...client.Option) Uint64QueryGooseGrpcClient {
+	options := client.NewOptions(opts...)
+	client := &uint64QueryGooseGrpcClient{
+		uint64QueryGooseClient: &uint64QueryGooseClient{
+			client: options.Client(),
+			encoder: uint64QueryGooseRequestEncoder{
+				target:         target,
+				marshalOptions: options.MarshalOptions(),
+				resolver:       options.Resolver(),
+				registry:       options.ResolverRegistry(),
+				pathPrefix:     options.PathPrefix(),
+			},
+			decoder: uint64QueryGooseResponseDecoder{
+				unmarshalOptions: options.UnmarshalOptions(),
+				errorDecoder:     options.ErrorDecoder(),
+				errorFactory:     options.ErrorFactory(),
+			},
+			shouldFailFast:          options.ShouldFailFast(),
+			onValidationErrCallback: options.OnValidationErrCallback(),
+			middleware:              client.Chain(options.Middlewares()...),
+		},
+	}
+	return client
+}
+
+type uint64QueryGooseGrpcClient struct {
+	*uint64QueryGooseClient
+}
+
+func (c *uint64QueryGooseGrpcClient) Uint64Query(ctx context.Context, req *Uint64QueryRequest, opts ...grpc.CallOption) (resp *httpbody.HttpBody, err error) {
+	ctx = goose.WithEndpoint(ctx, uint64QueryGooseUint64QueryEndpoint)
+	var response *http.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.Uint64Query(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.Uint64Query(ctx, response)
+}
+
 type FloatQueryGooseService interface {
 	FloatQuery(ctx context.Context, req *FloatQueryRequest) (*httpbody.HttpBody, error)
 }
 
-func AppendFloatQueryGooseRoute(router *http.ServeMux, service FloatQueryGooseService, opts ...server.Option) *http.ServeMux {
+var (
+	floatQueryGooseFloatQueryEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.query.v1.FloatQuery/FloatQuery",
+		Method:   "GET",
+		Pattern:  "/v1/float",
+		Request:  "leo.goose.example.query.v1.FloatQueryRequest",
+		Response: "google.api.HttpBody",
+	}
+)
+
+func AppendFloatQueryGooseRoute[R goose.Router](router R, service FloatQueryGooseService, opts ...server.Option) R {
 	options := server.NewOptions(opts...)
 	handler := floatQueryGooseHandler{
 		service: service,
@@ -1044,7 +1466,8 @@ func AppendFloatQueryGooseRoute(router *http.ServeMux, service FloatQueryGooseSe
 		onValidationErrCallback: options.OnValidationErrCallback(),
 		middleware:              server.Chain(options.Middlewares()...),
 	}
-	router.Handle("GET /v1/float", http.HandlerFunc(handler.FloatQuery))
+	registry := options.RouteRegistry()
+	registry.Handle(router, floatQueryGooseFloatQueryEndpoint, "GET "+options.PathPrefix()+"/v1/float", http.HandlerFunc(handler.FloatQuery))
 	return router
 }
 
@@ -1126,6 +1549,8 @@ func NewFloatQueryGooseClient(target string, opts ...client.Option) FloatQueryGo
 			target:         target,
 			marshalOptions: options.MarshalOptions(),
 			resolver:       options.Resolver(),
+			registry:       options.ResolverRegistry(),
+			pathPrefix:     options.PathPrefix(),
 		},
 		decoder: floatQueryGooseResponseDecoder{
 			unmarshalOptions: options.UnmarshalOptions(),
@@ -1149,6 +1574,7 @@ type floatQueryGooseClient struct {
 }
 
 func (c *floatQueryGooseClient) FloatQuery(ctx context.Context, req *FloatQueryRequest) (*httpbody.HttpBody, error) {
+	ctx = goose.WithEndpoint(ctx, floatQueryGooseFloatQueryEndpoint)
 	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
 		return nil, err
 	}
@@ -1171,13 +1597,20 @@ type floatQueryGooseRequestEncoder struct {
 	target         string
 	marshalOptions protojson.MarshalOptions
 	resolver       resolver.Resolver
+	registry       *resolver.Registry
+	pathPrefix     string
+}
+
+func (encoder *floatQueryGooseRequestEncoder) resolve(ctx context.Context) (*url.URL, error) {
+	return encoder.registry.Resolve(ctx, encoder.resolver, encoder.target)
 }
 
 func (encoder *floatQueryGooseRequestEncoder) FloatQuery(ctx context.Context, req *FloatQueryRequest) (*http.Request, error) {
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
-	target, err := resolver.Resolve(ctx, encoder.resolver, encoder.target)
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -1185,6 +1618,10 @@ func (encoder *floatQueryGooseRequestEncoder) FloatQuery(ctx context.Context, re
 	header := http.Header{}
 	var body bytes.Buffer
 	path := "/v1/float"
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
+	if err != nil {
+		return nil, err
+	}
 	target.Path = path
 	queries := url.Values{}
 	queries["float"] = append(queries["float"], goose.FormatFloat(req.GetFloat(), 'f', -1, 32))
@@ -1211,6 +1648,9 @@ func (decoder *floatQueryGooseResponseDecoder) FloatQuery(ctx context.Context, r
 	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
 		return nil, respErr
 	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
 	resp := &httpbody.HttpBody{}
 	if err := client.DecodeHttpBody(ctx, response, resp); err != nil {
 		return nil, err
@@ -1218,11 +1658,75 @@ func (decoder *floatQueryGooseResponseDecoder) FloatQuery(ctx context.Context, r
 	return resp, nil
 }
 
+type FloatQueryGooseGrpcClient interface {
+	FloatQuery(ctx context.Context, req *FloatQueryRequest, opts ...grpc.CallOption) (*httpbody.HttpBody, error)
+}
+
+func NewFloatQueryGooseGrpcClient(target string, opts ...client.Option) FloatQueryGooseGrpcClient {
+	options := client.NewOptions(opts...)
+	client := &floatQueryGooseGrpcClient{
+		floatQueryGooseClient: &floatQueryGooseClient{
+			client: options.Client(),
+			encoder: floatQueryGooseRequestEncoder{
+				target:         target,
+				marshalOptions: options.MarshalOptions(),
+				resolver:       options.Resolver(),
+				registry:       options.ResolverRegistry(),
+				pathPrefix:     options.PathPrefix(),
+			},
+			decoder: floatQueryGooseResponseDecoder{
+				unmarshalOptions: options.UnmarshalOptions(),
+				errorDecoder:     options.ErrorDecoder(),
+				errorFactory:     options.ErrorFactory(),
+			},
+			shouldFailFast:          options.ShouldFailFast(),
+			onValidationErrCallback: options.OnValidationErrCallback(),
+			middleware:              client.Chain(options.Middlewares()...),
+		},
+	}
+	return client
+}
+
+type floatQueryGooseGrpcClient struct {
+	*floatQueryGooseClient
+}
+
+func (c *floatQueryGooseGrpcClient) FloatQuery(ctx context.Context, req *FloatQueryRequest, opts ...grpc.CallOption) (resp *httpbody.HttpBody, err error) {
+	ctx = goose.WithEndpoint(ctx, floatQueryGooseFloatQueryEndpoint)
+	var response *http.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.FloatQuery(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.FloatQuery(ctx, response)
+}
+
 type DoubleQueryGooseService interface {
 	DoubleQuery(ctx context.Context, req *DoubleQueryRequest) (*httpbody.HttpBody, error)
 }
 
-func AppendDoubleQueryGooseRoute(router *http.ServeMux, service DoubleQueryGooseService, opts ...server.Option) *http.ServeMux {
+var (
+	doubleQueryGooseDoubleQueryEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.query.v1.DoubleQuery/DoubleQuery",
+		Method:   "GET",
+		Pattern:  "/v1/double",
+		Request:  "leo.goose.example.query.v1.DoubleQueryRequest",
+		Response: "google.api.HttpBody",
+	}
+)
+
+func AppendDoubleQueryGooseRoute[R goose.Router](router R, service DoubleQueryGooseService, opts ...server.Option) R {
 	options := server.NewOptions(opts...)
 	handler := doubleQueryGooseHandler{
 		service: service,
@@ -1238,7 +1742,8 @@ func AppendDoubleQueryGooseRoute(router *http.ServeMux, service DoubleQueryGoose
 		onValidationErrCallback: options.OnValidationErrCallback(),
 		middleware:              server.Chain(options.Middlewares()...),
 	}
-	router.Handle("GET /v1/double", http.HandlerFunc(handler.DoubleQuery))
+	registry := options.RouteRegistry()
+	registry.Handle(router, doubleQueryGooseDoubleQueryEndpoint, "GET "+options.PathPrefix()+"/v1/double", http.HandlerFunc(handler.DoubleQuery))
 	return router
 }
 
@@ -1320,6 +1825,8 @@ func NewDoubleQueryGooseClient(target string, opts ...client.Option) DoubleQuery
 			target:         target,
 			marshalOptions: options.MarshalOptions(),
 			resolver:       options.Resolver(),
+			registry:       options.ResolverRegistry(),
+			pathPrefix:     options.PathPrefix(),
 		},
 		decoder: doubleQueryGooseResponseDecoder{
 			unmarshalOptions: options.UnmarshalOptions(),
@@ -1343,6 +1850,7 @@ type doubleQueryGooseClient struct {
 }
 
 func (c *doubleQueryGooseClient) DoubleQuery(ctx context.Context, req *DoubleQueryRequest) (*httpbody.HttpBody, error) {
+	ctx = goose.WithEndpoint(ctx, doubleQueryGooseDoubleQueryEndpoint)
 	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
 		return nil, err
 	}
@@ -1365,13 +1873,20 @@ type doubleQueryGooseRequestEncoder struct {
 	target         string
 	marshalOptions protojson.MarshalOptions
 	resolver       resolver.Resolver
+	registry       *resolver.Registry
+	pathPrefix     string
+}
+
+func (encoder *doubleQueryGooseRequestEncoder) resolve(ctx context.Context) (*url.URL, error) {
+	return encoder.registry.Resolve(ctx, encoder.resolver, encoder.target)
 }
 
 func (encoder *doubleQueryGooseRequestEncoder) DoubleQuery(ctx context.Context, req *DoubleQueryRequest) (*http.Request, error) {
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
-	target, err := resolver.Resolve(ctx, encoder.resolver, encoder.target)
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -1379,6 +1894,10 @@ func (encoder *doubleQueryGooseRequestEncoder) DoubleQuery(ctx context.Context,
 	header := http.Header{}
 	var body bytes.Buffer
 	path := "/v1/double"
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
+	if err != nil {
+		return nil, err
+	}
 	target.Path = path
 	queries := url.Values{}
 	queries["double"] = append(queries["double"], goose.FormatFloat(req.GetDouble(), 'f', -1, 64))
@@ -1405,6 +1924,9 @@ func (decoder *doubleQueryGooseResponseDecoder) DoubleQuery(ctx context.Context,
 	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
 		return nil, respErr
 	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
 	resp := &httpbody.HttpBody{}
 	if err := client.DecodeHttpBody(ctx, response, resp); err != nil {
 		return nil, err
@@ -1412,11 +1934,75 @@ func (decoder *doubleQueryGooseResponseDecoder) DoubleQuery(ctx context.Context,
 	return resp, nil
 }
 
+type DoubleQueryGooseGrpcClient interface {
+	DoubleQuery(ctx context.Context, req *DoubleQueryRequest, opts ...grpc.CallOption) (*httpbody.HttpBody, error)
+}
+
+func NewDoubleQueryGooseGrpcClient(target string, opts ...client.Option) DoubleQueryGooseGrpcClient {
+	options := client.NewOptions(opts...)
+	client := &doubleQueryGooseGrpcClient{
+		doubleQueryGooseClient: &doubleQueryGooseClient{
+			client: options.Client(),
+			encoder: doubleQueryGooseRequestEncoder{
+				target:         target,
+				marshalOptions: options.MarshalOptions(),
+				resolver:       options.Resolver(),
+				registry:       options.ResolverRegistry(),
+				pathPrefix:     options.PathPrefix(),
+			},
+			decoder: doubleQueryGooseResponseDecoder{
+				unmarshalOptions: options.UnmarshalOptions(),
+				errorDecoder:     options.ErrorDecoder(),
+				errorFactory:     options.ErrorFactory(),
+			},
+			shouldFailFast:          options.ShouldFailFast(),
+			onValidationErrCallback: options.OnValidationErrCallback(),
+			middleware:              client.Chain(options.Middlewares()...),
+		},
+	}
+	return client
+}
+
+type doubleQueryGooseGrpcClient struct {
+	*doubleQueryGooseClient
+}
+
+func (c *doubleQueryGooseGrpcClient) DoubleQuery(ctx context.Context, req *DoubleQueryRequest, opts ...grpc.CallOption) (resp *httpbody.HttpBody, err error) {
+	ctx = goose.WithEndpoint(ctx, doubleQueryGooseDoubleQueryEndpoint)
+	var response *http.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.DoubleQuery(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.DoubleQuery(ctx, response)
+}
+
 type StringQueryGooseService interface {
 	StringQuery(ctx context.Context, req *StringQueryRequest) (*httpbody.HttpBody, error)
 }
 
-func AppendStringQueryGooseRoute(router *http.ServeMux, service StringQueryGooseService, opts ...server.Option) *http.ServeMux {
+var (
+	stringQueryGooseStringQueryEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.query.v1.StringQuery/StringQuery",
+		Method:   "GET",
+		Pattern:  "/v1/string",
+		Request:  "leo.goose.example.query.v1.StringQueryRequest",
+		Response: "google.api.HttpBody",
+	}
+)
+
+func AppendStringQueryGooseRoute[R goose.Router](router R, service StringQueryGooseService, opts ...server.Option) R {
 	options := server.NewOptions(opts...)
 	handler := stringQueryGooseHandler{
 		service: service,
@@ -1432,7 +2018,8 @@ func AppendStringQueryGooseRoute(router *http.ServeMux, service StringQueryGoose
 		onValidationErrCallback: options.OnValidationErrCallback(),
 		middleware:              server.Chain(options.Middlewares()...),
 	}
-	router.Handle("GET /v1/string", http.HandlerFunc(handler.StringQuery))
+	registry := options.RouteRegistry()
+	registry.Handle(router, stringQueryGooseStringQueryEndpoint, "GET "+options.PathPrefix()+"/v1/string", http.HandlerFunc(handler.StringQuery))
 	return router
 }
 
@@ -1514,6 +2101,8 @@ func NewStringQueryGooseClient(target string, opts ...client.Option) StringQuery
 			target:         target,
 			marshalOptions: options.MarshalOptions(),
 			resolver:       options.Resolver(),
+			registry:       options.ResolverRegistry(),
+			pathPrefix:     options.PathPrefix(),
 		},
 		decoder: stringQueryGooseResponseDecoder{
 			unmarshalOptions: options.UnmarshalOptions(),
@@ -1537,6 +2126,7 @@ type stringQueryGooseClient struct {
 }
 
 func (c *stringQueryGooseClient) StringQuery(ctx context.Context, req *StringQueryRequest) (*httpbody.HttpBody, error) {
+	ctx = goose.WithEndpoint(ctx, stringQueryGooseStringQueryEndpoint)
 	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
 		return nil, err
 	}
@@ -1559,13 +2149,20 @@ type stringQueryGooseRequestEncoder struct {
 	target         string
 	marshalOptions protojson.MarshalOptions
 	resolver       resolver.Resolver
+	registry       *resolver.Registry
+	pathPrefix     string
+}
+
+func (encoder *stringQueryGooseRequestEncoder) resolve(ctx context.Context) (*url.URL, error) {
+	return encoder.registry.Resolve(ctx, encoder.resolver, encoder.target)
 }
 
 func (encoder *stringQueryGooseRequestEncoder) StringQuery(ctx context.Context, req *StringQueryRequest) (*http.Request, error) {
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
-	target, err := resolver.Resolve(ctx, encoder.resolver, encoder.target)
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -1573,6 +2170,10 @@ func (encoder *stringQueryGooseRequestEncoder) StringQuery(ctx context.Context,
 	header := http.Header{}
 	var body bytes.Buffer
 	path := "/v1/string"
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
+	if err != nil {
+		return nil, err
+	}
 	target.Path = path
 	queries := url.Values{}
 	queries["string"] = append(queries["string"], req.GetString_())
@@ -1599,6 +2200,9 @@ func (decoder *stringQueryGooseResponseDecoder) StringQuery(ctx context.Context,
 	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
 		return nil, respErr
 	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
 	resp := &httpbody.HttpBody{}
 	if err := client.DecodeHttpBody(ctx, response, resp); err != nil {
 		return nil, err
@@ -1606,11 +2210,75 @@ func (decoder *stringQueryGooseResponseDecoder) StringQuery(ctx context.Context,
 	return resp, nil
 }
 
+type StringQueryGooseGrpcClient interface {
+	StringQuery(ctx context.Context, req *StringQueryRequest, opts ...grpc.CallOption) (*httpbody.HttpBody, error)
+}
+
+func NewStringQueryGooseGrpcClient(target string, opts ...client.Option) StringQueryGooseGrpcClient {
+	options := client.NewOptions(opts...)
+	client := &stringQueryGooseGrpcClient{
+		stringQueryGooseClient: &stringQueryGooseClient{
+			client: options.Client(),
+			encoder: stringQueryGooseRequestEncoder{
+				target:         target,
+				marshalOptions: options.MarshalOptions(),
+				resolver:       options.Resolver(),
+				registry:       options.ResolverRegistry(),
+				pathPrefix:     options.PathPrefix(),
+			},
+			decoder: stringQueryGooseResponseDecoder{
+				unmarshalOptions: options.UnmarshalOptions(),
+				errorDecoder:     options.ErrorDecoder(),
+				errorFactory:     options.ErrorFactory(),
+			},
+			shouldFailFast:          options.ShouldFailFast(),
+			onValidationErrCallback: options.OnValidationErrCallback(),
+			middleware:              client.Chain(options.Middlewares()...),
+		},
+	}
+	return client
+}
+
+type stringQueryGooseGrpcClient struct {
+	*stringQueryGooseClient
+}
+
+func (c *stringQueryGooseGrpcClient) StringQuery(ctx context.Context, req *StringQueryRequest, opts ...grpc.CallOption) (resp *httpbody.HttpBody, err error) {
+	ctx = goose.WithEndpoint(ctx, stringQueryGooseStringQueryEndpoint)
+	var response *http.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.StringQuery(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.StringQuery(ctx, response)
+}
+
 type EnumQueryGooseService interface {
 	EnumQuery(ctx context.Context, req *EnumQueryRequest) (*httpbody.HttpBody, error)
 }
 
-func AppendEnumQueryGooseRoute(router *http.ServeMux, service EnumQueryGooseService, opts ...server.Option) *http.ServeMux {
+var (
+	enumQueryGooseEnumQueryEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.query.v1.EnumQuery/EnumQuery",
+		Method:   "GET",
+		Pattern:  "/v1/enum",
+		Request:  "leo.goose.example.query.v1.EnumQueryRequest",
+		Response: "google.api.HttpBody",
+	}
+)
+
+func AppendEnumQueryGooseRoute[R goose.Router](router R, service EnumQueryGooseService, opts ...server.Option) R {
 	options := server.NewOptions(opts...)
 	handler := enumQueryGooseHandler{
 		service: service,
@@ -1626,7 +2294,8 @@ func AppendEnumQueryGooseRoute(router *http.ServeMux, service EnumQueryGooseServ
 		onValidationErrCallback: options.OnValidationErrCallback(),
 		middleware:              server.Chain(options.Middlewares()...),
 	}
-	router.Handle("GET /v1/enum", http.HandlerFunc(handler.EnumQuery))
+	registry := options.RouteRegistry()
+	registry.Handle(router, enumQueryGooseEnumQueryEndpoint, "GET "+options.PathPrefix()+"/v1/enum", http.HandlerFunc(handler.EnumQuery))
 	return router
 }
 
@@ -1706,6 +2375,8 @@ func NewEnumQueryGooseClient(target string, opts ...client.Option) EnumQueryGoos
 			target:         target,
 			marshalOptions: options.MarshalOptions(),
 			resolver:       options.Resolver(),
+			registry:       options.ResolverRegistry(),
+			pathPrefix:     options.PathPrefix(),
 		},
 		decoder: enumQueryGooseResponseDecoder{
 			unmarshalOptions: options.UnmarshalOptions(),
@@ -1729,6 +2400,7 @@ type enumQueryGooseClient struct {
 }
 
 func (c *enumQueryGooseClient) EnumQuery(ctx context.Context, req *EnumQueryRequest) (*httpbody.HttpBody, error) {
+	ctx = goose.WithEndpoint(ctx, enumQueryGooseEnumQueryEndpoint)
 	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
 		return nil, err
 	}
@@ -1751,13 +2423,20 @@ type enumQueryGooseRequestEncoder struct {
 	target         string
 	marshalOptions protojson.MarshalOptions
 	resolver       resolver.Resolver
+	registry       *resolver.Registry
+	pathPrefix     string
+}
+
+func (encoder *enumQueryGooseRequestEncoder) resolve(ctx context.Context) (*url.URL, error) {
+	return encoder.registry.Resolve(ctx, encoder.resolver, encoder.target)
 }
 
 func (encoder *enumQueryGooseRequestEncoder) EnumQuery(ctx context.Context, req *EnumQueryRequest) (*http.Request, error) {
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
-	target, err := resolver.Resolve(ctx, encoder.resolver, encoder.target)
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -1765,6 +2444,10 @@ func (encoder *enumQueryGooseRequestEncoder) EnumQuery(ctx context.Context, req
 	header := http.Header{}
 	var body bytes.Buffer
 	path := "/v1/enum"
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
+	if err != nil {
+		return nil, err
+	}
 	target.Path = path
 	queries := url.Values{}
 	queries["status"] = append(queries["status"], goose.FormatInt(req.GetStatus(), 10))
@@ -1789,9 +2472,66 @@ func (decoder *enumQueryGooseResponseDecoder) EnumQuery(ctx context.Context, res
 	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
 		return nil, respErr
 	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
 	resp := &httpbody.HttpBody{}
 	if err := client.DecodeHttpBody(ctx, response, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
 }
+
+type EnumQueryGooseGrpcClient interface {
+	EnumQuery(ctx context.Context, req *EnumQueryRequest, opts ...grpc.CallOption) (*httpbody.HttpBody, error)
+}
+
+func NewEnumQueryGooseGrpcClient(target string, opts ...client.Option) EnumQueryGooseGrpcClient {
+	options := client.NewOptions(opts...)
+	client := &enumQueryGooseGrpcClient{
+		enumQueryGooseClient: &enumQueryGooseClient{
+			client: options.Client(),
+			encoder: enumQueryGooseRequestEncoder{
+				target:         target,
+				marshalOptions: options.MarshalOptions(),
+				resolver:       options.Resolver(),
+				registry:       options.ResolverRegistry(),
+				pathPrefix:     options.PathPrefix(),
+			},
+			decoder: enumQueryGooseResponseDecoder{
+				unmarshalOptions: options.UnmarshalOptions(),
+				errorDecoder:     options.ErrorDecoder(),
+				errorFactory:     options.ErrorFactory(),
+			},
+			shouldFailFast:          options.ShouldFailFast(),
+			onValidationErrCallback: options.OnValidationErrCallback(),
+			middleware:              client.Chain(options.Middlewares()...),
+		},
+	}
+	return client
+}
+
+type enumQueryGooseGrpcClient struct {
+	*enumQueryGooseClient
+}
+
+func (c *enumQueryGooseGrpcClient) EnumQuery(ctx context.Context, req *EnumQueryRequest, opts ...grpc.CallOption) (resp *httpbody.HttpBody, err error) {
+	ctx = goose.WithEndpoint(ctx, enumQueryGooseEnumQueryEndpoint)
+	var response *http.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.EnumQuery(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.EnumQuery(ctx, response)
+}
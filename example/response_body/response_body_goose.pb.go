@@ -8,10 +8,12 @@ import (
 	errors "errors"
 	goose "github.com/go-leo/goose"
 	client "github.com/go-leo/goose/client"
+	grpcx "github.com/go-leo/goose/client/grpcx"
 	resolver "github.com/go-leo/goose/client/resolver"
 	server "github.com/go-leo/goose/server"
 	httpbody "google.golang.org/genproto/googleapis/api/httpbody"
 	http "google.golang.org/genproto/googleapis/rpc/http"
+	grpc "google.golang.org/grpc"
 	protojson "google.golang.org/protobuf/encoding/protojson"
 	http1 "net/http"
 	url "net/url"
@@ -26,7 +28,52 @@ type ResponseBodyGooseService interface {
 	HttpResponse(ctx context.Context, req *Request) (*http.HttpResponse, error)
 }
 
-func AppendResponseBodyGooseRoute(router *http1.ServeMux, service ResponseBodyGooseService, opts ...server.Option) *http1.ServeMux {
+var (
+	responseBodyGooseOmittedResponseEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.response_body.v1.ResponseBody/OmittedResponse",
+		Method:   "GET",
+		Pattern:  "/v1/omitted/response",
+		Request:  "leo.goose.example.response_body.v1.Request",
+		Response: "leo.goose.example.response_body.v1.Response",
+	}
+	responseBodyGooseStarResponseEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.response_body.v1.ResponseBody/StarResponse",
+		Method:   "GET",
+		Pattern:  "/v1/star/response",
+		Request:  "leo.goose.example.response_body.v1.Request",
+		Response: "leo.goose.example.response_body.v1.Response",
+	}
+	responseBodyGooseNamedResponseEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.response_body.v1.ResponseBody/NamedResponse",
+		Method:   "GET",
+		Pattern:  "/v1/named/response",
+		Request:  "leo.goose.example.response_body.v1.Request",
+		Response: "leo.goose.example.response_body.v1.NamedBodyResponse",
+	}
+	responseBodyGooseHttpBodyResponseEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.response_body.v1.ResponseBody/HttpBodyResponse",
+		Method:   "GET",
+		Pattern:  "/v1/http/body/omitted/response",
+		Request:  "leo.goose.example.response_body.v1.Request",
+		Response: "google.api.HttpBody",
+	}
+	responseBodyGooseHttpBodyNamedResponseEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.response_body.v1.ResponseBody/HttpBodyNamedResponse",
+		Method:   "GET",
+		Pattern:  "/v1/http/body/named/response",
+		Request:  "leo.goose.example.response_body.v1.Request",
+		Response: "leo.goose.example.response_body.v1.NamedHttpBodyResponse",
+	}
+	responseBodyGooseHttpResponseEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.response_body.v1.ResponseBody/HttpResponse",
+		Method:   "GET",
+		Pattern:  "/v1/http/response",
+		Request:  "leo.goose.example.response_body.v1.Request",
+		Response: "google.rpc.HttpResponse",
+	}
+)
+
+func AppendResponseBodyGooseRoute[R goose.Router](router R, service ResponseBodyGooseService, opts ...server.Option) R {
 	options := server.NewOptions(opts...)
 	handler := responseBodyGooseHandler{
 		service: service,
@@ -42,12 +89,13 @@ func AppendResponseBodyGooseRoute(router *http1.ServeMux, service ResponseBodyGo
 		onValidationErrCallback: options.OnValidationErrCallback(),
 		middleware:              server.Chain(options.Middlewares()...),
 	}
-	router.Handle("GET /v1/omitted/response", http1.HandlerFunc(handler.OmittedResponse))
-	router.Handle("GET /v1/star/response", http1.HandlerFunc(handler.StarResponse))
-	router.Handle("GET /v1/named/response", http1.HandlerFunc(handler.NamedResponse))
-	router.Handle("GET /v1/http/body/omitted/response", http1.HandlerFunc(handler.HttpBodyResponse))
-	router.Handle("GET /v1/http/body/named/response", http1.HandlerFunc(handler.HttpBodyNamedResponse))
-	router.Handle("GET /v1/http/response", http1.HandlerFunc(handler.HttpResponse))
+	registry := options.RouteRegistry()
+	registry.Handle(router, responseBodyGooseOmittedResponseEndpoint, "GET "+options.PathPrefix()+"/v1/omitted/response", http1.HandlerFunc(handler.OmittedResponse))
+	registry.Handle(router, responseBodyGooseStarResponseEndpoint, "GET "+options.PathPrefix()+"/v1/star/response", http1.HandlerFunc(handler.StarResponse))
+	registry.Handle(router, responseBodyGooseNamedResponseEndpoint, "GET "+options.PathPrefix()+"/v1/named/response", http1.HandlerFunc(handler.NamedResponse))
+	registry.Handle(router, responseBodyGooseHttpBodyResponseEndpoint, "GET "+options.PathPrefix()+"/v1/http/body/omitted/response", http1.HandlerFunc(handler.HttpBodyResponse))
+	registry.Handle(router, responseBodyGooseHttpBodyNamedResponseEndpoint, "GET "+options.PathPrefix()+"/v1/http/body/named/response", http1.HandlerFunc(handler.HttpBodyNamedResponse))
+	registry.Handle(router, responseBodyGooseHttpResponseEndpoint, "GET "+options.PathPrefix()+"/v1/http/response", http1.HandlerFunc(handler.HttpResponse))
 	return router
 }
 
@@ -350,6 +398,8 @@ func NewResponseBodyGooseClient(target string, opts ...client.Option) ResponseBo
 			target:         target,
 			marshalOptions: options.MarshalOptions(),
 			resolver:       options.Resolver(),
+			registry:       options.ResolverRegistry(),
+			pathPrefix:     options.PathPrefix(),
 		},
 		decoder: responseBodyGooseResponseDecoder{
 			unmarshalOptions: options.UnmarshalOptions(),
@@ -373,6 +423,7 @@ type responseBodyGooseClient struct {
 }
 
 func (c *responseBodyGooseClient) OmittedResponse(ctx context.Context, req *Request) (*Response, error) {
+	ctx = goose.WithEndpoint(ctx, responseBodyGooseOmittedResponseEndpoint)
 	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
 		return nil, err
 	}
@@ -392,6 +443,7 @@ func (c *responseBodyGooseClient) OmittedResponse(ctx context.Context, req *Requ
 }
 
 func (c *responseBodyGooseClient) StarResponse(ctx context.Context, req *Request) (*Response, error) {
+	ctx = goose.WithEndpoint(ctx, responseBodyGooseStarResponseEndpoint)
 	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
 		return nil, err
 	}
@@ -411,6 +463,7 @@ func (c *responseBodyGooseClient) StarResponse(ctx context.Context, req *Request
 }
 
 func (c *responseBodyGooseClient) NamedResponse(ctx context.Context, req *Request) (*NamedBodyResponse, error) {
+	ctx = goose.WithEndpoint(ctx, responseBodyGooseNamedResponseEndpoint)
 	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
 		return nil, err
 	}
@@ -430,6 +483,7 @@ func (c *responseBodyGooseClient) NamedResponse(ctx context.Context, req *Reques
 }
 
 func (c *responseBodyGooseClient) HttpBodyResponse(ctx context.Context, req *Request) (*httpbody.HttpBody, error) {
+	ctx = goose.WithEndpoint(ctx, responseBodyGooseHttpBodyResponseEndpoint)
 	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
 		return nil, err
 	}
@@ -449,6 +503,7 @@ func (c *responseBodyGooseClient) HttpBodyResponse(ctx context.Context, req *Req
 }
 
 func (c *responseBodyGooseClient) HttpBodyNamedResponse(ctx context.Context, req *Request) (*NamedHttpBodyResponse, error) {
+	ctx = goose.WithEndpoint(ctx, responseBodyGooseHttpBodyNamedResponseEndpoint)
 	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
 		return nil, err
 	}
@@ -468,6 +523,7 @@ func (c *responseBodyGooseClient) HttpBodyNamedResponse(ctx context.Context, req
 }
 
 func (c *responseBodyGooseClient) HttpResponse(ctx context.Context, req *Request) (*http.HttpResponse, error) {
+	ctx = goose.WithEndpoint(ctx, responseBodyGooseHttpResponseEndpoint)
 	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
 		return nil, err
 	}
@@ -490,13 +546,20 @@ type responseBodyGooseRequestEncoder struct {
 	target         string
 	marshalOptions protojson.MarshalOptions
 	resolver       resolver.Resolver
+	registry       *resolver.Registry
+	pathPrefix     string
+}
+
+func (encoder *responseBodyGooseRequestEncoder) resolve(ctx context.Context) (*url.URL, error) {
+	return encoder.registry.Resolve(ctx, encoder.resolver, encoder.target)
 }
 
 func (encoder *responseBodyGooseRequestEncoder) OmittedResponse(ctx context.Context, req *Request) (*http1.Request, error) {
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
-	target, err := resolver.Resolve(ctx, encoder.resolver, encoder.target)
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -504,6 +567,10 @@ func (encoder *responseBodyGooseRequestEncoder) OmittedResponse(ctx context.Cont
 	header := http1.Header{}
 	var body bytes.Buffer
 	path := "/v1/omitted/response"
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
+	if err != nil {
+		return nil, err
+	}
 	target.Path = path
 	queries := url.Values{}
 	queries["message"] = append(queries["message"], req.GetMessage())
@@ -520,7 +587,8 @@ func (encoder *responseBodyGooseRequestEncoder) StarResponse(ctx context.Context
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
-	target, err := resolver.Resolve(ctx, encoder.resolver, encoder.target)
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -528,6 +596,10 @@ func (encoder *responseBodyGooseRequestEncoder) StarResponse(ctx context.Context
 	header := http1.Header{}
 	var body bytes.Buffer
 	path := "/v1/star/response"
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
+	if err != nil {
+		return nil, err
+	}
 	target.Path = path
 	queries := url.Values{}
 	queries["message"] = append(queries["message"], req.GetMessage())
@@ -544,7 +616,8 @@ func (encoder *responseBodyGooseRequestEncoder) NamedResponse(ctx context.Contex
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
-	target, err := resolver.Resolve(ctx, encoder.resolver, encoder.target)
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -552,6 +625,10 @@ func (encoder *responseBodyGooseRequestEncoder) NamedResponse(ctx context.Contex
 	header := http1.Header{}
 	var body bytes.Buffer
 	path := "/v1/named/response"
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
+	if err != nil {
+		return nil, err
+	}
 	target.Path = path
 	queries := url.Values{}
 	queries["message"] = append(queries["message"], req.GetMessage())
@@ -568,7 +645,8 @@ func (encoder *responseBodyGooseRequestEncoder) HttpBodyResponse(ctx context.Con
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
-	target, err := resolver.Resolve(ctx, encoder.resolver, encoder.target)
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -576,6 +654,10 @@ func (encoder *responseBodyGooseRequestEncoder) HttpBodyResponse(ctx context.Con
 	header := http1.Header{}
 	var body bytes.Buffer
 	path := "/v1/http/body/omitted/response"
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
+	if err != nil {
+		return nil, err
+	}
 	target.Path = path
 	queries := url.Values{}
 	queries["message"] = append(queries["message"], req.GetMessage())
@@ -592,7 +674,8 @@ func (encoder *responseBodyGooseRequestEncoder) HttpBodyNamedResponse(ctx contex
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
-	target, err := resolver.Resolve(ctx, encoder.resolver, encoder.target)
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -600,6 +683,10 @@ func (encoder *responseBodyGooseRequestEncoder) HttpBodyNamedResponse(ctx contex
 	header := http1.Header{}
 	var body bytes.Buffer
 	path := "/v1/http/body/named/response"
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
+	if err != nil {
+		return nil, err
+	}
 	target.Path = path
 	queries := url.Values{}
 	queries["message"] = append(queries["message"], req.GetMessage())
@@ -616,7 +703,8 @@ func (encoder *responseBodyGooseRequestEncoder) HttpResponse(ctx context.Context
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
-	target, err := resolver.Resolve(ctx, encoder.resolver, encoder.target)
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -624,6 +712,10 @@ func (encoder *responseBodyGooseRequestEncoder) HttpResponse(ctx context.Context
 	header := http1.Header{}
 	var body bytes.Buffer
 	path := "/v1/http/response"
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
+	if err != nil {
+		return nil, err
+	}
 	target.Path = path
 	queries := url.Values{}
 	queries["message"] = append(queries["message"], req.GetMessage())
@@ -646,6 +738,9 @@ func (decoder *responseBodyGooseResponseDecoder) OmittedResponse(ctx context.Con
 	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
 		return nil, respErr
 	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
 	resp := &Response{}
 	if err := client.DecodeMessage(ctx, response, resp, decoder.unmarshalOptions); err != nil {
 		return nil, err
@@ -657,6 +752,9 @@ func (decoder *responseBodyGooseResponseDecoder) StarResponse(ctx context.Contex
 	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
 		return nil, respErr
 	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
 	resp := &Response{}
 	if err := client.DecodeMessage(ctx, response, resp, decoder.unmarshalOptions); err != nil {
 		return nil, err
@@ -668,6 +766,9 @@ func (decoder *responseBodyGooseResponseDecoder) NamedResponse(ctx context.Conte
 	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
 		return nil, respErr
 	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
 	resp := &NamedBodyResponse{}
 	if resp.Body == nil {
 		resp.Body = &NamedBodyResponse_Body{}
@@ -682,6 +783,9 @@ func (decoder *responseBodyGooseResponseDecoder) HttpBodyResponse(ctx context.Co
 	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
 		return nil, respErr
 	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
 	resp := &httpbody.HttpBody{}
 	if err := client.DecodeHttpBody(ctx, response, resp); err != nil {
 		return nil, err
@@ -693,6 +797,9 @@ func (decoder *responseBodyGooseResponseDecoder) HttpBodyNamedResponse(ctx conte
 	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
 		return nil, respErr
 	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
 	resp := &NamedHttpBodyResponse{}
 	if resp.Body == nil {
 		resp.Body = &httpbody.HttpBody{}
@@ -713,3 +820,167 @@ func (decoder *responseBodyGooseResponseDecoder) HttpResponse(ctx context.Contex
 	}
 	return resp, nil
 }
+
+type ResponseBodyGooseGrpcClient interface {
+	OmittedResponse(ctx context.Context, req *Request, opts ...grpc.CallOption) (*Response, error)
+	StarResponse(ctx context.Context, req *Request, opts ...grpc.CallOption) (*Response, error)
+	NamedResponse(ctx context.Context, req *Request, opts ...grpc.CallOption) (*NamedBodyResponse, error)
+	HttpBodyResponse(ctx context.Context, req *Request, opts ...grpc.CallOption) (*httpbody.HttpBody, error)
+	HttpBodyNamedResponse(ctx context.Context, req *Request, opts ...grpc.CallOption) (*NamedHttpBodyResponse, error)
+	HttpResponse(ctx context.Context, req *Request, opts ...grpc.CallOption) (*http.HttpResponse, error)
+}
+
+func NewResponseBodyGooseGrpcClient(target string, opts ...client.Option) ResponseBodyGooseGrpcClient {
+	options := client.NewOptions(opts...)
+	client := &responseBodyGooseGrpcClient{
+		responseBodyGooseClient: &responseBodyGooseClient{
+			client: options.Client(),
+			encoder: responseBodyGooseRequestEncoder{
+				target:         target,
+				marshalOptions: options.MarshalOptions(),
+				resolver:       options.Resolver(),
+				registry:       options.ResolverRegistry(),
+				pathPrefix:     options.PathPrefix(),
+			},
+			decoder: responseBodyGooseResponseDecoder{
+				unmarshalOptions: options.UnmarshalOptions(),
+				errorDecoder:     options.ErrorDecoder(),
+				errorFactory:     options.ErrorFactory(),
+			},
+			shouldFailFast:          options.ShouldFailFast(),
+			onValidationErrCallback: options.OnValidationErrCallback(),
+			middleware:              client.Chain(options.Middlewares()...),
+		},
+	}
+	return client
+}
+
+type responseBodyGooseGrpcClient struct {
+	*responseBodyGooseClient
+}
+
+func (c *responseBodyGooseGrpcClient) OmittedResponse(ctx context.Context, req *Request, opts ...grpc.CallOption) (resp *Response, err error) {
+	ctx = goose.WithEndpoint(ctx, responseBodyGooseOmittedResponseEndpoint)
+	var response *http1.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.OmittedResponse(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.OmittedResponse(ctx, response)
+}
+
+func (c *responseBodyGooseGrpcClient) StarResponse(ctx context.Context, req *Request, opts ...grpc.CallOption) (resp *Response, err error) {
+	ctx = goose.WithEndpoint(ctx, responseBodyGooseStarResponseEndpoint)
+	var response *http1.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.StarResponse(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.StarResponse(ctx, response)
+}
+
+func (c *responseBodyGooseGrpcClient) NamedResponse(ctx context.Context, req *Request, opts ...grpc.CallOption) (resp *NamedBodyResponse, err error) {
+	ctx = goose.WithEndpoint(ctx, responseBodyGooseNamedResponseEndpoint)
+	var response *http1.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.NamedResponse(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.NamedResponse(ctx, response)
+}
+
+func (c *responseBodyGooseGrpcClient) HttpBodyResponse(ctx context.Context, req *Request, opts ...grpc.CallOption) (resp *httpbody.HttpBody, err error) {
+	ctx = goose.WithEndpoint(ctx, responseBodyGooseHttpBodyResponseEndpoint)
+	var response *http1.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.HttpBodyResponse(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.HttpBodyResponse(ctx, response)
+}
+
+func (c *responseBodyGooseGrpcClient) HttpBodyNamedResponse(ctx context.Context, req *Request, opts ...grpc.CallOption) (resp *NamedHttpBodyResponse, err error) {
+	ctx = goose.WithEndpoint(ctx, responseBodyGooseHttpBodyNamedResponseEndpoint)
+	var response *http1.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.HttpBodyNamedResponse(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.HttpBodyNamedResponse(ctx, response)
+}
+
+func (c *responseBodyGooseGrpcClient) HttpResponse(ctx context.Context, req *Request, opts ...grpc.CallOption) (resp *http.HttpResponse, err error) {
+	ctx = goose.WithEndpoint(ctx, responseBodyGooseHttpResponseEndpoint)
+	var response *http1.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.HttpResponse(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.HttpResponse(ctx, response)
+}
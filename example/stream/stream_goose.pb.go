@@ -0,0 +1,427 @@
+// Code generated by protoc-gen-goose. DO NOT EDIT.
+
+package stream
+
+import (
+	bytes "bytes"
+	context "context"
+	errors "errors"
+	goose "github.com/go-leo/goose"
+	client "github.com/go-leo/goose/client"
+	resolver "github.com/go-leo/goose/client/resolver"
+	server "github.com/go-leo/goose/server"
+	protojson "google.golang.org/protobuf/encoding/protojson"
+	http "net/http"
+	url "net/url"
+)
+
+type StreamGooseService interface {
+	Upload(ctx context.Context, req *goose.HttpBodyStream) (*UploadResponse, error)
+	Download(ctx context.Context, req *DownloadRequest) (*goose.HttpBodyStream, error)
+	Echo(ctx context.Context, req *goose.HttpBodyStream) (*goose.HttpBodyStream, error)
+}
+
+var (
+	streamGooseUploadEndpoint = &goose.Endpoint{
+		FullName:         "/leo.goose.example.stream.v1.Stream/Upload",
+		Method:           "POST",
+		Pattern:          "/v1/files",
+		Request:          "google.api.HttpBody",
+		Response:         "leo.goose.example.stream.v1.UploadResponse",
+		StreamingRequest: true,
+	}
+	streamGooseDownloadEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.stream.v1.Stream/Download",
+		Method:   "GET",
+		Pattern:  "/v1/files/{name}",
+		Request:  "leo.goose.example.stream.v1.DownloadRequest",
+		Response: "google.api.HttpBody",
+	}
+	streamGooseEchoEndpoint = &goose.Endpoint{
+		FullName:         "/leo.goose.example.stream.v1.Stream/Echo",
+		Method:           "POST",
+		Pattern:          "/v1/echo",
+		Request:          "google.api.HttpBody",
+		Response:         "google.api.HttpBody",
+		StreamingRequest: true,
+	}
+)
+
+func AppendStreamGooseRoute[R goose.Router](router R, service StreamGooseService, opts ...server.Option) R {
+	options := server.NewOptions(opts...)
+	handler := streamGooseHandler{
+		service: service,
+		decoder: streamGooseRequestDecoder{
+			unmarshalOptions: options.UnmarshalOptions(),
+		},
+		encoder: streamGooseResponseEncoder{
+			marshalOptions:   options.MarshalOptions(),
+			unmarshalOptions: options.UnmarshalOptions(),
+		},
+		errorEncoder:            options.ErrorEncoder(),
+		shouldFailFast:          options.ShouldFailFast(),
+		onValidationErrCallback: options.OnValidationErrCallback(),
+		middleware:              server.Chain(options.Middlewares()...),
+	}
+	registry := options.RouteRegistry()
+	registry.Handle(router, streamGooseUploadEndpoint, "POST "+options.PathPrefix()+"/v1/files", http.HandlerFunc(handler.Upload))
+	registry.Handle(router, streamGooseDownloadEndpoint, "GET "+options.PathPrefix()+"/v1/files/{name}", http.HandlerFunc(handler.Download))
+	registry.Handle(router, streamGooseEchoEndpoint, "POST "+options.PathPrefix()+"/v1/echo", http.HandlerFunc(handler.Echo))
+	return router
+}
+
+type streamGooseHandler struct {
+	service                 StreamGooseService
+	decoder                 streamGooseRequestDecoder
+	encoder                 streamGooseResponseEncoder
+	errorEncoder            goose.ErrorEncoder
+	shouldFailFast          bool
+	onValidationErrCallback goose.OnValidationErrCallback
+	middleware              server.Middleware
+}
+
+func (h streamGooseHandler) Upload(response http.ResponseWriter, request *http.Request) {
+	invoke := func(response http.ResponseWriter, request *http.Request) {
+		ctx := request.Context()
+		req, err := h.decoder.Upload(ctx, request)
+		if err != nil {
+			h.errorEncoder(ctx, err, response)
+			return
+		}
+		resp, err := h.service.Upload(ctx, req)
+		if err != nil {
+			h.errorEncoder(ctx, err, response)
+			return
+		}
+		if err := h.encoder.Upload(ctx, response, resp); err != nil {
+			h.errorEncoder(ctx, err, response)
+			return
+		}
+	}
+	server.Invoke(h.middleware, response, request, invoke)
+}
+
+func (h streamGooseHandler) Download(response http.ResponseWriter, request *http.Request) {
+	invoke := func(response http.ResponseWriter, request *http.Request) {
+		ctx := request.Context()
+		req, err := h.decoder.Download(ctx, request)
+		if err != nil {
+			h.errorEncoder(ctx, err, response)
+			return
+		}
+		if err := goose.ValidateRequest(ctx, req, h.shouldFailFast, h.onValidationErrCallback); err != nil {
+			h.errorEncoder(ctx, err, response)
+			return
+		}
+		resp, err := h.service.Download(ctx, req)
+		if err != nil {
+			h.errorEncoder(ctx, err, response)
+			return
+		}
+		if err := h.encoder.Download(ctx, response, resp); err != nil {
+			h.errorEncoder(ctx, err, response)
+			return
+		}
+	}
+	server.Invoke(h.middleware, response, request, invoke)
+}
+
+func (h streamGooseHandler) Echo(response http.ResponseWriter, request *http.Request) {
+	invoke := func(response http.ResponseWriter, request *http.Request) {
+		ctx := request.Context()
+		req, err := h.decoder.Echo(ctx, request)
+		if err != nil {
+			h.errorEncoder(ctx, err, response)
+			return
+		}
+		resp, err := h.service.Echo(ctx, req)
+		if err != nil {
+			h.errorEncoder(ctx, err, response)
+			return
+		}
+		if err := h.encoder.Echo(ctx, response, resp); err != nil {
+			h.errorEncoder(ctx, err, response)
+			return
+		}
+	}
+	server.Invoke(h.middleware, response, request, invoke)
+}
+
+type streamGooseRequestDecoder struct {
+	unmarshalOptions protojson.UnmarshalOptions
+}
+
+func (decoder streamGooseRequestDecoder) Upload(ctx context.Context, request *http.Request) (*goose.HttpBodyStream, error) {
+	req := &goose.HttpBodyStream{}
+	if err := server.DecodeHttpBodyStream(ctx, request, req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+func (decoder streamGooseRequestDecoder) Download(ctx context.Context, request *http.Request) (*DownloadRequest, error) {
+	req := &DownloadRequest{}
+	ok, err := server.CustomDecodeRequest(ctx, request, req)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return req, nil
+	}
+	vars := goose.FormFromPath(request, "name")
+	var varErr error
+	req.Name = vars.Get("name")
+	if varErr != nil {
+		return nil, varErr
+	}
+	return req, nil
+}
+func (decoder streamGooseRequestDecoder) Echo(ctx context.Context, request *http.Request) (*goose.HttpBodyStream, error) {
+	req := &goose.HttpBodyStream{}
+	if err := server.DecodeHttpBodyStream(ctx, request, req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+type streamGooseResponseEncoder struct {
+	marshalOptions   protojson.MarshalOptions
+	unmarshalOptions protojson.UnmarshalOptions
+}
+
+func (encoder streamGooseResponseEncoder) Upload(ctx context.Context, w http.ResponseWriter, resp *UploadResponse) error {
+	return server.EncodeResponse(ctx, w, resp, encoder.marshalOptions)
+}
+func (encoder streamGooseResponseEncoder) Download(ctx context.Context, w http.ResponseWriter, resp *goose.HttpBodyStream) error {
+	return server.EncodeHttpBodyStream(ctx, w, resp)
+}
+func (encoder streamGooseResponseEncoder) Echo(ctx context.Context, w http.ResponseWriter, resp *goose.HttpBodyStream) error {
+	return server.EncodeHttpBodyStream(ctx, w, resp)
+}
+
+func NewStreamGooseClient(target string, opts ...client.Option) StreamGooseService {
+	options := client.NewOptions(opts...)
+	client := &streamGooseClient{
+		client: options.Client(),
+		encoder: streamGooseRequestEncoder{
+			target:         target,
+			marshalOptions: options.MarshalOptions(),
+			resolver:       options.Resolver(),
+			registry:       options.ResolverRegistry(),
+			pathPrefix:     options.PathPrefix(),
+		},
+		decoder: streamGooseResponseDecoder{
+			unmarshalOptions: options.UnmarshalOptions(),
+			errorDecoder:     options.ErrorDecoder(),
+			errorFactory:     options.ErrorFactory(),
+		},
+		shouldFailFast:          options.ShouldFailFast(),
+		onValidationErrCallback: options.OnValidationErrCallback(),
+		middleware:              client.Chain(options.Middlewares()...),
+	}
+	return client
+}
+
+type streamGooseClient struct {
+	client                  *http.Client
+	encoder                 streamGooseRequestEncoder
+	decoder                 streamGooseResponseDecoder
+	shouldFailFast          bool
+	onValidationErrCallback goose.OnValidationErrCallback
+	middleware              client.Middleware
+}
+
+func (c *streamGooseClient) Upload(ctx context.Context, req *goose.HttpBodyStream) (*UploadResponse, error) {
+	ctx = goose.WithEndpoint(ctx, streamGooseUploadEndpoint)
+	request, err := c.encoder.Upload(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	response, err := client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.decoder.Upload(ctx, response)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *streamGooseClient) Download(ctx context.Context, req *DownloadRequest) (*goose.HttpBodyStream, error) {
+	ctx = goose.WithEndpoint(ctx, streamGooseDownloadEndpoint)
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.Download(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	response, err := client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.decoder.Download(ctx, response)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *streamGooseClient) Echo(ctx context.Context, req *goose.HttpBodyStream) (*goose.HttpBodyStream, error) {
+	ctx = goose.WithEndpoint(ctx, streamGooseEchoEndpoint)
+	request, err := c.encoder.Echo(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	response, err := client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.decoder.Echo(ctx, response)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+type streamGooseRequestEncoder struct {
+	target         string
+	marshalOptions protojson.MarshalOptions
+	resolver       resolver.Resolver
+	registry       *resolver.Registry
+	pathPrefix     string
+}
+
+func (encoder *streamGooseRequestEncoder) resolve(ctx context.Context) (*url.URL, error) {
+	return encoder.registry.Resolve(ctx, encoder.resolver, encoder.target)
+}
+
+func (encoder *streamGooseRequestEncoder) Upload(ctx context.Context, req *goose.HttpBodyStream) (*http.Request, error) {
+	if req == nil {
+		return nil, errors.New("request is nil")
+	}
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	method := "POST"
+	header := http.Header{}
+	body := client.EncodeHttpBodyStream(ctx, req, header)
+	path := "/v1/files"
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
+	if err != nil {
+		return nil, err
+	}
+	target.Path = path
+	request, err := http.NewRequestWithContext(ctx, method, target.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	goose.CopyHeader(request.Header, header)
+	return request, nil
+}
+
+func (encoder *streamGooseRequestEncoder) Download(ctx context.Context, req *DownloadRequest) (*http.Request, error) {
+	if req == nil {
+		return nil, errors.New("request is nil")
+	}
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	method := "GET"
+	header := http.Header{}
+	var body bytes.Buffer
+	path := "/v1/files/{name}"
+	pairs := map[string]string{
+		"name": req.GetName(),
+	}
+	path = goose.URLPath(path, pairs)
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
+	if err != nil {
+		return nil, err
+	}
+	target.Path = path
+	request, err := http.NewRequestWithContext(ctx, method, target.String(), &body)
+	if err != nil {
+		return nil, err
+	}
+	goose.CopyHeader(request.Header, header)
+	return request, nil
+}
+
+func (encoder *streamGooseRequestEncoder) Echo(ctx context.Context, req *goose.HttpBodyStream) (*http.Request, error) {
+	if req == nil {
+		return nil, errors.New("request is nil")
+	}
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	method := "POST"
+	header := http.Header{}
+	body := client.EncodeHttpBodyStream(ctx, req, header)
+	path := "/v1/echo"
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
+	if err != nil {
+		return nil, err
+	}
+	target.Path = path
+	request, err := http.NewRequestWithContext(ctx, method, target.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	goose.CopyHeader(request.Header, header)
+	return request, nil
+}
+
+type streamGooseResponseDecoder struct {
+	unmarshalOptions protojson.UnmarshalOptions
+	errorDecoder     goose.ErrorDecoder
+	errorFactory     goose.ErrorFactory
+}
+
+func (decoder *streamGooseResponseDecoder) Upload(ctx context.Context, response *http.Response) (*UploadResponse, error) {
+	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
+		return nil, respErr
+	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
+	resp := &UploadResponse{}
+	if err := client.DecodeMessage(ctx, response, resp, decoder.unmarshalOptions); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (decoder *streamGooseResponseDecoder) Download(ctx context.Context, response *http.Response) (*goose.HttpBodyStream, error) {
+	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
+		return nil, respErr
+	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
+	resp := &goose.HttpBodyStream{}
+	if err := client.DecodeHttpBodyStream(ctx, response, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (decoder *streamGooseResponseDecoder) Echo(ctx context.Context, response *http.Response) (*goose.HttpBodyStream, error) {
+	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
+		return nil, respErr
+	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
+	resp := &goose.HttpBodyStream{}
+	if err := client.DecodeHttpBodyStream(ctx, response, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
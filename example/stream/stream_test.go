@@ -0,0 +1,134 @@
+package stream
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-leo/goose"
+)
+
+// ---- Mock Service ----
+
+type MockStreamService struct{}
+
+func (m *MockStreamService) Upload(ctx context.Context, req *goose.HttpBodyStream) (*UploadResponse, error) {
+	size, err := io.Copy(io.Discard, req.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &UploadResponse{ContentType: req.ContentType, Size: size}, nil
+}
+
+func (m *MockStreamService) Download(ctx context.Context, req *DownloadRequest) (*goose.HttpBodyStream, error) {
+	reader, writer := io.Pipe()
+	go func() {
+		for i := 0; i < 3; i++ {
+			if _, err := fmt.Fprintf(writer, "%s,%d\n", req.GetName(), i); err != nil {
+				writer.CloseWithError(err)
+				return
+			}
+		}
+		writer.Close()
+	}()
+	return &goose.HttpBodyStream{ContentType: "text/csv", Body: reader}, nil
+}
+
+func (m *MockStreamService) Echo(ctx context.Context, req *goose.HttpBodyStream) (*goose.HttpBodyStream, error) {
+	return &goose.HttpBodyStream{ContentType: req.ContentType, Body: req.Body}, nil
+}
+
+func runServer(server *http.Server, port int) {
+	router := http.NewServeMux()
+	router = AppendStreamGooseRoute(router, &MockStreamService{})
+	server.Addr = fmt.Sprintf(":%d", port)
+	server.Handler = router
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		panic(err)
+	}
+}
+
+func newClient(port int) StreamGooseService {
+	return NewStreamGooseClient(fmt.Sprintf("http://localhost:%d", port))
+}
+
+// ---- Test Cases ----
+
+func TestUpload(t *testing.T) {
+	server := new(http.Server)
+	defer server.Shutdown(context.Background())
+	go runServer(server, 58081)
+	time.Sleep(1 * time.Second)
+
+	const size = 32 << 20
+	client := newClient(58081)
+	resp, err := client.Upload(context.Background(), &goose.HttpBodyStream{
+		ContentType: "application/octet-stream",
+		Body:        io.NopCloser(io.LimitReader(rand.Reader, size)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.GetSize() != size {
+		t.Fatalf("size = %d, want %d", resp.GetSize(), size)
+	}
+	if resp.GetContentType() != "application/octet-stream" {
+		t.Fatalf("content type = %q, want application/octet-stream", resp.GetContentType())
+	}
+}
+
+func TestDownload(t *testing.T) {
+	server := new(http.Server)
+	defer server.Shutdown(context.Background())
+	go runServer(server, 58082)
+	time.Sleep(1 * time.Second)
+
+	client := newClient(58082)
+	resp, err := client.Download(context.Background(), &DownloadRequest{Name: "report.csv"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.ContentType != "text/csv" {
+		t.Fatalf("content type = %q, want text/csv", resp.ContentType)
+	}
+	if want := "report.csv,0\nreport.csv,1\nreport.csv,2\n"; string(data) != want {
+		t.Fatalf("body = %q, want %q", data, want)
+	}
+}
+
+func TestEcho(t *testing.T) {
+	server := new(http.Server)
+	defer server.Shutdown(context.Background())
+	go runServer(server, 58083)
+	time.Sleep(1 * time.Second)
+
+	body := strings.Repeat("goose", 1<<16)
+	client := newClient(58083)
+	resp, err := client.Echo(context.Background(), &goose.HttpBodyStream{
+		ContentType: "text/plain",
+		Body:        io.NopCloser(strings.NewReader(body)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, []byte(body)) {
+		t.Fatalf("echo body mismatch, got %d bytes, want %d", len(data), len(body))
+	}
+}
@@ -8,8 +8,10 @@ import (
 	errors "errors"
 	goose "github.com/go-leo/goose"
 	client "github.com/go-leo/goose/client"
+	grpcx "github.com/go-leo/goose/client/grpcx"
 	resolver "github.com/go-leo/goose/client/resolver"
 	server "github.com/go-leo/goose/server"
+	grpc "google.golang.org/grpc"
 	protojson "google.golang.org/protobuf/encoding/protojson"
 	http "net/http"
 	url "net/url"
@@ -24,7 +26,52 @@ type UserGooseService interface {
 	ListUser(ctx context.Context, req *ListUserRequest) (*ListUserResponse, error)
 }
 
-func AppendUserGooseRoute(router *http.ServeMux, service UserGooseService, opts ...server.Option) *http.ServeMux {
+var (
+	userGooseCreateUserEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.user.v1.User/CreateUser",
+		Method:   "POST",
+		Pattern:  "/v1/user",
+		Request:  "leo.goose.example.user.v1.CreateUserRequest",
+		Response: "leo.goose.example.user.v1.CreateUserResponse",
+	}
+	userGooseDeleteUserEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.user.v1.User/DeleteUser",
+		Method:   "DELETE",
+		Pattern:  "/v1/user/{id}",
+		Request:  "leo.goose.example.user.v1.DeleteUserRequest",
+		Response: "leo.goose.example.user.v1.DeleteUserResponse",
+	}
+	userGooseModifyUserEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.user.v1.User/ModifyUser",
+		Method:   "PUT",
+		Pattern:  "/v1/user/{id}",
+		Request:  "leo.goose.example.user.v1.ModifyUserRequest",
+		Response: "leo.goose.example.user.v1.ModifyUserResponse",
+	}
+	userGooseUpdateUserEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.user.v1.User/UpdateUser",
+		Method:   "PATCH",
+		Pattern:  "/v1/user/{id}",
+		Request:  "leo.goose.example.user.v1.UpdateUserRequest",
+		Response: "leo.goose.example.user.v1.UpdateUserResponse",
+	}
+	userGooseGetUserEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.user.v1.User/GetUser",
+		Method:   "GET",
+		Pattern:  "/v1/user/{id}",
+		Request:  "leo.goose.example.user.v1.GetUserRequest",
+		Response: "leo.goose.example.user.v1.GetUserResponse",
+	}
+	userGooseListUserEndpoint = &goose.Endpoint{
+		FullName: "/leo.goose.example.user.v1.User/ListUser",
+		Method:   "GET",
+		Pattern:  "/v1/users",
+		Request:  "leo.goose.example.user.v1.ListUserRequest",
+		Response: "leo.goose.example.user.v1.ListUserResponse",
+	}
+)
+
+func AppendUserGooseRoute[R goose.Router](router R, service UserGooseService, opts ...server.Option) R {
 	options := server.NewOptions(opts...)
 	handler := userGooseHandler{
 		service: service,
@@ -40,12 +87,13 @@ func AppendUserGooseRoute(router *http.ServeMux, service UserGooseService, opts
 		onValidationErrCallback: options.OnValidationErrCallback(),
 		middleware:              server.Chain(options.Middlewares()...),
 	}
-	router.Handle("POST /v1/user", http.HandlerFunc(handler.CreateUser))
-	router.Handle("DELETE /v1/user/{id}", http.HandlerFunc(handler.DeleteUser))
-	router.Handle("PUT /v1/user/{id}", http.HandlerFunc(handler.ModifyUser))
-	router.Handle("PATCH /v1/user/{id}", http.HandlerFunc(handler.UpdateUser))
-	router.Handle("GET /v1/user/{id}", http.HandlerFunc(handler.GetUser))
-	router.Handle("GET /v1/users", http.HandlerFunc(handler.ListUser))
+	registry := options.RouteRegistry()
+	registry.Handle(router, userGooseCreateUserEndpoint, "POST "+options.PathPrefix()+"/v1/user", http.HandlerFunc(handler.CreateUser))
+	registry.Handle(router, userGooseDeleteUserEndpoint, "DELETE "+options.PathPrefix()+"/v1/user/{id}", http.HandlerFunc(handler.DeleteUser))
+	registry.Handle(router, userGooseModifyUserEndpoint, "PUT "+options.PathPrefix()+"/v1/user/{id}", http.HandlerFunc(handler.ModifyUser))
+	registry.Handle(router, userGooseUpdateUserEndpoint, "PATCH "+options.PathPrefix()+"/v1/user/{id}", http.HandlerFunc(handler.UpdateUser))
+	registry.Handle(router, userGooseGetUserEndpoint, "GET "+options.PathPrefix()+"/v1/user/{id}", http.HandlerFunc(handler.GetUser))
+	registry.Handle(router, userGooseListUserEndpoint, "GET "+options.PathPrefix()+"/v1/users", http.HandlerFunc(handler.ListUser))
 	return router
 }
 
@@ -329,7 +377,7 @@ type userGooseResponseEncoder struct {
 }
 
 func (encoder userGooseResponseEncoder) CreateUser(ctx context.Context, w http.ResponseWriter, resp *CreateUserResponse) error {
-	return server.EncodeResponse(ctx, w, resp, encoder.marshalOptions)
+	return server.EncodeResponseWithStatus(ctx, w, resp, 201, encoder.marshalOptions)
 }
 func (encoder userGooseResponseEncoder) DeleteUser(ctx context.Context, w http.ResponseWriter, resp *DeleteUserResponse) error {
 	return server.EncodeResponse(ctx, w, resp, encoder.marshalOptions)
@@ -355,6 +403,8 @@ func NewUserGooseClient(target string, opts ...client.Option) UserGooseService {
 			target:         target,
 			marshalOptions: options.MarshalOptions(),
 			resolver:       options.Resolver(),
+			registry:       options.ResolverRegistry(),
+			pathPrefix:     options.PathPrefix(),
 		},
 		decoder: userGooseResponseDecoder{
 			unmarshalOptions: options.UnmarshalOptions(),
@@ -378,6 +428,7 @@ type userGooseClient struct {
 }
 
 func (c *userGooseClient) CreateUser(ctx context.Context, req *CreateUserRequest) (*CreateUserResponse, error) {
+	ctx = goose.WithEndpoint(ctx, userGooseCreateUserEndpoint)
 	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
 		return nil, err
 	}
@@ -397,6 +448,7 @@ func (c *userGooseClient) CreateUser(ctx context.Context, req *CreateUserRequest
 }
 
 func (c *userGooseClient) DeleteUser(ctx context.Context, req *DeleteUserRequest) (*DeleteUserResponse, error) {
+	ctx = goose.WithEndpoint(ctx, userGooseDeleteUserEndpoint)
 	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
 		return nil, err
 	}
@@ -416,6 +468,7 @@ func (c *userGooseClient) DeleteUser(ctx context.Context, req *DeleteUserRequest
 }
 
 func (c *userGooseClient) ModifyUser(ctx context.Context, req *ModifyUserRequest) (*ModifyUserResponse, error) {
+	ctx = goose.WithEndpoint(ctx, userGooseModifyUserEndpoint)
 	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
 		return nil, err
 	}
@@ -435,6 +488,7 @@ func (c *userGooseClient) ModifyUser(ctx context.Context, req *ModifyUserRequest
 }
 
 func (c *userGooseClient) UpdateUser(ctx context.Context, req *UpdateUserRequest) (*UpdateUserResponse, error) {
+	ctx = goose.WithEndpoint(ctx, userGooseUpdateUserEndpoint)
 	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
 		return nil, err
 	}
@@ -454,6 +508,7 @@ func (c *userGooseClient) UpdateUser(ctx context.Context, req *UpdateUserRequest
 }
 
 func (c *userGooseClient) GetUser(ctx context.Context, req *GetUserRequest) (*GetUserResponse, error) {
+	ctx = goose.WithEndpoint(ctx, userGooseGetUserEndpoint)
 	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
 		return nil, err
 	}
@@ -473,6 +528,7 @@ func (c *userGooseClient) GetUser(ctx context.Context, req *GetUserRequest) (*Ge
 }
 
 func (c *userGooseClient) ListUser(ctx context.Context, req *ListUserRequest) (*ListUserResponse, error) {
+	ctx = goose.WithEndpoint(ctx, userGooseListUserEndpoint)
 	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
 		return nil, err
 	}
@@ -495,13 +551,20 @@ type userGooseRequestEncoder struct {
 	target         string
 	marshalOptions protojson.MarshalOptions
 	resolver       resolver.Resolver
+	registry       *resolver.Registry
+	pathPrefix     string
+}
+
+func (encoder *userGooseRequestEncoder) resolve(ctx context.Context) (*url.URL, error) {
+	return encoder.registry.Resolve(ctx, encoder.resolver, encoder.target)
 }
 
 func (encoder *userGooseRequestEncoder) CreateUser(ctx context.Context, req *CreateUserRequest) (*http.Request, error) {
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
-	target, err := resolver.Resolve(ctx, encoder.resolver, encoder.target)
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -512,6 +575,10 @@ func (encoder *userGooseRequestEncoder) CreateUser(ctx context.Context, req *Cre
 		return nil, err
 	}
 	path := "/v1/user"
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
+	if err != nil {
+		return nil, err
+	}
 	target.Path = path
 	request, err := http.NewRequestWithContext(ctx, method, target.String(), &body)
 	if err != nil {
@@ -525,7 +592,8 @@ func (encoder *userGooseRequestEncoder) DeleteUser(ctx context.Context, req *Del
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
-	target, err := resolver.Resolve(ctx, encoder.resolver, encoder.target)
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -537,7 +605,7 @@ func (encoder *userGooseRequestEncoder) DeleteUser(ctx context.Context, req *Del
 		"id": goose.FormatInt(req.GetId(), 10),
 	}
 	path = goose.URLPath(path, pairs)
-	path, err = url.JoinPath(target.Path, path)
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
 	if err != nil {
 		return nil, err
 	}
@@ -554,7 +622,8 @@ func (encoder *userGooseRequestEncoder) ModifyUser(ctx context.Context, req *Mod
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
-	target, err := resolver.Resolve(ctx, encoder.resolver, encoder.target)
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -569,7 +638,7 @@ func (encoder *userGooseRequestEncoder) ModifyUser(ctx context.Context, req *Mod
 		"id": goose.FormatInt(req.GetId(), 10),
 	}
 	path = goose.URLPath(path, pairs)
-	path, err = url.JoinPath(target.Path, path)
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
 	if err != nil {
 		return nil, err
 	}
@@ -586,7 +655,8 @@ func (encoder *userGooseRequestEncoder) UpdateUser(ctx context.Context, req *Upd
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
-	target, err := resolver.Resolve(ctx, encoder.resolver, encoder.target)
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -601,7 +671,7 @@ func (encoder *userGooseRequestEncoder) UpdateUser(ctx context.Context, req *Upd
 		"id": goose.FormatInt(req.GetId(), 10),
 	}
 	path = goose.URLPath(path, pairs)
-	path, err = url.JoinPath(target.Path, path)
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
 	if err != nil {
 		return nil, err
 	}
@@ -618,7 +688,8 @@ func (encoder *userGooseRequestEncoder) GetUser(ctx context.Context, req *GetUse
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
-	target, err := resolver.Resolve(ctx, encoder.resolver, encoder.target)
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -630,7 +701,7 @@ func (encoder *userGooseRequestEncoder) GetUser(ctx context.Context, req *GetUse
 		"id": goose.FormatInt(req.GetId(), 10),
 	}
 	path = goose.URLPath(path, pairs)
-	path, err = url.JoinPath(target.Path, path)
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
 	if err != nil {
 		return nil, err
 	}
@@ -647,7 +718,8 @@ func (encoder *userGooseRequestEncoder) ListUser(ctx context.Context, req *ListU
 	if req == nil {
 		return nil, errors.New("request is nil")
 	}
-	target, err := resolver.Resolve(ctx, encoder.resolver, encoder.target)
+	ctx = client.WithTargetResolver(ctx, encoder.resolve)
+	target, err := encoder.resolve(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -655,6 +727,10 @@ func (encoder *userGooseRequestEncoder) ListUser(ctx context.Context, req *ListU
 	header := http.Header{}
 	var body bytes.Buffer
 	path := "/v1/users"
+	path, err = url.JoinPath(target.Path, encoder.pathPrefix, path)
+	if err != nil {
+		return nil, err
+	}
 	target.Path = path
 	queries := url.Values{}
 	queries["page_num"] = append(queries["page_num"], goose.FormatInt(req.GetPageNum(), 10))
@@ -678,6 +754,9 @@ func (decoder *userGooseResponseDecoder) CreateUser(ctx context.Context, respons
 	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
 		return nil, respErr
 	}
+	if err := client.CheckStatus(response, 201); err != nil {
+		return nil, err
+	}
 	resp := &CreateUserResponse{}
 	if err := client.DecodeMessage(ctx, response, resp, decoder.unmarshalOptions); err != nil {
 		return nil, err
@@ -689,6 +768,9 @@ func (decoder *userGooseResponseDecoder) DeleteUser(ctx context.Context, respons
 	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
 		return nil, respErr
 	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
 	resp := &DeleteUserResponse{}
 	if err := client.DecodeMessage(ctx, response, resp, decoder.unmarshalOptions); err != nil {
 		return nil, err
@@ -700,6 +782,9 @@ func (decoder *userGooseResponseDecoder) ModifyUser(ctx context.Context, respons
 	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
 		return nil, respErr
 	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
 	resp := &ModifyUserResponse{}
 	if err := client.DecodeMessage(ctx, response, resp, decoder.unmarshalOptions); err != nil {
 		return nil, err
@@ -711,6 +796,9 @@ func (decoder *userGooseResponseDecoder) UpdateUser(ctx context.Context, respons
 	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
 		return nil, respErr
 	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
 	resp := &UpdateUserResponse{}
 	if err := client.DecodeMessage(ctx, response, resp, decoder.unmarshalOptions); err != nil {
 		return nil, err
@@ -722,6 +810,9 @@ func (decoder *userGooseResponseDecoder) GetUser(ctx context.Context, response *
 	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
 		return nil, respErr
 	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
 	resp := &GetUserResponse{}
 	if err := client.DecodeMessage(ctx, response, resp, decoder.unmarshalOptions); err != nil {
 		return nil, err
@@ -733,9 +824,176 @@ func (decoder *userGooseResponseDecoder) ListUser(ctx context.Context, response
 	if respErr, ok := decoder.errorDecoder(ctx, response, decoder.errorFactory); ok {
 		return nil, respErr
 	}
+	if err := client.CheckStatus(response); err != nil {
+		return nil, err
+	}
 	resp := &ListUserResponse{}
 	if err := client.DecodeMessage(ctx, response, resp, decoder.unmarshalOptions); err != nil {
 		return nil, err
 	}
 	return resp, nil
 }
+
+type UserGooseGrpcClient interface {
+	CreateUser(ctx context.Context, req *CreateUserRequest, opts ...grpc.CallOption) (*CreateUserResponse, error)
+	DeleteUser(ctx context.Context, req *DeleteUserRequest, opts ...grpc.CallOption) (*DeleteUserResponse, error)
+	ModifyUser(ctx context.Context, req *ModifyUserRequest, opts ...grpc.CallOption) (*ModifyUserResponse, error)
+	UpdateUser(ctx context.Context, req *UpdateUserRequest, opts ...grpc.CallOption) (*UpdateUserResponse, error)
+	GetUser(ctx context.Context, req *GetUserRequest, opts ...grpc.CallOption) (*GetUserResponse, error)
+	ListUser(ctx context.Context, req *ListUserRequest, opts ...grpc.CallOption) (*ListUserResponse, error)
+}
+
+func NewUserGooseGrpcClient(target string, opts ...client.Option) UserGooseGrpcClient {
+	options := client.NewOptions(opts...)
+	client := &userGooseGrpcClient{
+		userGooseClient: &userGooseClient{
+			client: options.Client(),
+			encoder: userGooseRequestEncoder{
+				target:         target,
+				marshalOptions: options.MarshalOptions(),
+				resolver:       options.Resolver(),
+				registry:       options.ResolverRegistry(),
+				pathPrefix:     options.PathPrefix(),
+			},
+			decoder: userGooseResponseDecoder{
+				unmarshalOptions: options.UnmarshalOptions(),
+				errorDecoder:     options.ErrorDecoder(),
+				errorFactory:     options.ErrorFactory(),
+			},
+			shouldFailFast:          options.ShouldFailFast(),
+			onValidationErrCallback: options.OnValidationErrCallback(),
+			middleware:              client.Chain(options.Middlewares()...),
+		},
+	}
+	return client
+}
+
+type userGooseGrpcClient struct {
+	*userGooseClient
+}
+
+func (c *userGooseGrpcClient) CreateUser(ctx context.Context, req *CreateUserRequest, opts ...grpc.CallOption) (resp *CreateUserResponse, err error) {
+	ctx = goose.WithEndpoint(ctx, userGooseCreateUserEndpoint)
+	var response *http.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.CreateUser(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.CreateUser(ctx, response)
+}
+
+func (c *userGooseGrpcClient) DeleteUser(ctx context.Context, req *DeleteUserRequest, opts ...grpc.CallOption) (resp *DeleteUserResponse, err error) {
+	ctx = goose.WithEndpoint(ctx, userGooseDeleteUserEndpoint)
+	var response *http.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.DeleteUser(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.DeleteUser(ctx, response)
+}
+
+func (c *userGooseGrpcClient) ModifyUser(ctx context.Context, req *ModifyUserRequest, opts ...grpc.CallOption) (resp *ModifyUserResponse, err error) {
+	ctx = goose.WithEndpoint(ctx, userGooseModifyUserEndpoint)
+	var response *http.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.ModifyUser(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.ModifyUser(ctx, response)
+}
+
+func (c *userGooseGrpcClient) UpdateUser(ctx context.Context, req *UpdateUserRequest, opts ...grpc.CallOption) (resp *UpdateUserResponse, err error) {
+	ctx = goose.WithEndpoint(ctx, userGooseUpdateUserEndpoint)
+	var response *http.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.UpdateUser(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.UpdateUser(ctx, response)
+}
+
+func (c *userGooseGrpcClient) GetUser(ctx context.Context, req *GetUserRequest, opts ...grpc.CallOption) (resp *GetUserResponse, err error) {
+	ctx = goose.WithEndpoint(ctx, userGooseGetUserEndpoint)
+	var response *http.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.GetUser(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.GetUser(ctx, response)
+}
+
+func (c *userGooseGrpcClient) ListUser(ctx context.Context, req *ListUserRequest, opts ...grpc.CallOption) (resp *ListUserResponse, err error) {
+	ctx = goose.WithEndpoint(ctx, userGooseListUserEndpoint)
+	var response *http.Response
+	defer func() {
+		grpcx.AfterCall(response, err, opts...)
+	}()
+	if err := goose.ValidateRequest(ctx, req, c.shouldFailFast, c.onValidationErrCallback); err != nil {
+		return nil, err
+	}
+	request, err := c.encoder.ListUser(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	grpcx.AppendOutgoingHeader(ctx, request.Header)
+	response, err = client.Invoke(c.middleware, c.client, request)
+	if err != nil {
+		return nil, err
+	}
+	return c.decoder.ListUser(ctx, response)
+}
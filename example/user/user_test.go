@@ -5,8 +5,16 @@ import (
 	errors "errors"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
+
+	"github.com/go-leo/goose/app"
+	"github.com/go-leo/goose/client"
+	"github.com/go-leo/goose/client/resolver"
+	"github.com/go-leo/goose/server"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 // ---- Mock Service ----
@@ -66,11 +74,15 @@ func TestCreateUser(t *testing.T) {
 	go runServer(server, 8081)
 	time.Sleep(1 * time.Second)
 
-	client := newClient(8081)
-	resp, err := client.CreateUser(context.Background(), &CreateUserRequest{Name: "alice"})
+	cli := newClient(8081)
+	var info client.CallInfo
+	resp, err := cli.CreateUser(client.WithCallInfo(context.Background(), &info), &CreateUserRequest{Name: "alice"})
 	if err != nil {
 		t.Fatal(err)
 	}
+	if info.StatusCode != http.StatusCreated {
+		t.Fatalf("status code = %d, want 201", info.StatusCode)
+	}
 	if resp.GetItem().GetName() != "hello" && resp.GetItem().GetId() != 1 {
 		t.Fatal("resp is not equal")
 	}
@@ -157,3 +169,109 @@ func TestListUser(t *testing.T) {
 		t.Fatal("resp is not equal")
 	}
 }
+
+func TestGrpcClient(t *testing.T) {
+	server := new(http.Server)
+	defer server.Shutdown(context.Background())
+	go runServer(server, 8087)
+	time.Sleep(1 * time.Second)
+
+	client := NewUserGooseGrpcClient(fmt.Sprintf("http://localhost:%d", 8087))
+	var header metadata.MD
+	resp, err := client.GetUser(context.Background(), &GetUserRequest{Id: 3}, grpc.Header(&header))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.GetItem().GetName() != "bob" || resp.GetItem().GetId() != 3 {
+		t.Fatal("resp is not equal")
+	}
+	if len(header.Get("content-type")) == 0 {
+		t.Fatal("response header is not received")
+	}
+}
+
+func TestResolverRegistry(t *testing.T) {
+	server := new(http.Server)
+	defer server.Shutdown(context.Background())
+	go runServer(server, 8088)
+	time.Sleep(1 * time.Second)
+
+	// the "users" scheme is only known by the registry of the client
+	lb := resolver.NewBalancingResolver("users", resolver.StaticResolver{Addresses: []string{"localhost:8088"}}, nil)
+	cli := NewUserGooseClient("users://", client.ResolverRegistry(resolver.NewRegistry(lb)))
+	resp, err := cli.GetUser(context.Background(), &GetUserRequest{Id: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.GetItem().GetId() != 3 {
+		t.Fatal("resp is not equal")
+	}
+
+	if _, err := newClient(8088).GetUser(context.Background(), &GetUserRequest{Id: 3}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewUserGooseClient("users://").GetUser(context.Background(), &GetUserRequest{Id: 3}); err == nil {
+		t.Fatal("the users scheme leaked to the default registry")
+	}
+}
+
+func TestPathPrefix(t *testing.T) {
+	router := AppendUserGooseRoute(http.NewServeMux(), &MockUserService{}, server.PathPrefix("/api/internal"))
+	srv := &http.Server{Addr: ":8089", Handler: router}
+	defer srv.Shutdown(context.Background())
+	go srv.ListenAndServe()
+	time.Sleep(1 * time.Second)
+
+	cli := NewUserGooseClient("http://localhost:8089", client.PathPrefix("api/internal"))
+	resp, err := cli.GetUser(context.Background(), &GetUserRequest{Id: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.GetItem().GetId() != 5 {
+		t.Fatal("resp is not equal")
+	}
+
+	// the prefix also applies to the routes without path variables
+	created, err := cli.CreateUser(context.Background(), &CreateUserRequest{Name: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.GetItem().GetName() != "alice" {
+		t.Fatal("resp is not equal")
+	}
+	list, err := cli.ListUser(context.Background(), &ListUserRequest{PageNum: 1, PageSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.GetPageSize() != 10 {
+		t.Fatal("resp is not equal")
+	}
+
+	// the target path comes before the prefix
+	cli = NewUserGooseClient("http://localhost:8089/api", client.PathPrefix("internal"))
+	if _, err := cli.GetUser(context.Background(), &GetUserRequest{Id: 5}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cli.ListUser(context.Background(), &ListUserRequest{PageNum: 1, PageSize: 10}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := newClient(8089).GetUser(context.Background(), &GetUserRequest{Id: 5}); err == nil {
+		t.Fatal("the route was not mounted under the prefix")
+	}
+}
+
+func TestAppService(t *testing.T) {
+	a := app.New(app.Service(AppendUserGooseRoute, UserGooseService(&MockUserService{}), server.PathPrefix("/api")))
+	srv := httptest.NewServer(a.Handler())
+	defer srv.Close()
+
+	cli := NewUserGooseClient(srv.URL, client.PathPrefix("/api"))
+	resp, err := cli.GetUser(context.Background(), &GetUserRequest{Id: 9})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.GetItem().GetId() != 9 {
+		t.Fatal("resp is not equal")
+	}
+}
@@ -0,0 +1,369 @@
+// Package health provides liveness and readiness checking with per-service serving status,
+// in the spirit of the gRPC health checking protocol
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Status is the serving status of a check, a service or the whole server
+type Status int
+
+const (
+	// Unknown is the status of a check that has not reported yet
+	Unknown Status = iota
+	// Serving is the status of a healthy check, service or server
+	Serving
+	// NotServing is the status of a failed check, a stopped service or a server shutting down
+	NotServing
+	// ServiceUnknown is the status of a service that is not registered
+	ServiceUnknown
+)
+
+// String returns the name of the status, as in the gRPC health checking protocol
+func (s Status) String() string {
+	switch s {
+	case Serving:
+		return "SERVING"
+	case NotServing:
+		return "NOT_SERVING"
+	case ServiceUnknown:
+		return "SERVICE_UNKNOWN"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// MarshalJSON encodes the status as its name
+func (s Status) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// Check checks a dependency of the server, such as a database, returning an error if it is unhealthy
+type Check func(ctx context.Context) error
+
+// CheckResult is the result of a check
+type CheckResult struct {
+	Status   Status `json:"status"`          // Serving if the check succeeded
+	Error    string `json:"error,omitempty"` // Error of the failed check
+	Duration string `json:"duration"`        // Time the check took
+}
+
+// Report is the result of a liveness or readiness probe
+type Report struct {
+	Status   Status                 `json:"status"`             // Serving if all the checks succeeded and the server is serving
+	Checks   map[string]CheckResult `json:"checks,omitempty"`   // Results of the checks, by name
+	Services map[string]Status      `json:"services,omitempty"` // Serving status of the services, readiness only
+}
+
+// ErrTimeout is the error of a check that did not finish within the timeout
+var ErrTimeout = errors.New("health: check timed out")
+
+// options holds configuration options for the checker
+type options struct {
+	timeout time.Duration // Timeout of each check
+}
+
+// apply applies the given options to the options struct
+// Parameters:
+//   - opts: Variable number of Option functions
+//
+// Returns:
+//   - *options: Pointer to the updated options struct
+func (o *options) apply(opts ...Option) *options {
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Option is a function type for configuring the checker
+type Option func(o *options)
+
+// defaultOptions returns the default configuration options
+// Returns:
+//   - *options: Default options, a timeout of 1 second
+func defaultOptions() *options {
+	return &options{
+		timeout: time.Second,
+	}
+}
+
+// Timeout sets the timeout of each check, a slow check is reported as failed with ErrTimeout
+// Parameters:
+//   - d: Timeout duration
+//
+// Returns:
+//   - Option: Function to set the timeout option
+func Timeout(d time.Duration) Option {
+	return func(o *options) {
+		o.timeout = d
+	}
+}
+
+// DefaultChecker is the checker used by goose.AppendHealth
+var DefaultChecker = NewChecker()
+
+// Checker runs the liveness and readiness checks and keeps the serving status of the services
+type Checker struct {
+	opt        *options
+	mu         sync.RWMutex
+	liveness   map[string]Check
+	readiness  map[string]Check
+	services   map[string]Status // Serving status by service name, "" is the whole server
+	isShutdown bool
+}
+
+// NewChecker creates a checker, serving with no check
+// Parameters:
+//   - opts: Variable number of Option functions for configuration
+//
+// Returns:
+//   - *Checker: The checker
+func NewChecker(opts ...Option) *Checker {
+	return &Checker{
+		opt:       defaultOptions().apply(opts...),
+		liveness:  make(map[string]Check),
+		readiness: make(map[string]Check),
+		services:  map[string]Status{"": Serving},
+	}
+}
+
+// AddLivenessCheck adds a check telling whether the process must be restarted, such as a deadlock detector
+// Parameters:
+//   - name: Name of the check, replacing the check with the same name
+//   - check: The check
+func (c *Checker) AddLivenessCheck(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.liveness[name] = check
+}
+
+// AddReadinessCheck adds a check telling whether the server can handle requests, such as a database ping
+// Parameters:
+//   - name: Name of the check, replacing the check with the same name
+//   - check: The check
+func (c *Checker) AddReadinessCheck(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.readiness[name] = check
+}
+
+// SetServingStatus sets the serving status of a service, ignored after Shutdown
+// Parameters:
+//   - service: Name of the service, such as "leo.example.user.v1.User", or "" for the whole server
+//   - status: The serving status
+func (c *Checker) SetServingStatus(service string, status Status) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.isShutdown {
+		return
+	}
+	c.services[service] = status
+}
+
+// ServingStatus returns the serving status of a service
+// Parameters:
+//   - service: Name of the service, or "" for the whole server
+//
+// Returns:
+//   - Status: The serving status, ServiceUnknown if the service is not registered
+func (c *Checker) ServingStatus(service string) Status {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	status, ok := c.services[service]
+	if !ok {
+		return ServiceUnknown
+	}
+	return status
+}
+
+// Shutdown sets all the services to NotServing and ignores the later status updates,
+// so that load balancers stop sending requests before the server stops
+func (c *Checker) Shutdown() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.isShutdown = true
+	for service := range c.services {
+		c.services[service] = NotServing
+	}
+}
+
+// Resume sets the whole server to Serving and accepts the status updates again.
+// The services keep their status, set with SetServingStatus by their owners.
+func (c *Checker) Resume() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.isShutdown = false
+	c.services[""] = Serving
+}
+
+// Liveness runs the liveness checks, the serving status is ignored since a server shutting down is still alive
+// Parameters:
+//   - ctx: Context of the checks
+//
+// Returns:
+//   - Report: Serving if all the liveness checks succeeded
+func (c *Checker) Liveness(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := maps.Clone(c.liveness)
+	c.mu.RUnlock()
+	return c.run(ctx, checks)
+}
+
+// Readiness runs the readiness checks and reports the serving status of the services
+// Parameters:
+//   - ctx: Context of the checks
+//
+// Returns:
+//   - Report: Serving if all the readiness checks succeeded and the server is serving
+func (c *Checker) Readiness(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := maps.Clone(c.readiness)
+	services := make(map[string]Status, len(c.services))
+	for service, status := range c.services {
+		if service != "" {
+			services[service] = status
+		}
+	}
+	serving := c.services[""]
+	c.mu.RUnlock()
+	report := c.run(ctx, checks)
+	if serving != Serving {
+		report.Status = serving
+	}
+	if len(services) > 0 {
+		report.Services = services
+	}
+	return report
+}
+
+// run runs the checks concurrently, each one bounded by the timeout
+func (c *Checker) run(ctx context.Context, checks map[string]Check) Report {
+	report := Report{Status: Serving}
+	if len(checks) == 0 {
+		return report
+	}
+	type result struct {
+		name   string
+		result CheckResult
+	}
+	results := make(chan result, len(checks))
+	for name, check := range checks {
+		go func() {
+			results <- result{name: name, result: c.runCheck(ctx, check)}
+		}()
+	}
+	report.Checks = make(map[string]CheckResult, len(checks))
+	for range checks {
+		r := <-results
+		report.Checks[r.name] = r.result
+		if r.result.Status != Serving {
+			report.Status = NotServing
+		}
+	}
+	return report
+}
+
+// runCheck runs a check, giving up when the timeout elapses even if the check ignores its context
+func (c *Checker) runCheck(ctx context.Context, check Check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.opt.timeout)
+	defer cancel()
+	start := time.Now()
+	errc := make(chan error, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				errc <- fmt.Errorf("health: check panicked: %v", p)
+			}
+		}()
+		errc <- check(ctx)
+	}()
+	var err error
+	select {
+	case err = <-errc:
+	case <-ctx.Done():
+		err = ctx.Err()
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = ErrTimeout
+		}
+	}
+	result := CheckResult{Status: Serving, Duration: time.Since(start).String()}
+	if err != nil {
+		result.Status = NotServing
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// LivenessHandler returns an HTTP handler of the liveness probe
+// Returns:
+//   - http.Handler: Handler writing the JSON report, with status 200 if serving, 503 otherwise
+func (c *Checker) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		writeReport(response, c.Liveness(request.Context()))
+	})
+}
+
+// ReadinessHandler returns an HTTP handler of the readiness probe.
+// With a "service" query parameter, only the serving status of that service is reported, like the gRPC Check method.
+// Returns:
+//   - http.Handler: Handler writing the JSON report, with status 200 if serving, 503 otherwise,
+//     404 for an unknown service
+func (c *Checker) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		if !request.URL.Query().Has("service") {
+			writeReport(response, c.Readiness(request.Context()))
+			return
+		}
+		writeReport(response, Report{Status: c.ServingStatus(request.URL.Query().Get("service"))})
+	})
+}
+
+// writeReport writes a report as JSON
+func writeReport(response http.ResponseWriter, report Report) {
+	statusCode := http.StatusOK
+	switch report.Status {
+	case Serving:
+	case ServiceUnknown:
+		statusCode = http.StatusNotFound
+	default:
+		statusCode = http.StatusServiceUnavailable
+	}
+	response.Header().Set("Content-Type", "application/json; charset=utf-8")
+	response.Header().Set("Cache-Control", "no-store")
+	response.WriteHeader(statusCode)
+	_ = json.NewEncoder(response).Encode(report)
+}
+
+// Router registers the health endpoints, such as *http.ServeMux or a router adapter.
+// It has the method set of goose.Router, which this package cannot import.
+type Router interface {
+	// Handle registers the handler for the given pattern
+	// Parameters:
+	//   - pattern: Pattern in the syntax of http.ServeMux, such as "GET /health"
+	//   - handler: The handler
+	Handle(pattern string, handler http.Handler)
+}
+
+// Append registers the health endpoints of a checker on the router:
+// "GET /health" and "GET /health/ready" for readiness, "GET /health/live" for liveness
+// Parameters:
+//   - router: The router to register on
+//   - checker: The checker
+//
+// Returns:
+//   - R: The router
+func Append[R Router](router R, checker *Checker) R {
+	router.Handle("GET /health", checker.ReadinessHandler())
+	router.Handle("GET /health/ready", checker.ReadinessHandler())
+	router.Handle("GET /health/live", checker.LivenessHandler())
+	return router
+}
@@ -0,0 +1,173 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestReadiness(t *testing.T) {
+	checker := NewChecker()
+	checker.AddReadinessCheck("db", func(ctx context.Context) error { return nil })
+	report := checker.Readiness(context.Background())
+	if report.Status != Serving || report.Checks["db"].Status != Serving {
+		t.Fatalf("expected serving, got %+v", report)
+	}
+
+	checker.AddReadinessCheck("cache", func(ctx context.Context) error { return errors.New("unreachable") })
+	report = checker.Readiness(context.Background())
+	if report.Status != NotServing {
+		t.Fatalf("expected not serving, got %v", report.Status)
+	}
+	if got := report.Checks["cache"]; got.Status != NotServing || got.Error != "unreachable" {
+		t.Fatalf("unexpected cache result: %+v", got)
+	}
+	if report.Checks["db"].Status != Serving {
+		t.Fatalf("expected db serving, got %+v", report.Checks["db"])
+	}
+}
+
+func TestLivenessIgnoresReadiness(t *testing.T) {
+	checker := NewChecker()
+	checker.AddReadinessCheck("db", func(ctx context.Context) error { return errors.New("down") })
+	checker.Shutdown()
+	if report := checker.Liveness(context.Background()); report.Status != Serving {
+		t.Fatalf("expected alive, got %v", report.Status)
+	}
+}
+
+func TestCheckTimeout(t *testing.T) {
+	checker := NewChecker(Timeout(10 * time.Millisecond))
+	block := make(chan struct{})
+	defer close(block)
+	checker.AddLivenessCheck("slow", func(ctx context.Context) error {
+		<-block
+		return nil
+	})
+	start := time.Now()
+	report := checker.Liveness(context.Background())
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("check was not bounded by the timeout: %v", elapsed)
+	}
+	if got := report.Checks["slow"]; got.Status != NotServing || got.Error != ErrTimeout.Error() {
+		t.Fatalf("expected timeout, got %+v", got)
+	}
+}
+
+func TestCheckPanic(t *testing.T) {
+	checker := NewChecker()
+	checker.AddLivenessCheck("panic", func(ctx context.Context) error { panic("boom") })
+	if report := checker.Liveness(context.Background()); report.Checks["panic"].Status != NotServing {
+		t.Fatalf("expected not serving, got %+v", report.Checks["panic"])
+	}
+}
+
+func TestServingStatus(t *testing.T) {
+	checker := NewChecker()
+	if got := checker.ServingStatus("leo.example.user.v1.User"); got != ServiceUnknown {
+		t.Fatalf("expected service unknown, got %v", got)
+	}
+	checker.SetServingStatus("leo.example.user.v1.User", Serving)
+	checker.SetServingStatus("leo.example.order.v1.Order", NotServing)
+	if got := checker.ServingStatus("leo.example.user.v1.User"); got != Serving {
+		t.Fatalf("expected serving, got %v", got)
+	}
+	report := checker.Readiness(context.Background())
+	if report.Status != Serving || report.Services["leo.example.order.v1.Order"] != NotServing {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	checker.Shutdown()
+	checker.SetServingStatus("leo.example.user.v1.User", Serving)
+	if got := checker.ServingStatus("leo.example.user.v1.User"); got != NotServing {
+		t.Fatalf("expected not serving after shutdown, got %v", got)
+	}
+	if got := checker.Readiness(context.Background()).Status; got != NotServing {
+		t.Fatalf("expected server not serving after shutdown, got %v", got)
+	}
+
+	checker.Resume()
+	if got := checker.ServingStatus(""); got != Serving {
+		t.Fatalf("expected serving after resume, got %v", got)
+	}
+	// the services are not resumed behind the back of their owners
+	if got := checker.ServingStatus("leo.example.user.v1.User"); got != NotServing {
+		t.Fatalf("expected the service to stay not serving after resume, got %v", got)
+	}
+	checker.SetServingStatus("leo.example.user.v1.User", Serving)
+	if got := checker.ServingStatus("leo.example.user.v1.User"); got != Serving {
+		t.Fatalf("expected the status updates accepted after resume, got %v", got)
+	}
+}
+
+func TestAppend(t *testing.T) {
+	checker := NewChecker()
+	checker.SetServingStatus("leo.example.user.v1.User", Serving)
+	router := Append(http.NewServeMux(), checker)
+
+	tests := []struct {
+		name       string
+		target     string
+		shutdown   bool
+		wantCode   int
+		wantStatus string
+	}{
+		{name: "health", target: "/health", wantCode: http.StatusOK, wantStatus: "SERVING"},
+		{name: "ready", target: "/health/ready", wantCode: http.StatusOK, wantStatus: "SERVING"},
+		{name: "live", target: "/health/live", wantCode: http.StatusOK, wantStatus: "SERVING"},
+		{name: "service", target: "/health/ready?service=leo.example.user.v1.User", wantCode: http.StatusOK, wantStatus: "SERVING"},
+		{name: "unknown service", target: "/health/ready?service=unknown", wantCode: http.StatusNotFound, wantStatus: "SERVICE_UNKNOWN"},
+		{name: "shutdown ready", target: "/health/ready", shutdown: true, wantCode: http.StatusServiceUnavailable, wantStatus: "NOT_SERVING"},
+		{name: "shutdown live", target: "/health/live", shutdown: true, wantCode: http.StatusOK, wantStatus: "SERVING"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.shutdown {
+				checker.Shutdown()
+				defer checker.Resume()
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			if rr.Code != tt.wantCode {
+				t.Fatalf("expected code %d, got %d", tt.wantCode, rr.Code)
+			}
+			if ct := rr.Header().Get("Content-Type"); ct != "application/json; charset=utf-8" {
+				t.Fatalf("unexpected content type %q", ct)
+			}
+			var body struct {
+				Status string `json:"status"`
+			}
+			if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Status != tt.wantStatus {
+				t.Fatalf("expected status %s, got %s", tt.wantStatus, body.Status)
+			}
+		})
+	}
+}
+
+// patternRouter is a Router other than *http.ServeMux, recording the registered patterns
+type patternRouter struct {
+	patterns []string
+}
+
+func (r *patternRouter) Handle(pattern string, handler http.Handler) {
+	r.patterns = append(r.patterns, pattern)
+}
+
+func TestAppendRouter(t *testing.T) {
+	router := &patternRouter{}
+	if got := Append(router, NewChecker()); got != router {
+		t.Fatal("expected the router to be returned")
+	}
+	want := []string{"GET /health", "GET /health/ready", "GET /health/live"}
+	if !slices.Equal(router.patterns, want) {
+		t.Fatalf("expected patterns %v, got %v", want, router.patterns)
+	}
+}
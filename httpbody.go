@@ -0,0 +1,18 @@
+package goose
+
+import "io"
+
+// HttpBodyStream is the streaming counterpart of google.api.HttpBody.
+// Methods declared with `stream google.api.HttpBody` as request or response
+// use it instead of *httpbody.HttpBody, so that large bodies such as file
+// uploads and downloads are never buffered in memory.
+type HttpBodyStream struct {
+	// ContentType is the HTTP Content-Type header of the body
+	ContentType string
+	// Body is the streamed content.
+	// On the server side, the request body is closed by the HTTP server and the
+	// response body is closed by the encoder once it has been copied.
+	// On the client side, the request body is closed by the HTTP client and the
+	// response body must be closed by the caller.
+	Body io.ReadCloser
+}
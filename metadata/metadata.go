@@ -0,0 +1,199 @@
+// Package metadata provides request metadata carried in the context, the goose
+// equivalent of gRPC metadata. Incoming metadata is built from the HTTP request
+// headers on the server side, outgoing metadata is sent as HTTP request headers
+// on the client side.
+package metadata
+
+import (
+	"context"
+	"strings"
+)
+
+// MD is a mapping from metadata keys to values. Keys are always lower-cased.
+type MD map[string][]string
+
+// New creates an MD from a given key-value map.
+//
+// Parameters:
+//   - m: The key-value map, keys are lower-cased
+//
+// Returns:
+//   - MD: The created metadata
+func New(m map[string]string) MD {
+	md := make(MD, len(m))
+	for key, value := range m {
+		key = strings.ToLower(key)
+		md[key] = append(md[key], value)
+	}
+	return md
+}
+
+// Pairs returns an MD formed by the mapping of key, value ...
+// Pairs panics if len(kv) is odd.
+//
+// Parameters:
+//   - kv: Key-value pairs, keys are lower-cased
+//
+// Returns:
+//   - MD: The created metadata
+func Pairs(kv ...string) MD {
+	if len(kv)%2 == 1 {
+		panic("metadata: Pairs got an odd number of input pairs")
+	}
+	md := make(MD, len(kv)/2)
+	for i := 0; i < len(kv); i += 2 {
+		key := strings.ToLower(kv[i])
+		md[key] = append(md[key], kv[i+1])
+	}
+	return md
+}
+
+// Len returns the number of items in md.
+func (md MD) Len() int {
+	return len(md)
+}
+
+// Copy returns a copy of md.
+func (md MD) Copy() MD {
+	out := make(MD, len(md))
+	for key, values := range md {
+		out[key] = append([]string(nil), values...)
+	}
+	return out
+}
+
+// Get obtains the values for a given key.
+//
+// Parameters:
+//   - key: The key, lower-cased before the lookup
+//
+// Returns:
+//   - []string: The values of the key
+func (md MD) Get(key string) []string {
+	return md[strings.ToLower(key)]
+}
+
+// Set sets the values of a given key, replacing any existing values.
+//
+// Parameters:
+//   - key: The key, lower-cased before it is stored
+//   - values: The values to set
+func (md MD) Set(key string, values ...string) {
+	if len(values) == 0 {
+		return
+	}
+	md[strings.ToLower(key)] = values
+}
+
+// Append adds the values to key, keeping the existing values.
+//
+// Parameters:
+//   - key: The key, lower-cased before it is stored
+//   - values: The values to append
+func (md MD) Append(key string, values ...string) {
+	if len(values) == 0 {
+		return
+	}
+	key = strings.ToLower(key)
+	md[key] = append(md[key], values...)
+}
+
+// Delete removes the values for a given key.
+//
+// Parameters:
+//   - key: The key, lower-cased before the removal
+func (md MD) Delete(key string) {
+	delete(md, strings.ToLower(key))
+}
+
+// Join joins any number of mds into a single MD.
+// The order of values for each key is determined by the order in which the mds are given.
+//
+// Parameters:
+//   - mds: The metadata to join
+//
+// Returns:
+//   - MD: The joined metadata
+func Join(mds ...MD) MD {
+	out := MD{}
+	for _, md := range mds {
+		for key, values := range md {
+			out[key] = append(out[key], values...)
+		}
+	}
+	return out
+}
+
+// incomingKey is the context key of the incoming metadata
+type incomingKey struct{}
+
+// outgoingKey is the context key of the outgoing metadata
+type outgoingKey struct{}
+
+// NewIncomingContext creates a new context with incoming md attached.
+//
+// Parameters:
+//   - ctx: The parent context
+//   - md: The incoming metadata
+//
+// Returns:
+//   - context.Context: The context carrying the incoming metadata
+func NewIncomingContext(ctx context.Context, md MD) context.Context {
+	return context.WithValue(ctx, incomingKey{}, md)
+}
+
+// FromIncomingContext returns the incoming metadata in ctx if it exists.
+// The returned MD must not be modified, use Copy first.
+//
+// Parameters:
+//   - ctx: The context that may carry incoming metadata
+//
+// Returns:
+//   - MD: The incoming metadata
+//   - bool: True if the context carries incoming metadata
+func FromIncomingContext(ctx context.Context) (MD, bool) {
+	md, ok := ctx.Value(incomingKey{}).(MD)
+	return md, ok
+}
+
+// NewOutgoingContext creates a new context with outgoing md attached,
+// replacing any outgoing metadata already attached.
+//
+// Parameters:
+//   - ctx: The parent context
+//   - md: The outgoing metadata
+//
+// Returns:
+//   - context.Context: The context carrying the outgoing metadata
+func NewOutgoingContext(ctx context.Context, md MD) context.Context {
+	return context.WithValue(ctx, outgoingKey{}, md)
+}
+
+// AppendToOutgoingContext returns a new context with the provided kv merged
+// with any existing outgoing metadata in the context.
+// AppendToOutgoingContext panics if len(kv) is odd.
+//
+// Parameters:
+//   - ctx: The parent context
+//   - kv: Key-value pairs to append
+//
+// Returns:
+//   - context.Context: The context carrying the merged outgoing metadata
+func AppendToOutgoingContext(ctx context.Context, kv ...string) context.Context {
+	md, _ := FromOutgoingContext(ctx)
+	return NewOutgoingContext(ctx, Join(md, Pairs(kv...)))
+}
+
+// FromOutgoingContext returns the outgoing metadata in ctx if it exists.
+// The returned MD must not be modified, use Copy first.
+//
+// Parameters:
+//   - ctx: The context that may carry outgoing metadata
+//
+// Returns:
+//   - MD: The outgoing metadata
+//   - bool: True if the context carries outgoing metadata
+func FromOutgoingContext(ctx context.Context) (MD, bool) {
+	md, ok := ctx.Value(outgoingKey{}).(MD)
+	return md, ok
+}
@@ -0,0 +1,81 @@
+package metadata
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestPairs(t *testing.T) {
+	md := Pairs("Key", "a", "key", "b", "other", "c")
+	want := MD{"key": {"a", "b"}, "other": {"c"}}
+	if !reflect.DeepEqual(md, want) {
+		t.Errorf("Pairs() = %v, want %v", md, want)
+	}
+}
+
+func TestPairsOddPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Pairs with odd arguments should panic")
+		}
+	}()
+	Pairs("key")
+}
+
+func TestNew(t *testing.T) {
+	md := New(map[string]string{"Key": "value"})
+	if got := md.Get("KEY"); !reflect.DeepEqual(got, []string{"value"}) {
+		t.Errorf("Get() = %v, want [value]", got)
+	}
+}
+
+func TestMDSetAppendDelete(t *testing.T) {
+	md := MD{}
+	md.Set("Key", "a")
+	md.Append("key", "b")
+	if got := md.Get("key"); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("Get() = %v, want [a b]", got)
+	}
+	md.Set("key", "c")
+	if got := md.Get("key"); !reflect.DeepEqual(got, []string{"c"}) {
+		t.Errorf("Get() after Set = %v, want [c]", got)
+	}
+	md.Delete("KEY")
+	if md.Len() != 0 {
+		t.Errorf("Len() after Delete = %d, want 0", md.Len())
+	}
+}
+
+func TestCopy(t *testing.T) {
+	md := Pairs("key", "a")
+	cp := md.Copy()
+	cp.Append("key", "b")
+	if got := md.Get("key"); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("original modified by copy: %v", got)
+	}
+}
+
+func TestIncomingContext(t *testing.T) {
+	if _, ok := FromIncomingContext(context.Background()); ok {
+		t.Error("FromIncomingContext should report false for an empty context")
+	}
+	ctx := NewIncomingContext(context.Background(), Pairs("key", "value"))
+	md, ok := FromIncomingContext(ctx)
+	if !ok || !reflect.DeepEqual(md.Get("key"), []string{"value"}) {
+		t.Errorf("FromIncomingContext() = %v, %v", md, ok)
+	}
+}
+
+func TestAppendToOutgoingContext(t *testing.T) {
+	ctx := AppendToOutgoingContext(context.Background(), "key", "a")
+	ctx = AppendToOutgoingContext(ctx, "key", "b", "other", "c")
+	md, ok := FromOutgoingContext(ctx)
+	if !ok {
+		t.Fatal("FromOutgoingContext should report true")
+	}
+	want := MD{"key": {"a", "b"}, "other": {"c"}}
+	if !reflect.DeepEqual(md, want) {
+		t.Errorf("FromOutgoingContext() = %v, want %v", md, want)
+	}
+}
@@ -0,0 +1,158 @@
+package metadata
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-leo/goose/client"
+	"github.com/go-leo/goose/server"
+)
+
+// options holds configuration options for the metadata middlewares
+type options struct {
+	prefix  string          // Header prefix marking metadata headers, stripped from the metadata key
+	allowed map[string]bool // Lower-cased header names always bridged as-is
+}
+
+// apply applies the given options to the options struct
+// Parameters:
+//   - opts: Variable number of Option functions
+//
+// Returns:
+//   - *options: Pointer to the updated options struct
+func (o *options) apply(opts ...Option) *options {
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Option is a function type for configuring the metadata middlewares
+type Option func(o *options)
+
+// defaultOptions returns the default configuration options
+// Returns:
+//   - *options: Default options which bridge every header
+func defaultOptions() *options {
+	return &options{
+		allowed: map[string]bool{},
+	}
+}
+
+// Prefix bridges only the headers starting with prefix (case-insensitive).
+// The server strips the prefix from the metadata key, the client prepends it to the header name.
+// For example with prefix "X-Md-", the header "X-Md-Tenant" becomes the metadata key "tenant".
+// Parameters:
+//   - prefix: Header prefix
+//
+// Returns:
+//   - Option: Function to set the prefix option
+func Prefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = strings.ToLower(prefix)
+	}
+}
+
+// AllowHeaders bridges the given headers as-is, in addition to the headers matching Prefix.
+// Parameters:
+//   - keys: Header names
+//
+// Returns:
+//   - Option: Function to append the allowed headers
+func AllowHeaders(keys ...string) Option {
+	return func(o *options) {
+		for _, key := range keys {
+			o.allowed[strings.ToLower(key)] = true
+		}
+	}
+}
+
+// bridgeAll reports whether no filter is configured and every header is bridged
+func (o *options) bridgeAll() bool {
+	return o.prefix == "" && len(o.allowed) == 0
+}
+
+// headerToKey maps a header name to a metadata key
+// Returns:
+//   - string: The metadata key
+//   - bool: False if the header is not bridged
+func (o *options) headerToKey(name string) (string, bool) {
+	key := strings.ToLower(name)
+	if o.bridgeAll() || o.allowed[key] {
+		return key, true
+	}
+	if o.prefix != "" && strings.HasPrefix(key, o.prefix) && len(key) > len(o.prefix) {
+		return key[len(o.prefix):], true
+	}
+	return "", false
+}
+
+// keyToHeader maps a metadata key to a header name
+// Returns:
+//   - string: The header name
+//   - bool: False if the key is neither allowed nor prefixable, and is not sent
+func (o *options) keyToHeader(key string) (string, bool) {
+	if o.bridgeAll() || o.allowed[key] {
+		return key, true
+	}
+	if o.prefix != "" {
+		return o.prefix + key, true
+	}
+	return "", false
+}
+
+// Server creates a server middleware that stores the request headers as incoming metadata
+// Parameters:
+//   - opts: Variable number of Option functions for configuration
+//
+// Returns:
+//   - server.Middleware: Server middleware function
+//
+// Behavior:
+//  1. Selects the request headers according to Prefix and AllowHeaders
+//  2. Stores them as incoming metadata in the request context
+//  3. Invokes the next handler
+func Server(opts ...Option) server.Middleware {
+	opt := defaultOptions().apply(opts...)
+	return func(response http.ResponseWriter, request *http.Request, invoker http.HandlerFunc) {
+		md := MD{}
+		for name, values := range request.Header {
+			if key, ok := opt.headerToKey(name); ok {
+				md.Append(key, values...)
+			}
+		}
+		request = request.WithContext(NewIncomingContext(request.Context(), md))
+		invoker(response, request)
+	}
+}
+
+// Client creates a client middleware that sends the outgoing metadata as request headers
+// Parameters:
+//   - opts: Variable number of Option functions for configuration
+//
+// Returns:
+//   - client.Middleware: Client middleware function
+//
+// Behavior:
+//  1. Reads the outgoing metadata from the request context
+//  2. Adds each key as a request header, prefixed by Prefix unless allowed by AllowHeaders,
+//     skipping the keys that are not allowed when no Prefix is set
+//  3. Invokes the next handler
+func Client(opts ...Option) client.Middleware {
+	opt := defaultOptions().apply(opts...)
+	return func(cli *http.Client, request *http.Request, invoker client.Invoker) (*http.Response, error) {
+		md, ok := FromOutgoingContext(request.Context())
+		if ok {
+			for key, values := range md {
+				name, ok := opt.keyToHeader(key)
+				if !ok {
+					continue
+				}
+				for _, value := range values {
+					request.Header.Add(name, value)
+				}
+			}
+		}
+		return invoker(cli, request)
+	}
+}
@@ -0,0 +1,86 @@
+package metadata
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func serveIncoming(t *testing.T, header http.Header, opts ...Option) MD {
+	t.Helper()
+	var md MD
+	request := httptest.NewRequest(http.MethodGet, "http://example.test/", nil)
+	request.Header = header
+	Server(opts...)(httptest.NewRecorder(), request, func(w http.ResponseWriter, r *http.Request) {
+		md, _ = FromIncomingContext(r.Context())
+	})
+	return md
+}
+
+func sendOutgoing(t *testing.T, md MD, opts ...Option) http.Header {
+	t.Helper()
+	request := httptest.NewRequest(http.MethodGet, "http://example.test/", nil)
+	request = request.WithContext(NewOutgoingContext(context.Background(), md))
+	var header http.Header
+	_, _ = Client(opts...)(http.DefaultClient, request, func(cli *http.Client, request *http.Request) (*http.Response, error) {
+		header = request.Header
+		return nil, nil
+	})
+	return header
+}
+
+func TestServerAllHeaders(t *testing.T) {
+	md := serveIncoming(t, http.Header{"X-Request-Id": {"abc"}, "Accept": {"*/*"}})
+	want := MD{"x-request-id": {"abc"}, "accept": {"*/*"}}
+	if !reflect.DeepEqual(md, want) {
+		t.Errorf("incoming metadata = %v, want %v", md, want)
+	}
+}
+
+func TestServerPrefixAndAllowHeaders(t *testing.T) {
+	md := serveIncoming(t,
+		http.Header{"X-Md-Tenant": {"t1"}, "X-Request-Id": {"abc"}, "Accept": {"*/*"}},
+		Prefix("X-Md-"), AllowHeaders("X-Request-Id"),
+	)
+	want := MD{"tenant": {"t1"}, "x-request-id": {"abc"}}
+	if !reflect.DeepEqual(md, want) {
+		t.Errorf("incoming metadata = %v, want %v", md, want)
+	}
+}
+
+func TestClientAllKeys(t *testing.T) {
+	header := sendOutgoing(t, Pairs("x-request-id", "abc", "x-request-id", "def"))
+	if got := header.Values("X-Request-Id"); !reflect.DeepEqual(got, []string{"abc", "def"}) {
+		t.Errorf("X-Request-Id = %v, want [abc def]", got)
+	}
+}
+
+func TestClientPrefixAndAllowHeaders(t *testing.T) {
+	header := sendOutgoing(t, Pairs("tenant", "t1", "x-request-id", "abc"), Prefix("X-Md-"), AllowHeaders("X-Request-Id"))
+	if got := header.Get("X-Md-Tenant"); got != "t1" {
+		t.Errorf("X-Md-Tenant = %q, want t1", got)
+	}
+	if got := header.Get("X-Request-Id"); got != "abc" {
+		t.Errorf("X-Request-Id = %q, want abc", got)
+	}
+}
+
+func TestClientAllowHeadersOnly(t *testing.T) {
+	header := sendOutgoing(t, Pairs("tenant", "t1", "x-request-id", "abc"), AllowHeaders("X-Request-Id"))
+	want := http.Header{"X-Request-Id": {"abc"}}
+	if !reflect.DeepEqual(header, want) {
+		t.Errorf("header = %v, want %v", header, want)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	opts := []Option{Prefix("X-Md-")}
+	header := sendOutgoing(t, Pairs("tenant", "t1", "user", "u1"), opts...)
+	md := serveIncoming(t, header, opts...)
+	want := MD{"tenant": {"t1"}, "user": {"u1"}}
+	if !reflect.DeepEqual(md, want) {
+		t.Errorf("round trip metadata = %v, want %v", md, want)
+	}
+}
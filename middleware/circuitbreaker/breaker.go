@@ -0,0 +1,209 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// State is the state of a circuit breaker
+type State int
+
+const (
+	// StateClosed lets requests through and records their outcome
+	StateClosed State = iota
+	// StateOpen rejects requests until the open timeout elapses
+	StateOpen
+	// StateHalfOpen lets a limited number of probe requests through to decide whether to close again
+	StateHalfOpen
+)
+
+// String returns the name of the state
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// ErrOpen is matched by errors.Is for the errors returned while a circuit is open or half-open and saturated
+var ErrOpen = errors.New("circuitbreaker: circuit open")
+
+// OpenError is returned instead of sending a request rejected by a circuit breaker.
+// Returned by a server, it is encoded as 503 Service Unavailable with a Retry-After header.
+type OpenError struct {
+	Key        string        // The key of the circuit breaker
+	State      State         // The state of the circuit breaker
+	RetryAfter time.Duration // The remaining time before probe requests are let through
+}
+
+// Error returns the error message
+func (e *OpenError) Error() string {
+	return fmt.Sprintf("circuitbreaker: circuit %s for %q", e.State, e.Key)
+}
+
+// Unwrap returns ErrOpen
+func (e *OpenError) Unwrap() error {
+	return ErrOpen
+}
+
+// StatusCode returns 503 Service Unavailable
+func (e *OpenError) StatusCode() int {
+	return http.StatusServiceUnavailable
+}
+
+// Headers returns the Retry-After header
+func (e *OpenError) Headers() http.Header {
+	seconds := int(math.Ceil(e.RetryAfter.Seconds()))
+	return http.Header{"Retry-After": []string{strconv.Itoa(max(seconds, 1))}}
+}
+
+// numBuckets is the number of buckets of the rolling window
+const numBuckets = 10
+
+// bucket counts the outcomes of the requests of a slice of the rolling window
+type bucket struct {
+	epoch    int64 // The index of the slice since the Unix epoch
+	requests int   // Number of requests
+	failures int   // Number of failed requests
+}
+
+// breaker is the circuit breaker of a key
+type breaker struct {
+	key        string
+	opt        *options
+	mu         sync.Mutex
+	state      State
+	generation uint64             // Incremented on each state change, outcomes of older generations are ignored
+	buckets    [numBuckets]bucket // Rolling window of the closed state
+	openedAt   time.Time          // When the breaker last opened
+	inFlight   int                // Number of probe requests in flight in the half-open state
+	successes  int                // Number of successful probe requests in the half-open state
+}
+
+// allow reports whether a request can be sent
+// Returns:
+//   - uint64: The generation the outcome of the request must be recorded with
+//   - error: An *OpenError if the request is rejected
+func (b *breaker) allow(now time.Time) (uint64, error) {
+	var changed *transition
+	b.mu.Lock()
+	defer func() {
+		b.mu.Unlock()
+		b.notify(changed)
+	}()
+	switch b.state {
+	case StateOpen:
+		if elapsed := now.Sub(b.openedAt); elapsed < b.opt.openTimeout {
+			return 0, &OpenError{Key: b.key, State: StateOpen, RetryAfter: b.opt.openTimeout - elapsed}
+		}
+		changed = b.setState(StateHalfOpen, now)
+		fallthrough
+	case StateHalfOpen:
+		if b.inFlight >= b.opt.halfOpenRequests {
+			return 0, &OpenError{Key: b.key, State: StateHalfOpen, RetryAfter: b.opt.openTimeout}
+		}
+		b.inFlight++
+	}
+	return b.generation, nil
+}
+
+// record records the outcome of a request sent in the given generation
+func (b *breaker) record(generation uint64, failed bool, now time.Time) {
+	var changed *transition
+	b.mu.Lock()
+	defer func() {
+		b.mu.Unlock()
+		b.notify(changed)
+	}()
+	if generation != b.generation {
+		return
+	}
+	switch b.state {
+	case StateClosed:
+		epoch := now.UnixNano() / int64(b.opt.window/numBuckets)
+		current := &b.buckets[epoch%numBuckets]
+		if current.epoch != epoch {
+			*current = bucket{epoch: epoch}
+		}
+		current.requests++
+		if failed {
+			current.failures++
+		}
+		if !failed {
+			return
+		}
+		var requests, failures int
+		for _, bucket := range b.buckets {
+			if epoch-bucket.epoch < numBuckets {
+				requests += bucket.requests
+				failures += bucket.failures
+			}
+		}
+		if requests >= b.opt.minRequests && float64(failures) >= b.opt.failureRatio*float64(requests) {
+			changed = b.setState(StateOpen, now)
+		}
+	case StateHalfOpen:
+		b.inFlight--
+		if failed {
+			changed = b.setState(StateOpen, now)
+			return
+		}
+		b.successes++
+		if b.successes >= b.opt.halfOpenRequests {
+			changed = b.setState(StateClosed, now)
+		}
+	}
+}
+
+// release releases the probe slot of a request sent in the given generation without recording its outcome,
+// such as a request canceled by the caller, which tells nothing about the health of the service
+func (b *breaker) release(generation uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if generation != b.generation {
+		return
+	}
+	if b.state == StateHalfOpen {
+		b.inFlight--
+	}
+}
+
+// transition is a state change of a breaker, notified once the breaker is unlocked
+type transition struct {
+	from State
+	to   State
+}
+
+// setState moves the breaker to a new state, resetting its counters
+// Returns:
+//   - *transition: The state change to notify after unlocking the breaker
+func (b *breaker) setState(state State, now time.Time) *transition {
+	from := b.state
+	b.state = state
+	b.generation++
+	b.buckets = [numBuckets]bucket{}
+	b.inFlight = 0
+	b.successes = 0
+	if state == StateOpen {
+		b.openedAt = now
+	}
+	return &transition{from: from, to: state}
+}
+
+// notify calls the state change observer, without holding the lock so that it may use the breaker
+func (b *breaker) notify(changed *transition) {
+	if changed != nil && b.opt.onStateChange != nil {
+		b.opt.onStateChange(b.key, changed.from, changed.to)
+	}
+}
@@ -0,0 +1,233 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// newBreaker creates a breaker with the options normalized as in Client
+func newBreaker(opts ...Option) *breaker {
+	opt := defaultOptions().apply(opts...)
+	opt.window = max(opt.window, numBuckets)
+	opt.halfOpenRequests = max(opt.halfOpenRequests, 1)
+	return &breaker{key: "users", opt: opt}
+}
+
+// send lets a request through at now and records its outcome
+func send(t *testing.T, b *breaker, now time.Time, failed bool) {
+	t.Helper()
+	generation, err := b.allow(now)
+	if err != nil {
+		t.Fatalf("request rejected in state %s: %v", b.state, err)
+	}
+	b.record(generation, failed, now)
+}
+
+func TestBreakerStateMachine(t *testing.T) {
+	var transitions []State
+	b := newBreaker(MinRequests(4), FailureRatio(0.5), OpenTimeout(30*time.Second), HalfOpenRequests(2),
+		OnStateChange(func(key string, from State, to State) { transitions = append(transitions, to) }))
+	now := time.Unix(1000, 0)
+
+	// closed: below MinRequests, failures do not open the circuit
+	for range 3 {
+		send(t, b, now, true)
+	}
+	send(t, b, now, false)
+	if b.state != StateClosed {
+		t.Fatalf("state = %s, want closed below the failure ratio", b.state)
+	}
+	send(t, b, now, true)
+	if b.state != StateOpen {
+		t.Fatalf("state = %s, want open at 4 failures out of 5", b.state)
+	}
+
+	// open: requests are rejected until the timeout elapses
+	_, err := b.allow(now.Add(10 * time.Second))
+	var openErr *OpenError
+	if !errors.As(err, &openErr) || !errors.Is(err, ErrOpen) {
+		t.Fatalf("err = %v, want an *OpenError", err)
+	}
+	if openErr.State != StateOpen || openErr.RetryAfter != 20*time.Second || openErr.Key != "users" {
+		t.Errorf("OpenError = %+v, want open with 20s left", openErr)
+	}
+
+	// half-open: up to HalfOpenRequests probes
+	now = now.Add(30 * time.Second)
+	first, err := b.allow(now)
+	if err != nil || b.state != StateHalfOpen {
+		t.Fatalf("state = %s, err = %v, want a half-open probe", b.state, err)
+	}
+	second, err := b.allow(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.allow(now); !errors.As(err, &openErr) || openErr.State != StateHalfOpen {
+		t.Fatalf("err = %v, want the half-open state saturated", err)
+	}
+	b.record(first, false, now)
+	if b.state != StateHalfOpen {
+		t.Fatalf("state = %s, want half-open until all the probes succeed", b.state)
+	}
+	b.record(second, false, now)
+	if b.state != StateClosed {
+		t.Fatalf("state = %s, want closed after the successful probes", b.state)
+	}
+
+	// a failed probe opens the circuit again
+	for range 4 {
+		send(t, b, now, true)
+	}
+	now = now.Add(30 * time.Second)
+	probe, err := b.allow(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.record(probe, true, now)
+	if b.state != StateOpen {
+		t.Fatalf("state = %s, want open after a failed probe", b.state)
+	}
+	if b.openedAt != now {
+		t.Errorf("openedAt = %v, want the time of the failed probe", b.openedAt)
+	}
+
+	want := []State{StateOpen, StateHalfOpen, StateClosed, StateOpen, StateHalfOpen, StateOpen}
+	if len(transitions) != len(want) {
+		t.Fatalf("transitions = %v, want %v", transitions, want)
+	}
+	for i := range want {
+		if transitions[i] != want[i] {
+			t.Fatalf("transitions = %v, want %v", transitions, want)
+		}
+	}
+}
+
+func TestBreakerStaleOutcome(t *testing.T) {
+	b := newBreaker(MinRequests(1), FailureRatio(1))
+	now := time.Unix(1000, 0)
+	stale, err := b.allow(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	send(t, b, now, true)
+	if b.state != StateOpen {
+		t.Fatalf("state = %s, want open", b.state)
+	}
+	now = now.Add(b.opt.openTimeout)
+	probe, err := b.allow(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the outcome of a request sent while closed does not count as a probe
+	b.record(stale, false, now)
+	if b.state != StateHalfOpen || b.inFlight != 1 {
+		t.Fatalf("state = %s with %d probes, want the stale outcome ignored", b.state, b.inFlight)
+	}
+	b.record(probe, false, now)
+	if b.state != StateClosed {
+		t.Fatalf("state = %s, want closed", b.state)
+	}
+}
+
+func TestBreakerRollingWindow(t *testing.T) {
+	b := newBreaker(MinRequests(4), FailureRatio(0.5), Window(10*time.Second))
+	start := time.Unix(1000, 0)
+
+	send(t, b, start, true)
+	send(t, b, start, true)
+	// the failures of the first slice leave the window
+	send(t, b, start.Add(11*time.Second), true)
+	send(t, b, start.Add(11*time.Second), true)
+	if b.state != StateClosed {
+		t.Fatalf("state = %s, want the expired failures ignored", b.state)
+	}
+	send(t, b, start.Add(15*time.Second), false)
+	send(t, b, start.Add(19*time.Second), false)
+	if b.state != StateClosed {
+		t.Fatalf("state = %s, want closed at 2 failures out of 4", b.state)
+	}
+	// 3 failures out of 5 within the window
+	send(t, b, start.Add(20*time.Second), true)
+	if b.state != StateOpen {
+		t.Fatalf("state = %s, want open at 3 failures out of 5", b.state)
+	}
+
+	// a bucket reused after a full turn of the window starts from zero
+	b = newBreaker(MinRequests(2), FailureRatio(1), Window(10*time.Second))
+	send(t, b, start, true)
+	send(t, b, start.Add(10*time.Second), true)
+	if b.state != StateClosed {
+		t.Fatalf("state = %s, want the reused bucket reset", b.state)
+	}
+}
+
+func TestBreakerRelease(t *testing.T) {
+	b := newBreaker(MinRequests(1), FailureRatio(1))
+	now := time.Unix(1000, 0)
+
+	// a released request is not counted in the closed state
+	generation, _ := b.allow(now)
+	b.release(generation)
+	if b.buckets != [numBuckets]bucket{} {
+		t.Fatalf("buckets = %v, want no request recorded", b.buckets)
+	}
+
+	send(t, b, now, true)
+	now = now.Add(b.opt.openTimeout)
+	probe, err := b.allow(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.release(probe)
+	if b.state != StateHalfOpen || b.inFlight != 0 {
+		t.Fatalf("state = %s with %d probes, want the slot released", b.state, b.inFlight)
+	}
+	probe, err = b.allow(now)
+	if err != nil {
+		t.Fatalf("err = %v, want a new probe after the release", err)
+	}
+	b.record(probe, false, now)
+	if b.state != StateClosed {
+		t.Fatalf("state = %s, want closed", b.state)
+	}
+}
+
+func TestOpenErrorHeaders(t *testing.T) {
+	tests := []struct {
+		retryAfter time.Duration
+		want       string
+	}{
+		{retryAfter: 1500 * time.Millisecond, want: "2"},
+		{retryAfter: 30 * time.Second, want: "30"},
+		{retryAfter: 0, want: "1"},
+	}
+	for _, tt := range tests {
+		err := &OpenError{Key: "users", State: StateOpen, RetryAfter: tt.retryAfter}
+		if got := err.Headers().Get("Retry-After"); got != tt.want {
+			t.Errorf("Retry-After for %v = %q, want %q", tt.retryAfter, got, tt.want)
+		}
+		if err.StatusCode() != 503 {
+			t.Errorf("StatusCode = %d, want 503", err.StatusCode())
+		}
+	}
+}
+
+func TestBreakerStateChangeOutsideLock(t *testing.T) {
+	var b *breaker
+	var states []State
+	b = newBreaker(MinRequests(1), FailureRatio(1), OpenTimeout(time.Second),
+		OnStateChange(func(key string, from State, to State) {
+			// the breaker must be unlocked, or locking it again deadlocks
+			b.mu.Lock()
+			states = append(states, b.state)
+			b.mu.Unlock()
+		}))
+	now := time.Unix(1000, 0)
+	send(t, b, now, true)
+	send(t, b, now.Add(time.Second), false)
+	if got := fmt.Sprint(states); got != "[open half-open closed]" {
+		t.Errorf("states = %s, want each transition notified", got)
+	}
+}
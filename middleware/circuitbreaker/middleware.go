@@ -0,0 +1,233 @@
+// Package circuitbreaker provides a client middleware stopping requests to a failing service
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-leo/goose/client"
+)
+
+// KeyFunc returns the key of the circuit breaker guarding a request
+// Parameters:
+//   - request: The outgoing HTTP request
+//
+// Returns:
+//   - string: The circuit breaker key, requests with the same key share a circuit breaker
+type KeyFunc func(request *http.Request) string
+
+// ByHost keys circuit breakers by the host of the request, the default
+func ByHost(request *http.Request) string {
+	return request.URL.Host
+}
+
+// ByEndpoint keys circuit breakers by the method, host and path of the request.
+// For paths with parameters, prefer a KeyFunc mapping the path to its route pattern.
+func ByEndpoint(request *http.Request) string {
+	return request.Method + " " + request.URL.Host + request.URL.Path
+}
+
+// FailureFunc reports whether the outcome of a request counts as a failure
+// Parameters:
+//   - response: The HTTP response, nil if err is not nil
+//   - err: The transport error
+//
+// Returns:
+//   - bool: True if the request failed
+type FailureFunc func(response *http.Response, err error) bool
+
+// IsFailure is the default FailureFunc, counting transport errors and 5xx responses as failures.
+// Requests canceled by the caller are not classified, see Client.
+func IsFailure(response *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled)
+	}
+	return response.StatusCode >= http.StatusInternalServerError
+}
+
+// options holds configuration options for the circuit breaker middleware
+type options struct {
+	keyFunc          KeyFunc                                // Key of the circuit breaker of a request
+	isFailure        FailureFunc                            // Classification of outcomes
+	failureRatio     float64                                // Ratio of failures opening the circuit
+	minRequests      int                                    // Minimum number of requests in the window before opening
+	window           time.Duration                          // Duration of the rolling window
+	openTimeout      time.Duration                          // Duration of the open state
+	halfOpenRequests int                                    // Number of successful probes closing the circuit
+	onStateChange    func(key string, from State, to State) // Observer of state changes
+}
+
+// apply applies the given options to the options struct
+// Parameters:
+//   - opts: Variable number of Option functions
+//
+// Returns:
+//   - *options: Pointer to the updated options struct
+func (o *options) apply(opts ...Option) *options {
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Option is a function type for configuring the circuit breaker middleware
+type Option func(o *options)
+
+// defaultOptions returns the default configuration options
+// Returns:
+//   - *options: Default options, one circuit breaker per host opening when half of
+//     at least 20 requests fail within 10s, staying open for 30s and closing after 1 successful probe
+func defaultOptions() *options {
+	return &options{
+		keyFunc:          ByHost,
+		isFailure:        IsFailure,
+		failureRatio:     0.5,
+		minRequests:      20,
+		window:           10 * time.Second,
+		openTimeout:      30 * time.Second,
+		halfOpenRequests: 1,
+	}
+}
+
+// Key sets the function returning the circuit breaker key of a request
+// Parameters:
+//   - keyFunc: Key function, such as ByHost or ByEndpoint
+//
+// Returns:
+//   - Option: Function to set the key option
+func Key(keyFunc KeyFunc) Option {
+	return func(o *options) {
+		o.keyFunc = keyFunc
+	}
+}
+
+// Failure sets the function reporting whether a request failed
+// Parameters:
+//   - isFailure: Failure function
+//
+// Returns:
+//   - Option: Function to set the failure option
+func Failure(isFailure FailureFunc) Option {
+	return func(o *options) {
+		o.isFailure = isFailure
+	}
+}
+
+// FailureRatio sets the ratio of failed requests in the window opening the circuit
+// Parameters:
+//   - ratio: Ratio between 0 and 1
+//
+// Returns:
+//   - Option: Function to set the failure ratio option
+func FailureRatio(ratio float64) Option {
+	return func(o *options) {
+		o.failureRatio = ratio
+	}
+}
+
+// MinRequests sets the minimum number of requests in the window before the circuit can open
+// Parameters:
+//   - n: Minimum number of requests
+//
+// Returns:
+//   - Option: Function to set the min requests option
+func MinRequests(n int) Option {
+	return func(o *options) {
+		o.minRequests = n
+	}
+}
+
+// Window sets the duration of the rolling window counting requests in the closed state
+// Parameters:
+//   - window: Window duration
+//
+// Returns:
+//   - Option: Function to set the window option
+func Window(window time.Duration) Option {
+	return func(o *options) {
+		o.window = window
+	}
+}
+
+// OpenTimeout sets how long the circuit stays open before probe requests are let through
+// Parameters:
+//   - timeout: Open state duration
+//
+// Returns:
+//   - Option: Function to set the open timeout option
+func OpenTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.openTimeout = timeout
+	}
+}
+
+// HalfOpenRequests sets the number of concurrent probe requests in the half-open state,
+// which must all succeed to close the circuit
+// Parameters:
+//   - n: Number of probe requests
+//
+// Returns:
+//   - Option: Function to set the half-open requests option
+func HalfOpenRequests(n int) Option {
+	return func(o *options) {
+		o.halfOpenRequests = n
+	}
+}
+
+// OnStateChange sets a function called when a circuit breaker changes state.
+// It is called outside of the breaker lock, by the request that caused the change,
+// so calls of concurrent requests may run in parallel.
+// Parameters:
+//   - f: Function receiving the key and the states
+//
+// Returns:
+//   - Option: Function to set the state change option
+func OnStateChange(f func(key string, from State, to State)) Option {
+	return func(o *options) {
+		o.onStateChange = f
+	}
+}
+
+// Client creates a client circuit breaker middleware
+// Parameters:
+//   - opts: Variable number of Option functions for configuration
+//
+// Returns:
+//   - client.Middleware: Client middleware function
+//
+// Behavior:
+//  1. Closed: sends requests and records their outcome in a rolling window,
+//     opens when the failure ratio is reached over at least MinRequests requests
+//  2. Open: rejects requests with an *OpenError until OpenTimeout elapses
+//  3. Half-open: sends up to HalfOpenRequests probes, closes when they all succeed
+//     and opens again on the first failure
+//  4. Requests canceled by the caller are neutral, they release their probe slot
+//     without being recorded as a success or a failure
+func Client(opts ...Option) client.Middleware {
+	opt := defaultOptions().apply(opts...)
+	opt.window = max(opt.window, numBuckets)
+	opt.halfOpenRequests = max(opt.halfOpenRequests, 1)
+	var breakers sync.Map
+	return func(cli *http.Client, request *http.Request, invoker client.Invoker) (*http.Response, error) {
+		key := opt.keyFunc(request)
+		value, ok := breakers.Load(key)
+		if !ok {
+			value, _ = breakers.LoadOrStore(key, &breaker{key: key, opt: opt})
+		}
+		b := value.(*breaker)
+		generation, err := b.allow(time.Now())
+		if err != nil {
+			return nil, err
+		}
+		response, err := invoker(cli, request)
+		if errors.Is(err, context.Canceled) {
+			b.release(generation)
+			return response, err
+		}
+		b.record(generation, opt.isFailure(response, err), time.Now())
+		return response, err
+	}
+}
@@ -0,0 +1,137 @@
+package circuitbreaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// statusInvoker answers every request with the status code of its host, counting the calls
+type statusInvoker struct {
+	status map[string]int
+	err    error
+	calls  int
+}
+
+func (f *statusInvoker) invoke(cli *http.Client, request *http.Request) (*http.Response, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &http.Response{StatusCode: f.status[request.URL.Host], Header: http.Header{}, Body: http.NoBody}, nil
+}
+
+func get(t *testing.T, url string) *http.Request {
+	t.Helper()
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return request
+}
+
+func TestClient(t *testing.T) {
+	invoker := &statusInvoker{status: map[string]int{"users": 500, "orders": 200}}
+	middleware := Client(MinRequests(3), FailureRatio(1))
+	for range 3 {
+		response, err := middleware(http.DefaultClient, get(t, "http://users/v1/user"), invoker.invoke)
+		if err != nil || response.StatusCode != 500 {
+			t.Fatalf("err = %v, want the 500 response while closed", err)
+		}
+	}
+	_, err := middleware(http.DefaultClient, get(t, "http://users/v1/user"), invoker.invoke)
+	var openErr *OpenError
+	if !errors.As(err, &openErr) || openErr.Key != "users" {
+		t.Fatalf("err = %v, want an *OpenError for users", err)
+	}
+	if invoker.calls != 3 {
+		t.Errorf("%d calls, want the rejected request not sent", invoker.calls)
+	}
+	// breakers are keyed by host by default
+	if _, err := middleware(http.DefaultClient, get(t, "http://orders/v1/order"), invoker.invoke); err != nil {
+		t.Fatalf("err = %v, want the orders circuit closed", err)
+	}
+}
+
+func TestClientByEndpoint(t *testing.T) {
+	invoker := &statusInvoker{status: map[string]int{"users": 500}}
+	middleware := Client(Key(ByEndpoint), MinRequests(1), FailureRatio(1))
+	_, _ = middleware(http.DefaultClient, get(t, "http://users/v1/user"), invoker.invoke)
+	if _, err := middleware(http.DefaultClient, get(t, "http://users/v1/user"), invoker.invoke); !errors.Is(err, ErrOpen) {
+		t.Fatalf("err = %v, want the endpoint circuit open", err)
+	}
+	if _, err := middleware(http.DefaultClient, get(t, "http://users/v1/users"), invoker.invoke); errors.Is(err, ErrOpen) {
+		t.Fatal("another endpoint of the host must have its own circuit")
+	}
+}
+
+func TestClientHalfOpen(t *testing.T) {
+	invoker := &statusInvoker{status: map[string]int{"users": 500}}
+	var transitions []string
+	middleware := Client(MinRequests(1), FailureRatio(1), OpenTimeout(50*time.Millisecond),
+		OnStateChange(func(key string, from State, to State) {
+			transitions = append(transitions, fmt.Sprintf("%s->%s", from, to))
+		}))
+	_, _ = middleware(http.DefaultClient, get(t, "http://users/"), invoker.invoke)
+	if _, err := middleware(http.DefaultClient, get(t, "http://users/"), invoker.invoke); !errors.Is(err, ErrOpen) {
+		t.Fatalf("err = %v, want open", err)
+	}
+	time.Sleep(60 * time.Millisecond)
+	invoker.status["users"] = 200
+	if _, err := middleware(http.DefaultClient, get(t, "http://users/"), invoker.invoke); err != nil {
+		t.Fatalf("err = %v, want the probe sent", err)
+	}
+	want := "[closed->open open->half-open half-open->closed]"
+	if got := fmt.Sprint(transitions); got != want {
+		t.Errorf("transitions = %s, want %s", got, want)
+	}
+}
+
+func TestClientCanceledProbe(t *testing.T) {
+	invoker := &statusInvoker{status: map[string]int{"users": 500}}
+	var states []State
+	middleware := Client(MinRequests(1), FailureRatio(1), OpenTimeout(50*time.Millisecond),
+		OnStateChange(func(key string, from State, to State) { states = append(states, to) }))
+	_, _ = middleware(http.DefaultClient, get(t, "http://users/"), invoker.invoke)
+	time.Sleep(60 * time.Millisecond)
+
+	// the canceled probe neither closes nor opens the circuit, and frees its slot
+	invoker.err = fmt.Errorf("Get \"http://users/\": %w", context.Canceled)
+	if _, err := middleware(http.DefaultClient, get(t, "http://users/"), invoker.invoke); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want the cancellation", err)
+	}
+	if got := fmt.Sprint(states); got != "[open half-open]" {
+		t.Fatalf("states = %s, want the circuit left half-open", got)
+	}
+	invoker.err = nil
+	if _, err := middleware(http.DefaultClient, get(t, "http://users/"), invoker.invoke); err != nil {
+		t.Fatalf("err = %v, want another probe let through", err)
+	}
+	// the 500 of the probe opens the circuit again
+	if _, err := middleware(http.DefaultClient, get(t, "http://users/"), invoker.invoke); !errors.Is(err, ErrOpen) {
+		t.Fatalf("err = %v, want open after the failed probe", err)
+	}
+}
+
+func TestIsFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		response *http.Response
+		err      error
+		want     bool
+	}{
+		{name: "ok", response: &http.Response{StatusCode: 200}, want: false},
+		{name: "client error", response: &http.Response{StatusCode: 404}, want: false},
+		{name: "server error", response: &http.Response{StatusCode: 503}, want: true},
+		{name: "transport error", err: errors.New("connection refused"), want: true},
+		{name: "deadline", err: context.DeadlineExceeded, want: true},
+	}
+	for _, tt := range tests {
+		if got := IsFailure(tt.response, tt.err); got != tt.want {
+			t.Errorf("%s: IsFailure = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
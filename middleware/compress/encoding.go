@@ -0,0 +1,83 @@
+package compress
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+
+	"github.com/andybalholm/brotli"
+	"github.com/go-leo/goose/server"
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	// Gzip is the "gzip" content coding
+	Gzip = "gzip"
+	// Deflate is the "deflate" content coding
+	Deflate = "deflate"
+	// Brotli is the "br" content coding
+	Brotli = "br"
+	// Zstd is the "zstd" content coding
+	Zstd = "zstd"
+)
+
+// maxZstdWindow bounds the memory a zstd frame may require to be decoded
+const maxZstdWindow = 32 << 20
+
+// compressor creates a writer compressing into w
+type compressor func(w io.Writer) (io.WriteCloser, error)
+
+// compressors holds the compressor of each supported content coding
+var compressors = map[string]compressor{
+	Gzip: func(w io.Writer) (io.WriteCloser, error) {
+		return gzip.NewWriter(w), nil
+	},
+	// HTTP deflate is the zlib format (RFC 1950), not raw DEFLATE
+	Deflate: func(w io.Writer) (io.WriteCloser, error) {
+		return zlib.NewWriterLevel(w, zlib.DefaultCompression)
+	},
+	Brotli: func(w io.Writer) (io.WriteCloser, error) {
+		return brotli.NewWriter(w), nil
+	},
+	Zstd: func(w io.Writer) (io.WriteCloser, error) {
+		return zstd.NewWriter(w, zstd.WithEncoderConcurrency(1))
+	},
+}
+
+// decompressors holds the decompressor of each supported content coding
+var decompressors = map[string]server.Decompressor{
+	Gzip:    server.GzipDecompressor,
+	Deflate: server.DeflateDecompressor,
+	Brotli:  BrotliDecompressor,
+	Zstd:    ZstdDecompressor,
+}
+
+// ZstdDecompressor decompresses "zstd" bodies.
+// It can be registered on the server with server.Decompression(compress.Zstd, compress.ZstdDecompressor).
+//
+// Parameters:
+//   - r: The compressed body
+//
+// Returns:
+//   - io.ReadCloser: The decompressed body
+//   - error: If the zstd decoder cannot be created
+func ZstdDecompressor(r io.Reader) (io.ReadCloser, error) {
+	decoder, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxWindow(maxZstdWindow))
+	if err != nil {
+		return nil, err
+	}
+	return decoder.IOReadCloser(), nil
+}
+
+// BrotliDecompressor decompresses "br" bodies.
+// It can be registered on the server with server.Decompression(compress.Brotli, compress.BrotliDecompressor).
+//
+// Parameters:
+//   - r: The compressed body
+//
+// Returns:
+//   - io.ReadCloser: The decompressed body
+//   - error: Always nil
+func BrotliDecompressor(r io.Reader) (io.ReadCloser, error) {
+	return io.NopCloser(brotli.NewReader(r)), nil
+}
@@ -0,0 +1,432 @@
+// Package compress provides server and client middlewares compressing HTTP bodies.
+// The server middleware negotiates the response encoding from Accept-Encoding,
+// the client middleware advertises the supported encodings and transparently
+// decompresses responses. gzip, deflate, br (brotli) and zstd are supported.
+package compress
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-leo/goose"
+	"github.com/go-leo/goose/client"
+	"github.com/go-leo/goose/server"
+)
+
+// options holds configuration options for the compress middlewares
+type options struct {
+	minSize      int      // Minimum response size in bytes to compress
+	contentTypes []string // Media types to compress, empty compresses every media type
+	encodings    []string // Supported encodings in order of preference
+
+	maxDecompressedBytes int64 // Maximum size of a decompressed response body, 0 means unlimited
+}
+
+// apply applies the given options to the options struct
+// Parameters:
+//   - opts: Variable number of Option functions
+//
+// Returns:
+//   - *options: Pointer to the updated options struct
+func (o *options) apply(opts ...Option) *options {
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Option is a function type for configuring the compress middlewares
+type Option func(o *options)
+
+// defaultOptions returns the default configuration options
+// Returns:
+//   - *options: Default options, compressing textual responses of at least 1 KiB with zstd, br, gzip or deflate
+func defaultOptions() *options {
+	return &options{
+		minSize: 1024,
+		contentTypes: []string{
+			"text/",
+			"application/json",
+			"application/javascript",
+			"application/xml",
+			"application/x-www-form-urlencoded",
+			"image/svg+xml",
+			"+json",
+			"+xml",
+		},
+		encodings:            []string{Zstd, Brotli, Gzip, Deflate},
+		maxDecompressedBytes: server.DefaultMaxDecompressedBytes,
+	}
+}
+
+// MinSize sets the minimum response size to compress, smaller responses are sent as is.
+// Parameters:
+//   - size: Minimum size in bytes
+//
+// Returns:
+//   - Option: Function to set the minimum size option
+func MinSize(size int) Option {
+	return func(o *options) {
+		o.minSize = size
+	}
+}
+
+// ContentTypes sets the media types to compress, replacing the defaults.
+// An entry ending with "/" matches a type ("text/"), an entry starting with "+"
+// matches a structured syntax suffix ("+json"), any other entry matches exactly.
+// Without entries, every media type is compressed.
+// Parameters:
+//   - contentTypes: Media types to compress
+//
+// Returns:
+//   - Option: Function to set the content types option
+func ContentTypes(contentTypes ...string) Option {
+	return func(o *options) {
+		o.contentTypes = contentTypes
+	}
+}
+
+// Encodings sets the supported encodings in order of preference, unknown encodings are ignored.
+// Parameters:
+//   - encodings: Encodings among Zstd, Brotli, Gzip and Deflate
+//
+// Returns:
+//   - Option: Function to set the encodings option
+func Encodings(encodings ...string) Option {
+	return func(o *options) {
+		o.encodings = o.encodings[:0]
+		for _, encoding := range encodings {
+			encoding = strings.ToLower(encoding)
+			if _, ok := compressors[encoding]; ok {
+				o.encodings = append(o.encodings, encoding)
+			}
+		}
+	}
+}
+
+// MaxDecompressedBytes limits the size of responses decompressed by the client middleware,
+// protecting against zip bombs. Reading past the limit fails with ErrResponseTooLarge.
+// Defaults to server.DefaultMaxDecompressedBytes, large streamed downloads need a higher limit or 0.
+// Parameters:
+//   - n: The maximum number of bytes, 0 means unlimited
+//
+// Returns:
+//   - Option: Function to set the decompressed response limit
+func MaxDecompressedBytes(n int64) Option {
+	return func(o *options) {
+		o.maxDecompressedBytes = n
+	}
+}
+
+// Server creates a server middleware compressing responses
+// Parameters:
+//   - opts: Variable number of Option functions for configuration
+//
+// Returns:
+//   - server.Middleware: Server middleware function
+//
+// Behavior:
+//  1. Negotiates the encoding from the Accept-Encoding request header and the preference order
+//  2. Buffers the response until MinSize bytes are written or the handler returns
+//  3. Compresses the response if it is large enough, has a compressible content type
+//     and is not already encoded, otherwise sends it as is
+//  4. Sends streamed responses as is, server-sent events and responses flushed
+//     before MinSize bytes are written, so that each chunk is delivered right away
+func Server(opts ...Option) server.Middleware {
+	opt := defaultOptions().apply(opts...)
+	return func(response http.ResponseWriter, request *http.Request, invoker http.HandlerFunc) {
+		response.Header().Add("Vary", "Accept-Encoding")
+		encoding := negotiate(request.Header.Values("Accept-Encoding"), opt.encodings)
+		if encoding == "" || request.Method == http.MethodHead {
+			invoker(response, request)
+			return
+		}
+		writer := &compressWriter{ResponseWriter: response, opt: opt, encoding: encoding, status: http.StatusOK}
+		defer writer.finish()
+		invoker(writer, request)
+	}
+}
+
+// Client creates a client middleware decompressing responses
+// Parameters:
+//   - opts: Variable number of Option functions for configuration
+//
+// Returns:
+//   - client.Middleware: Client middleware function
+//
+// Behavior:
+//  1. Advertises the supported encodings in Accept-Encoding, unless the header is already set
+//  2. Invokes the next handler
+//  3. Replaces the body of an encoded response with its decompressed content,
+//     so that decoders such as client.DecodeMessage read plain data
+//  4. Fails reading the decompressed body with ErrResponseTooLarge past MaxDecompressedBytes
+func Client(opts ...Option) client.Middleware {
+	opt := defaultOptions().apply(opts...)
+	acceptEncoding := strings.Join(opt.encodings, ", ")
+	return func(cli *http.Client, request *http.Request, invoker client.Invoker) (*http.Response, error) {
+		if request.Header.Get("Accept-Encoding") == "" && acceptEncoding != "" {
+			request.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		response, err := invoker(cli, request)
+		if err != nil || response == nil {
+			return response, err
+		}
+		encoding := strings.ToLower(strings.TrimSpace(response.Header.Get("Content-Encoding")))
+		decompressor, ok := decompressors[encoding]
+		if !ok {
+			return response, nil
+		}
+		reader, err := decompressor(response.Body)
+		if err != nil {
+			return nil, errors.Join(err, response.Body.Close())
+		}
+		body := &decompressedBody{Reader: reader, decompressor: reader, body: response.Body}
+		if opt.maxDecompressedBytes > 0 {
+			body.Reader = &limitedReader{reader: reader, n: opt.maxDecompressedBytes}
+		}
+		response.Body = body
+		response.Header.Del("Content-Encoding")
+		response.Header.Del("Content-Length")
+		response.ContentLength = -1
+		response.Uncompressed = true
+		return response, nil
+	}
+}
+
+// negotiate selects the preferred encoding accepted with the highest quality
+// Parameters:
+//   - acceptEncodings: The Accept-Encoding header values
+//   - encodings: The supported encodings in order of preference
+//
+// Returns:
+//   - string: The selected encoding, empty if none is acceptable
+func negotiate(acceptEncodings []string, encodings []string) string {
+	qualities := map[string]float64{}
+	for _, value := range acceptEncodings {
+		for _, part := range strings.Split(value, ",") {
+			coding, params, _ := strings.Cut(part, ";")
+			coding = strings.ToLower(strings.TrimSpace(coding))
+			if coding == "" {
+				continue
+			}
+			quality := 1.0
+			if name, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(name) == "q" {
+				if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+					quality = q
+				}
+			}
+			qualities[coding] = quality
+		}
+	}
+	best, bestQuality := "", 0.0
+	for _, encoding := range encodings {
+		quality, ok := qualities[encoding]
+		if !ok {
+			quality = qualities["*"]
+		}
+		if quality > bestQuality {
+			best, bestQuality = encoding, quality
+		}
+	}
+	return best
+}
+
+// compressible reports whether the media type of contentType is in contentTypes
+func compressible(contentType string, contentTypes []string) bool {
+	if len(contentTypes) == 0 {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, candidate := range contentTypes {
+		candidate = strings.ToLower(candidate)
+		switch {
+		case strings.HasSuffix(candidate, "/"):
+			if strings.HasPrefix(mediaType, candidate) {
+				return true
+			}
+		case strings.HasPrefix(candidate, "+"):
+			if strings.HasSuffix(mediaType, candidate) {
+				return true
+			}
+		default:
+			if mediaType == candidate {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isEventStream reports whether contentType is text/event-stream, server-sent events are never compressed
+func isEventStream(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return mediaType == "text/event-stream"
+}
+
+// compressWriter buffers the beginning of the response to decide whether to compress it
+type compressWriter struct {
+	http.ResponseWriter
+	opt      *options
+	encoding string         // The negotiated encoding
+	status   int            // The status code written by the handler
+	buf      []byte         // The response data written before the decision
+	decided  bool           // Whether the header has been sent
+	streamed bool           // Whether the handler flushed before the header was sent
+	writer   io.WriteCloser // The compressing writer, nil if the response is sent as is
+}
+
+// WriteHeader records the status code, the header is sent once the body is known to be compressible or not
+func (w *compressWriter) WriteHeader(code int) {
+	if w.decided {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if code >= 100 && code <= 199 && code != http.StatusSwitchingProtocols {
+		// Informational responses, such as 103 Early Hints, are sent right away
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	w.status = code
+	if !goose.BodyAllowedForStatus(code) {
+		w.decide()
+	}
+}
+
+// Write buffers p until MinSize bytes are written, then writes through the compressor
+func (w *compressWriter) Write(p []byte) (int, error) {
+	if !w.decided {
+		w.buf = append(w.buf, p...)
+		if len(w.buf) >= w.opt.minSize {
+			if err := w.decide(); err != nil {
+				return 0, err
+			}
+		}
+		return len(p), nil
+	}
+	if w.writer != nil {
+		return w.writer.Write(p)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush sends the buffered data. A response flushed before the decision is streamed, it is sent as is.
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		w.streamed = true
+		if err := w.decide(); err != nil {
+			return
+		}
+	}
+	if flusher, ok := w.writer.(interface{ Flush() error }); ok {
+		if err := flusher.Flush(); err != nil {
+			return
+		}
+	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap returns the underlying response writer, for http.ResponseController
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// decide sends the header, compressed if the response qualifies, and the buffered data
+// Returns:
+//   - error: If the buffered data cannot be written
+func (w *compressWriter) decide() error {
+	w.decided = true
+	header := w.Header()
+	if header.Get("Content-Type") == "" && len(w.buf) > 0 {
+		header.Set("Content-Type", http.DetectContentType(w.buf))
+	}
+	if w.shouldCompress() {
+		if writer, err := compressors[w.encoding](w.ResponseWriter); err == nil {
+			w.writer = writer
+			header.Set("Content-Encoding", w.encoding)
+			header.Del("Content-Length")
+		}
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	_, err := w.Write(buf)
+	return err
+}
+
+// shouldCompress reports whether the response qualifies for compression
+func (w *compressWriter) shouldCompress() bool {
+	header := w.Header()
+	switch {
+	case !goose.BodyAllowedForStatus(w.status):
+		return false
+	case header.Get("Content-Encoding") != "":
+		return false
+	case header.Get("Content-Range") != "":
+		return false
+	case w.streamed || isEventStream(header.Get("Content-Type")):
+		return false
+	case len(w.buf) < w.opt.minSize:
+		return false
+	}
+	return compressible(header.Get("Content-Type"), w.opt.contentTypes)
+}
+
+// finish sends a response left undecided and closes the compressor
+func (w *compressWriter) finish() {
+	if !w.decided {
+		if err := w.decide(); err != nil {
+			return
+		}
+	}
+	if w.writer != nil {
+		_ = w.writer.Close()
+	}
+}
+
+// ErrResponseTooLarge is returned when a decompressed response body exceeds MaxDecompressedBytes
+var ErrResponseTooLarge = errors.New("compress: decompressed response body too large")
+
+// limitedReader reads at most n bytes and fails with ErrResponseTooLarge beyond them
+type limitedReader struct {
+	reader io.Reader // The decompressed body
+	n      int64     // Remaining bytes before the limit is reached
+}
+
+// Read reads from the decompressed body, failing once more than the limit is read
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.n < 0 {
+		return 0, ErrResponseTooLarge
+	}
+	// Read one byte past the limit to detect bodies exceeding it
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+	n, err := l.reader.Read(p)
+	l.n -= int64(n)
+	if l.n < 0 {
+		return n + int(l.n), ErrResponseTooLarge
+	}
+	return n, err
+}
+
+// decompressedBody closes both the decompressor and the response body
+type decompressedBody struct {
+	io.Reader
+	decompressor io.ReadCloser
+	body         io.ReadCloser
+}
+
+// Close closes the decompressor and the response body
+func (b *decompressedBody) Close() error {
+	return errors.Join(b.decompressor.Close(), b.body.Close())
+}
@@ -0,0 +1,358 @@
+package compress
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-leo/goose/server"
+)
+
+// serve runs the server middleware of opts with the handler and returns the recorder
+func serve(t *testing.T, acceptEncoding string, handler http.HandlerFunc, opts ...Option) *httptest.ResponseRecorder {
+	t.Helper()
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	if acceptEncoding != "" {
+		request.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	rec := httptest.NewRecorder()
+	server.Invoke(Server(opts...), rec, request, handler)
+	return rec
+}
+
+// text writes a text/plain body of the given size
+func text(size int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = io.WriteString(w, strings.Repeat("a", size))
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	preference := []string{Zstd, Brotli, Gzip, Deflate}
+	tests := []struct {
+		name           string
+		acceptEncoding []string
+		want           string
+	}{
+		{name: "none", acceptEncoding: nil, want: ""},
+		{name: "single", acceptEncoding: []string{"gzip"}, want: Gzip},
+		{name: "preference order", acceptEncoding: []string{"gzip, br"}, want: Brotli},
+		{name: "case insensitive", acceptEncoding: []string{"GZIP"}, want: Gzip},
+		{name: "quality", acceptEncoding: []string{"br;q=0.8, gzip;q=0.9"}, want: Gzip},
+		{name: "quality with spaces", acceptEncoding: []string{"br ; q=0.5, deflate"}, want: Deflate},
+		{name: "refused", acceptEncoding: []string{"gzip;q=0"}, want: ""},
+		{name: "identity only", acceptEncoding: []string{"identity"}, want: ""},
+		{name: "unknown", acceptEncoding: []string{"compress, x-custom"}, want: ""},
+		{name: "wildcard", acceptEncoding: []string{"*"}, want: Zstd},
+		{name: "wildcard refused", acceptEncoding: []string{"*;q=0"}, want: ""},
+		{name: "wildcard with exclusion", acceptEncoding: []string{"zstd;q=0, *;q=0.5"}, want: Brotli},
+		{name: "explicit beats wildcard", acceptEncoding: []string{"*;q=0.1, deflate;q=0.5"}, want: Deflate},
+		{name: "several headers", acceptEncoding: []string{"deflate;q=0.2", "gzip"}, want: Gzip},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := negotiate(tt.acceptEncoding, preference); got != tt.want {
+				t.Errorf("negotiate(%q) = %q, want %q", tt.acceptEncoding, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompressible(t *testing.T) {
+	contentTypes := defaultOptions().contentTypes
+	tests := []struct {
+		contentType string
+		want        bool
+	}{
+		{contentType: "text/html; charset=utf-8", want: true},
+		{contentType: "application/json", want: true},
+		{contentType: "application/problem+json", want: true},
+		{contentType: "image/svg+xml", want: true},
+		{contentType: "image/png", want: false},
+		{contentType: "application/octet-stream", want: false},
+		{contentType: "", want: false},
+	}
+	for _, tt := range tests {
+		if got := compressible(tt.contentType, contentTypes); got != tt.want {
+			t.Errorf("compressible(%q) = %v, want %v", tt.contentType, got, tt.want)
+		}
+	}
+	if !compressible("image/png", nil) {
+		t.Error("every media type must be compressible without content types")
+	}
+}
+
+func TestServerCompresses(t *testing.T) {
+	rec := serve(t, "gzip", text(2048))
+	if got := rec.Header().Get("Content-Encoding"); got != Gzip {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+	if got := rec.Header().Get("Vary"); got != "Accept-Encoding" {
+		t.Errorf("Vary = %q, want Accept-Encoding", got)
+	}
+	reader, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != strings.Repeat("a", 2048) {
+		t.Errorf("decompressed body has %d bytes, want 2048", len(data))
+	}
+}
+
+func TestServerMinSize(t *testing.T) {
+	rec := serve(t, "gzip", text(100))
+	if got := rec.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("Content-Encoding = %q, want none below the minimum size", got)
+	}
+	if rec.Body.String() != strings.Repeat("a", 100) {
+		t.Errorf("body = %q, want the plain body", rec.Body.String())
+	}
+
+	rec = serve(t, "gzip", text(100), MinSize(10))
+	if got := rec.Header().Get("Content-Encoding"); got != Gzip {
+		t.Errorf("Content-Encoding = %q, want gzip above MinSize", got)
+	}
+}
+
+func TestServerNotAccepted(t *testing.T) {
+	for _, acceptEncoding := range []string{"", "identity", "gzip;q=0"} {
+		rec := serve(t, acceptEncoding, text(2048))
+		if got := rec.Header().Get("Content-Encoding"); got != "" {
+			t.Errorf("Accept-Encoding %q: Content-Encoding = %q, want none", acceptEncoding, got)
+		}
+		if rec.Body.Len() != 2048 {
+			t.Errorf("Accept-Encoding %q: body has %d bytes, want 2048", acceptEncoding, rec.Body.Len())
+		}
+	}
+}
+
+func TestServerSkips(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{
+			name: "content type",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "image/png")
+				_, _ = w.Write(make([]byte, 2048))
+			},
+		},
+		{
+			name: "already encoded",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/plain")
+				w.Header().Set("Content-Encoding", "br")
+				_, _ = w.Write(make([]byte, 2048))
+			},
+		},
+		{
+			name: "range",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/plain")
+				w.Header().Set("Content-Range", "bytes 0-2047/4096")
+				w.WriteHeader(http.StatusPartialContent)
+				_, _ = w.Write(make([]byte, 2048))
+			},
+		},
+		{
+			name: "event stream",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/event-stream")
+				_, _ = w.Write(make([]byte, 2048))
+			},
+		},
+		{
+			name: "flushed",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/plain")
+				_, _ = io.WriteString(w, "chunk")
+				http.NewResponseController(w).Flush()
+				_, _ = w.Write(make([]byte, 2048))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(t, "gzip", tt.handler)
+			if got := rec.Header().Get("Content-Encoding"); got == Gzip {
+				t.Errorf("Content-Encoding = %q, want the response sent as is", got)
+			}
+		})
+	}
+}
+
+func TestServerFlushedAfterDecision(t *testing.T) {
+	rec := serve(t, "gzip", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = io.WriteString(w, strings.Repeat("a", 2048))
+		http.NewResponseController(w).Flush()
+		_, _ = io.WriteString(w, "tail")
+	})
+	if got := rec.Header().Get("Content-Encoding"); got != Gzip {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+	if !rec.Flushed {
+		t.Error("the flush did not reach the response writer")
+	}
+	reader, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(reader)
+	if string(data) != strings.Repeat("a", 2048)+"tail" {
+		t.Errorf("decompressed body has %d bytes, want 2052", len(data))
+	}
+}
+
+func TestServerNoBody(t *testing.T) {
+	rec := serve(t, "gzip", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("status = %d, want 204", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("Content-Encoding = %q, want none", got)
+	}
+}
+
+func TestClientRoundTrip(t *testing.T) {
+	for _, encoding := range []string{Zstd, Brotli, Gzip, Deflate} {
+		t.Run(encoding, func(t *testing.T) {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				server.Invoke(Server(Encodings(encoding)), w, r, text(4096))
+			}
+			srv := httptest.NewServer(http.HandlerFunc(handler))
+			defer srv.Close()
+
+			var encoded string
+			record := func(cli *http.Client, request *http.Request) (*http.Response, error) {
+				response, err := cli.Do(request)
+				if err == nil {
+					encoded = response.Header.Get("Content-Encoding")
+				}
+				return response, err
+			}
+			request, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+			response, err := Client()(srv.Client(), request, record)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer response.Body.Close()
+			if encoded != encoding {
+				t.Errorf("response encoded with %q, want %q", encoded, encoding)
+			}
+			if got := request.Header.Get("Accept-Encoding"); got != "zstd, br, gzip, deflate" {
+				t.Errorf("Accept-Encoding = %q", got)
+			}
+			if response.Header.Get("Content-Encoding") != "" || !response.Uncompressed {
+				t.Error("the response must be marked as decompressed")
+			}
+			data, err := io.ReadAll(response.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != strings.Repeat("a", 4096) {
+				t.Errorf("body has %d bytes, want 4096", len(data))
+			}
+		})
+	}
+}
+
+func TestClientKeepsAcceptEncoding(t *testing.T) {
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Accept-Encoding", "gzip")
+	_, err := Client()(http.DefaultClient, request, func(cli *http.Client, request *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: http.NoBody}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := request.Header.Get("Accept-Encoding"); got != "gzip" {
+		t.Errorf("Accept-Encoding = %q, want the header set by the caller", got)
+	}
+}
+
+func TestServerDeflateIsZlib(t *testing.T) {
+	rec := serve(t, "deflate", text(4096))
+	if got := rec.Header().Get("Content-Encoding"); got != Deflate {
+		t.Fatalf("Content-Encoding = %q, want deflate", got)
+	}
+	reader, err := zlib.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf("the deflate response must be zlib wrapped: %v", err)
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil || len(data) != 4096 {
+		t.Errorf("read %d bytes, %v, want 4096", len(data), err)
+	}
+}
+
+func TestClientRawDeflate(t *testing.T) {
+	var buf bytes.Buffer
+	w, _ := flate.NewWriter(&buf, flate.DefaultCompression)
+	_, _ = w.Write([]byte("hello"))
+	_ = w.Close()
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	response, err := Client()(http.DefaultClient, request, func(cli *http.Client, request *http.Request) (*http.Response, error) {
+		header := http.Header{"Content-Encoding": []string{Deflate}}
+		return &http.Response{StatusCode: http.StatusOK, Header: header, Body: io.NopCloser(&buf)}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(response.Body)
+	if err != nil || string(data) != "hello" {
+		t.Errorf("body = %q, %v, want hello", data, err)
+	}
+}
+
+func TestClientMaxDecompressedBytes(t *testing.T) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, _ = w.Write([]byte(strings.Repeat("a", 4096)))
+	_ = w.Close()
+	encoded := buf.Bytes()
+	tests := []struct {
+		name    string
+		limit   int64
+		wantErr error
+	}{
+		{name: "exceeded", limit: 1024, wantErr: ErrResponseTooLarge},
+		{name: "exact", limit: 4096},
+		{name: "unlimited", limit: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			response, err := Client(MaxDecompressedBytes(tt.limit))(http.DefaultClient, request, func(cli *http.Client, request *http.Request) (*http.Response, error) {
+				header := http.Header{"Content-Encoding": []string{Gzip}}
+				return &http.Response{StatusCode: http.StatusOK, Header: header, Body: io.NopCloser(bytes.NewReader(encoded))}, nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer response.Body.Close()
+			data, err := io.ReadAll(response.Body)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && len(data) != 4096 {
+				t.Errorf("body has %d bytes, want 4096", len(data))
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package concurrency
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrLimitExceeded is matched by errors.Is for the errors returned when the concurrency limit is reached
+var ErrLimitExceeded = errors.New("concurrency: limit exceeded")
+
+// LimitError is returned instead of sending a request rejected by the client concurrency limiter.
+// The server middleware encodes it, without key, for the requests it rejects.
+// Returned by a server, it is encoded as 503 Service Unavailable.
+type LimitError struct {
+	Key   string // The key of the limiter, empty for the server limiter
+	Limit int    // The concurrency limit when the request was rejected
+}
+
+// Error returns the error message
+func (e *LimitError) Error() string {
+	if e.Key == "" {
+		return fmt.Sprintf("concurrency: %d requests in flight", e.Limit)
+	}
+	return fmt.Sprintf("concurrency: %d requests in flight for %q", e.Limit, e.Key)
+}
+
+// Unwrap returns ErrLimitExceeded
+func (e *LimitError) Unwrap() error {
+	return ErrLimitExceeded
+}
+
+// StatusCode returns 503 Service Unavailable
+func (e *LimitError) StatusCode() int {
+	return http.StatusServiceUnavailable
+}
+
+// limiter is an AIMD concurrency limiter: the limit grows by one after each
+// successful request sent while at least half of the limit is in use, and is
+// multiplied by the backoff ratio after each request showing an overload
+type limiter struct {
+	opt      *options
+	mu       sync.Mutex
+	limit    float64 // The current concurrency limit
+	inFlight int     // The number of requests in flight
+}
+
+// newLimiter creates a limiter starting at the initial limit
+func newLimiter(opt *options) *limiter {
+	return &limiter{opt: opt, limit: float64(opt.initialLimit)}
+}
+
+// acquire reserves a slot for a request
+// Returns:
+//   - int: The current limit
+//   - bool: False if the limit is reached
+func (l *limiter) acquire() (int, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	limit := int(l.limit)
+	if l.inFlight >= limit {
+		return limit, false
+	}
+	l.inFlight++
+	return limit, true
+}
+
+// release frees the slot of a request and adjusts the limit
+// Parameters:
+//   - latency: The duration of the request
+//   - overloaded: Whether the outcome of the request shows an overload
+func (l *limiter) release(latency time.Duration, overloaded bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	inFlight := l.inFlight
+	l.inFlight--
+	if overloaded || latency > l.opt.maxLatency {
+		l.limit = max(l.limit*l.opt.backoffRatio, float64(l.opt.minLimit))
+		return
+	}
+	// only grow a limit that is actually used
+	if float64(inFlight)*2 >= l.limit {
+		l.limit = min(l.limit+1, float64(l.opt.maxLimit))
+	}
+}
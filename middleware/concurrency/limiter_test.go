@@ -0,0 +1,102 @@
+package concurrency
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLimiter(t *testing.T) {
+	opt := defaultOptions().apply(InitialLimit(4), MinLimit(2), MaxLimit(5), BackoffRatio(0.5), MaxLatency(time.Second)).normalize()
+	l := newLimiter(opt)
+
+	// the limit is reached
+	for i := range 4 {
+		if limit, ok := l.acquire(); !ok || limit != 4 {
+			t.Fatalf("acquire %d = %d, %t, want 4, true", i, limit, ok)
+		}
+	}
+	if limit, ok := l.acquire(); ok || limit != 4 {
+		t.Fatalf("acquire beyond the limit = %d, %t, want 4, false", limit, ok)
+	}
+
+	tests := []struct {
+		name       string
+		latency    time.Duration
+		overloaded bool
+		want       float64
+	}{
+		// 4 in flight of 4, the limit is used
+		{name: "success", latency: time.Millisecond, want: 5},
+		// the limit never exceeds MaxLimit
+		{name: "success at max", latency: time.Millisecond, want: 5},
+		// 2 in flight of 5, the limit is not used enough to grow
+		{name: "success unused", latency: time.Millisecond, want: 5},
+		// multiplicative decrease
+		{name: "overloaded", latency: time.Millisecond, overloaded: true, want: 2.5},
+	}
+	for _, tt := range tests {
+		l.release(tt.latency, tt.overloaded)
+		if l.limit != tt.want {
+			t.Errorf("%s: limit = %v, want %v", tt.name, l.limit, tt.want)
+		}
+	}
+	if l.inFlight != 0 {
+		t.Fatalf("inFlight = %d, want 0", l.inFlight)
+	}
+
+	// a slow request shows an overload, the limit never goes below MinLimit
+	_, _ = l.acquire()
+	l.release(2*time.Second, false)
+	if l.limit != 2 {
+		t.Errorf("slow: limit = %v, want 2", l.limit)
+	}
+	_, _ = l.acquire()
+	l.release(time.Millisecond, true)
+	if l.limit != 2 {
+		t.Errorf("overloaded at min: limit = %v, want 2", l.limit)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want options
+	}{
+		{
+			name: "defaults",
+			want: options{initialLimit: 20, minLimit: 1, maxLimit: 1000, backoffRatio: 0.9},
+		},
+		{
+			name: "inconsistent limits",
+			opts: []Option{InitialLimit(50), MinLimit(0), MaxLimit(-1), BackoffRatio(1)},
+			want: options{initialLimit: 1, minLimit: 1, maxLimit: 1, backoffRatio: 0.9},
+		},
+		{
+			name: "initial below min",
+			opts: []Option{InitialLimit(1), MinLimit(5), MaxLimit(10), BackoffRatio(0.5)},
+			want: options{initialLimit: 5, minLimit: 5, maxLimit: 10, backoffRatio: 0.5},
+		},
+	}
+	for _, tt := range tests {
+		got := defaultOptions().apply(tt.opts...).normalize()
+		if got.initialLimit != tt.want.initialLimit || got.minLimit != tt.want.minLimit ||
+			got.maxLimit != tt.want.maxLimit || got.backoffRatio != tt.want.backoffRatio {
+			t.Errorf("%s: got %+v, want %+v", tt.name, *got, tt.want)
+		}
+	}
+}
+
+func TestLimitError(t *testing.T) {
+	var err error = &LimitError{Key: "localhost:8080", Limit: 3}
+	if !errors.Is(err, ErrLimitExceeded) {
+		t.Error("LimitError does not match ErrLimitExceeded")
+	}
+	if got := err.(*LimitError).StatusCode(); got != 503 {
+		t.Errorf("StatusCode() = %d, want 503", got)
+	}
+	if got := err.Error(); got != `concurrency: 3 requests in flight for "localhost:8080"` {
+		t.Errorf("Error() = %q", got)
+	}
+}
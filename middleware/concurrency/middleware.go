@@ -0,0 +1,239 @@
+// Package concurrency provides server and client middlewares shedding load with an adaptive concurrency limit
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-leo/goose"
+	"github.com/go-leo/goose/client"
+	"github.com/go-leo/goose/server"
+)
+
+// KeyFunc returns the key of the client limiter of a request
+// Parameters:
+//   - request: The outgoing HTTP request
+//
+// Returns:
+//   - string: The limiter key, requests with the same key share a concurrency limit
+type KeyFunc func(request *http.Request) string
+
+// ByHost keys client limiters by the host of the request, the default
+func ByHost(request *http.Request) string {
+	return request.URL.Host
+}
+
+// options holds configuration options for the concurrency middlewares
+type options struct {
+	initialLimit int                // Limit before any adjustment
+	minLimit     int                // Lower bound of the limit
+	maxLimit     int                // Upper bound of the limit
+	backoffRatio float64            // Factor applied to the limit on overload
+	maxLatency   time.Duration      // Latency beyond which a request shows an overload
+	keyFunc      KeyFunc            // Key of the client limiter of a request
+	errorEncoder goose.ErrorEncoder // Encoder of the LimitError of the requests rejected by the server
+}
+
+// apply applies the given options to the options struct
+// Parameters:
+//   - opts: Variable number of Option functions
+//
+// Returns:
+//   - *options: Pointer to the updated options struct
+func (o *options) apply(opts ...Option) *options {
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Option is a function type for configuring the concurrency middlewares
+type Option func(o *options)
+
+// defaultOptions returns the default configuration options
+// Returns:
+//   - *options: Default options, a limit starting at 20 between 1 and 1000,
+//     reduced by 10% when a request takes more than 5s or shows an overload
+func defaultOptions() *options {
+	return &options{
+		initialLimit: 20,
+		minLimit:     1,
+		maxLimit:     1000,
+		backoffRatio: 0.9,
+		maxLatency:   5 * time.Second,
+		keyFunc:      ByHost,
+		errorEncoder: goose.DefaultEncodeError,
+	}
+}
+
+// InitialLimit sets the concurrency limit before any adjustment
+// Parameters:
+//   - n: Initial number of requests in flight
+//
+// Returns:
+//   - Option: Function to set the initial limit option
+func InitialLimit(n int) Option {
+	return func(o *options) {
+		o.initialLimit = n
+	}
+}
+
+// MinLimit sets the lower bound of the concurrency limit
+// Parameters:
+//   - n: Minimum number of requests in flight
+//
+// Returns:
+//   - Option: Function to set the min limit option
+func MinLimit(n int) Option {
+	return func(o *options) {
+		o.minLimit = n
+	}
+}
+
+// MaxLimit sets the upper bound of the concurrency limit
+// Parameters:
+//   - n: Maximum number of requests in flight
+//
+// Returns:
+//   - Option: Function to set the max limit option
+func MaxLimit(n int) Option {
+	return func(o *options) {
+		o.maxLimit = n
+	}
+}
+
+// BackoffRatio sets the factor applied to the concurrency limit when a request shows an overload
+// Parameters:
+//   - ratio: Ratio between 0 and 1
+//
+// Returns:
+//   - Option: Function to set the backoff ratio option
+func BackoffRatio(ratio float64) Option {
+	return func(o *options) {
+		o.backoffRatio = ratio
+	}
+}
+
+// MaxLatency sets the latency beyond which a request shows an overload
+// Parameters:
+//   - latency: Maximum latency of a healthy request
+//
+// Returns:
+//   - Option: Function to set the max latency option
+func MaxLatency(latency time.Duration) Option {
+	return func(o *options) {
+		o.maxLatency = latency
+	}
+}
+
+// Key sets the function returning the key of the client limiter of a request, it is ignored by Server
+// Parameters:
+//   - keyFunc: Key function, such as ByHost
+//
+// Returns:
+//   - Option: Function to set the key option
+func Key(keyFunc KeyFunc) Option {
+	return func(o *options) {
+		o.keyFunc = keyFunc
+	}
+}
+
+// ErrorEncoder sets the encoder of the LimitError of the requests rejected by the server,
+// usually the error encoder of the server, it is ignored by Client
+// Parameters:
+//   - encoder: Error encoder, goose.DefaultEncodeError by default
+//
+// Returns:
+//   - Option: Function to set the error encoder option
+func ErrorEncoder(encoder goose.ErrorEncoder) Option {
+	return func(o *options) {
+		o.errorEncoder = encoder
+	}
+}
+
+// normalize keeps the limits consistent
+func (o *options) normalize() *options {
+	o.minLimit = max(o.minLimit, 1)
+	o.maxLimit = max(o.maxLimit, o.minLimit)
+	o.initialLimit = min(max(o.initialLimit, o.minLimit), o.maxLimit)
+	if o.backoffRatio <= 0 || o.backoffRatio >= 1 {
+		o.backoffRatio = 0.9
+	}
+	return o
+}
+
+// isOverloadStatus reports whether a status code shows an overload
+func isOverloadStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests ||
+		statusCode == http.StatusServiceUnavailable ||
+		statusCode == http.StatusGatewayTimeout
+}
+
+// Server creates a server concurrency limit middleware
+// Parameters:
+//   - opts: Variable number of Option functions for configuration
+//
+// Returns:
+//   - server.Middleware: Server middleware function
+//
+// Behavior:
+//  1. Encodes a *LimitError, 503 Service Unavailable, when the requests in flight reach the limit
+//  2. Otherwise handles the request and measures its latency
+//  3. Reduces the limit when the request takes more than MaxLatency or is answered with 429, 503 or 504,
+//     increases it by one otherwise
+func Server(opts ...Option) server.Middleware {
+	opt := defaultOptions().apply(opts...).normalize()
+	l := newLimiter(opt)
+	return func(response http.ResponseWriter, request *http.Request, invoker http.HandlerFunc) {
+		if limit, ok := l.acquire(); !ok {
+			opt.errorEncoder(request.Context(), &LimitError{Limit: limit}, response)
+			return
+		}
+		writer := server.NewRecordingResponseWriter(response)
+		start := time.Now()
+		defer func() {
+			l.release(time.Since(start), isOverloadStatus(writer.StatusCode()))
+		}()
+		invoker(writer, request)
+	}
+}
+
+// Client creates a client concurrency limit middleware, with a limiter per target
+// Parameters:
+//   - opts: Variable number of Option functions for configuration
+//
+// Returns:
+//   - client.Middleware: Client middleware function
+//
+// Behavior:
+//  1. Returns a *LimitError without sending the request when the requests in flight to the target reach the limit
+//  2. Otherwise sends the request and measures its latency until the response headers
+//  3. Reduces the limit when the request takes more than MaxLatency, fails with a transport error
+//     other than a canceled context, or is answered with 429, 503 or 504; increases it by one otherwise
+func Client(opts ...Option) client.Middleware {
+	opt := defaultOptions().apply(opts...).normalize()
+	var limiters sync.Map
+	return func(cli *http.Client, request *http.Request, invoker client.Invoker) (*http.Response, error) {
+		key := opt.keyFunc(request)
+		value, ok := limiters.Load(key)
+		if !ok {
+			value, _ = limiters.LoadOrStore(key, newLimiter(opt))
+		}
+		l := value.(*limiter)
+		limit, ok := l.acquire()
+		if !ok {
+			return nil, &LimitError{Key: key, Limit: limit}
+		}
+		start := time.Now()
+		response, err := invoker(cli, request)
+		if err != nil {
+			l.release(time.Since(start), !errors.Is(err, context.Canceled))
+			return response, err
+		}
+		l.release(time.Since(start), isOverloadStatus(response.StatusCode))
+		return response, err
+	}
+}
@@ -0,0 +1,225 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/go-leo/goose/client"
+	"github.com/go-leo/goose/server"
+)
+
+func TestServer(t *testing.T) {
+	middleware := Server(InitialLimit(1), MaxLimit(1))
+	release := make(chan struct{})
+	started := make(chan struct{})
+	blocking := func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	first := httptest.NewRecorder()
+	go func() {
+		defer wg.Done()
+		server.Invoke(middleware, first, httptest.NewRequest(http.MethodGet, "/", nil), blocking)
+	}()
+	<-started
+
+	// the limit is reached
+	rec := httptest.NewRecorder()
+	server.Invoke(middleware, rec, httptest.NewRequest(http.MethodGet, "/", nil), func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the request beyond the limit was handled")
+	})
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", rec.Code)
+	}
+	if got := rec.Body.String(); got != "concurrency: 1 requests in flight" {
+		t.Errorf("body = %q, want the LimitError message", got)
+	}
+
+	close(release)
+	wg.Wait()
+	if first.Code != http.StatusOK {
+		t.Errorf("first status = %d, want 200", first.Code)
+	}
+
+	// the slot is released
+	rec = httptest.NewRecorder()
+	server.Invoke(middleware, rec, httptest.NewRequest(http.MethodGet, "/", nil), func(w http.ResponseWriter, r *http.Request) {})
+	if rec.Code != http.StatusOK {
+		t.Errorf("status after release = %d, want 200", rec.Code)
+	}
+}
+
+func TestServerOverloadStatus(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		want       int
+	}{
+		{statusCode: http.StatusOK, want: 3},
+		{statusCode: http.StatusInternalServerError, want: 3},
+		{statusCode: http.StatusTooManyRequests, want: 1},
+		{statusCode: http.StatusServiceUnavailable, want: 1},
+		{statusCode: http.StatusGatewayTimeout, want: 1},
+	}
+	for _, tt := range tests {
+		middleware := Server(InitialLimit(2), MaxLimit(3), BackoffRatio(0.5))
+		server.Invoke(middleware, httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil), func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.statusCode)
+		})
+		// the limit is observed through the number of requests let through concurrently
+		if got := concurrent(middleware); got != tt.want {
+			t.Errorf("status %d: limit = %d, want %d", tt.statusCode, got, tt.want)
+		}
+	}
+}
+
+// concurrent returns the number of blocked requests the middleware lets through
+func concurrent(middleware server.Middleware) int {
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	n := 0
+	for {
+		started := make(chan struct{})
+		rec := httptest.NewRecorder()
+		done := make(chan struct{})
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(done)
+			server.Invoke(middleware, rec, httptest.NewRequest(http.MethodGet, "/", nil), func(w http.ResponseWriter, r *http.Request) {
+				close(started)
+				<-release
+			})
+		}()
+		select {
+		case <-started:
+			n++
+			continue
+		case <-done:
+		}
+		break
+	}
+	close(release)
+	wg.Wait()
+	return n
+}
+
+// fakeInvoker answers with a status code, or an error, after an optional wait
+type fakeInvoker struct {
+	statusCode int
+	err        error
+	wait       chan struct{}
+	started    chan struct{}
+}
+
+func (f *fakeInvoker) invoke(cli *http.Client, request *http.Request) (*http.Response, error) {
+	if f.started != nil {
+		close(f.started)
+	}
+	if f.wait != nil {
+		<-f.wait
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &http.Response{StatusCode: f.statusCode, Body: http.NoBody}, nil
+}
+
+func TestClient(t *testing.T) {
+	middleware := Client(InitialLimit(1), MaxLimit(1))
+	blocked := &fakeInvoker{statusCode: http.StatusOK, wait: make(chan struct{}), started: make(chan struct{})}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		request := httptest.NewRequest(http.MethodGet, "http://a.example/", nil)
+		if _, err := middleware(http.DefaultClient, request, blocked.invoke); err != nil {
+			t.Error(err)
+		}
+	}()
+	<-blocked.started
+
+	// the limit of the host is reached
+	request := httptest.NewRequest(http.MethodGet, "http://a.example/", nil)
+	_, err := middleware(http.DefaultClient, request, func(cli *http.Client, request *http.Request) (*http.Response, error) {
+		t.Error("the request beyond the limit was sent")
+		return nil, nil
+	})
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) || limitErr.Key != "a.example" || limitErr.Limit != 1 {
+		t.Errorf("err = %v, want a LimitError for a.example", err)
+	}
+
+	// other hosts have their own limiter
+	request = httptest.NewRequest(http.MethodGet, "http://b.example/", nil)
+	ok := &fakeInvoker{statusCode: http.StatusOK}
+	if _, err := middleware(http.DefaultClient, request, ok.invoke); err != nil {
+		t.Errorf("b.example: err = %v", err)
+	}
+
+	close(blocked.wait)
+	wg.Wait()
+	request = httptest.NewRequest(http.MethodGet, "http://a.example/", nil)
+	if _, err := middleware(http.DefaultClient, request, ok.invoke); err != nil {
+		t.Errorf("after release: err = %v", err)
+	}
+}
+
+func TestClientAdjustments(t *testing.T) {
+	tests := []struct {
+		name    string
+		invoker *fakeInvoker
+		want    int
+	}{
+		{name: "success", invoker: &fakeInvoker{statusCode: http.StatusOK}, want: 3},
+		{name: "overload status", invoker: &fakeInvoker{statusCode: http.StatusServiceUnavailable}, want: 1},
+		{name: "transport error", invoker: &fakeInvoker{err: errors.New("connection refused")}, want: 1},
+		{name: "canceled", invoker: &fakeInvoker{err: context.Canceled}, want: 3},
+	}
+	for _, tt := range tests {
+		middleware := Client(InitialLimit(2), MaxLimit(3), BackoffRatio(0.5))
+		_, _ = middleware(http.DefaultClient, httptest.NewRequest(http.MethodGet, "http://a.example/", nil), tt.invoker.invoke)
+		// the limit is observed through the number of requests sent concurrently
+		if got := concurrentClient(t, middleware); got != tt.want {
+			t.Errorf("%s: limit = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+// concurrentClient returns the number of blocked requests the middleware sends
+func concurrentClient(t *testing.T, middleware client.Middleware) int {
+	var blocked []*fakeInvoker
+	var wg sync.WaitGroup
+	for {
+		f := &fakeInvoker{statusCode: http.StatusOK, wait: make(chan struct{}), started: make(chan struct{})}
+		errc := make(chan error, 1)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := middleware(http.DefaultClient, httptest.NewRequest(http.MethodGet, "http://a.example/", nil), f.invoke)
+			errc <- err
+		}()
+		select {
+		case <-f.started:
+			blocked = append(blocked, f)
+			continue
+		case err := <-errc:
+			if !errors.Is(err, ErrLimitExceeded) {
+				t.Errorf("err = %v, want ErrLimitExceeded", err)
+			}
+		}
+		break
+	}
+	for _, f := range blocked {
+		close(f.wait)
+	}
+	wg.Wait()
+	return len(blocked)
+}
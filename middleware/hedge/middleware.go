@@ -0,0 +1,382 @@
+// Package hedge provides a client middleware sending hedged requests to cut tail latency
+package hedge
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-leo/goose/client"
+	"github.com/go-leo/goose/client/resolver"
+)
+
+// options holds configuration options for the hedge middleware
+type options struct {
+	maxAttempts int               // Maximum number of attempts, including the first one
+	delay       time.Duration     // Delay before each hedged attempt
+	percentile  float64           // Percentile of the observed latencies used as delay, 0 to disable
+	methods     []string          // HTTP methods to hedge
+	resolver    resolver.Resolver // Resolver of the instance of the hedged attempts
+	target      string            // Target resolved by the resolver
+}
+
+// apply applies the given options to the options struct
+// Parameters:
+//   - opts: Variable number of Option functions
+//
+// Returns:
+//   - *options: Pointer to the updated options struct
+func (o *options) apply(opts ...Option) *options {
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Option is a function type for configuring the hedge middleware
+type Option func(o *options)
+
+// defaultOptions returns the default configuration options
+// Returns:
+//   - *options: Default options, one hedged attempt of GET, HEAD and OPTIONS requests after 100ms
+func defaultOptions() *options {
+	return &options{
+		maxAttempts: 2,
+		delay:       100 * time.Millisecond,
+		methods:     []string{http.MethodGet, http.MethodHead, http.MethodOptions},
+	}
+}
+
+// MaxAttempts sets the maximum number of attempts, including the first one
+// Parameters:
+//   - n: Maximum number of attempts, 1 disables hedging
+//
+// Returns:
+//   - Option: Function to set the max attempts option
+func MaxAttempts(n int) Option {
+	return func(o *options) {
+		o.maxAttempts = n
+	}
+}
+
+// Delay sets the delay before each hedged attempt, also used by Percentile until enough latencies are observed
+// Parameters:
+//   - d: Hedging delay
+//
+// Returns:
+//   - Option: Function to set the delay option
+func Delay(d time.Duration) Option {
+	return func(o *options) {
+		o.delay = d
+	}
+}
+
+// Percentile sets the delay before each hedged attempt to a percentile of the latencies of the recent
+// successful requests, such as 0.95 to hedge the 5% slowest requests
+// Parameters:
+//   - p: Percentile between 0 and 1
+//
+// Returns:
+//   - Option: Function to set the percentile option
+func Percentile(p float64) Option {
+	return func(o *options) {
+		o.percentile = p
+	}
+}
+
+// Methods sets the HTTP methods to hedge, which must be idempotent, replacing GET, HEAD and OPTIONS
+// Parameters:
+//   - methods: Hedged HTTP methods
+//
+// Returns:
+//   - Option: Function to set the methods option
+func Methods(methods ...string) Option {
+	return func(o *options) {
+		o.methods = methods
+	}
+}
+
+// Resolve sets the resolver and the target the hedged attempts are sent to, replacing the resolver of the client.
+// By default, the hedged attempts of the requests of generated clients are resolved with the resolver of the client,
+// the one of client.TargetResolverFromContext, and the other requests are hedged to their own URL.
+// With a resolver.BalancingResolver, each hedged attempt is sent to a newly picked instance, preferably not used yet.
+// Parameters:
+//   - r: The resolver of the client
+//   - target: The target of the client, such as "lb://users"
+//
+// Returns:
+//   - Option: Function to set the resolver option
+func Resolve(r resolver.Resolver, target string) Option {
+	return func(o *options) {
+		o.resolver = r
+		o.target = target
+	}
+}
+
+// Client creates a client hedge middleware
+// Parameters:
+//   - opts: Variable number of Option functions for configuration
+//
+// Returns:
+//   - client.Middleware: Client middleware function
+//
+// Behavior:
+//  1. Sends requests with a method that is not hedged, or a body that cannot be replayed with GetBody, once
+//  2. Sends a hedged attempt each time the delay elapses without a response, or as soon as an attempt fails,
+//     up to MaxAttempts attempts
+//  3. Returns the first response with a status below 500, canceling the other attempts and closing their responses
+//  4. Returns the last response or error when all the attempts fail
+//
+// The requests fail without being sent if the target of Resolve is not a valid URL.
+func Client(opts ...Option) client.Middleware {
+	opt := defaultOptions().apply(opts...)
+	var resolve client.TargetResolver
+	var targetErr error
+	if opt.resolver != nil {
+		target, err := url.Parse(opt.target)
+		if err != nil {
+			targetErr = fmt.Errorf("hedge: invalid target %q: %w", opt.target, err)
+		}
+		resolve = func(ctx context.Context) (*url.URL, error) {
+			return opt.resolver.Resolve(ctx, target)
+		}
+	}
+	latencies := &latencyWindow{}
+	return func(cli *http.Client, request *http.Request, invoker client.Invoker) (*http.Response, error) {
+		if targetErr != nil {
+			return nil, targetErr
+		}
+		if !opt.hedgeable(request) {
+			return invoker(cli, request)
+		}
+		h := &hedger{opt: opt, resolve: resolve, cli: cli, request: request, invoker: invoker, results: make(chan *attempt, opt.maxAttempts)}
+		if h.resolve == nil {
+			h.resolve, _ = client.TargetResolverFromContext(request.Context())
+		}
+		return h.do(latencies)
+	}
+}
+
+// hedgeable reports whether the request may be sent several times
+func (o *options) hedgeable(request *http.Request) bool {
+	if o.maxAttempts <= 1 {
+		return false
+	}
+	if request.Body != nil && request.Body != http.NoBody && request.GetBody == nil {
+		return false
+	}
+	method := request.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	return slices.Contains(o.methods, method)
+}
+
+// attempt is an attempt of a hedged request
+type attempt struct {
+	start    time.Time
+	cancel   context.CancelFunc
+	response *http.Response
+	err      error
+}
+
+// succeeded reports whether the attempt ends the hedged request
+func (a *attempt) succeeded() bool {
+	return a.err == nil && a.response.StatusCode < http.StatusInternalServerError
+}
+
+// hedger sends the attempts of a hedged request
+type hedger struct {
+	opt      *options
+	resolve  client.TargetResolver // Resolver of the instance of the hedged attempts, nil to hedge to the URL of the request
+	cli      *http.Client
+	request  *http.Request
+	invoker  client.Invoker
+	results  chan *attempt
+	attempts []*attempt
+	hosts    []string // Hosts the attempts were sent to
+}
+
+// do sends the attempts and returns the first successful response
+func (h *hedger) do(latencies *latencyWindow) (*http.Response, error) {
+	delay := latencies.delay(h.opt)
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	h.launch(h.request)
+	var last *attempt
+	for pending := 1; pending > 0; {
+		select {
+		case <-timer.C:
+			if h.launchNext() {
+				pending++
+				timer.Reset(delay)
+			}
+		case result := <-h.results:
+			pending--
+			if result.succeeded() {
+				if last != nil {
+					discard(last)
+				}
+				latencies.add(time.Since(result.start))
+				h.cancelOthers(result, pending)
+				result.response.Body = &cancelBody{ReadCloser: result.response.Body, cancel: result.cancel}
+				return result.response, nil
+			}
+			if last != nil {
+				discard(last)
+			}
+			last = result
+			// hedge a failed attempt right away
+			if h.launchNext() {
+				pending++
+			}
+		}
+	}
+	if last.response != nil && last.response.Body != nil {
+		last.response.Body = &cancelBody{ReadCloser: last.response.Body, cancel: last.cancel}
+	} else {
+		last.cancel()
+	}
+	return last.response, last.err
+}
+
+// launchNext launches a hedged attempt if the attempts are not exhausted
+func (h *hedger) launchNext() bool {
+	if len(h.attempts) >= h.opt.maxAttempts || h.request.Context().Err() != nil {
+		return false
+	}
+	request, err := h.hedgedRequest()
+	if err != nil {
+		a := &attempt{start: time.Now(), cancel: func() {}, err: err}
+		h.attempts = append(h.attempts, a)
+		h.results <- a
+		return true
+	}
+	h.launch(request)
+	return true
+}
+
+// hedgedRequest returns a copy of the request with a fresh body, sent to another instance if a resolver is known
+func (h *hedger) hedgedRequest() (*http.Request, error) {
+	request := h.request.Clone(h.request.Context())
+	if h.request.GetBody != nil {
+		body, err := h.request.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		request.Body = body
+	}
+	if h.resolve == nil {
+		return request, nil
+	}
+	var target *url.URL
+	for i := 0; i < 3; i++ {
+		resolved, err := h.resolve(request.Context())
+		if err != nil {
+			return request, nil
+		}
+		target = resolved
+		if !slices.Contains(h.hosts, resolved.Host) {
+			break
+		}
+	}
+	if target != nil {
+		request.URL.Scheme = target.Scheme
+		request.URL.Host = target.Host
+		request.Host = ""
+	}
+	return request, nil
+}
+
+// launch sends an attempt in the background
+func (h *hedger) launch(request *http.Request) {
+	ctx, cancel := context.WithCancel(request.Context())
+	a := &attempt{start: time.Now(), cancel: cancel}
+	h.attempts = append(h.attempts, a)
+	h.hosts = append(h.hosts, request.URL.Host)
+	request = request.WithContext(ctx)
+	go func() {
+		a.response, a.err = h.invoker(h.cli, request)
+		h.results <- a
+	}()
+}
+
+// cancelOthers cancels the attempts but the winner, and discards the responses of the pending ones
+func (h *hedger) cancelOthers(winner *attempt, pending int) {
+	for _, a := range h.attempts {
+		if a != winner {
+			a.cancel()
+		}
+	}
+	if pending == 0 {
+		return
+	}
+	go func() {
+		for i := 0; i < pending; i++ {
+			discard(<-h.results)
+		}
+	}()
+}
+
+// discard closes the response of an attempt that is not returned and cancels it
+func discard(a *attempt) {
+	if a.response != nil && a.response.Body != nil {
+		_, _ = io.CopyN(io.Discard, a.response.Body, 4<<10)
+		_ = a.response.Body.Close()
+	}
+	a.cancel()
+}
+
+// cancelBody cancels the context of the attempt when the body of the returned response is closed
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the body and cancels the attempt
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// latencyWindowSize is the number of latencies kept to compute the percentile
+const latencyWindowSize = 128
+
+// latencyWindow keeps the latencies of the recent successful requests
+type latencyWindow struct {
+	mu        sync.Mutex
+	latencies [latencyWindowSize]time.Duration
+	count     int // Number of latencies added
+}
+
+// add adds the latency of a successful request
+func (w *latencyWindow) add(latency time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.latencies[w.count%latencyWindowSize] = latency
+	w.count++
+}
+
+// delay returns the hedging delay, the percentile of the latencies once at least 16 are known
+func (w *latencyWindow) delay(opt *options) time.Duration {
+	if opt.percentile <= 0 {
+		return opt.delay
+	}
+	w.mu.Lock()
+	n := min(w.count, latencyWindowSize)
+	if n < 16 {
+		w.mu.Unlock()
+		return opt.delay
+	}
+	latencies := slices.Clone(w.latencies[:n])
+	w.mu.Unlock()
+	slices.Sort(latencies)
+	i := int(float64(n-1) * min(opt.percentile, 1))
+	return latencies[i]
+}
@@ -0,0 +1,348 @@
+package hedge
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-leo/goose/client"
+	"github.com/go-leo/goose/client/resolver"
+)
+
+// attemptServer is a test server answering each attempt with the handler, given the index of the attempt from 0
+type attemptServer struct {
+	*httptest.Server
+	attempts atomic.Int32
+	canceled chan int // Receives the attempts canceled by the client
+}
+
+func newAttemptServer(t *testing.T, handler func(s *attemptServer, w http.ResponseWriter, r *http.Request, i int)) *attemptServer {
+	t.Helper()
+	s := &attemptServer{canceled: make(chan int, 32)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler(s, w, r, int(s.attempts.Add(1))-1)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// hang blocks the attempt until the client cancels it
+func (s *attemptServer) hang(r *http.Request, i int) {
+	// the server only sees the connection closed once the body is read
+	_, _ = io.Copy(io.Discard, r.Body)
+	select {
+	case <-r.Context().Done():
+		s.canceled <- i
+	case <-time.After(10 * time.Second):
+	}
+}
+
+// expectCanceled waits for the server to see the attempt canceled
+func (s *attemptServer) expectCanceled(t *testing.T, want int) {
+	t.Helper()
+	select {
+	case i := <-s.canceled:
+		if i != want {
+			t.Errorf("attempt %d canceled, want %d", i, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("attempt %d not canceled", want)
+	}
+}
+
+// trackedBody records whether a response body was closed
+type trackedBody struct {
+	io.ReadCloser
+	closed atomic.Bool
+}
+
+func (b *trackedBody) Close() error {
+	b.closed.Store(true)
+	return b.ReadCloser.Close()
+}
+
+// tracker is an invoker sending the requests and tracking the response bodies
+type tracker struct {
+	mu     sync.Mutex
+	bodies []*trackedBody
+}
+
+func (tr *tracker) invoke(cli *http.Client, request *http.Request) (*http.Response, error) {
+	response, err := cli.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	body := &trackedBody{ReadCloser: response.Body}
+	response.Body = body
+	tr.mu.Lock()
+	tr.bodies = append(tr.bodies, body)
+	tr.mu.Unlock()
+	return response, nil
+}
+
+// unclosed returns the number of response bodies not closed
+func (tr *tracker) unclosed() int {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	n := 0
+	for _, body := range tr.bodies {
+		if !body.closed.Load() {
+			n++
+		}
+	}
+	return n
+}
+
+// send sends a request through the middleware and returns the status and the body of the response and the latency
+func send(t *testing.T, middleware client.Middleware, tr *tracker, method string, url string) (int, string, time.Duration) {
+	t.Helper()
+	request, err := http.NewRequest(method, url, strings.NewReader("request"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	response, err := middleware(http.DefaultClient, request, tr.invoke)
+	elapsed := time.Since(start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(response.Body)
+	_ = response.Body.Close()
+	return response.StatusCode, string(body), elapsed
+}
+
+func TestHedgeAfterDelay(t *testing.T) {
+	s := newAttemptServer(t, func(s *attemptServer, w http.ResponseWriter, r *http.Request, i int) {
+		if i == 0 {
+			s.hang(r, i)
+			return
+		}
+		_, _ = io.WriteString(w, "hedged")
+	})
+	_, body, elapsed := send(t, Client(Delay(50*time.Millisecond)), &tracker{}, http.MethodGet, s.URL)
+	if body != "hedged" {
+		t.Errorf("body = %q, want the response of the hedged attempt", body)
+	}
+	if elapsed < 50*time.Millisecond {
+		t.Errorf("elapsed = %v, want the hedged attempt sent after the delay", elapsed)
+	}
+	// the slow attempt is canceled
+	s.expectCanceled(t, 0)
+}
+
+func TestFirstSuccessWins(t *testing.T) {
+	s := newAttemptServer(t, func(s *attemptServer, w http.ResponseWriter, r *http.Request, i int) {
+		if i == 0 {
+			time.Sleep(100 * time.Millisecond)
+			_, _ = io.WriteString(w, "first")
+			return
+		}
+		s.hang(r, i)
+	})
+	_, body, _ := send(t, Client(Delay(20*time.Millisecond)), &tracker{}, http.MethodGet, s.URL)
+	if body != "first" {
+		t.Errorf("body = %q, want the response of the first attempt", body)
+	}
+	if got := s.attempts.Load(); got != 2 {
+		t.Errorf("attempts = %d, want 2", got)
+	}
+	// the hedged attempt is canceled
+	s.expectCanceled(t, 1)
+}
+
+func TestNoHedgeBeforeDelay(t *testing.T) {
+	s := newAttemptServer(t, func(s *attemptServer, w http.ResponseWriter, r *http.Request, i int) {
+		_, _ = io.WriteString(w, "ok")
+	})
+	_, body, _ := send(t, Client(Delay(time.Second)), &tracker{}, http.MethodGet, s.URL)
+	if body != "ok" || s.attempts.Load() != 1 {
+		t.Errorf("body = %q after %d attempts, want a single attempt", body, s.attempts.Load())
+	}
+}
+
+func TestNotHedged(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		opts   []Option
+		body   io.Reader
+	}{
+		{name: "POST", method: http.MethodPost},
+		{name: "PATCH", method: http.MethodPatch},
+		{name: "method not configured", method: http.MethodGet, opts: []Option{Methods(http.MethodPut)}},
+		{name: "single attempt", method: http.MethodGet, opts: []Option{MaxAttempts(1)}},
+		// the body cannot be replayed without GetBody
+		{name: "body not replayable", method: http.MethodGet, body: io.NopCloser(strings.NewReader("request"))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newAttemptServer(t, func(s *attemptServer, w http.ResponseWriter, r *http.Request, i int) {
+				time.Sleep(100 * time.Millisecond)
+			})
+			request, _ := http.NewRequest(tt.method, s.URL, tt.body)
+			response, err := Client(append([]Option{Delay(5 * time.Millisecond)}, tt.opts...)...)(http.DefaultClient, request, (&tracker{}).invoke)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_ = response.Body.Close()
+			if got := s.attempts.Load(); got != 1 {
+				t.Errorf("attempts = %d, want the request sent once", got)
+			}
+		})
+	}
+}
+
+func TestFailedAttemptHedgedAtOnce(t *testing.T) {
+	s := newAttemptServer(t, func(s *attemptServer, w http.ResponseWriter, r *http.Request, i int) {
+		if i == 0 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = io.WriteString(w, "ok")
+	})
+	tr := &tracker{}
+	code, body, elapsed := send(t, Client(Delay(time.Hour)), tr, http.MethodGet, s.URL)
+	if code != http.StatusOK || body != "ok" {
+		t.Errorf("response = %d %q, want the successful hedged attempt", code, body)
+	}
+	if elapsed > 5*time.Second {
+		t.Errorf("elapsed = %v, want the failed attempt hedged without waiting for the delay", elapsed)
+	}
+	// the response of the failed attempt is closed
+	if n := tr.unclosed(); n != 0 {
+		t.Errorf("%d response bodies not closed", n)
+	}
+}
+
+func TestAllAttemptsFail(t *testing.T) {
+	s := newAttemptServer(t, func(s *attemptServer, w http.ResponseWriter, r *http.Request, i int) {
+		http.Error(w, "unavailable "+strconv.Itoa(i), http.StatusServiceUnavailable)
+	})
+	tr := &tracker{}
+	code, body, _ := send(t, Client(MaxAttempts(3), Delay(time.Hour)), tr, http.MethodGet, s.URL)
+	if code != http.StatusServiceUnavailable || body != "unavailable 2\n" {
+		t.Errorf("response = %d %q, want the last failed attempt", code, body)
+	}
+	if got := s.attempts.Load(); got != 3 {
+		t.Errorf("attempts = %d, want 3", got)
+	}
+	if n := tr.unclosed(); n != 0 {
+		t.Errorf("%d response bodies not closed", n)
+	}
+}
+
+func TestPercentileDelay(t *testing.T) {
+	var hangFrom atomic.Int32
+	hangFrom.Store(1 << 30)
+	s := newAttemptServer(t, func(s *attemptServer, w http.ResponseWriter, r *http.Request, i int) {
+		if int32(i) == hangFrom.Load() {
+			s.hang(r, i)
+			return
+		}
+		_, _ = io.WriteString(w, strconv.Itoa(i))
+	})
+	middleware := Client(Delay(time.Hour), Percentile(0.9))
+	tr := &tracker{}
+	// fast requests set the delay once 16 latencies are known
+	for range 16 {
+		send(t, middleware, tr, http.MethodGet, s.URL)
+	}
+	hangFrom.Store(16)
+	_, body, elapsed := send(t, middleware, tr, http.MethodGet, s.URL)
+	if body != "17" {
+		t.Errorf("body = %q, want the response of the hedged attempt", body)
+	}
+	if elapsed > 5*time.Second {
+		t.Errorf("elapsed = %v, want the delay of the observed latencies rather than Delay", elapsed)
+	}
+}
+
+func TestHedgeToAnotherInstance(t *testing.T) {
+	slow := newAttemptServer(t, func(s *attemptServer, w http.ResponseWriter, r *http.Request, i int) {
+		s.hang(r, i)
+	})
+	fast := newAttemptServer(t, func(s *attemptServer, w http.ResponseWriter, r *http.Request, i int) {
+		_, _ = io.WriteString(w, "fast")
+	})
+	slowURL, _ := url.Parse(slow.URL)
+	fastURL, _ := url.Parse(fast.URL)
+	lb := resolver.NewBalancingResolver("lb", resolver.StaticResolver{Addresses: []string{slowURL.Host, fastURL.Host}}, nil)
+	_, body, _ := send(t, Client(Delay(20*time.Millisecond), Resolve(lb, "lb://user")), &tracker{}, http.MethodGet, slow.URL)
+	if body != "fast" {
+		t.Errorf("body = %q, want the hedged attempt sent to the instance not used yet", body)
+	}
+}
+
+func TestHedgeWithClientResolver(t *testing.T) {
+	slow := newAttemptServer(t, func(s *attemptServer, w http.ResponseWriter, r *http.Request, i int) {
+		s.hang(r, i)
+	})
+	fast := newAttemptServer(t, func(s *attemptServer, w http.ResponseWriter, r *http.Request, i int) {
+		_, _ = io.WriteString(w, "fast")
+	})
+	instances := []string{slow.URL, fast.URL}
+	var resolved atomic.Int32
+	resolve := func(ctx context.Context) (*url.URL, error) {
+		return url.Parse(instances[int(resolved.Add(1)-1)%len(instances)])
+	}
+	request, err := http.NewRequestWithContext(client.WithTargetResolver(context.Background(), resolve), http.MethodGet, slow.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := Client(Delay(20*time.Millisecond))(http.DefaultClient, request, (&tracker{}).invoke)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	if body, _ := io.ReadAll(response.Body); string(body) != "fast" {
+		t.Errorf("body = %q, want the hedged attempt resolved by the client resolver", body)
+	}
+}
+
+func TestInvalidTarget(t *testing.T) {
+	lb := resolver.NewBalancingResolver("lb", resolver.StaticResolver{}, nil)
+	middleware := Client(Resolve(lb, "lb://user/%zz"))
+	request := httptest.NewRequest(http.MethodGet, "http://user/", nil)
+	invoker := func(cli *http.Client, request *http.Request) (*http.Response, error) {
+		t.Error("the request must not be sent")
+		return nil, nil
+	}
+	if _, err := middleware(http.DefaultClient, request, invoker); err == nil || !strings.Contains(err.Error(), "invalid target") {
+		t.Errorf("err = %v, want the invalid target error", err)
+	}
+}
+
+func TestLatencyWindow(t *testing.T) {
+	opt := defaultOptions().apply(Delay(time.Second), Percentile(0.5))
+	w := &latencyWindow{}
+	for i := range 15 {
+		w.add(time.Duration(i+1) * time.Millisecond)
+	}
+	if got := w.delay(opt); got != time.Second {
+		t.Errorf("delay with 15 latencies = %v, want Delay", got)
+	}
+	for i := 15; i < 20; i++ {
+		w.add(time.Duration(i+1) * time.Millisecond)
+	}
+	if got := w.delay(opt); got != 10*time.Millisecond {
+		t.Errorf("median of 1ms to 20ms = %v, want 10ms", got)
+	}
+	// only the recent latencies are kept
+	for range latencyWindowSize {
+		w.add(time.Minute)
+	}
+	if got := w.delay(opt); got != time.Minute {
+		t.Errorf("delay = %v, want the recent latencies", got)
+	}
+	if got := w.delay(defaultOptions().apply(Delay(time.Second))); got != time.Second {
+		t.Errorf("delay without percentile = %v, want Delay", got)
+	}
+}
@@ -37,6 +37,26 @@ func FromContext(ctx context.Context) (*jwt.Token, bool) {
 	return v, ok
 }
 
+// Subject retrieves the subject claim of the JWT token from the context,
+// such as the key of ratelimit.ByContext
+// Parameters:
+//   - ctx: Context that may contain a JWT token
+//
+// Returns:
+//   - string: The "sub" claim of the token
+//   - bool: True if a token with a non-empty subject was found, false otherwise
+func Subject(ctx context.Context) (string, bool) {
+	token, ok := FromContext(ctx)
+	if !ok || token.Claims == nil {
+		return "", false
+	}
+	subject, err := token.Claims.GetSubject()
+	if err != nil || subject == "" {
+		return "", false
+	}
+	return subject, true
+}
+
 // options holds configuration options for the JWT middleware
 type options struct {
 	realm         string             // Authentication realm for WWW-Authenticate header
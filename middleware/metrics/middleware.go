@@ -0,0 +1,244 @@
+// Package metrics provides server and client middlewares recording the RED metrics of requests with Prometheus
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-leo/goose"
+	"github.com/go-leo/goose/client"
+	"github.com/go-leo/goose/server"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// options holds configuration options for the metrics middlewares
+type options struct {
+	registerer prometheus.Registerer // Registerer of the collectors
+	gatherer   prometheus.Gatherer   // Gatherer of the scrape endpoint
+	namespace  string                // Namespace of the metric names
+	buckets    []float64             // Buckets of the latency histograms, in seconds
+}
+
+// apply applies the given options to the options struct
+// Parameters:
+//   - opts: Variable number of Option functions
+//
+// Returns:
+//   - *options: Pointer to the updated options struct
+func (o *options) apply(opts ...Option) *options {
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Option is a function type for configuring the metrics middlewares
+type Option func(o *options)
+
+// defaultOptions returns the default configuration options
+// Returns:
+//   - *options: Default options, the default Prometheus registry, the "goose" namespace and the default buckets
+func defaultOptions() *options {
+	return &options{
+		registerer: prometheus.DefaultRegisterer,
+		gatherer:   prometheus.DefaultGatherer,
+		namespace:  "goose",
+		buckets:    prometheus.DefBuckets,
+	}
+}
+
+// Registry sets the registry of the collectors and of the scrape endpoint, the default Prometheus registry by default
+// Parameters:
+//   - registry: Prometheus registry, such as prometheus.NewRegistry()
+//
+// Returns:
+//   - Option: Function to set the registry option
+func Registry(registry *prometheus.Registry) Option {
+	return func(o *options) {
+		o.registerer = registry
+		o.gatherer = registry
+	}
+}
+
+// Namespace sets the namespace of the metric names, "goose" by default
+// Parameters:
+//   - namespace: Namespace, such as the name of the application
+//
+// Returns:
+//   - Option: Function to set the namespace option
+func Namespace(namespace string) Option {
+	return func(o *options) {
+		o.namespace = namespace
+	}
+}
+
+// Buckets sets the buckets of the latency histograms, prometheus.DefBuckets by default
+// Parameters:
+//   - buckets: Upper bounds of the buckets, in seconds
+//
+// Returns:
+//   - Option: Function to set the buckets option
+func Buckets(buckets ...float64) Option {
+	return func(o *options) {
+		o.buckets = buckets
+	}
+}
+
+// labelNames are the labels of the metrics. The route is the pattern of the endpoint, not the URL of the
+// request, so that the number of series stays bounded.
+var labelNames = []string{"service", "method", "route"}
+
+// metrics holds the RED collectors of a side, server or client
+type metrics struct {
+	requests *prometheus.CounterVec   // Requests by status code
+	errors   *prometheus.CounterVec   // Failed requests
+	duration *prometheus.HistogramVec // Latency of the requests
+	inFlight *prometheus.GaugeVec     // Requests being handled
+}
+
+// newMetrics creates the collectors of a side and registers them, reusing the collectors already registered
+// by another middleware of the same side
+func newMetrics(opt *options, subsystem string) *metrics {
+	return &metrics{
+		requests: register(opt.registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: opt.namespace,
+			Subsystem: subsystem,
+			Name:      "requests_total",
+			Help:      "Total number of requests by status code.",
+		}, append(labelNames, "code"))),
+		errors: register(opt.registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: opt.namespace,
+			Subsystem: subsystem,
+			Name:      "errors_total",
+			Help:      "Total number of requests failed with a transport error, a 5xx status or a panic.",
+		}, labelNames)),
+		duration: register(opt.registerer, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: opt.namespace,
+			Subsystem: subsystem,
+			Name:      "request_duration_seconds",
+			Help:      "Latency of the requests in seconds.",
+			Buckets:   opt.buckets,
+		}, labelNames)),
+		inFlight: register(opt.registerer, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: opt.namespace,
+			Subsystem: subsystem,
+			Name:      "in_flight_requests",
+			Help:      "Number of requests being handled.",
+		}, labelNames)),
+	}
+}
+
+// register registers a collector, returning the existing one if an identical collector is already registered
+func register[C prometheus.Collector](registerer prometheus.Registerer, collector C) C {
+	if err := registerer.Register(collector); err != nil {
+		var registered prometheus.AlreadyRegisteredError
+		if errors.As(err, &registered) {
+			if existing, ok := registered.ExistingCollector.(C); ok {
+				return existing
+			}
+		}
+		panic(err)
+	}
+	return collector
+}
+
+// observe records a finished request
+func (m *metrics) observe(labels []string, code string, failed bool, start time.Time) {
+	m.requests.WithLabelValues(append(labels, code)...).Inc()
+	if failed {
+		m.errors.WithLabelValues(labels...).Inc()
+	}
+	m.duration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+}
+
+// Server creates a server metrics middleware
+// Parameters:
+//   - opts: Variable number of Option functions for configuration
+//
+// Returns:
+//   - server.Middleware: Server middleware function
+//
+// Behavior:
+//  1. Labels the metrics with the service, the RPC method and the route of the goose endpoint,
+//     or the pattern of the http.ServeMux route for other handlers
+//  2. Tracks the requests being handled in goose_server_in_flight_requests
+//  3. Records goose_server_requests_total by status code, goose_server_request_duration_seconds and
+//     goose_server_errors_total for 5xx statuses and panics
+func Server(opts ...Option) server.Middleware {
+	m := newMetrics(defaultOptions().apply(opts...), "server")
+	return func(response http.ResponseWriter, request *http.Request, invoker http.HandlerFunc) {
+		labels := requestLabels(request)
+		inFlight := m.inFlight.WithLabelValues(labels...)
+		inFlight.Inc()
+		defer inFlight.Dec()
+		start := time.Now()
+		writer := server.NewRecordingResponseWriter(response)
+		defer func() {
+			if p := recover(); p != nil {
+				m.observe(labels, strconv.Itoa(http.StatusInternalServerError), true, start)
+				panic(p)
+			}
+			m.observe(labels, strconv.Itoa(writer.StatusCode()), writer.StatusCode() >= http.StatusInternalServerError, start)
+		}()
+		invoker(writer, request)
+	}
+}
+
+// Client creates a client metrics middleware
+// Parameters:
+//   - opts: Variable number of Option functions for configuration
+//
+// Returns:
+//   - client.Middleware: Client middleware function
+//
+// Behavior:
+//  1. Labels the metrics with the service, the RPC method and the route of the goose endpoint
+//  2. Tracks the requests being sent in goose_client_in_flight_requests
+//  3. Records goose_client_requests_total by status code, "error" for transport errors,
+//     goose_client_request_duration_seconds and goose_client_errors_total for transport errors and 5xx statuses
+func Client(opts ...Option) client.Middleware {
+	m := newMetrics(defaultOptions().apply(opts...), "client")
+	return func(cli *http.Client, request *http.Request, invoker client.Invoker) (*http.Response, error) {
+		labels := requestLabels(request)
+		inFlight := m.inFlight.WithLabelValues(labels...)
+		inFlight.Inc()
+		defer inFlight.Dec()
+		start := time.Now()
+		response, err := invoker(cli, request)
+		if err != nil {
+			m.observe(labels, "error", true, start)
+			return response, err
+		}
+		m.observe(labels, strconv.Itoa(response.StatusCode), response.StatusCode >= http.StatusInternalServerError, start)
+		return response, err
+	}
+}
+
+// AppendMetrics registers the Prometheus scrape endpoint "GET /metrics" on the router
+// Parameters:
+//   - router: The router to register on, such as an *http.ServeMux or a router adapter
+//   - opts: Variable number of Option functions, Registry selects the registry to expose
+//
+// Returns:
+//   - R: The router
+func AppendMetrics[R goose.Router](router R, opts ...Option) R {
+	opt := defaultOptions().apply(opts...)
+	router.Handle("GET /metrics", promhttp.HandlerFor(opt.gatherer, promhttp.HandlerOpts{}))
+	return router
+}
+
+// requestLabels returns the label values of a request
+func requestLabels(request *http.Request) []string {
+	endpoint, ok := goose.EndpointFromContext(request.Context())
+	if !ok {
+		route := request.Pattern
+		if route == "" {
+			route = request.Method
+		}
+		return []string{"", "", route}
+	}
+	return []string{endpoint.Service(), endpoint.RPCMethod(), endpoint.Route()}
+}
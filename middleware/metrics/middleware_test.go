@@ -0,0 +1,225 @@
+package metrics
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-leo/goose"
+	"github.com/go-leo/goose/server"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+)
+
+var getUser = &goose.Endpoint{
+	FullName: "/leo.example.user.v1.User/GetUser",
+	Method:   http.MethodGet,
+	Pattern:  "/v1/user/{id}",
+}
+
+// serve handles a request of the endpoint through the middleware, recovering the panics
+func serve(middleware server.Middleware, endpoint *goose.Endpoint, path string, handler http.HandlerFunc) {
+	request := httptest.NewRequest(http.MethodGet, path, nil)
+	if endpoint != nil {
+		request = request.WithContext(goose.WithEndpoint(request.Context(), endpoint))
+	}
+	defer func() { _ = recover() }()
+	server.Invoke(middleware, httptest.NewRecorder(), request, handler)
+}
+
+func status(code int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(code)
+	}
+}
+
+func TestServer(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	middleware := Server(Registry(registry))
+	var inFlight float64
+	serve(middleware, getUser, "/v1/user/1", func(w http.ResponseWriter, r *http.Request) {
+		inFlight = gauge(t, registry, "goose_server_in_flight_requests")
+		_, _ = io.WriteString(w, "ok")
+	})
+	serve(middleware, getUser, "/v1/user/2", status(http.StatusNotFound))
+	serve(middleware, getUser, "/v1/user/3", status(http.StatusServiceUnavailable))
+	serve(middleware, getUser, "/v1/user/4", func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	if inFlight != 1 {
+		t.Errorf("in flight during the request = %v, want 1", inFlight)
+	}
+	if got := gauge(t, registry, "goose_server_in_flight_requests"); got != 0 {
+		t.Errorf("in flight after the requests = %v, want 0", got)
+	}
+	expected := `
+# HELP goose_server_requests_total Total number of requests by status code.
+# TYPE goose_server_requests_total counter
+goose_server_requests_total{code="200",method="GetUser",route="GET /v1/user/{id}",service="leo.example.user.v1.User"} 1
+goose_server_requests_total{code="404",method="GetUser",route="GET /v1/user/{id}",service="leo.example.user.v1.User"} 1
+goose_server_requests_total{code="500",method="GetUser",route="GET /v1/user/{id}",service="leo.example.user.v1.User"} 1
+goose_server_requests_total{code="503",method="GetUser",route="GET /v1/user/{id}",service="leo.example.user.v1.User"} 1
+# HELP goose_server_errors_total Total number of requests failed with a transport error, a 5xx status or a panic.
+# TYPE goose_server_errors_total counter
+goose_server_errors_total{method="GetUser",route="GET /v1/user/{id}",service="leo.example.user.v1.User"} 2
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected), "goose_server_requests_total", "goose_server_errors_total"); err != nil {
+		t.Error(err)
+	}
+	if got := histogramCount(t, registry, "goose_server_request_duration_seconds"); got != 4 {
+		t.Errorf("observed durations = %d, want 4", got)
+	}
+}
+
+func TestServerRouteLabels(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	middleware := Server(Registry(registry))
+	// the URLs of an endpoint share a series
+	for _, path := range []string{"/v1/user/1", "/v1/user/2", "/v1/user/3"} {
+		serve(middleware, getUser, path, status(http.StatusOK))
+	}
+	// other handlers are labelled by the pattern of their route, or only by their method
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /files/{path...}", func(w http.ResponseWriter, r *http.Request) {
+		server.Invoke(middleware, w, r, status(http.StatusOK))
+	})
+	for _, path := range []string{"/files/a", "/files/b/c"} {
+		mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	serve(middleware, nil, "/unrouted/1", status(http.StatusOK))
+	serve(middleware, nil, "/unrouted/2", status(http.StatusOK))
+
+	expected := `
+# HELP goose_server_requests_total Total number of requests by status code.
+# TYPE goose_server_requests_total counter
+goose_server_requests_total{code="200",method="",route="GET",service=""} 2
+goose_server_requests_total{code="200",method="",route="GET /files/{path...}",service=""} 2
+goose_server_requests_total{code="200",method="GetUser",route="GET /v1/user/{id}",service="leo.example.user.v1.User"} 3
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected), "goose_server_requests_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestClient(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	middleware := Client(Registry(registry), Namespace("app"))
+	results := []struct {
+		code int
+		err  error
+	}{
+		{code: http.StatusOK},
+		{code: http.StatusBadGateway},
+		{err: errors.New("connection refused")},
+	}
+	for _, result := range results {
+		request, _ := http.NewRequest(http.MethodGet, "http://localhost/v1/user/1", nil)
+		request = request.WithContext(goose.WithEndpoint(request.Context(), getUser))
+		_, _ = middleware(http.DefaultClient, request, func(cli *http.Client, request *http.Request) (*http.Response, error) {
+			if result.err != nil {
+				return nil, result.err
+			}
+			return &http.Response{StatusCode: result.code, Body: http.NoBody}, nil
+		})
+	}
+	expected := `
+# HELP app_client_requests_total Total number of requests by status code.
+# TYPE app_client_requests_total counter
+app_client_requests_total{code="200",method="GetUser",route="GET /v1/user/{id}",service="leo.example.user.v1.User"} 1
+app_client_requests_total{code="502",method="GetUser",route="GET /v1/user/{id}",service="leo.example.user.v1.User"} 1
+app_client_requests_total{code="error",method="GetUser",route="GET /v1/user/{id}",service="leo.example.user.v1.User"} 1
+# HELP app_client_errors_total Total number of requests failed with a transport error, a 5xx status or a panic.
+# TYPE app_client_errors_total counter
+app_client_errors_total{method="GetUser",route="GET /v1/user/{id}",service="leo.example.user.v1.User"} 2
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected), "app_client_requests_total", "app_client_errors_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSharedCollectors(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	// the middlewares of a side share the collectors of the registry
+	first, second := Server(Registry(registry)), Server(Registry(registry))
+	serve(first, getUser, "/v1/user/1", status(http.StatusOK))
+	serve(second, getUser, "/v1/user/1", status(http.StatusOK))
+	expected := `
+# HELP goose_server_requests_total Total number of requests by status code.
+# TYPE goose_server_requests_total counter
+goose_server_requests_total{code="200",method="GetUser",route="GET /v1/user/{id}",service="leo.example.user.v1.User"} 2
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected), "goose_server_requests_total"); err != nil {
+		t.Error(err)
+	}
+	// server and client collectors coexist
+	Client(Registry(registry))
+}
+
+// recordingRouter is a goose.Router recording the registered patterns
+type recordingRouter struct {
+	*http.ServeMux
+	patterns []string
+}
+
+func (r *recordingRouter) Handle(pattern string, handler http.Handler) {
+	r.patterns = append(r.patterns, pattern)
+	r.ServeMux.Handle(pattern, handler)
+}
+
+func TestAppendMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	serve(Server(Registry(registry)), getUser, "/v1/user/1", status(http.StatusOK))
+
+	router := &recordingRouter{ServeMux: http.NewServeMux()}
+	if got := AppendMetrics(router, Registry(registry)); got != router {
+		t.Error("AppendMetrics did not return the router")
+	}
+	if len(router.patterns) != 1 || router.patterns[0] != "GET /metrics" {
+		t.Errorf("patterns = %v", router.patterns)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `goose_server_requests_total{code="200"`) {
+		t.Errorf("scrape = %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+// family returns the metrics of a family of the registry
+func family(t *testing.T, registry *prometheus.Registry, name string) []*dto.Metric {
+	t.Helper()
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range families {
+		if f.GetName() == name {
+			return f.GetMetric()
+		}
+	}
+	return nil
+}
+
+// gauge returns the sum of the gauges of a family
+func gauge(t *testing.T, registry *prometheus.Registry, name string) float64 {
+	t.Helper()
+	var sum float64
+	for _, m := range family(t, registry, name) {
+		sum += m.GetGauge().GetValue()
+	}
+	return sum
+}
+
+// histogramCount returns the number of observations of the histograms of a family
+func histogramCount(t *testing.T, registry *prometheus.Registry, name string) uint64 {
+	t.Helper()
+	var count uint64
+	for _, m := range family(t, registry, name) {
+		count += m.GetHistogram().GetSampleCount()
+	}
+	return count
+}
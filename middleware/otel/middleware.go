@@ -0,0 +1,195 @@
+// Package otel provides server and client middlewares tracing requests with OpenTelemetry
+package otel
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-leo/goose"
+	"github.com/go-leo/goose/client"
+	"github.com/go-leo/goose/server"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName is the name of the tracer
+const instrumentationName = "github.com/go-leo/goose/middleware/otel"
+
+// options holds configuration options for the tracing middlewares
+type options struct {
+	tracerProvider trace.TracerProvider          // Provider of the tracer
+	propagators    propagation.TextMapPropagator // Propagators of the trace context
+}
+
+// apply applies the given options to the options struct
+// Parameters:
+//   - opts: Variable number of Option functions
+//
+// Returns:
+//   - *options: Pointer to the updated options struct
+func (o *options) apply(opts ...Option) *options {
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Option is a function type for configuring the tracing middlewares
+type Option func(o *options)
+
+// defaultOptions returns the default configuration options
+// Returns:
+//   - *options: Default options, the global tracer provider and the W3C trace context and baggage propagators
+func defaultOptions() *options {
+	return &options{
+		tracerProvider: otel.GetTracerProvider(),
+		propagators:    propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}),
+	}
+}
+
+// TracerProvider sets the provider of the tracer, the global tracer provider by default
+// Parameters:
+//   - provider: Tracer provider, such as an SDK provider with an in-memory exporter in tests
+//
+// Returns:
+//   - Option: Function to set the tracer provider option
+func TracerProvider(provider trace.TracerProvider) Option {
+	return func(o *options) {
+		o.tracerProvider = provider
+	}
+}
+
+// Propagators sets the propagators of the trace context, W3C traceparent and baggage by default
+// Parameters:
+//   - propagators: Propagators, such as otel.GetTextMapPropagator()
+//
+// Returns:
+//   - Option: Function to set the propagators option
+func Propagators(propagators propagation.TextMapPropagator) Option {
+	return func(o *options) {
+		o.propagators = propagators
+	}
+}
+
+// Server creates a server tracing middleware
+// Parameters:
+//   - opts: Variable number of Option functions for configuration
+//
+// Returns:
+//   - server.Middleware: Server middleware function
+//
+// Behavior:
+//  1. Extracts the trace context and the baggage of the request headers
+//  2. Starts a server span named after the RPC method of the goose endpoint, such as
+//     "leo.example.user.v1.User/GetUser", with the route, method and size attributes of the request
+//  3. Records the status code and the size of the response, marking the span as failed for 5xx statuses and panics
+func Server(opts ...Option) server.Middleware {
+	opt := defaultOptions().apply(opts...)
+	tracer := opt.tracerProvider.Tracer(instrumentationName)
+	return func(response http.ResponseWriter, request *http.Request, invoker http.HandlerFunc) {
+		ctx := opt.propagators.Extract(request.Context(), propagation.HeaderCarrier(request.Header))
+		name, attrs := requestAttributes(request)
+		if peer, _, err := net.SplitHostPort(request.RemoteAddr); err == nil {
+			attrs = append(attrs, semconv.ClientAddress(peer))
+		}
+		ctx, span := tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(attrs...))
+		writer := server.NewRecordingResponseWriter(response)
+		defer func() {
+			if p := recover(); p != nil {
+				span.SetStatus(codes.Error, "panic")
+				span.End()
+				panic(p)
+			}
+			span.SetAttributes(semconv.HTTPResponseStatusCode(writer.StatusCode()), semconv.HTTPResponseBodySize(int(writer.Written())))
+			if writer.StatusCode() >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(writer.StatusCode()))
+			}
+			span.End()
+		}()
+		invoker(writer, request.WithContext(ctx))
+	}
+}
+
+// Client creates a client tracing middleware
+// Parameters:
+//   - opts: Variable number of Option functions for configuration
+//
+// Returns:
+//   - client.Middleware: Client middleware function
+//
+// Behavior:
+//  1. Starts a client span named after the RPC method of the goose endpoint, a child of the span of the request context
+//  2. Injects the trace context and the baggage into the request headers
+//  3. Records the status code and the size of the response, marking the span as failed for
+//     transport errors and 4xx and 5xx statuses
+func Client(opts ...Option) client.Middleware {
+	opt := defaultOptions().apply(opts...)
+	tracer := opt.tracerProvider.Tracer(instrumentationName)
+	return func(cli *http.Client, request *http.Request, invoker client.Invoker) (*http.Response, error) {
+		name, attrs := requestAttributes(request)
+		attrs = append(attrs, semconv.URLFull(request.URL.Redacted()))
+		ctx, span := tracer.Start(request.Context(), name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+		defer span.End()
+		request = request.Clone(ctx)
+		opt.propagators.Inject(ctx, propagation.HeaderCarrier(request.Header))
+		response, err := invoker(cli, request)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return response, err
+		}
+		span.SetAttributes(semconv.HTTPResponseStatusCode(response.StatusCode))
+		if response.ContentLength >= 0 {
+			span.SetAttributes(semconv.HTTPResponseBodySize(int(response.ContentLength)))
+		}
+		if response.StatusCode >= http.StatusBadRequest {
+			span.SetStatus(codes.Error, http.StatusText(response.StatusCode))
+		}
+		return response, err
+	}
+}
+
+// requestAttributes returns the span name and the attributes of a request
+func requestAttributes(request *http.Request) (string, []attribute.KeyValue) {
+	attrs := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String(request.Method),
+		semconv.URLPath(request.URL.Path),
+	}
+	host := request.Host
+	if host == "" {
+		host = request.URL.Host
+	}
+	if hostname, port, err := net.SplitHostPort(host); err == nil {
+		attrs = append(attrs, semconv.ServerAddress(hostname))
+		if port, err := strconv.Atoi(port); err == nil {
+			attrs = append(attrs, semconv.ServerPort(port))
+		}
+	} else if host != "" {
+		attrs = append(attrs, semconv.ServerAddress(host))
+	}
+	if request.ContentLength > 0 {
+		attrs = append(attrs, semconv.HTTPRequestBodySize(int(request.ContentLength)))
+	}
+	if userAgent := request.UserAgent(); userAgent != "" {
+		attrs = append(attrs, semconv.UserAgentOriginal(userAgent))
+	}
+	endpoint, ok := goose.EndpointFromContext(request.Context())
+	if !ok {
+		if request.Pattern != "" {
+			return request.Pattern, attrs
+		}
+		return request.Method, attrs
+	}
+	attrs = append(attrs,
+		semconv.HTTPRoute(endpoint.Pattern),
+		semconv.RPCService(endpoint.Service()),
+		semconv.RPCMethod(endpoint.RPCMethod()),
+	)
+	return strings.TrimPrefix(endpoint.FullName, "/"), attrs
+}
@@ -0,0 +1,236 @@
+// Package ratelimit provides a server middleware limiting the rate of requests per client
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-leo/goose"
+	"github.com/go-leo/goose/middleware/basicauth"
+	"github.com/go-leo/goose/server"
+)
+
+// ErrLimitExceeded is matched by errors.Is for the errors returned when a quota is exhausted
+var ErrLimitExceeded = errors.New("ratelimit: too many requests")
+
+// LimitError is encoded when the quota of a request is exhausted,
+// as 429 Too Many Requests with a Retry-After header.
+type LimitError struct {
+	Key        string        // The key of the exhausted quota
+	RetryAfter time.Duration // The time before the quota allows a request again
+}
+
+// Error returns the error message
+func (e *LimitError) Error() string {
+	return ErrLimitExceeded.Error()
+}
+
+// Unwrap returns ErrLimitExceeded
+func (e *LimitError) Unwrap() error {
+	return ErrLimitExceeded
+}
+
+// StatusCode returns 429 Too Many Requests
+func (e *LimitError) StatusCode() int {
+	return http.StatusTooManyRequests
+}
+
+// Headers returns the Retry-After header
+func (e *LimitError) Headers() http.Header {
+	return http.Header{"Retry-After": []string{seconds(max(e.RetryAfter, time.Second))}}
+}
+
+// KeyFunc returns the key a request is rate limited by
+// Parameters:
+//   - request: The incoming HTTP request
+//
+// Returns:
+//   - string: The key, requests with the same key share a quota; empty if the request is not limited
+type KeyFunc func(request *http.Request) string
+
+// ByIP keys requests by the IP of the remote address, the default.
+// Behind a reverse proxy, prefer ByHeader with the header the proxy sets.
+func ByIP(request *http.Request) string {
+	host, _, err := net.SplitHostPort(request.RemoteAddr)
+	if err != nil {
+		return request.RemoteAddr
+	}
+	return host
+}
+
+// ByHeader keys requests by a header set by a trusted reverse proxy, such as X-Real-IP.
+// For X-Forwarded-For, the last address, appended by the proxy, is used.
+// Parameters:
+//   - name: The header name
+//
+// Returns:
+//   - KeyFunc: A key function falling back to ByIP when the header is absent
+func ByHeader(name string) KeyFunc {
+	return func(request *http.Request) string {
+		values := request.Header.Values(name)
+		if len(values) == 0 {
+			return ByIP(request)
+		}
+		value := values[len(values)-1]
+		if i := strings.LastIndexByte(value, ','); i >= 0 {
+			value = value[i+1:]
+		}
+		if value = strings.TrimSpace(value); value == "" {
+			return ByIP(request)
+		}
+		return value
+	}
+}
+
+// ByBasicAuthUser keys requests by the user authenticated by basicauth.Server,
+// requests without user are not limited
+func ByBasicAuthUser(request *http.Request) string {
+	user, _ := basicauth.FromContext(request.Context())
+	return user
+}
+
+// ByContext keys requests by a principal stored in the context by an authentication middleware,
+// such as jwtauth.Subject; requests without principal are not limited
+// Parameters:
+//   - principal: Function retrieving the principal from the context
+//
+// Returns:
+//   - KeyFunc: A key function
+func ByContext(principal func(ctx context.Context) (string, bool)) KeyFunc {
+	return func(request *http.Request) string {
+		key, ok := principal(request.Context())
+		if !ok {
+			return ""
+		}
+		return key
+	}
+}
+
+// options holds configuration options for the rate limit middleware
+type options struct {
+	keyFunc      KeyFunc            // Key of the quota of a request
+	store        Store              // State of the quotas
+	errorEncoder goose.ErrorEncoder // Encoder of the LimitError of rejected requests
+	now          func() time.Time   // Clock, replaced in tests
+}
+
+// apply applies the given options to the options struct
+// Parameters:
+//   - opts: Variable number of Option functions
+//
+// Returns:
+//   - *options: Pointer to the updated options struct
+func (o *options) apply(opts ...Option) *options {
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Option is a function type for configuring the rate limit middleware
+type Option func(o *options)
+
+// defaultOptions returns the default configuration options
+// Returns:
+//   - *options: Default options, requests keyed by client IP in a memory store
+func defaultOptions() *options {
+	return &options{
+		keyFunc:      ByIP,
+		errorEncoder: goose.DefaultEncodeError,
+		now:          time.Now,
+	}
+}
+
+// Key sets the function returning the key a request is rate limited by
+// Parameters:
+//   - keyFunc: Key function, such as ByIP, ByHeader, ByBasicAuthUser or ByContext
+//
+// Returns:
+//   - Option: Function to set the key option
+func Key(keyFunc KeyFunc) Option {
+	return func(o *options) {
+		o.keyFunc = keyFunc
+	}
+}
+
+// WithStore sets the store keeping the state of the quotas
+// Parameters:
+//   - store: Store, such as a store shared between server instances
+//
+// Returns:
+//   - Option: Function to set the store option
+func WithStore(store Store) Option {
+	return func(o *options) {
+		o.store = store
+	}
+}
+
+// ErrorEncoder sets the encoder of the LimitError of rejected requests,
+// usually the error encoder of the server
+// Parameters:
+//   - encoder: Error encoder, goose.DefaultEncodeError by default
+//
+// Returns:
+//   - Option: Function to set the error encoder option
+func ErrorEncoder(encoder goose.ErrorEncoder) Option {
+	return func(o *options) {
+		o.errorEncoder = encoder
+	}
+}
+
+// Server creates a server rate limit middleware
+// Parameters:
+//   - limit: The limit applied to each key, such as PerSecond(10)
+//   - opts: Variable number of Option functions for configuration
+//
+// Returns:
+//   - server.Middleware: Server middleware function
+//
+// Behavior:
+//  1. Keys the request with the key function, requests with an empty key are not limited
+//  2. Sets the RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers
+//  3. Encodes a *LimitError, 429 Too Many Requests with a Retry-After header, when the quota is exhausted
+//  4. Lets the request through, logging the error, when the store fails
+//
+// Placed after an authentication middleware, requests can be keyed by principal.
+func Server(limit Limit, opts ...Option) server.Middleware {
+	opt := defaultOptions().apply(opts...)
+	if opt.store == nil {
+		opt.store = NewMemoryStore()
+	}
+	return func(response http.ResponseWriter, request *http.Request, invoker http.HandlerFunc) {
+		key := opt.keyFunc(request)
+		if key == "" {
+			invoker(response, request)
+			return
+		}
+		ctx := request.Context()
+		result, err := opt.store.Allow(ctx, key, limit, opt.now())
+		if err != nil {
+			slog.ErrorContext(ctx, "ratelimit: store failed", slog.String("key", key), slog.String("error", err.Error()))
+			invoker(response, request)
+			return
+		}
+		header := response.Header()
+		header.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+		header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		header.Set("RateLimit-Reset", seconds(result.Reset))
+		if !result.Allowed {
+			opt.errorEncoder(ctx, &LimitError{Key: key, RetryAfter: result.RetryAfter}, response)
+			return
+		}
+		invoker(response, request)
+	}
+}
+
+// seconds formats a duration in seconds, rounded up
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,168 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-leo/goose"
+	"github.com/go-leo/goose/server"
+)
+
+// fakeClock is a clock moved forward by the tests
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+// withClock replaces the clock of the middleware
+func withClock(clock *fakeClock) Option {
+	return func(o *options) {
+		o.now = clock.Now
+	}
+}
+
+// failingStore always fails
+type failingStore struct{}
+
+func (failingStore) Allow(ctx context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	return Result{}, errors.New("store unavailable")
+}
+
+func serve(middleware server.Middleware, remoteAddr string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodGet, "/v1/user", nil)
+	request.RemoteAddr = remoteAddr
+	rec := httptest.NewRecorder()
+	server.Invoke(middleware, rec, request, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	return rec
+}
+
+func TestServer(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	middleware := Server(Limit{Algorithm: TokenBucket, Requests: 2, Period: time.Minute}, withClock(clock))
+	tests := []struct {
+		name       string
+		advance    time.Duration
+		remoteAddr string
+		code       int
+		remaining  string
+		reset      string
+		retryAfter string
+	}{
+		{name: "first", remoteAddr: "10.0.0.1:1234", code: 200, remaining: "1", reset: "30"},
+		{name: "second", remoteAddr: "10.0.0.1:1235", code: 200, remaining: "0", reset: "60"},
+		{name: "exhausted", remoteAddr: "10.0.0.1:1236", code: 429, remaining: "0", reset: "60", retryAfter: "30"},
+		{name: "other client", remoteAddr: "10.0.0.2:1234", code: 200, remaining: "1", reset: "30"},
+		{name: "partially refilled", advance: 20 * time.Second, remoteAddr: "10.0.0.1:1234", code: 429, remaining: "0", reset: "40", retryAfter: "10"},
+		{name: "refilled", advance: 10 * time.Second, remoteAddr: "10.0.0.1:1234", code: 200, remaining: "0", reset: "60"},
+	}
+	for _, tt := range tests {
+		clock.now = clock.now.Add(tt.advance)
+		rec := serve(middleware, tt.remoteAddr)
+		header := rec.Header()
+		if rec.Code != tt.code {
+			t.Errorf("%s: status = %d, want %d", tt.name, rec.Code, tt.code)
+		}
+		if got := header.Get("RateLimit-Limit"); got != "2" {
+			t.Errorf("%s: RateLimit-Limit = %q, want 2", tt.name, got)
+		}
+		if got := header.Get("RateLimit-Remaining"); got != tt.remaining {
+			t.Errorf("%s: RateLimit-Remaining = %q, want %q", tt.name, got, tt.remaining)
+		}
+		if got := header.Get("RateLimit-Reset"); got != tt.reset {
+			t.Errorf("%s: RateLimit-Reset = %q, want %q", tt.name, got, tt.reset)
+		}
+		if got := header.Get("Retry-After"); got != tt.retryAfter {
+			t.Errorf("%s: Retry-After = %q, want %q", tt.name, got, tt.retryAfter)
+		}
+	}
+}
+
+func TestServerRetryAfterAtLeastOneSecond(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	middleware := Server(Limit{Algorithm: TokenBucket, Requests: 10, Period: time.Second}, withClock(clock))
+	for range 10 {
+		serve(middleware, "10.0.0.1:1234")
+	}
+	rec := serve(middleware, "10.0.0.1:1234")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "1" {
+		t.Errorf("status = %d, Retry-After = %q, want 429 after 1 second", rec.Code, rec.Header().Get("Retry-After"))
+	}
+}
+
+func TestServerErrorEncoder(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	var encoded error
+	encoder := func(ctx context.Context, err error, response http.ResponseWriter) {
+		encoded = err
+		goose.DefaultEncodeError(ctx, err, response)
+	}
+	middleware := Server(Limit{Algorithm: TokenBucket, Requests: 1, Period: time.Minute}, withClock(clock), ErrorEncoder(encoder))
+	serve(middleware, "10.0.0.1:1234")
+	rec := serve(middleware, "10.0.0.1:1234")
+	var limitErr *LimitError
+	if !errors.As(encoded, &limitErr) || !errors.Is(encoded, ErrLimitExceeded) || limitErr.Key != "10.0.0.1" {
+		t.Fatalf("encoded error = %v, want a *LimitError of the client IP", encoded)
+	}
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "60" {
+		t.Errorf("status = %d, Retry-After = %q, want 429 after 60 seconds", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	if got := rec.Body.String(); got != ErrLimitExceeded.Error() {
+		t.Errorf("body = %q, want the error message", got)
+	}
+}
+
+func TestServerNotLimited(t *testing.T) {
+	// requests without key are not limited
+	principal := func(ctx context.Context) (string, bool) { return "", false }
+	middleware := Server(Limit{Algorithm: TokenBucket, Requests: 0, Period: time.Minute}, Key(ByContext(principal)))
+	if rec := serve(middleware, "10.0.0.1:1234"); rec.Code != http.StatusOK || rec.Header().Get("RateLimit-Limit") != "" {
+		t.Errorf("status = %d, want the request without key let through", rec.Code)
+	}
+
+	// requests are let through when the store fails
+	middleware = Server(Limit{Algorithm: TokenBucket, Requests: 0, Period: time.Minute}, WithStore(failingStore{}))
+	if rec := serve(middleware, "10.0.0.1:1234"); rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want the request let through on a store error", rec.Code)
+	}
+}
+
+func TestKeyFuncs(t *testing.T) {
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.RemoteAddr = "10.0.0.1:1234"
+	if got := ByIP(request); got != "10.0.0.1" {
+		t.Errorf("ByIP = %q", got)
+	}
+	request.RemoteAddr = "pipe"
+	if got := ByIP(request); got != "pipe" {
+		t.Errorf("ByIP without port = %q", got)
+	}
+
+	tests := []struct {
+		values []string
+		want   string
+	}{
+		{values: nil, want: "pipe"},
+		{values: []string{"1.1.1.1"}, want: "1.1.1.1"},
+		{values: []string{"1.1.1.1, 2.2.2.2"}, want: "2.2.2.2"},
+		{values: []string{"1.1.1.1", "3.3.3.3 "}, want: "3.3.3.3"},
+		{values: []string{"1.1.1.1,"}, want: "pipe"},
+	}
+	for _, tt := range tests {
+		request.Header.Del("X-Forwarded-For")
+		for _, value := range tt.values {
+			request.Header.Add("X-Forwarded-For", value)
+		}
+		if got := ByHeader("X-Forwarded-For")(request); got != tt.want {
+			t.Errorf("ByHeader(%q) = %q, want %q", tt.values, got, tt.want)
+		}
+	}
+}
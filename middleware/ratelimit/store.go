@@ -0,0 +1,194 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Algorithm is a rate limiting algorithm
+type Algorithm int
+
+const (
+	// TokenBucket lets bursts of up to Limit.Requests requests through,
+	// tokens are refilled continuously at Limit.Requests per Limit.Period
+	TokenBucket Algorithm = iota
+	// SlidingWindow lets Limit.Requests requests through per sliding Limit.Period,
+	// approximated by weighting the count of the previous fixed window
+	SlidingWindow
+)
+
+// Limit describes a rate limit
+type Limit struct {
+	Algorithm Algorithm     // The rate limiting algorithm
+	Requests  int           // The number of requests allowed per period, the burst of a token bucket
+	Period    time.Duration // The period, the time to refill a token bucket
+}
+
+// PerSecond returns a token bucket limit of n requests per second
+func PerSecond(n int) Limit {
+	return Limit{Algorithm: TokenBucket, Requests: n, Period: time.Second}
+}
+
+// PerMinute returns a token bucket limit of n requests per minute
+func PerMinute(n int) Limit {
+	return Limit{Algorithm: TokenBucket, Requests: n, Period: time.Minute}
+}
+
+// Result is the outcome of a rate limit check
+type Result struct {
+	Allowed    bool          // Whether the request is allowed
+	Limit      int           // The number of requests allowed per period
+	Remaining  int           // The number of requests still allowed now
+	Reset      time.Duration // The time until the quota is fully restored
+	RetryAfter time.Duration // The time until a request is allowed again, when not allowed
+}
+
+// Store applies limits to keys and keeps their state.
+// NewMemoryStore keeps the state in memory; implement Store over an external
+// database, such as Redis, to share limits between server instances.
+type Store interface {
+	// Allow checks and records a request of key
+	// Parameters:
+	//   - ctx: Context of the request
+	//   - key: The rate limited key, such as a client IP
+	//   - limit: The limit applied to the key
+	//   - now: The time of the request
+	//
+	// Returns:
+	//   - Result: Whether the request is allowed, and the remaining quota
+	//   - error: If the state of the key cannot be read or written
+	Allow(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+}
+
+// memoryEntry is the state of a key in the memory store
+type memoryEntry struct {
+	// token bucket
+	tokens float64   // Available tokens
+	last   time.Time // Time of the last refill
+	// sliding window
+	windowStart time.Time // Start of the current fixed window
+	current     int       // Requests of the current fixed window
+	previous    int       // Requests of the previous fixed window
+
+	expiresAt time.Time // When the entry is back to its initial state and can be dropped
+}
+
+// MemoryStore is a Store keeping the state of the keys in memory.
+// Entries of idle keys are dropped once their quota is fully restored.
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]*memoryEntry
+	calls   int // Calls since the last sweep of expired entries
+}
+
+// NewMemoryStore creates an in-memory Store
+// Returns:
+//   - *MemoryStore: An empty memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: map[string]*memoryEntry{}}
+}
+
+// Allow checks and records a request of key, see Store
+func (s *MemoryStore) Allow(ctx context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(now)
+	entry, ok := s.entries[key]
+	if !ok {
+		entry = &memoryEntry{tokens: float64(limit.Requests), last: now, windowStart: now}
+		s.entries[key] = entry
+	}
+	if limit.Algorithm == SlidingWindow {
+		return entry.slidingWindow(limit, now), nil
+	}
+	return entry.tokenBucket(limit, now), nil
+}
+
+// sweep drops the expired entries, every 1024 calls
+func (s *MemoryStore) sweep(now time.Time) {
+	s.calls++
+	if s.calls < 1024 {
+		return
+	}
+	s.calls = 0
+	for key, entry := range s.entries {
+		if now.After(entry.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+}
+
+// tokenBucket refills the bucket and takes a token if available
+func (e *memoryEntry) tokenBucket(limit Limit, now time.Time) Result {
+	burst := float64(limit.Requests)
+	perToken := limit.Period / time.Duration(max(limit.Requests, 1))
+	if elapsed := now.Sub(e.last); elapsed > 0 && perToken > 0 {
+		e.tokens = min(burst, e.tokens+float64(elapsed)/float64(perToken))
+	}
+	e.last = now
+	result := Result{Limit: limit.Requests}
+	if e.tokens >= 1 {
+		e.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - e.tokens) * float64(perToken))
+	}
+	result.Remaining = int(e.tokens)
+	result.Reset = time.Duration((burst - e.tokens) * float64(perToken))
+	e.expiresAt = now.Add(result.Reset)
+	return result
+}
+
+// slidingWindow counts the request if the weighted count of the window allows it
+func (e *memoryEntry) slidingWindow(limit Limit, now time.Time) Result {
+	window := limit.Period
+	if window <= 0 {
+		window = time.Second
+	}
+	// move the fixed windows forward
+	if elapsed := now.Sub(e.windowStart); elapsed >= window {
+		windows := elapsed / window
+		if windows == 1 {
+			e.previous = e.current
+		} else {
+			e.previous = 0
+		}
+		e.current = 0
+		e.windowStart = e.windowStart.Add(windows * window)
+	}
+	elapsed := now.Sub(e.windowStart)
+	weight := 1 - float64(elapsed)/float64(window)
+	count := float64(e.previous)*weight + float64(e.current)
+	result := Result{Limit: limit.Requests, Reset: window - elapsed}
+	if count+1 <= float64(limit.Requests) {
+		e.current++
+		count++
+		result.Allowed = true
+	} else {
+		result.RetryAfter = e.retryAfter(limit.Requests, window, elapsed)
+	}
+	result.Remaining = max(limit.Requests-int(math.Ceil(count)), 0)
+	if e.current > 0 {
+		// the count of the current window weighs until the end of the next one
+		result.Reset += window
+	}
+	e.expiresAt = now.Add(result.Reset)
+	return result
+}
+
+// retryAfter returns the time until the weighted count drops enough to let a request through
+func (e *memoryEntry) retryAfter(limit int, window time.Duration, elapsed time.Duration) time.Duration {
+	if limit <= 0 {
+		return window - elapsed
+	}
+	if e.current+1 <= limit && e.previous > 0 {
+		// previous*(1-(elapsed+t)/window) + current + 1 <= limit
+		excess := float64(e.previous)*(1-float64(elapsed)/float64(window)) + float64(e.current+1-limit)
+		return time.Duration(math.Ceil(excess * float64(window) / float64(e.previous)))
+	}
+	// in the next window: current*(1-t/window) + 1 <= limit
+	t := time.Duration(math.Ceil(float64(window) * (1 - float64(limit-1)/float64(e.current))))
+	return window - elapsed + max(t, 0)
+}
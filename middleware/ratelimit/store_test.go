@@ -0,0 +1,118 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// step is a request of a key at an offset of the fake clock, with the expected result
+type step struct {
+	at   time.Duration
+	want Result
+}
+
+func runSteps(t *testing.T, limit Limit, steps []step) {
+	t.Helper()
+	store := NewMemoryStore()
+	start := time.Unix(1000, 0)
+	for i, s := range steps {
+		got, err := store.Allow(context.Background(), "key", limit, start.Add(s.at))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != s.want {
+			t.Errorf("step %d at %v: got %+v, want %+v", i, s.at, got, s.want)
+		}
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	limit := Limit{Algorithm: TokenBucket, Requests: 2, Period: time.Second}
+	runSteps(t, limit, []step{
+		// the burst is available right away
+		{at: 0, want: Result{Allowed: true, Limit: 2, Remaining: 1, Reset: 500 * time.Millisecond}},
+		{at: 0, want: Result{Allowed: true, Limit: 2, Remaining: 0, Reset: time.Second}},
+		{at: 0, want: Result{Allowed: false, Limit: 2, Remaining: 0, Reset: time.Second, RetryAfter: 500 * time.Millisecond}},
+		// half a token is refilled
+		{at: 250 * time.Millisecond, want: Result{Allowed: false, Limit: 2, Remaining: 0, Reset: 750 * time.Millisecond, RetryAfter: 250 * time.Millisecond}},
+		// one token is refilled every 500ms
+		{at: 500 * time.Millisecond, want: Result{Allowed: true, Limit: 2, Remaining: 0, Reset: time.Second}},
+		// the bucket never holds more than the burst
+		{at: 10 * time.Second, want: Result{Allowed: true, Limit: 2, Remaining: 1, Reset: 500 * time.Millisecond}},
+	})
+}
+
+func TestTokenBucketPerMinute(t *testing.T) {
+	runSteps(t, PerMinute(60), []step{
+		{at: 0, want: Result{Allowed: true, Limit: 60, Remaining: 59, Reset: time.Second}},
+		{at: 1500 * time.Millisecond, want: Result{Allowed: true, Limit: 60, Remaining: 59, Reset: time.Second}},
+	})
+}
+
+func TestTokenBucketZero(t *testing.T) {
+	runSteps(t, Limit{Algorithm: TokenBucket, Requests: 0, Period: time.Second}, []step{
+		{at: 0, want: Result{Allowed: false, Limit: 0, Remaining: 0, Reset: 0, RetryAfter: time.Second}},
+	})
+}
+
+func TestSlidingWindow(t *testing.T) {
+	limit := Limit{Algorithm: SlidingWindow, Requests: 4, Period: 10 * time.Second}
+	runSteps(t, limit, []step{
+		{at: 0, want: Result{Allowed: true, Limit: 4, Remaining: 3, Reset: 20 * time.Second}},
+		{at: 0, want: Result{Allowed: true, Limit: 4, Remaining: 2, Reset: 20 * time.Second}},
+		{at: 0, want: Result{Allowed: true, Limit: 4, Remaining: 1, Reset: 20 * time.Second}},
+		{at: 0, want: Result{Allowed: true, Limit: 4, Remaining: 0, Reset: 20 * time.Second}},
+		// the 4 requests weigh 3 a quarter into the next window
+		{at: 0, want: Result{Allowed: false, Limit: 4, Remaining: 0, Reset: 20 * time.Second, RetryAfter: 12500 * time.Millisecond}},
+		{at: 5 * time.Second, want: Result{Allowed: false, Limit: 4, Remaining: 0, Reset: 15 * time.Second, RetryAfter: 7500 * time.Millisecond}},
+		{at: 12500 * time.Millisecond, want: Result{Allowed: true, Limit: 4, Remaining: 0, Reset: 17500 * time.Millisecond}},
+		// previous*(1-elapsed/window) + current + 1 <= limit, half into the window
+		{at: 12500 * time.Millisecond, want: Result{Allowed: false, Limit: 4, Remaining: 0, Reset: 17500 * time.Millisecond, RetryAfter: 2500 * time.Millisecond}},
+		{at: 15 * time.Second, want: Result{Allowed: true, Limit: 4, Remaining: 0, Reset: 15 * time.Second}},
+		// two windows later, nothing weighs anymore
+		{at: 35 * time.Second, want: Result{Allowed: true, Limit: 4, Remaining: 3, Reset: 15 * time.Second}},
+	})
+}
+
+func TestMemoryStoreKeys(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Unix(1000, 0)
+	limit := Limit{Algorithm: TokenBucket, Requests: 1, Period: time.Minute}
+	for _, key := range []string{"a", "b"} {
+		if result, _ := store.Allow(context.Background(), key, limit, now); !result.Allowed {
+			t.Errorf("key %s: first request denied", key)
+		}
+	}
+	if result, _ := store.Allow(context.Background(), "a", limit, now); result.Allowed {
+		t.Error("key a: second request allowed")
+	}
+}
+
+func TestMemoryStoreExpiry(t *testing.T) {
+	store := NewMemoryStore()
+	start := time.Unix(1000, 0)
+	limit := Limit{Algorithm: TokenBucket, Requests: 1, Period: time.Second}
+	_, _ = store.Allow(context.Background(), "idle", limit, start)
+	if got := store.entries["idle"].expiresAt; got != start.Add(time.Second) {
+		t.Fatalf("expiresAt = %v, want when the bucket is full again", got)
+	}
+	// the sweep runs every 1024 calls
+	for range 1022 {
+		_, _ = store.Allow(context.Background(), "active", limit, start.Add(2*time.Second))
+	}
+	if _, ok := store.entries["idle"]; !ok {
+		t.Fatal("the idle key was dropped before the sweep")
+	}
+	_, _ = store.Allow(context.Background(), "active", limit, start.Add(2*time.Second))
+	if _, ok := store.entries["idle"]; ok {
+		t.Error("the idle key was not dropped by the sweep")
+	}
+	if _, ok := store.entries["active"]; !ok {
+		t.Error("the active key was dropped")
+	}
+	// a dropped key starts again with a full quota
+	if result, _ := store.Allow(context.Background(), "idle", limit, start.Add(2*time.Second)); !result.Allowed {
+		t.Error("the idle key did not start with a full quota")
+	}
+}
@@ -0,0 +1,310 @@
+// Package retry provides a client middleware retrying failed requests with exponential backoff
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/go-leo/goose/client"
+)
+
+// BackoffFunc returns the delay before a retry
+// Parameters:
+//   - attempt: The number of the attempt that failed, starting at 1
+//
+// Returns:
+//   - time.Duration: The delay before the next attempt
+type BackoffFunc func(attempt int) time.Duration
+
+// ExponentialBackoff creates a backoff doubling the delay after each attempt, with full jitter
+// Parameters:
+//   - base: The delay ceiling after the first attempt
+//   - maxDelay: The maximum delay ceiling
+//
+// Returns:
+//   - BackoffFunc: A backoff returning a random delay in [0, min(maxDelay, base*2^(attempt-1))]
+func ExponentialBackoff(base time.Duration, maxDelay time.Duration) BackoffFunc {
+	return func(attempt int) time.Duration {
+		ceiling := maxDelay
+		if shift := attempt - 1; shift < 62 && base<<shift > 0 && base<<shift < maxDelay {
+			ceiling = base << shift
+		}
+		if ceiling <= 0 {
+			return 0
+		}
+		return rand.N(ceiling + 1)
+	}
+}
+
+// options holds configuration options for the retry middleware
+type options struct {
+	maxAttempts   int                  // Maximum number of attempts, including the first one
+	backoff       BackoffFunc          // Delay between attempts
+	statusCodes   []int                // Response status codes to retry
+	errorFilter   func(err error) bool // Reports whether a transport error is retryable
+	methods       []string             // HTTP methods to retry
+	maxRetryAfter time.Duration        // Maximum honored Retry-After delay
+}
+
+// apply applies the given options to the options struct
+// Parameters:
+//   - opts: Variable number of Option functions
+//
+// Returns:
+//   - *options: Pointer to the updated options struct
+func (o *options) apply(opts ...Option) *options {
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Option is a function type for configuring the retry middleware
+type Option func(o *options)
+
+// defaultOptions returns the default configuration options
+// Returns:
+//   - *options: Default options, 3 attempts of idempotent requests failing with
+//     a transport error or a 429, 502, 503 or 504 status, exponential backoff from 100ms to 10s
+func defaultOptions() *options {
+	return &options{
+		maxAttempts: 3,
+		backoff:     ExponentialBackoff(100*time.Millisecond, 10*time.Second),
+		statusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		errorFilter: IsRetryableError,
+		methods: []string{
+			http.MethodGet,
+			http.MethodHead,
+			http.MethodOptions,
+			http.MethodTrace,
+			http.MethodPut,
+			http.MethodDelete,
+		},
+		maxRetryAfter: time.Minute,
+	}
+}
+
+// MaxAttempts sets the maximum number of attempts, including the first one
+// Parameters:
+//   - n: Maximum number of attempts, 1 disables retries
+//
+// Returns:
+//   - Option: Function to set the max attempts option
+func MaxAttempts(n int) Option {
+	return func(o *options) {
+		o.maxAttempts = n
+	}
+}
+
+// Backoff sets the delay between attempts
+// Parameters:
+//   - backoff: Backoff function, such as ExponentialBackoff
+//
+// Returns:
+//   - Option: Function to set the backoff option
+func Backoff(backoff BackoffFunc) Option {
+	return func(o *options) {
+		o.backoff = backoff
+	}
+}
+
+// StatusCodes sets the response status codes to retry, replacing the defaults
+// Parameters:
+//   - codes: Retryable status codes
+//
+// Returns:
+//   - Option: Function to set the status codes option
+func StatusCodes(codes ...int) Option {
+	return func(o *options) {
+		o.statusCodes = codes
+	}
+}
+
+// ErrorFilter sets the function reporting whether a transport error is retryable
+// Parameters:
+//   - filter: Function returning true for retryable errors
+//
+// Returns:
+//   - Option: Function to set the error filter option
+func ErrorFilter(filter func(err error) bool) Option {
+	return func(o *options) {
+		o.errorFilter = filter
+	}
+}
+
+// Methods sets the HTTP methods to retry, replacing the idempotent methods retried by default.
+// Requests with an Idempotency-Key or X-Idempotency-Key header are retried whatever their method.
+// Parameters:
+//   - methods: Retryable HTTP methods
+//
+// Returns:
+//   - Option: Function to set the methods option
+func Methods(methods ...string) Option {
+	return func(o *options) {
+		o.methods = methods
+	}
+}
+
+// MaxRetryAfter sets the maximum honored Retry-After delay, responses asking for longer are not retried
+// Parameters:
+//   - d: Maximum Retry-After delay
+//
+// Returns:
+//   - Option: Function to set the max Retry-After option
+func MaxRetryAfter(d time.Duration) Option {
+	return func(o *options) {
+		o.maxRetryAfter = d
+	}
+}
+
+// IsRetryableError reports whether a transport error is retryable, that is any error but a canceled or expired context
+// Parameters:
+//   - err: The transport error
+//
+// Returns:
+//   - bool: True if the request can be retried
+func IsRetryableError(err error) bool {
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// Client creates a client retry middleware
+// Parameters:
+//   - opts: Variable number of Option functions for configuration
+//
+// Returns:
+//   - client.Middleware: Client middleware function
+//
+// Behavior:
+//  1. Sends requests with a non retryable method, or a body that cannot be replayed with GetBody, once
+//  2. Retries transport errors accepted by the error filter and responses with a retryable status code
+//  3. Waits for the Retry-After delay of the response if any, the backoff delay otherwise
+//  4. Gives up, returning the last response or error, when the attempts are exhausted
+//     or when the delay would exceed the context deadline
+//
+// Placed after timeout.Client, the timeout bounds all the attempts;
+// placed before, each attempt has its own timeout.
+func Client(opts ...Option) client.Middleware {
+	opt := defaultOptions().apply(opts...)
+	return func(cli *http.Client, request *http.Request, invoker client.Invoker) (*http.Response, error) {
+		if !opt.retryableRequest(request) {
+			return invoker(cli, request)
+		}
+		ctx := request.Context()
+		for attempt := 1; ; attempt++ {
+			req := request
+			if attempt > 1 {
+				var err error
+				if req, err = replay(request); err != nil {
+					return nil, err
+				}
+			}
+			response, err := invoker(cli, req)
+			if attempt >= opt.maxAttempts || !opt.retryableResult(ctx, response, err) {
+				return response, err
+			}
+			delay := opt.backoff(attempt)
+			if retryAfter, ok := parseRetryAfter(response); ok {
+				if retryAfter > opt.maxRetryAfter {
+					return response, err
+				}
+				delay = retryAfter
+			}
+			if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= delay {
+				return response, err
+			}
+			discard(response)
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, ctx.Err()
+			case <-timer.C:
+			}
+		}
+	}
+}
+
+// retryableRequest reports whether the request may be sent again
+func (o *options) retryableRequest(request *http.Request) bool {
+	if o.maxAttempts <= 1 {
+		return false
+	}
+	if request.Body != nil && request.Body != http.NoBody && request.GetBody == nil {
+		return false
+	}
+	if request.Header.Get("Idempotency-Key") != "" || request.Header.Get("X-Idempotency-Key") != "" {
+		return true
+	}
+	method := request.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	return slices.Contains(o.methods, method)
+}
+
+// retryableResult reports whether the response or the error of an attempt calls for a retry
+func (o *options) retryableResult(ctx context.Context, response *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return o.errorFilter != nil && o.errorFilter(err)
+	}
+	return response != nil && slices.Contains(o.statusCodes, response.StatusCode)
+}
+
+// replay returns a copy of the request with a fresh body
+func replay(request *http.Request) (*http.Request, error) {
+	req := request.Clone(request.Context())
+	if request.GetBody != nil {
+		body, err := request.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = body
+	}
+	return req, nil
+}
+
+// parseRetryAfter returns the delay of the Retry-After header, in seconds or as an HTTP date
+func parseRetryAfter(response *http.Response) (time.Duration, bool) {
+	if response == nil {
+		return 0, false
+	}
+	value := response.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		// bound the delay to avoid overflows, it is then compared with MaxRetryAfter
+		return time.Duration(min(seconds, 1<<32)) * time.Second, true
+	}
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	return max(time.Until(date), 0), true
+}
+
+// discard drains and closes the body of a response that is not returned, so that its connection can be reused
+func discard(response *http.Response) {
+	if response == nil || response.Body == nil {
+		return
+	}
+	_, _ = io.CopyN(io.Discard, response.Body, 4<<10)
+	_ = response.Body.Close()
+}
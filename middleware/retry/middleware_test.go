@@ -0,0 +1,336 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// noBackoff retries right away
+func noBackoff(int) time.Duration { return 0 }
+
+// trackedBody records whether a response body was closed
+type trackedBody struct {
+	io.Reader
+	closed bool
+}
+
+func (b *trackedBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+// result is the outcome of an attempt of the fake invoker
+type result struct {
+	status int
+	header http.Header
+	err    error
+}
+
+// fakeInvoker replays the results in order, repeating the last one, and records the attempts
+type fakeInvoker struct {
+	results  []result
+	bodies   []string       // Request bodies of the attempts
+	received []*trackedBody // Response bodies returned
+}
+
+func (f *fakeInvoker) invoke(cli *http.Client, request *http.Request) (*http.Response, error) {
+	var body string
+	if request.Body != nil {
+		data, _ := io.ReadAll(request.Body)
+		body = string(data)
+	}
+	f.bodies = append(f.bodies, body)
+	r := f.results[min(len(f.bodies), len(f.results))-1]
+	if r.err != nil {
+		return nil, r.err
+	}
+	header := r.header
+	if header == nil {
+		header = http.Header{}
+	}
+	responseBody := &trackedBody{Reader: strings.NewReader("body")}
+	f.received = append(f.received, responseBody)
+	return &http.Response{StatusCode: r.status, Header: header, Body: responseBody}, nil
+}
+
+func (f *fakeInvoker) attempts() int {
+	return len(f.bodies)
+}
+
+func newRequest(t *testing.T, method string, body string) *http.Request {
+	t.Helper()
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	request, err := http.NewRequest(method, "http://localhost/v1/user", reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return request
+}
+
+func TestRetryStatusCodes(t *testing.T) {
+	invoker := &fakeInvoker{results: []result{{status: 503}, {status: 502}, {status: 200}}}
+	response, err := Client(Backoff(noBackoff))(http.DefaultClient, newRequest(t, http.MethodGet, ""), invoker.invoke)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.StatusCode != http.StatusOK || invoker.attempts() != 3 {
+		t.Fatalf("status = %d after %d attempts, want 200 after 3", response.StatusCode, invoker.attempts())
+	}
+	for i, body := range invoker.received[:2] {
+		if !body.closed {
+			t.Errorf("the body of the failed attempt %d was not closed", i+1)
+		}
+	}
+	if invoker.received[2].closed {
+		t.Error("the body of the returned response was closed")
+	}
+}
+
+func TestRetryExhausted(t *testing.T) {
+	invoker := &fakeInvoker{results: []result{{status: 503}}}
+	response, err := Client(Backoff(noBackoff), MaxAttempts(4))(http.DefaultClient, newRequest(t, http.MethodGet, ""), invoker.invoke)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.StatusCode != http.StatusServiceUnavailable || invoker.attempts() != 4 {
+		t.Fatalf("status = %d after %d attempts, want the last 503 after 4", response.StatusCode, invoker.attempts())
+	}
+}
+
+func TestRetryNotRetryableStatus(t *testing.T) {
+	invoker := &fakeInvoker{results: []result{{status: 500}, {status: 200}}}
+	response, _ := Client(Backoff(noBackoff))(http.DefaultClient, newRequest(t, http.MethodGet, ""), invoker.invoke)
+	if response.StatusCode != http.StatusInternalServerError || invoker.attempts() != 1 {
+		t.Fatalf("status = %d after %d attempts, want 500 after 1", response.StatusCode, invoker.attempts())
+	}
+
+	invoker = &fakeInvoker{results: []result{{status: 500}, {status: 200}}}
+	response, _ = Client(Backoff(noBackoff), StatusCodes(500))(http.DefaultClient, newRequest(t, http.MethodGet, ""), invoker.invoke)
+	if response.StatusCode != http.StatusOK || invoker.attempts() != 2 {
+		t.Fatalf("status = %d after %d attempts, want 200 after 2", response.StatusCode, invoker.attempts())
+	}
+}
+
+func TestRetryErrors(t *testing.T) {
+	invoker := &fakeInvoker{results: []result{{err: errors.New("connection reset")}, {status: 200}}}
+	response, err := Client(Backoff(noBackoff))(http.DefaultClient, newRequest(t, http.MethodGet, ""), invoker.invoke)
+	if err != nil || response.StatusCode != http.StatusOK || invoker.attempts() != 2 {
+		t.Fatalf("err = %v after %d attempts, want 200 after 2", err, invoker.attempts())
+	}
+
+	invoker = &fakeInvoker{results: []result{{err: context.DeadlineExceeded}, {status: 200}}}
+	if _, err := Client(Backoff(noBackoff))(http.DefaultClient, newRequest(t, http.MethodGet, ""), invoker.invoke); !errors.Is(err, context.DeadlineExceeded) || invoker.attempts() != 1 {
+		t.Fatalf("err = %v after %d attempts, want the deadline error after 1", err, invoker.attempts())
+	}
+
+	invoker = &fakeInvoker{results: []result{{err: errors.New("fatal")}, {status: 200}}}
+	filter := ErrorFilter(func(err error) bool { return false })
+	if _, err := Client(Backoff(noBackoff), filter)(http.DefaultClient, newRequest(t, http.MethodGet, ""), invoker.invoke); err == nil || invoker.attempts() != 1 {
+		t.Fatalf("err = %v after %d attempts, want the error after 1", err, invoker.attempts())
+	}
+}
+
+func TestRetryMethods(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		header string
+		opts   []Option
+		want   int
+	}{
+		{name: "GET", method: http.MethodGet, want: 2},
+		{name: "PUT", method: http.MethodPut, want: 2},
+		{name: "DELETE", method: http.MethodDelete, want: 2},
+		{name: "POST", method: http.MethodPost, want: 1},
+		{name: "PATCH", method: http.MethodPatch, want: 1},
+		{name: "POST with Idempotency-Key", method: http.MethodPost, header: "Idempotency-Key", want: 2},
+		{name: "PATCH with X-Idempotency-Key", method: http.MethodPatch, header: "X-Idempotency-Key", want: 2},
+		{name: "POST allowed", method: http.MethodPost, opts: []Option{Methods(http.MethodPost)}, want: 2},
+		{name: "GET not allowed", method: http.MethodGet, opts: []Option{Methods(http.MethodPost)}, want: 1},
+		{name: "single attempt", method: http.MethodGet, opts: []Option{MaxAttempts(1)}, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := newRequest(t, tt.method, "")
+			if tt.header != "" {
+				request.Header.Set(tt.header, "key")
+			}
+			invoker := &fakeInvoker{results: []result{{status: 503}, {status: 200}}}
+			_, _ = Client(append([]Option{Backoff(noBackoff)}, tt.opts...)...)(http.DefaultClient, request, invoker.invoke)
+			if invoker.attempts() != tt.want {
+				t.Errorf("%d attempts, want %d", invoker.attempts(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryGetBody(t *testing.T) {
+	request := newRequest(t, http.MethodPut, `{"name":"bob"}`)
+	invoker := &fakeInvoker{results: []result{{status: 503}, {status: 503}, {status: 200}}}
+	if _, err := Client(Backoff(noBackoff))(http.DefaultClient, request, invoker.invoke); err != nil {
+		t.Fatal(err)
+	}
+	if invoker.attempts() != 3 {
+		t.Fatalf("%d attempts, want 3", invoker.attempts())
+	}
+	for i, body := range invoker.bodies {
+		if body != `{"name":"bob"}` {
+			t.Errorf("attempt %d sent %q, want the replayed body", i+1, body)
+		}
+	}
+
+	// a body that cannot be replayed is sent once
+	request = newRequest(t, http.MethodPut, "")
+	request.Body = io.NopCloser(strings.NewReader("stream"))
+	invoker = &fakeInvoker{results: []result{{status: 503}, {status: 200}}}
+	_, _ = Client(Backoff(noBackoff))(http.DefaultClient, request, invoker.invoke)
+	if invoker.attempts() != 1 {
+		t.Fatalf("%d attempts, want 1 without GetBody", invoker.attempts())
+	}
+
+	// a failing GetBody stops the retries
+	request = newRequest(t, http.MethodPut, "data")
+	request.GetBody = func() (io.ReadCloser, error) { return nil, errors.New("no body") }
+	invoker = &fakeInvoker{results: []result{{status: 503}, {status: 200}}}
+	if _, err := Client(Backoff(noBackoff))(http.DefaultClient, request, invoker.invoke); err == nil {
+		t.Fatal("expected the GetBody error")
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{name: "absent", value: "", ok: false},
+		{name: "seconds", value: "2", want: 2 * time.Second, ok: true},
+		{name: "zero", value: "0", want: 0, ok: true},
+		{name: "negative", value: "-1", ok: false},
+		{name: "invalid", value: "soon", ok: false},
+		{name: "huge", value: "99999999999", want: (1 << 32) * time.Second, ok: true},
+		{name: "past date", value: time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), want: 0, ok: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := &http.Response{Header: http.Header{}}
+			if tt.value != "" {
+				response.Header.Set("Retry-After", tt.value)
+			}
+			got, ok := parseRetryAfter(response)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("parseRetryAfter(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+
+	response := &http.Response{Header: http.Header{}}
+	response.Header.Set("Retry-After", time.Now().Add(30*time.Second).UTC().Format(http.TimeFormat))
+	got, ok := parseRetryAfter(response)
+	if !ok || got <= 28*time.Second || got > 30*time.Second {
+		t.Errorf("parseRetryAfter(date in 30s) = %v, %v", got, ok)
+	}
+	if _, ok := parseRetryAfter(nil); ok {
+		t.Error("parseRetryAfter(nil) must not report a delay")
+	}
+}
+
+func TestRetryAfterOverridesBackoff(t *testing.T) {
+	slow := Backoff(func(int) time.Duration { return time.Hour })
+	for _, value := range []string{"0", time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat)} {
+		invoker := &fakeInvoker{results: []result{{status: 429, header: http.Header{"Retry-After": {value}}}, {status: 200}}}
+		request := newRequest(t, http.MethodGet, "")
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_, _ = Client(slow)(http.DefaultClient, request, invoker.invoke)
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Retry-After %q did not replace the backoff delay", value)
+		}
+		if invoker.attempts() != 2 {
+			t.Errorf("Retry-After %q: %d attempts, want 2", value, invoker.attempts())
+		}
+	}
+}
+
+func TestMaxRetryAfter(t *testing.T) {
+	invoker := &fakeInvoker{results: []result{{status: 503, header: http.Header{"Retry-After": {"120"}}}, {status: 200}}}
+	response, err := Client(Backoff(noBackoff))(http.DefaultClient, newRequest(t, http.MethodGet, ""), invoker.invoke)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.StatusCode != http.StatusServiceUnavailable || invoker.attempts() != 1 {
+		t.Fatalf("status = %d after %d attempts, want to give up on a Retry-After above MaxRetryAfter", response.StatusCode, invoker.attempts())
+	}
+	if invoker.received[0].closed {
+		t.Error("the body of the returned response was closed")
+	}
+
+	invoker = &fakeInvoker{results: []result{{status: 503, header: http.Header{"Retry-After": {"0"}}}, {status: 200}}}
+	response, _ = Client(Backoff(noBackoff), MaxRetryAfter(0))(http.DefaultClient, newRequest(t, http.MethodGet, ""), invoker.invoke)
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want a retry when Retry-After equals MaxRetryAfter", response.StatusCode)
+	}
+}
+
+func TestRetryDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	request := newRequest(t, http.MethodGet, "").WithContext(ctx)
+	invoker := &fakeInvoker{results: []result{{status: 503}, {status: 200}}}
+	start := time.Now()
+	response, err := Client(Backoff(func(int) time.Duration { return time.Second }))(http.DefaultClient, request, invoker.invoke)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.StatusCode != http.StatusServiceUnavailable || invoker.attempts() != 1 {
+		t.Fatalf("status = %d after %d attempts, want the 503 when the delay exceeds the deadline", response.StatusCode, invoker.attempts())
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("gave up after %v, want right away", elapsed)
+	}
+}
+
+func TestRetryCanceledWhileWaiting(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	request := newRequest(t, http.MethodGet, "").WithContext(ctx)
+	invoker := &fakeInvoker{results: []result{{status: 503}, {status: 200}}}
+	time.AfterFunc(50*time.Millisecond, cancel)
+	_, err := Client(Backoff(func(int) time.Duration { return time.Hour }))(http.DefaultClient, request, invoker.invoke)
+	if !errors.Is(err, context.Canceled) || invoker.attempts() != 1 {
+		t.Fatalf("err = %v after %d attempts, want the cancellation after 1", err, invoker.attempts())
+	}
+	if !invoker.received[0].closed {
+		t.Error("the body of the abandoned response was not closed")
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(100*time.Millisecond, time.Second)
+	for attempt := 1; attempt <= 100; attempt++ {
+		ceiling := time.Second
+		if attempt <= 4 {
+			ceiling = 100 * time.Millisecond << (attempt - 1)
+		}
+		for range 20 {
+			if delay := backoff(attempt); delay < 0 || delay > ceiling {
+				t.Fatalf("attempt %d: delay %v outside [0, %v]", attempt, delay, ceiling)
+			}
+		}
+	}
+	if delay := ExponentialBackoff(0, 0)(1); delay != 0 {
+		t.Errorf("delay = %v, want 0 without a base", delay)
+	}
+}
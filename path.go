@@ -38,3 +38,22 @@ func URLPath(path string, pairs map[string]string) string {
 	}
 	return strings.Join(sections, "/")
 }
+
+// CleanPathPrefix normalizes a path prefix to a leading slash and no trailing slash,
+// so that it can be put in front of the paths of the routes.
+//
+// Parameters:
+//   - prefix: The prefix, such as "api/internal/" or "/v2"
+//
+// Returns:
+//   - string: The normalized prefix, such as "/api/internal", or "" for an empty prefix or "/"
+//
+// Example:
+//   CleanPathPrefix("api/internal/") returns "/api/internal"
+func CleanPathPrefix(prefix string) string {
+	prefix = strings.Trim(prefix, "/")
+	if prefix == "" {
+		return ""
+	}
+	return "/" + prefix
+}
@@ -0,0 +1,175 @@
+package goose
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+)
+
+// ErrNoResponseMetadata is returned by SetStatus, SetHeader, AddHeader and SetTrailer
+// when the context was not created by a goose server handler.
+var ErrNoResponseMetadata = errors.New("goose: no response metadata in context")
+
+// ResponseMetadata collects the status code, headers and trailers that a service
+// sets on its response. The server encoders apply them when writing the response.
+// It is safe for concurrent use.
+type ResponseMetadata struct {
+	mu      sync.Mutex
+	status  int         // HTTP status code, 0 means the encoder default
+	header  http.Header // HTTP headers to send with the response
+	trailer http.Header // HTTP trailers to send after the response body
+}
+
+// Status returns the status code set by the service, 0 if none was set.
+func (m *ResponseMetadata) Status() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.status
+}
+
+// Header returns a copy of the headers set by the service.
+func (m *ResponseMetadata) Header() http.Header {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.header.Clone()
+}
+
+// Trailer returns a copy of the trailers set by the service.
+func (m *ResponseMetadata) Trailer() http.Header {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.trailer.Clone()
+}
+
+// responseMetadataKey is the context key of the response metadata
+type responseMetadataKey struct{}
+
+// NewResponseMetadataContext returns a new context carrying an empty ResponseMetadata.
+// It is called by server.Invoke, services only need it in tests.
+//
+// Parameters:
+//   - ctx: The parent context
+//
+// Returns:
+//   - context.Context: The context carrying the response metadata
+func NewResponseMetadataContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, responseMetadataKey{}, &ResponseMetadata{
+		header:  http.Header{},
+		trailer: http.Header{},
+	})
+}
+
+// ResponseMetadataFromContext returns the ResponseMetadata carried by ctx.
+//
+// Parameters:
+//   - ctx: The context that may carry response metadata
+//
+// Returns:
+//   - *ResponseMetadata: The response metadata
+//   - bool: True if the context carries response metadata
+func ResponseMetadataFromContext(ctx context.Context) (*ResponseMetadata, bool) {
+	m, ok := ctx.Value(responseMetadataKey{}).(*ResponseMetadata)
+	return m, ok
+}
+
+// SetStatus sets the HTTP status code of the response, for example http.StatusCreated.
+// It only applies to successful responses, errors keep the status code chosen by the error encoder.
+//
+// Parameters:
+//   - ctx: The context passed to the service method
+//   - code: The HTTP status code
+//
+// Returns:
+//   - error: ErrNoResponseMetadata if ctx was not created by a goose server handler
+func SetStatus(ctx context.Context, code int) error {
+	m, ok := ResponseMetadataFromContext(ctx)
+	if !ok {
+		return ErrNoResponseMetadata
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.status = code
+	return nil
+}
+
+// SetHeader sets a response header, replacing any existing values of the key.
+//
+// Parameters:
+//   - ctx: The context passed to the service method
+//   - key: The header name
+//   - value: The header value
+//
+// Returns:
+//   - error: ErrNoResponseMetadata if ctx was not created by a goose server handler
+func SetHeader(ctx context.Context, key, value string) error {
+	m, ok := ResponseMetadataFromContext(ctx)
+	if !ok {
+		return ErrNoResponseMetadata
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.header.Set(key, value)
+	return nil
+}
+
+// AddHeader adds a response header, keeping any existing values of the key.
+// Use it for headers that may appear several times, such as Set-Cookie.
+//
+// Parameters:
+//   - ctx: The context passed to the service method
+//   - key: The header name
+//   - value: The header value
+//
+// Returns:
+//   - error: ErrNoResponseMetadata if ctx was not created by a goose server handler
+func AddHeader(ctx context.Context, key, value string) error {
+	m, ok := ResponseMetadataFromContext(ctx)
+	if !ok {
+		return ErrNoResponseMetadata
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.header.Add(key, value)
+	return nil
+}
+
+// SetTrailer adds a response trailer, sent after the response body.
+//
+// Parameters:
+//   - ctx: The context passed to the service method
+//   - key: The trailer name
+//   - value: The trailer value
+//
+// Returns:
+//   - error: ErrNoResponseMetadata if ctx was not created by a goose server handler
+func SetTrailer(ctx context.Context, key, value string) error {
+	m, ok := ResponseMetadataFromContext(ctx)
+	if !ok {
+		return ErrNoResponseMetadata
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.trailer.Add(key, value)
+	return nil
+}
+
+// BodyAllowedForStatus reports whether a response with the given status code may have a body.
+// Informational (1xx), 204 No Content and 304 Not Modified responses have no body.
+//
+// Parameters:
+//   - status: The HTTP status code
+//
+// Returns:
+//   - bool: True if a body may be written
+func BodyAllowedForStatus(status int) bool {
+	switch {
+	case status >= 100 && status <= 199:
+		return false
+	case status == http.StatusNoContent:
+		return false
+	case status == http.StatusNotModified:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,83 @@
+package goose
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResponseMetadataWithoutContext(t *testing.T) {
+	ctx := context.Background()
+	if err := SetStatus(ctx, http.StatusCreated); !errors.Is(err, ErrNoResponseMetadata) {
+		t.Errorf("SetStatus() error = %v, want ErrNoResponseMetadata", err)
+	}
+	if err := SetHeader(ctx, "Location", "/v1/users/1"); !errors.Is(err, ErrNoResponseMetadata) {
+		t.Errorf("SetHeader() error = %v, want ErrNoResponseMetadata", err)
+	}
+	if err := AddHeader(ctx, "Set-Cookie", "a=1"); !errors.Is(err, ErrNoResponseMetadata) {
+		t.Errorf("AddHeader() error = %v, want ErrNoResponseMetadata", err)
+	}
+	if err := SetTrailer(ctx, "X-Checksum", "abc"); !errors.Is(err, ErrNoResponseMetadata) {
+		t.Errorf("SetTrailer() error = %v, want ErrNoResponseMetadata", err)
+	}
+}
+
+func TestResponseMetadata(t *testing.T) {
+	ctx := NewResponseMetadataContext(context.Background())
+	_ = SetStatus(ctx, http.StatusCreated)
+	_ = SetHeader(ctx, "Location", "/v1/users/1")
+	_ = AddHeader(ctx, "Set-Cookie", "a=1")
+	_ = AddHeader(ctx, "Set-Cookie", "b=2")
+	_ = SetTrailer(ctx, "X-Checksum", "abc")
+
+	md, ok := ResponseMetadataFromContext(ctx)
+	if !ok {
+		t.Fatal("ResponseMetadataFromContext should report true")
+	}
+	if md.Status() != http.StatusCreated {
+		t.Errorf("Status() = %d, want 201", md.Status())
+	}
+	if got := md.Header().Get("Location"); got != "/v1/users/1" {
+		t.Errorf("Location = %q, want /v1/users/1", got)
+	}
+	if got := md.Header().Values("Set-Cookie"); len(got) != 2 {
+		t.Errorf("Set-Cookie = %v, want 2 values", got)
+	}
+	if got := md.Trailer().Get("X-Checksum"); got != "abc" {
+		t.Errorf("X-Checksum = %q, want abc", got)
+	}
+}
+
+func TestDefaultEncodeError_responseMetadata(t *testing.T) {
+	ctx := NewResponseMetadataContext(context.Background())
+	_ = SetStatus(ctx, http.StatusCreated)
+	_ = AddHeader(ctx, "Set-Cookie", "a=1")
+	rr := httptest.NewRecorder()
+	DefaultEncodeError(ctx, statusErr{}, rr)
+	resp := rr.Result()
+	defer resp.Body.Close()
+	if resp.StatusCode != 418 {
+		t.Errorf("status = %d, want 418", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Set-Cookie"); got != "a=1" {
+		t.Errorf("Set-Cookie = %q, want a=1", got)
+	}
+}
+
+func TestBodyAllowedForStatus(t *testing.T) {
+	tests := map[int]bool{
+		http.StatusContinue:    false,
+		http.StatusOK:          true,
+		http.StatusCreated:     true,
+		http.StatusNoContent:   false,
+		http.StatusNotModified: false,
+		http.StatusNotFound:    true,
+	}
+	for status, want := range tests {
+		if got := BodyAllowedForStatus(status); got != want {
+			t.Errorf("BodyAllowedForStatus(%d) = %v, want %v", status, got, want)
+		}
+	}
+}
@@ -0,0 +1,337 @@
+package goose
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/http/httpguts"
+)
+
+// Ensure http.ServeMux implements the Router interface
+var _ Router = (*http.ServeMux)(nil)
+
+// Router registers the handlers of the goose services.
+// *http.ServeMux implements it; adapters mount the services on other routers, such as chi, gorilla/mux or echo,
+// translating the patterns with ParseRoutePattern and setting the path values with http.Request.SetPathValue.
+type Router interface {
+	// Handle registers the handler for the given pattern
+	// Parameters:
+	//   - pattern: Pattern in the syntax of http.ServeMux, such as "GET /v1/user/{id}"
+	//   - handler: The handler
+	Handle(pattern string, handler http.Handler)
+}
+
+// RouteSegment is a segment of the path of a route pattern
+type RouteSegment struct {
+	Literal  string // Literal text of the segment, empty for a wildcard
+	Wildcard string // Name of the wildcard, such as "id" for "{id}"; empty for a literal or the wildcard of a trailing slash
+	Multi    bool   // Whether the wildcard matches the remaining segments, such as "{path...}" or a trailing slash
+}
+
+// IsWildcard reports whether the segment is a wildcard
+func (s RouteSegment) IsWildcard() bool {
+	return s.Multi || s.Wildcard != ""
+}
+
+// RoutePattern is a parsed route pattern in the syntax of http.ServeMux, "[METHOD ][HOST]/[PATH]"
+type RoutePattern struct {
+	Method   string         // HTTP method, empty to match all the methods
+	Host     string         // Host, empty to match all the hosts
+	Segments []RouteSegment // Segments of the path; a trailing slash is an anonymous multi wildcard, as in http.ServeMux
+	Exact    bool           // Whether the path ends with "/{$}", matching the trailing slash only
+}
+
+// ParseRoutePattern parses a route pattern in the syntax of http.ServeMux,
+// rejecting the patterns http.ServeMux panics on, such as a path with "//", "." or ".." segments
+// Parameters:
+//   - pattern: The pattern, such as "GET /v1/user/{id}" or "/static/{path...}"
+//
+// Returns:
+//   - *RoutePattern: The parsed pattern
+//   - error: Error if the pattern is invalid
+func ParseRoutePattern(pattern string) (*RoutePattern, error) {
+	p := &RoutePattern{}
+	rest := strings.TrimLeft(pattern, " \t")
+	if method, path, found := strings.Cut(rest, " "); found {
+		p.Method = method
+		rest = strings.TrimLeft(path, " \t")
+	}
+	if p.Method != "" && strings.IndexFunc(p.Method, func(r rune) bool { return !httpguts.IsTokenRune(r) }) >= 0 {
+		return nil, fmt.Errorf("goose: invalid route pattern %q: invalid method %q", pattern, p.Method)
+	}
+	slash := strings.IndexByte(rest, '/')
+	if slash < 0 {
+		return nil, fmt.Errorf("goose: invalid route pattern %q: missing path", pattern)
+	}
+	if strings.Contains(rest[:slash], "{") {
+		return nil, fmt.Errorf("goose: invalid route pattern %q: host contains '{'", pattern)
+	}
+	// paths are cleaned before matching, so an unclean path never matches
+	if p.Method != "" && p.Method != http.MethodConnect && rest[slash:] != cleanPath(rest[slash:]) {
+		return nil, fmt.Errorf("goose: invalid route pattern %q: non-CONNECT pattern with unclean path can never match", pattern)
+	}
+	p.Host, rest = rest[:slash], rest[slash+1:]
+	if rest == "" {
+		p.Segments = []RouteSegment{{Multi: true}}
+		return p, nil
+	}
+	segments := strings.Split(rest, "/")
+	names := make(map[string]bool)
+	for i, segment := range segments {
+		last := i == len(segments)-1
+		switch {
+		case segment == "" && last:
+			p.Segments = append(p.Segments, RouteSegment{Multi: true})
+		case segment == "{$}" && last:
+			p.Exact = true
+		case strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}"):
+			name, multi := strings.CutSuffix(segment[1:len(segment)-1], "...")
+			if !isWildcardName(name) {
+				return nil, fmt.Errorf("goose: invalid route pattern %q: bad wildcard %q", pattern, segment)
+			}
+			if names[name] {
+				return nil, fmt.Errorf("goose: invalid route pattern %q: duplicate wildcard name %q", pattern, name)
+			}
+			names[name] = true
+			if multi && !last {
+				return nil, fmt.Errorf("goose: invalid route pattern %q: %q must be the last segment", pattern, segment)
+			}
+			p.Segments = append(p.Segments, RouteSegment{Wildcard: name, Multi: multi})
+		case strings.ContainsAny(segment, "{}"):
+			return nil, fmt.Errorf("goose: invalid route pattern %q: a wildcard must be a full segment", pattern)
+		default:
+			p.Segments = append(p.Segments, RouteSegment{Literal: segment})
+		}
+	}
+	return p, nil
+}
+
+// cleanPath returns the canonical path, eliminating the empty, "." and ".." segments and keeping the trailing slash
+func cleanPath(p string) string {
+	cleaned := path.Clean(p)
+	if strings.HasSuffix(p, "/") && cleaned != "/" {
+		cleaned += "/"
+	}
+	return cleaned
+}
+
+// isWildcardName reports whether the name of a wildcard is a Go identifier, as required by http.ServeMux
+func isWildcardName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		if !unicode.IsLetter(c) && c != '_' && (i == 0 || !unicode.IsDigit(c)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Path formats the path of the pattern in the syntax of another router
+// Parameters:
+//   - wildcard: Function formatting a wildcard segment, such as ":" + name for echo;
+//     name is empty for the wildcard of a trailing slash
+//
+// Returns:
+//   - string: The path, such as "/v1/user/:id"
+func (p *RoutePattern) Path(wildcard func(name string, multi bool) string) string {
+	var builder strings.Builder
+	for _, segment := range p.Segments {
+		builder.WriteByte('/')
+		if segment.IsWildcard() {
+			builder.WriteString(wildcard(segment.Wildcard, segment.Multi))
+			continue
+		}
+		builder.WriteString(segment.Literal)
+	}
+	if p.Exact || len(p.Segments) == 0 {
+		builder.WriteByte('/')
+	}
+	return builder.String()
+}
+
+// PathPattern formats the path of the pattern in the syntax of http.ServeMux
+// Returns:
+//   - string: The path, such as "/v1/user/{id}", "/static/" or "/static/{$}"
+func (p *RoutePattern) PathPattern() string {
+	path := p.Path(func(name string, multi bool) string {
+		switch {
+		case name == "":
+			return ""
+		case multi:
+			return "{" + name + "...}"
+		default:
+			return "{" + name + "}"
+		}
+	})
+	if p.Exact {
+		return path + "{$}"
+	}
+	return path
+}
+
+// Wildcards returns the names of the wildcards of the pattern, to set the path values of the requests
+// Returns:
+//   - []string: The names, such as ["id"]
+func (p *RoutePattern) Wildcards() []string {
+	var names []string
+	for _, segment := range p.Segments {
+		if segment.Wildcard != "" {
+			names = append(names, segment.Wildcard)
+		}
+	}
+	return names
+}
+
+// relationship is the relationship between the sets of requests matched by two patterns, as in http.ServeMux
+type relationship int
+
+const (
+	equivalent   relationship = iota // Both match the same requests
+	moreGeneral                      // The first matches all the requests of the second and more
+	moreSpecific                     // The second matches all the requests of the first and more
+	disjoint                         // No request is matched by both
+	overlaps                         // Some requests are matched by both, neither is more specific
+)
+
+// ConflictsWith reports whether two patterns match some requests in common with neither taking precedence,
+// the condition on which http.ServeMux panics when the second one is registered
+// Parameters:
+//   - other: The other pattern
+//
+// Returns:
+//   - bool: True if the patterns conflict
+func (p *RoutePattern) ConflictsWith(other *RoutePattern) bool {
+	// a pattern with a host takes precedence over a pattern without one
+	if p.Host != other.Host {
+		return false
+	}
+	rel := p.compareMethods(other)
+	if rel == disjoint {
+		return false
+	}
+	rel = combineRelationships(rel, p.comparePaths(other))
+	return rel == equivalent || rel == overlaps
+}
+
+// compareMethods compares the methods of two patterns, an empty method matching all and GET matching HEAD
+func (p *RoutePattern) compareMethods(other *RoutePattern) relationship {
+	switch {
+	case p.Method == other.Method:
+		return equivalent
+	case p.Method == "":
+		return moreGeneral
+	case other.Method == "":
+		return moreSpecific
+	case p.Method == http.MethodGet && other.Method == http.MethodHead:
+		return moreGeneral
+	case p.Method == http.MethodHead && other.Method == http.MethodGet:
+		return moreSpecific
+	default:
+		return disjoint
+	}
+}
+
+// comparePaths compares the paths of two patterns segment by segment
+func (p *RoutePattern) comparePaths(other *RoutePattern) relationship {
+	segs1, segs2 := p.matchSegments(), other.matchSegments()
+	multi1, multi2 := segs1[len(segs1)-1].Multi, segs2[len(segs2)-1].Multi
+	if len(segs1) != len(segs2) && !multi1 && !multi2 {
+		return disjoint
+	}
+	rel := equivalent
+	for ; len(segs1) > 0 && len(segs2) > 0; segs1, segs2 = segs1[1:], segs2[1:] {
+		rel = combineRelationships(rel, compareSegments(segs1[0], segs2[0]))
+		if rel == disjoint {
+			return rel
+		}
+	}
+	switch {
+	case len(segs1) == 0 && len(segs2) == 0:
+		return rel
+	// the shorter pattern can only match requests of the longer one by ending with a multi wildcard
+	case len(segs1) < len(segs2) && multi1:
+		return combineRelationships(rel, moreGeneral)
+	case len(segs2) < len(segs1) && multi2:
+		return combineRelationships(rel, moreSpecific)
+	default:
+		return disjoint
+	}
+}
+
+// matchSegments returns the segments of the path, with "{$}" as a literal "/" segment matching the trailing slash only
+func (p *RoutePattern) matchSegments() []RouteSegment {
+	if !p.Exact {
+		return p.Segments
+	}
+	return append(p.Segments[:len(p.Segments):len(p.Segments)], RouteSegment{Literal: "/"})
+}
+
+// compareSegments compares two segments of the same position
+func compareSegments(s1, s2 RouteSegment) relationship {
+	switch {
+	case s1.Multi && s2.Multi:
+		return equivalent
+	case s1.Multi:
+		return moreGeneral
+	case s2.Multi:
+		return moreSpecific
+	case s1.IsWildcard() && s2.IsWildcard():
+		return equivalent
+	case s1.IsWildcard():
+		// a single wildcard does not match the empty trailing segment of "{$}"
+		if s2.Literal == "/" {
+			return disjoint
+		}
+		return moreGeneral
+	case s2.IsWildcard():
+		if s1.Literal == "/" {
+			return disjoint
+		}
+		return moreSpecific
+	case s1.Literal == s2.Literal:
+		return equivalent
+	default:
+		return disjoint
+	}
+}
+
+// combineRelationships combines the relationships of two parts of the patterns
+func combineRelationships(r1, r2 relationship) relationship {
+	switch r1 {
+	case equivalent:
+		return r2
+	case disjoint:
+		return disjoint
+	case overlaps:
+		if r2 == disjoint {
+			return disjoint
+		}
+		return overlaps
+	default:
+		switch r2 {
+		case equivalent:
+			return r1
+		case inverseRelationship(r1):
+			return overlaps
+		default:
+			return r2
+		}
+	}
+}
+
+// inverseRelationship returns the relationship seen from the other pattern
+func inverseRelationship(r relationship) relationship {
+	switch r {
+	case moreGeneral:
+		return moreSpecific
+	case moreSpecific:
+		return moreGeneral
+	default:
+		return r
+	}
+}
@@ -0,0 +1,86 @@
+// Package chirouter mounts goose services on a chi router
+package chirouter
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-leo/goose"
+)
+
+// router adapts a chi.Router to the goose.Router interface
+type router struct {
+	router chi.Router
+	heads  map[string]bool // Paths with an explicit HEAD route, not overridden by the HEAD route of a GET route
+}
+
+// New creates a goose.Router registering the handlers on a chi router.
+// The patterns are translated to the chi syntax: "{name}" is kept, "{name...}" and trailing slashes become "*".
+// As with http.ServeMux, a GET route also matches HEAD requests, unless a HEAD route is registered for the same path.
+// Host patterns are not supported.
+// Parameters:
+//   - r: The chi router, such as chi.NewRouter()
+//
+// Returns:
+//   - goose.Router: The router to pass to the generated AppendXxxGooseRoute functions
+func New(r chi.Router) goose.Router {
+	return &router{router: r, heads: make(map[string]bool)}
+}
+
+// Handle registers the handler, setting the path values of the requests from the URL parameters of chi
+// Parameters:
+//   - pattern: Pattern in the syntax of http.ServeMux, such as "GET /v1/user/{id}"
+//   - handler: The handler
+func (r *router) Handle(pattern string, handler http.Handler) {
+	p, err := goose.ParseRoutePattern(pattern)
+	if err != nil {
+		panic(err)
+	}
+	if p.Host != "" {
+		panic(fmt.Errorf("chirouter: host patterns are not supported: %q", pattern))
+	}
+	path := p.Path(func(name string, multi bool) string {
+		if multi {
+			return "*"
+		}
+		return "{" + name + "}"
+	})
+	h := http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		for _, segment := range p.Segments {
+			switch {
+			case segment.Wildcard == "":
+			case segment.Multi:
+				request.SetPathValue(segment.Wildcard, pathValue(request, chi.URLParam(request, "*")))
+			default:
+				request.SetPathValue(segment.Wildcard, pathValue(request, chi.URLParam(request, segment.Wildcard)))
+			}
+		}
+		handler.ServeHTTP(response, request)
+	})
+	if p.Method == "" {
+		r.router.Handle(path, h)
+		return
+	}
+	r.router.Method(p.Method, path, h)
+	switch p.Method {
+	case http.MethodHead:
+		r.heads[path] = true
+	case http.MethodGet:
+		if !r.heads[path] {
+			r.router.Method(http.MethodHead, path, h)
+		}
+	}
+}
+
+// pathValue unescapes a parameter, chi matching the escaped path when the path has escaped slashes
+func pathValue(request *http.Request, value string) string {
+	if request.URL.RawPath == "" {
+		return value
+	}
+	if unescaped, err := url.PathUnescape(value); err == nil {
+		return unescaped
+	}
+	return value
+}
@@ -0,0 +1,26 @@
+package chirouter
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-leo/goose"
+	"github.com/go-leo/goose/router/routertest"
+)
+
+func TestRouter(t *testing.T) {
+	routertest.Run(t, func() (goose.Router, http.Handler) {
+		mux := chi.NewRouter()
+		return New(mux), mux
+	})
+}
+
+func TestHostPattern(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Handle() with a host pattern must panic")
+		}
+	}()
+	New(chi.NewRouter()).Handle("GET example.com/v1/user/{id}", http.NotFoundHandler())
+}
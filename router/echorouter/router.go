@@ -0,0 +1,92 @@
+// Package echorouter mounts goose services on an echo server
+package echorouter
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/go-leo/goose"
+	"github.com/labstack/echo/v4"
+)
+
+// router adapts an echo.Echo to the goose.Router interface
+type router struct {
+	echo  *echo.Echo
+	heads map[string]bool // Host and paths with an explicit HEAD route, not overridden by the HEAD route of a GET route
+}
+
+// New creates a goose.Router registering the handlers on an echo server.
+// The patterns are translated to the echo syntax: "{name}" becomes ":name", "{name...}" and trailing slashes become "*".
+// As with http.ServeMux, a GET route also matches HEAD requests, unless a HEAD route is registered for the same path.
+// Parameters:
+//   - e: The echo server, such as echo.New()
+//
+// Returns:
+//   - goose.Router: The router to pass to the generated AppendXxxGooseRoute functions
+func New(e *echo.Echo) goose.Router {
+	return &router{echo: e, heads: make(map[string]bool)}
+}
+
+// Handle registers the handler, setting the path values of the requests from the path parameters of echo
+// Parameters:
+//   - pattern: Pattern in the syntax of http.ServeMux, such as "GET /v1/user/{id}"
+//   - handler: The handler
+func (r *router) Handle(pattern string, handler http.Handler) {
+	p, err := goose.ParseRoutePattern(pattern)
+	if err != nil {
+		panic(err)
+	}
+	path := p.Path(func(name string, multi bool) string {
+		if multi {
+			return "*"
+		}
+		return ":" + name
+	})
+	h := func(c echo.Context) error {
+		request := c.Request()
+		for _, segment := range p.Segments {
+			if segment.Wildcard == "" {
+				continue
+			}
+			key := segment.Wildcard
+			if segment.Multi {
+				key = "*"
+			}
+			request.SetPathValue(segment.Wildcard, pathValue(request, c.Param(key)))
+		}
+		handler.ServeHTTP(c.Response(), request)
+		return nil
+	}
+	var add func(method, path string, handler echo.HandlerFunc, middleware ...echo.MiddlewareFunc) *echo.Route
+	var addAny func(path string, handler echo.HandlerFunc, middleware ...echo.MiddlewareFunc) []*echo.Route
+	if p.Host != "" {
+		group := r.echo.Host(p.Host)
+		add, addAny = group.Add, group.Any
+	} else {
+		add, addAny = r.echo.Add, r.echo.Any
+	}
+	if p.Method == "" {
+		addAny(path, h)
+		return
+	}
+	add(p.Method, path, h)
+	switch p.Method {
+	case http.MethodHead:
+		r.heads[p.Host+path] = true
+	case http.MethodGet:
+		if !r.heads[p.Host+path] {
+			add(http.MethodHead, path, h)
+		}
+	}
+}
+
+// pathValue unescapes a parameter, echo matching the escaped path when the path has escaped slashes
+func pathValue(request *http.Request, value string) string {
+	if request.URL.RawPath == "" {
+		return value
+	}
+	if unescaped, err := url.PathUnescape(value); err == nil {
+		return unescaped
+	}
+	return value
+}
@@ -0,0 +1,54 @@
+package echorouter
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-leo/goose"
+	"github.com/go-leo/goose/router/routertest"
+	"github.com/labstack/echo/v4"
+)
+
+func TestRouter(t *testing.T) {
+	routertest.Run(t, func() (goose.Router, http.Handler) {
+		e := echo.New()
+		return New(e), e
+	})
+}
+
+func TestHostPattern(t *testing.T) {
+	handler := func(name string) http.Handler {
+		return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+			_, _ = io.WriteString(response, name+" "+request.PathValue("id"))
+		})
+	}
+	e := echo.New()
+	router := New(e)
+	router.Handle("GET a.example.com/v1/user/{id}", handler("a"))
+	router.Handle("GET b.example.com/v1/user/{id}", handler("b"))
+	tests := []struct {
+		method string
+		host   string
+		status int
+		want   string
+	}{
+		{method: http.MethodGet, host: "a.example.com", status: http.StatusOK, want: "a 5"},
+		{method: http.MethodGet, host: "b.example.com", status: http.StatusOK, want: "b 5"},
+		{method: http.MethodHead, host: "b.example.com", status: http.StatusOK, want: "b 5"},
+		{method: http.MethodGet, host: "c.example.com", status: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		request := httptest.NewRequest(tt.method, "/v1/user/5", nil)
+		request.Host = tt.host
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+		if recorder.Code != tt.status {
+			t.Fatalf("%s %s = %d, want %d", tt.method, tt.host, recorder.Code, tt.status)
+		}
+		if tt.status == http.StatusOK && recorder.Body.String() != tt.want {
+			t.Fatalf("%s %s = %q, want %q", tt.method, tt.host, recorder.Body.String(), tt.want)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+module github.com/go-leo/goose/router/muxrouter
+
+go 1.23.0
+
+require (
+	github.com/go-leo/goose v1.6.11
+	github.com/gorilla/mux v1.8.1
+)
+
+require (
+	golang.org/x/exp v0.0.0-20250811191247-51f88131bc50 // indirect
+	golang.org/x/net v0.43.0 // indirect
+	golang.org/x/text v0.28.0 // indirect
+	google.golang.org/genproto/googleapis/api v0.0.0-20250825161204-c5933d9347a5 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250825161204-c5933d9347a5 // indirect
+	google.golang.org/protobuf v1.36.10 // indirect
+)
+
+replace github.com/go-leo/goose => ../../
@@ -0,0 +1,128 @@
+// Package muxrouter mounts goose services on a gorilla/mux router
+package muxrouter
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-leo/goose"
+	"github.com/gorilla/mux"
+)
+
+// router adapts a mux.Router to the goose.Router interface
+type router struct {
+	router *mux.Router
+	heads  []*mux.Route // Explicit HEAD routes, matched before the GET routes whatever the registration order
+}
+
+// New creates a goose.Router registering the handlers on a gorilla/mux router.
+// The patterns are translated to the gorilla/mux syntax: "{name}" is kept, "{name...}" becomes "{name:.*}"
+// and a trailing slash becomes a path prefix.
+// As with http.ServeMux, a GET route also matches HEAD requests, unless a HEAD route matches them.
+// Unlike http.ServeMux, routes are matched in registration order and Handle does not reorder them:
+// register the path prefixes (patterns ending with a slash) after the routes they contain.
+// Call UseEncodedPath on the router so that a wildcard, such as "{id}", matches a segment with escaped slashes.
+// Parameters:
+//   - r: The gorilla/mux router, such as mux.NewRouter()
+//
+// Returns:
+//   - goose.Router: The router to pass to the generated AppendXxxGooseRoute functions
+func New(r *mux.Router) goose.Router {
+	return &router{router: r}
+}
+
+// Handle registers the handler, setting the path values of the requests from the variables of gorilla/mux
+// Parameters:
+//   - pattern: Pattern in the syntax of http.ServeMux, such as "GET /v1/user/{id}"
+//   - handler: The handler
+func (r *router) Handle(pattern string, handler http.Handler) {
+	p, err := goose.ParseRoutePattern(pattern)
+	if err != nil {
+		panic(err)
+	}
+	path := p.Path(func(name string, multi bool) string {
+		if name == "" {
+			return ""
+		}
+		if multi {
+			return "{" + name + ":.*}"
+		}
+		return "{" + name + "}"
+	})
+	var route *mux.Route
+	if last := p.Segments[len(p.Segments)-1]; last.Multi && last.Wildcard == "" {
+		// the anonymous wildcard of a trailing slash matches any path below it
+		route = r.router.PathPrefix(strings.TrimSuffix(path, "/") + "/")
+	} else {
+		route = r.router.Path(path)
+	}
+	expr, err := route.GetPathRegexp()
+	if err != nil {
+		panic(err)
+	}
+	escaped := regexp.MustCompile(expr)
+	names := p.Wildcards()
+	route.Handler(http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		values := pathValues(request, escaped, names)
+		for i, name := range names {
+			request.SetPathValue(name, values[i])
+		}
+		handler.ServeHTTP(response, request)
+	}))
+	switch p.Method {
+	case "":
+	case http.MethodGet:
+		route.Methods(http.MethodGet, http.MethodHead).MatcherFunc(r.notExplicitHead)
+	case http.MethodHead:
+		route.Methods(http.MethodHead)
+		r.heads = append(r.heads, route)
+	default:
+		route.Methods(p.Method)
+	}
+	if p.Host != "" {
+		route.Host(p.Host)
+	}
+}
+
+// notExplicitHead reports whether the request is not a HEAD request matched by an explicit HEAD route
+func (r *router) notExplicitHead(request *http.Request, _ *mux.RouteMatch) bool {
+	if request.Method != http.MethodHead {
+		return true
+	}
+	for _, head := range r.heads {
+		if head.Match(request, &mux.RouteMatch{}) {
+			return false
+		}
+	}
+	return true
+}
+
+// pathValues returns the values of the wildcards.
+// gorilla/mux matches the unescaped path unless UseEncodedPath is set, splitting the values with escaped slashes,
+// so the values are taken from the escaped path matched by the regexp of the route, then unescaped.
+func pathValues(request *http.Request, escaped *regexp.Regexp, names []string) []string {
+	values := make([]string, len(names))
+	vars := mux.Vars(request)
+	for i, name := range names {
+		values[i] = vars[name]
+	}
+	if request.URL.RawPath == "" {
+		return values
+	}
+	match := escaped.FindStringSubmatch(request.URL.EscapedPath())
+	if match == nil {
+		return values
+	}
+	for i := range names {
+		// gorilla/mux names the groups of the path variables v0, v1... in order
+		if index := escaped.SubexpIndex("v" + strconv.Itoa(i)); index >= 0 {
+			if unescaped, err := url.PathUnescape(match[index]); err == nil {
+				values[i] = unescaped
+			}
+		}
+	}
+	return values
+}
@@ -0,0 +1,117 @@
+package muxrouter
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-leo/goose"
+	"github.com/go-leo/goose/router/routertest"
+	"github.com/gorilla/mux"
+)
+
+func TestRouter(t *testing.T) {
+	routertest.Run(t, func() (goose.Router, http.Handler) {
+		muxRouter := mux.NewRouter()
+		return New(muxRouter), muxRouter
+	})
+}
+
+// named returns a handler writing its name and the path of the request
+func named(name string) http.Handler {
+	return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		_, _ = io.WriteString(response, name+" "+request.URL.Path)
+	})
+}
+
+func TestTrailingSlash(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string // Registered in order, path prefixes last
+		path     string
+		want     string
+	}{
+		{
+			name:     "path below the prefix",
+			patterns: []string{"GET /static/"},
+			path:     "/static/css/main.css",
+			want:     "GET /static/ /static/css/main.css",
+		},
+		{
+			name:     "prefix itself",
+			patterns: []string{"GET /static/"},
+			path:     "/static/",
+			want:     "GET /static/ /static/",
+		},
+		{
+			name:     "route before the root prefix",
+			patterns: []string{"GET /v1/user/{id}", "GET /"},
+			path:     "/v1/user/5",
+			want:     "GET /v1/user/{id} /v1/user/5",
+		},
+		{
+			name:     "root prefix",
+			patterns: []string{"GET /v1/user/{id}", "GET /"},
+			path:     "/v1/users/5",
+			want:     "GET / /v1/users/5",
+		},
+		{
+			name:     "route before a path prefix",
+			patterns: []string{"GET /api/v1/user/{id}", "GET /api/"},
+			path:     "/api/v1/user/5",
+			want:     "GET /api/v1/user/{id} /api/v1/user/5",
+		},
+		{
+			name:     "path prefix",
+			patterns: []string{"GET /api/v1/user/{id}", "GET /api/"},
+			path:     "/api/v2/user/5",
+			want:     "GET /api/ /api/v2/user/5",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			muxRouter, serveMux := mux.NewRouter(), http.NewServeMux()
+			for _, router := range []goose.Router{New(muxRouter), serveMux} {
+				for _, pattern := range tt.patterns {
+					router.Handle(pattern, named(pattern))
+				}
+			}
+			for name, handler := range map[string]http.Handler{"gorilla/mux": muxRouter, "http.ServeMux": serveMux} {
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.path, nil))
+				if recorder.Code != http.StatusOK || recorder.Body.String() != tt.want {
+					t.Fatalf("%s: GET %s = %d %q, want %q", name, tt.path, recorder.Code, recorder.Body.String(), tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestHostPattern(t *testing.T) {
+	muxRouter := mux.NewRouter()
+	router := New(muxRouter)
+	router.Handle("GET a.example.com/v1/user/{id}", named("a"))
+	router.Handle("GET b.example.com/v1/user/{id}", named("b"))
+	tests := []struct {
+		host   string
+		status int
+		want   string
+	}{
+		{host: "a.example.com", status: http.StatusOK, want: "a /v1/user/5"},
+		{host: "b.example.com", status: http.StatusOK, want: "b /v1/user/5"},
+		{host: "c.example.com", status: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		request := httptest.NewRequest(http.MethodGet, "/v1/user/5", nil)
+		request.Host = tt.host
+		recorder := httptest.NewRecorder()
+		muxRouter.ServeHTTP(recorder, request)
+		if recorder.Code != tt.status {
+			t.Fatalf("GET %s = %d, want %d", tt.host, recorder.Code, tt.status)
+		}
+		if tt.status == http.StatusOK && recorder.Body.String() != tt.want {
+			t.Fatalf("GET %s = %q, want %q", tt.host, recorder.Body.String(), tt.want)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+// Package routertest provides the conformance tests of the goose.Router adapters,
+// checking that they route requests as http.ServeMux does
+package routertest
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-leo/goose"
+	"github.com/go-leo/goose/server"
+)
+
+// NewRouter creates an empty router under test
+//
+// Returns:
+//   - goose.Router: The adapter the routes are registered on
+//   - http.Handler: The handler serving the requests, usually the adapted router
+type NewRouter func() (goose.Router, http.Handler)
+
+// Run runs the conformance tests of a router adapter.
+// Each test registers its routes on a new router, with handlers echoing the path values.
+//
+// Parameters:
+//   - t: The test
+//   - newRouter: The constructor of the router under test
+func Run(t *testing.T, newRouter NewRouter) {
+	t.Run("Routes", func(t *testing.T) { testRoutes(t, newRouter) })
+	t.Run("EscapedPath", func(t *testing.T) { testEscapedPath(t, newRouter) })
+	t.Run("Head", func(t *testing.T) { testHead(t, newRouter) })
+	t.Run("ExplicitHead", func(t *testing.T) { testExplicitHead(t, newRouter) })
+	t.Run("RouteRegistry", func(t *testing.T) { testRouteRegistry(t, newRouter) })
+}
+
+// echoValues returns a handler writing the name of the route and the path values of the wildcards as a JSON object
+func echoValues(name string, wildcards ...string) http.Handler {
+	return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		values := map[string]string{"route": name}
+		for _, wildcard := range wildcards {
+			values[wildcard] = request.PathValue(wildcard)
+		}
+		if endpoint, ok := goose.EndpointFromContext(request.Context()); ok {
+			values["pattern"] = endpoint.Pattern
+		}
+		response.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(response).Encode(values)
+	})
+}
+
+// request is a request sent to the router under test and its expected response
+type request struct {
+	method string
+	target string
+	status int               // Expected status code
+	values map[string]string // Expected route name and path values, checked if the status is 200
+}
+
+// serve sends the requests to the handler and checks the responses
+func serve(t *testing.T, handler http.Handler, requests []request) {
+	t.Helper()
+	for _, tt := range requests {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(tt.method, tt.target, nil))
+		if recorder.Code != tt.status {
+			t.Errorf("%s %s: status = %d, want %d", tt.method, tt.target, recorder.Code, tt.status)
+			continue
+		}
+		if tt.status != http.StatusOK {
+			continue
+		}
+		var values map[string]string
+		if err := json.Unmarshal(recorder.Body.Bytes(), &values); err != nil {
+			t.Errorf("%s %s: body = %q: %v", tt.method, tt.target, recorder.Body.String(), err)
+			continue
+		}
+		if !maps.Equal(values, tt.values) {
+			t.Errorf("%s %s: values = %v, want %v", tt.method, tt.target, values, tt.values)
+		}
+	}
+}
+
+// testRoutes checks the methods, the wildcards and the unmatched requests
+func testRoutes(t *testing.T, newRouter NewRouter) {
+	router, handler := newRouter()
+	router.Handle("GET /v1/user/{id}", echoValues("get", "id"))
+	router.Handle("POST /v1/user", echoValues("create"))
+	router.Handle("PUT /v1/user/{id}", echoValues("update", "id"))
+	router.Handle("PATCH /v1/user/{id}", echoValues("modify", "id"))
+	router.Handle("DELETE /v1/user/{id}", echoValues("delete", "id"))
+	router.Handle("GET /v1/users", echoValues("list"))
+	router.Handle("GET /v1/files/{path...}", echoValues("file", "path"))
+	serve(t, handler, []request{
+		{method: http.MethodGet, target: "/v1/user/5", status: http.StatusOK, values: map[string]string{"route": "get", "id": "5"}},
+		{method: http.MethodPost, target: "/v1/user", status: http.StatusOK, values: map[string]string{"route": "create"}},
+		{method: http.MethodPut, target: "/v1/user/5", status: http.StatusOK, values: map[string]string{"route": "update", "id": "5"}},
+		{method: http.MethodPatch, target: "/v1/user/5", status: http.StatusOK, values: map[string]string{"route": "modify", "id": "5"}},
+		{method: http.MethodDelete, target: "/v1/user/5", status: http.StatusOK, values: map[string]string{"route": "delete", "id": "5"}},
+		{method: http.MethodGet, target: "/v1/users", status: http.StatusOK, values: map[string]string{"route": "list"}},
+		{method: http.MethodGet, target: "/v1/files/css/main.css", status: http.StatusOK, values: map[string]string{"route": "file", "path": "css/main.css"}},
+		{method: http.MethodPost, target: "/v1/user/5", status: http.StatusMethodNotAllowed},
+		{method: http.MethodGet, target: "/v1/unknown", status: http.StatusNotFound},
+	})
+}
+
+// testEscapedPath checks that the path values are unescaped, escaped slashes included
+func testEscapedPath(t *testing.T, newRouter NewRouter) {
+	router, handler := newRouter()
+	router.Handle("GET /v1/strings/{s}/{rest...}", echoValues("strings", "s", "rest"))
+	serve(t, handler, []request{
+		{method: http.MethodGet, target: "/v1/strings/a%2Fb/def", status: http.StatusOK, values: map[string]string{"route": "strings", "s": "a/b", "rest": "def"}},
+		{method: http.MethodGet, target: "/v1/strings/abc/r%2Fs/t", status: http.StatusOK, values: map[string]string{"route": "strings", "s": "abc", "rest": "r/s/t"}},
+		{method: http.MethodGet, target: "/v1/strings/a%20b/d%25f", status: http.StatusOK, values: map[string]string{"route": "strings", "s": "a b", "rest": "d%f"}},
+	})
+}
+
+// testHead checks that a GET route matches HEAD requests
+func testHead(t *testing.T, newRouter NewRouter) {
+	router, handler := newRouter()
+	router.Handle("GET /v1/user/{id}", echoValues("get", "id"))
+	serve(t, handler, []request{
+		{method: http.MethodHead, target: "/v1/user/5", status: http.StatusOK, values: map[string]string{"route": "get", "id": "5"}},
+		{method: http.MethodPost, target: "/v1/user/5", status: http.StatusMethodNotAllowed},
+	})
+}
+
+// testExplicitHead checks that an explicit HEAD route wins over the GET route, whatever the registration order
+func testExplicitHead(t *testing.T, newRouter NewRouter) {
+	tests := []struct {
+		name    string
+		methods []string
+	}{
+		{name: "HEAD before GET", methods: []string{http.MethodHead, http.MethodGet}},
+		{name: "HEAD after GET", methods: []string{http.MethodGet, http.MethodHead}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, handler := newRouter()
+			for _, method := range tt.methods {
+				router.Handle(method+" /items/{id}", echoValues(method, "id"))
+			}
+			serve(t, handler, []request{
+				{method: http.MethodGet, target: "/items/1", status: http.StatusOK, values: map[string]string{"route": http.MethodGet, "id": "1"}},
+				{method: http.MethodHead, target: "/items/1", status: http.StatusOK, values: map[string]string{"route": http.MethodHead, "id": "1"}},
+			})
+		})
+	}
+}
+
+// testRouteRegistry checks the routes registered like the generated AppendXxxGooseRoute functions, under a path prefix
+func testRouteRegistry(t *testing.T, newRouter NewRouter) {
+	router, handler := newRouter()
+	options := server.NewOptions(server.PathPrefix("/api/"), server.RouteRegistry(goose.NewRouteRegistry()))
+	endpoint := &goose.Endpoint{FullName: "/leo.example.user.v1.User/GetUser", Method: http.MethodGet, Pattern: "/v1/user/{id}"}
+	options.RouteRegistry().Handle(router, endpoint, "GET "+options.PathPrefix()+"/v1/user/{id}", echoValues("get", "id"))
+	serve(t, handler, []request{
+		{method: http.MethodGet, target: "/api/v1/user/5", status: http.StatusOK, values: map[string]string{"route": "get", "id": "5", "pattern": "/api/v1/user/{id}"}},
+		{method: http.MethodGet, target: "/v1/user/5", status: http.StatusNotFound},
+	})
+}
@@ -0,0 +1,16 @@
+package routertest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-leo/goose"
+)
+
+// TestServeMux runs the conformance tests against http.ServeMux, the reference behavior
+func TestServeMux(t *testing.T) {
+	Run(t, func() (goose.Router, http.Handler) {
+		mux := http.NewServeMux()
+		return mux, mux
+	})
+}
@@ -0,0 +1,111 @@
+package goose
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseRoutePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    *RoutePattern
+	}{
+		{
+			pattern: "GET /v1/user/{id}",
+			want: &RoutePattern{Method: "GET", Segments: []RouteSegment{
+				{Literal: "v1"}, {Literal: "user"}, {Wildcard: "id"},
+			}},
+		},
+		{
+			pattern: "POST example.com/v1/files/{path...}",
+			want: &RoutePattern{Method: "POST", Host: "example.com", Segments: []RouteSegment{
+				{Literal: "v1"}, {Literal: "files"}, {Wildcard: "path", Multi: true},
+			}},
+		},
+		{
+			pattern: "/static/",
+			want: &RoutePattern{Segments: []RouteSegment{
+				{Literal: "static"}, {Multi: true},
+			}},
+		},
+		{
+			pattern: "/static/{$}",
+			want:    &RoutePattern{Segments: []RouteSegment{{Literal: "static"}}, Exact: true},
+		},
+		{
+			pattern: "/",
+			want:    &RoutePattern{Segments: []RouteSegment{{Multi: true}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			got, err := ParseRoutePattern(tt.pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseRoutePatternErrors(t *testing.T) {
+	patterns := []string{
+		"GET", "GET /v1/{}", "/v1/{path...}/x", "/v1/user{id}",
+		"GET{ /v1", "{host}/v1", "GET /v1/{a-b}", "GET /v1/{id}/{id}",
+		"GET /v1//user", "GET /v1/./user", "GET /v1/user/..",
+	}
+	for _, pattern := range patterns {
+		if _, err := ParseRoutePattern(pattern); err == nil {
+			t.Errorf("%q: expected error", pattern)
+		}
+	}
+}
+
+func TestRoutePatternPath(t *testing.T) {
+	echo := func(name string, multi bool) string {
+		if multi {
+			return "*"
+		}
+		return ":" + name
+	}
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{pattern: "GET /v1/user/{id}", want: "/v1/user/:id"},
+		{pattern: "GET /v1/{a}/{b}/{rest...}", want: "/v1/:a/:b/*"},
+		{pattern: "/static/", want: "/static/*"},
+		{pattern: "/static/{$}", want: "/static/"},
+		{pattern: "/{$}", want: "/"},
+	}
+	for _, tt := range tests {
+		p, err := ParseRoutePattern(tt.pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := p.Path(echo); got != tt.want {
+			t.Errorf("%q: expected %q, got %q", tt.pattern, tt.want, got)
+		}
+	}
+	p, _ := ParseRoutePattern("GET /v1/{a}/{b}/{rest...}")
+	if got := p.Wildcards(); !reflect.DeepEqual(got, []string{"a", "b", "rest"}) {
+		t.Errorf("unexpected wildcards %v", got)
+	}
+}
+
+func TestCleanPathPrefix(t *testing.T) {
+	tests := map[string]string{
+		"":               "",
+		"/":              "",
+		"api":            "/api",
+		"/api/internal/": "/api/internal",
+		"//v2//":         "/v2",
+	}
+	for prefix, want := range tests {
+		if got := CleanPathPrefix(prefix); got != want {
+			t.Errorf("CleanPathPrefix(%q): expected %q, got %q", prefix, want, got)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package goose
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+)
+
+// Route describes a route registered by a goose service
+type Route struct {
+	Method   string `json:"method"`   // HTTP method, such as "GET"
+	Path     string `json:"path"`     // Path pattern, with the path prefix, such as "/api/v1/user/{id}"
+	RPC      string `json:"rpc"`      // Full name of the RPC method, such as "/leo.example.user.v1.User/GetUser"
+	Request  string `json:"request"`  // Full name of the request message
+	Response string `json:"response"` // Full name of the response message
+}
+
+// RouteConflictError is the error of a route matching the same requests as a route already registered on a router
+type RouteConflictError struct {
+	Route    Route // The route being registered
+	Existing Route // The route already registered
+}
+
+// Error returns the patterns and the RPC methods of both routes
+func (e *RouteConflictError) Error() string {
+	return fmt.Sprintf("goose: route %q of %s conflicts with route %q of %s",
+		e.Route.Method+" "+e.Route.Path, e.Route.RPC, e.Existing.Method+" "+e.Existing.Path, e.Existing.RPC)
+}
+
+// registeredRoute is a route with its parsed pattern
+type registeredRoute struct {
+	route   Route
+	pattern *RoutePattern
+}
+
+// detector holds the routes of a router detecting conflicts
+type detector struct {
+	routes    []registeredRoute
+	collect   bool    // Whether Handle collects the conflicts instead of panicking
+	conflicts []error // Conflicts collected by Handle
+}
+
+// DefaultRouteRegistry is the registry used by the generated AppendXxxGooseRoute functions,
+// unless server.RouteRegistry sets another one
+var DefaultRouteRegistry = NewRouteRegistry()
+
+// RouteRegistry records the routes registered by the goose services,
+// and detects the conflicts of the routes registered on the routers passed to DetectConflicts or CollectConflicts.
+// The other routers do not detect conflicts: app.Handler is the only caller opting in by default.
+type RouteRegistry struct {
+	mu       sync.RWMutex
+	routers  map[Router]*detector // Routes by router, only for the routers detecting conflicts
+	routes   []Route              // Distinct routes, in registration order
+	distinct map[Route]struct{}
+}
+
+// NewRouteRegistry creates an empty route registry
+//
+// Returns:
+//   - *RouteRegistry: The registry
+func NewRouteRegistry() *RouteRegistry {
+	return &RouteRegistry{
+		routers:  make(map[Router]*detector),
+		distinct: make(map[Route]struct{}),
+	}
+}
+
+// DetectConflicts makes the registry detect the conflicts between the routes registered on the router from now on,
+// Handle panicking on a conflict.
+// The registry keeps the router until Forget is called, such as once all the routes are registered.
+//
+// Parameters:
+//   - router: The router, which must be comparable, such as an *http.ServeMux or a router adapter
+//
+// Returns:
+//   - error: An error if the router is not comparable
+func (r *RouteRegistry) DetectConflicts(router Router) error {
+	return r.detect(router, false)
+}
+
+// CollectConflicts makes the registry detect the conflicts between the routes registered on the router from now on,
+// Handle skipping a conflicting route and collecting its error instead of panicking.
+// Check the conflicts with Conflicts once all the routes are registered, then call Forget.
+//
+// Parameters:
+//   - router: The router, which must be comparable, such as an *http.ServeMux or a router adapter
+//
+// Returns:
+//   - error: An error if the router is not comparable
+func (r *RouteRegistry) CollectConflicts(router Router) error {
+	return r.detect(router, true)
+}
+
+// detect starts detecting the conflicts of the router
+func (r *RouteRegistry) detect(router Router, collect bool) error {
+	if !isComparable(router) {
+		return fmt.Errorf("goose: cannot detect the route conflicts of the non-comparable router %T", router)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if d, ok := r.routers[router]; ok {
+		d.collect = collect
+		return nil
+	}
+	r.routers[router] = &detector{collect: collect}
+	return nil
+}
+
+// Conflicts returns the conflicts collected by Handle on a router passed to CollectConflicts
+//
+// Parameters:
+//   - router: The router
+//
+// Returns:
+//   - error: The *RouteConflictError of each skipped route joined with errors.Join, nil if there is none
+func (r *RouteRegistry) Conflicts(router Router) error {
+	if !isComparable(router) {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	d, ok := r.routers[router]
+	if !ok {
+		return nil
+	}
+	return errors.Join(d.conflicts...)
+}
+
+// Forget stops detecting the conflicts of the routes registered on the router and releases it
+//
+// Parameters:
+//   - router: The router passed to DetectConflicts
+func (r *RouteRegistry) Forget(router Router) {
+	if !isComparable(router) {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.routers, router)
+}
+
+// isComparable reports whether the router can be a map key, a router such as a struct holding a func cannot
+func isComparable(router Router) bool {
+	return router != nil && reflect.ValueOf(router).Comparable()
+}
+
+// Register records the route of an endpoint on a router
+//
+// Parameters:
+//   - router: The router the route is registered on
+//   - endpoint: The endpoint of the route
+//   - pattern: The pattern registered on the router, such as "GET /api/v1/user/{id}"
+//
+// Returns:
+//   - Route: The route, with the path prefix
+//   - error: A *RouteConflictError if the router detects conflicts and the route conflicts with one of its routes,
+//     or a pattern error
+func (r *RouteRegistry) Register(router Router, endpoint *Endpoint, pattern string) (Route, error) {
+	parsed, err := ParseRoutePattern(pattern)
+	if err != nil {
+		return Route{}, err
+	}
+	route := Route{
+		Method:   parsed.Method,
+		Path:     parsed.PathPattern(),
+		RPC:      endpoint.FullName,
+		Request:  endpoint.Request,
+		Response: endpoint.Response,
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if isComparable(router) {
+		if d, ok := r.routers[router]; ok {
+			for _, existing := range d.routes {
+				if parsed.ConflictsWith(existing.pattern) {
+					return Route{}, &RouteConflictError{Route: route, Existing: existing.route}
+				}
+			}
+			d.routes = append(d.routes, registeredRoute{route: route, pattern: parsed})
+		}
+	}
+	if _, ok := r.distinct[route]; !ok {
+		r.distinct[route] = struct{}{}
+		r.routes = append(r.routes, route)
+	}
+	return route, nil
+}
+
+// Handle records the route of an endpoint and registers its handler on the router.
+// It panics on a conflict, naming both RPC methods, instead of letting the router panic or shadow a route;
+// on a router passed to CollectConflicts, it skips the route and collects the conflict instead.
+// The requests carry a copy of the endpoint whose pattern is the path of the route, with the path prefix.
+//
+// Parameters:
+//   - router: The router
+//   - endpoint: The endpoint of the route
+//   - pattern: The pattern, such as "GET /api/v1/user/{id}"
+//   - handler: The handler of the endpoint
+func (r *RouteRegistry) Handle(router Router, endpoint *Endpoint, pattern string, handler http.Handler) {
+	route, err := r.Register(router, endpoint, pattern)
+	if err != nil {
+		if r.collect(router, err) {
+			return
+		}
+		panic(err)
+	}
+	mounted := *endpoint
+	mounted.Pattern = route.Path
+	router.Handle(pattern, http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		handler.ServeHTTP(response, request.WithContext(WithEndpoint(request.Context(), &mounted)))
+	}))
+}
+
+// collect records a conflict of a router collecting its conflicts
+//
+// Returns:
+//   - bool: True if the conflict is collected
+func (r *RouteRegistry) collect(router Router, err error) bool {
+	var conflict *RouteConflictError
+	if !errors.As(err, &conflict) {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d, ok := r.routers[router]
+	if !ok || !d.collect {
+		return false
+	}
+	d.conflicts = append(d.conflicts, err)
+	return true
+}
+
+// Routes returns the distinct routes registered on all the routers, in registration order
+//
+// Returns:
+//   - []Route: The routes
+func (r *RouteRegistry) Routes() []Route {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]Route(nil), r.routes...)
+}
+
+// Handler returns an HTTP handler listing the routes as JSON
+//
+// Returns:
+//   - http.Handler: The handler
+func (r *RouteRegistry) Handler() http.Handler {
+	return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		response.Header().Set("Content-Type", "application/json; charset=utf-8")
+		_ = json.NewEncoder(response).Encode(r.Routes())
+	})
+}
+
+// AppendRoutes registers "GET /debug/goose/routes" on the router, listing the routes of DefaultRouteRegistry
+//
+// Parameters:
+//   - router: The router to register on
+//
+// Returns:
+//   - R: The router
+func AppendRoutes[R Router](router R) R {
+	router.Handle("GET /debug/goose/routes", DefaultRouteRegistry.Handler())
+	return router
+}
@@ -0,0 +1,256 @@
+package goose
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+// serveMuxConflicts reports whether http.ServeMux panics when registering both patterns
+func serveMuxConflicts(p1, p2 string) (conflict bool) {
+	defer func() {
+		conflict = recover() != nil
+	}()
+	mux := http.NewServeMux()
+	mux.Handle(p1, http.NotFoundHandler())
+	mux.Handle(p2, http.NotFoundHandler())
+	return false
+}
+
+func TestRoutePatternConflictsWith(t *testing.T) {
+	tests := []struct {
+		p1, p2 string
+		want   bool
+	}{
+		{p1: "GET /v1/user/{id}", p2: "GET /v1/user/me", want: false},
+		{p1: "GET /v1/user/{id}", p2: "GET /v1/user/{name}", want: true},
+		{p1: "GET /v1/user/{id}", p2: "POST /v1/user/{id}", want: false},
+		{p1: "GET /v1/user/{id}", p2: "HEAD /v1/user/{id}", want: false},
+		{p1: "/v1/user/{id}", p2: "GET /v1/user/{id}", want: false},
+		{p1: "/v1/user/{id}", p2: "GET /v1/{kind}/1", want: true},
+		{p1: "GET /a/{x}", p2: "GET /{y}/b", want: true},
+		{p1: "GET /a/{x...}", p2: "GET /a/{y}/c", want: false},
+		{p1: "GET /a/{x...}", p2: "GET /{y}/b/{z...}", want: true},
+		{p1: "GET /a/", p2: "GET /a/{x...}", want: true},
+		{p1: "GET /a/", p2: "GET /a/{$}", want: false},
+		{p1: "GET /a/{x}", p2: "GET /a/{$}", want: false},
+		{p1: "GET /a/{$}", p2: "GET /a/{$}", want: true},
+		{p1: "GET /", p2: "GET /{$}", want: false},
+		{p1: "GET /v1/user", p2: "GET /v1/user/{id}", want: false},
+		{p1: "GET example.com/v1/{id}", p2: "GET /v1/{id}", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.p1+" vs "+tt.p2, func(t *testing.T) {
+			p1, err := ParseRoutePattern(tt.p1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p2, err := ParseRoutePattern(tt.p2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := p1.ConflictsWith(p2); got != tt.want {
+				t.Errorf("expected conflict %v, got %v", tt.want, got)
+			}
+			if got := p2.ConflictsWith(p1); got != tt.want {
+				t.Errorf("expected symmetric conflict %v, got %v", tt.want, got)
+			}
+			if got := serveMuxConflicts(tt.p1, tt.p2); got != tt.want {
+				t.Errorf("http.ServeMux disagrees: conflict %v", got)
+			}
+		})
+	}
+}
+
+func TestRouteRegistry(t *testing.T) {
+	registry := NewRouteRegistry()
+	getUser := &Endpoint{FullName: "/leo.example.user.v1.User/GetUser", Method: "GET", Pattern: "/v1/user/{id}",
+		Request: "leo.example.user.v1.GetUserRequest", Response: "leo.example.user.v1.GetUserResponse"}
+	getMe := &Endpoint{FullName: "/leo.example.user.v1.User/GetMe", Method: "GET", Pattern: "/v1/user/me"}
+	getAccount := &Endpoint{FullName: "/leo.example.account.v1.Account/GetAccount", Method: "GET", Pattern: "/v1/user/{name}"}
+
+	router := http.NewServeMux()
+	if err := registry.DetectConflicts(router); err != nil {
+		t.Fatal(err)
+	}
+	registry.Handle(router, getUser, "GET /api/v1/user/{id}", http.NotFoundHandler())
+	registry.Handle(router, getMe, "GET /api/v1/user/me", http.NotFoundHandler())
+
+	_, err := registry.Register(router, getAccount, "GET /api/v1/user/{name}")
+	var conflict *RouteConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("expected a conflict, got %v", err)
+	}
+	if conflict.Existing.RPC != getUser.FullName || conflict.Route.RPC != getAccount.FullName {
+		t.Fatalf("unexpected conflict %v", conflict)
+	}
+	want := `goose: route "GET /api/v1/user/{name}" of /leo.example.account.v1.Account/GetAccount conflicts with route "GET /api/v1/user/{id}" of /leo.example.user.v1.User/GetUser`
+	if conflict.Error() != want {
+		t.Fatalf("unexpected message %q", conflict.Error())
+	}
+
+	// the same routes on another router do not conflict and are listed once
+	registry.Handle(http.NewServeMux(), getUser, "GET /api/v1/user/{id}", http.NotFoundHandler())
+	routes := registry.Routes()
+	if len(routes) != 2 {
+		t.Fatalf("expected 2 routes, got %v", routes)
+	}
+	if routes[0] != (Route{Method: "GET", Path: "/api/v1/user/{id}", RPC: getUser.FullName, Request: getUser.Request, Response: getUser.Response}) {
+		t.Fatalf("unexpected route %+v", routes[0])
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected Handle to panic on a conflict")
+			}
+		}()
+		registry.Handle(router, getAccount, "GET /api/v1/user/{name}", http.NotFoundHandler())
+	}()
+
+	rr := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/debug/goose/routes", nil))
+	var listed []Route
+	if err := json.Unmarshal(rr.Body.Bytes(), &listed); err != nil {
+		t.Fatal(err)
+	}
+	if len(listed) != 2 || listed[1].RPC != getMe.FullName {
+		t.Fatalf("unexpected listed routes %+v", listed)
+	}
+}
+
+// funcRouter is a router that is not comparable
+type funcRouter struct {
+	handle func(pattern string, handler http.Handler)
+}
+
+func (r funcRouter) Handle(pattern string, handler http.Handler) {
+	r.handle(pattern, handler)
+}
+
+func TestRouteRegistryDetectConflicts(t *testing.T) {
+	getUser := &Endpoint{FullName: "/leo.example.user.v1.User/GetUser", Method: "GET", Pattern: "/v1/user/{id}"}
+	getAccount := &Endpoint{FullName: "/leo.example.account.v1.Account/GetAccount", Method: "GET", Pattern: "/v1/user/{name}"}
+	var conflict *RouteConflictError
+
+	// the routers that do not detect conflicts are not kept
+	registry := NewRouteRegistry()
+	router := &patternRouter{}
+	registry.Handle(router, getUser, "GET /v1/user/{id}", http.NotFoundHandler())
+	registry.Handle(router, getAccount, "GET /v1/user/{name}", http.NotFoundHandler())
+	if len(registry.routers) != 0 {
+		t.Fatalf("routers = %v, want none", registry.routers)
+	}
+	if len(registry.Routes()) != 2 {
+		t.Fatalf("routes = %v, want 2", registry.Routes())
+	}
+
+	// a non-comparable router can be used but cannot detect conflicts
+	var patterns []string
+	funcs := funcRouter{handle: func(pattern string, handler http.Handler) { patterns = append(patterns, pattern) }}
+	if err := registry.DetectConflicts(funcs); err == nil {
+		t.Fatal("expected an error for a non-comparable router")
+	}
+	registry.Handle(funcs, getUser, "GET /v1/user/{id}", http.NotFoundHandler())
+	if len(patterns) != 1 {
+		t.Fatalf("patterns = %v, want 1", patterns)
+	}
+
+	// a forgotten router is released and no longer detects conflicts
+	mux := http.NewServeMux()
+	if err := registry.DetectConflicts(mux); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := registry.Register(mux, getUser, "GET /v1/user/{id}"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := registry.Register(mux, getAccount, "GET /v1/user/{name}"); !errors.As(err, &conflict) {
+		t.Fatalf("expected a conflict, got %v", err)
+	}
+	registry.Forget(mux)
+	if len(registry.routers) != 0 {
+		t.Fatalf("routers = %v, want none", registry.routers)
+	}
+	if _, err := registry.Register(mux, getAccount, "GET /v1/user/{name}"); err != nil {
+		t.Fatal(err)
+	}
+	registry.Forget(funcs)
+}
+
+func TestRouteRegistryCollectConflicts(t *testing.T) {
+	getUser := &Endpoint{FullName: "/leo.example.user.v1.User/GetUser", Method: "GET", Pattern: "/v1/user/{id}"}
+	getAccount := &Endpoint{FullName: "/leo.example.account.v1.Account/GetAccount", Method: "GET", Pattern: "/v1/user/{name}"}
+	getMe := &Endpoint{FullName: "/leo.example.user.v1.User/GetMe", Method: "GET", Pattern: "/v1/user/me"}
+
+	registry := NewRouteRegistry()
+	router := &patternRouter{}
+	if err := registry.CollectConflicts(router); err != nil {
+		t.Fatal(err)
+	}
+	registry.Handle(router, getUser, "GET /v1/user/{id}", http.NotFoundHandler())
+	registry.Handle(router, getAccount, "GET /v1/user/{name}", http.NotFoundHandler())
+	registry.Handle(router, getMe, "GET /v1/user/me", http.NotFoundHandler())
+
+	// the conflicting route is skipped, the others are registered
+	if want := []string{"GET /v1/user/{id}", "GET /v1/user/me"}; !slices.Equal(router.patterns, want) {
+		t.Fatalf("patterns = %v, want %v", router.patterns, want)
+	}
+	err := registry.Conflicts(router)
+	var conflict *RouteConflictError
+	if !errors.As(err, &conflict) || conflict.Route.RPC != getAccount.FullName || conflict.Existing.RPC != getUser.FullName {
+		t.Fatalf("Conflicts() = %v, want the conflict of GetAccount with GetUser", err)
+	}
+	if err := registry.Conflicts(http.NewServeMux()); err != nil {
+		t.Fatalf("Conflicts() of a router not collecting = %v, want nil", err)
+	}
+
+	// the invalid patterns still panic
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected Handle to panic on an invalid pattern")
+			}
+		}()
+		registry.Handle(router, getMe, "GET /v1/user/{id", http.NotFoundHandler())
+	}()
+
+	registry.Forget(router)
+	if err := registry.Conflicts(router); err != nil {
+		t.Fatalf("Conflicts() after Forget = %v, want nil", err)
+	}
+}
+
+func TestAppendRoutes(t *testing.T) {
+	router := &patternRouter{}
+	if got := AppendRoutes(router); got != router {
+		t.Fatal("expected the router to be returned")
+	}
+	if want := []string{"GET /debug/goose/routes"}; !slices.Equal(router.patterns, want) {
+		t.Fatalf("patterns = %v, want %v", router.patterns, want)
+	}
+}
+
+func TestRouteRegistryEndpoint(t *testing.T) {
+	getUser := &Endpoint{FullName: "/leo.example.user.v1.User/GetUser", Method: "GET", Pattern: "/v1/user/{id}"}
+	var got *Endpoint
+	handler := http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		got, _ = EndpointFromContext(request.Context())
+	})
+	router := http.NewServeMux()
+	registry := NewRouteRegistry()
+	registry.Handle(router, getUser, "GET /api/v1/user/{id}", handler)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/user/1", nil))
+	if got == nil {
+		t.Fatal("no endpoint in the context")
+	}
+	// the pattern of the endpoint is the path of the route, with the prefix
+	if got.Pattern != "/api/v1/user/{id}" || got.Route() != "GET "+registry.Routes()[0].Path {
+		t.Fatalf("endpoint = %+v, want the pattern %q", got, registry.Routes()[0].Path)
+	}
+	if got.FullName != getUser.FullName || getUser.Pattern != "/v1/user/{id}" {
+		t.Fatalf("endpoint = %+v, the generated endpoint = %+v", got, getUser)
+	}
+}
@@ -0,0 +1,222 @@
+package server
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-leo/goose"
+)
+
+// DefaultMaxDecompressedBytes is the default limit of a decompressed request body, 32 MiB.
+const DefaultMaxDecompressedBytes int64 = 32 << 20
+
+// Decompressor creates a reader that decompresses r, for a given Content-Encoding.
+//
+// Parameters:
+//   - r: The compressed request body
+//
+// Returns:
+//   - io.ReadCloser: The decompressed request body
+//   - error: If the compressed stream cannot be read
+type Decompressor func(r io.Reader) (io.ReadCloser, error)
+
+// GzipDecompressor decompresses "gzip" request bodies.
+func GzipDecompressor(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+// DeflateDecompressor decompresses "deflate" request bodies.
+// HTTP deflate is the zlib format (RFC 1950), bodies without a valid zlib header
+// are read as raw DEFLATE, as sent by some non-conforming clients.
+func DeflateDecompressor(r io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+	if header, err := br.Peek(2); err == nil && !isZlibHeader(header) {
+		return flate.NewReader(br), nil
+	}
+	return zlib.NewReader(br)
+}
+
+// isZlibHeader reports whether header starts a zlib stream using the deflate method
+func isZlibHeader(header []byte) bool {
+	return header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0
+}
+
+// defaultDecompressors returns the decompressors registered by default
+func defaultDecompressors() map[string]Decompressor {
+	return map[string]Decompressor{
+		"gzip":    GzipDecompressor,
+		"x-gzip":  GzipDecompressor,
+		"deflate": DeflateDecompressor,
+	}
+}
+
+// bodyTooLargeError is returned when a request body exceeds its limit.
+// It is encoded as 413 Request Entity Too Large.
+type bodyTooLargeError struct {
+	limit int64 // The exceeded limit in bytes
+}
+
+// Error returns the error message
+func (e *bodyTooLargeError) Error() string {
+	return fmt.Sprintf("goose: request body too large, limit %d bytes", e.limit)
+}
+
+// StatusCode returns 413 Request Entity Too Large
+func (e *bodyTooLargeError) StatusCode() int {
+	return http.StatusRequestEntityTooLarge
+}
+
+// limitedBody reads at most limit bytes and fails with bodyTooLargeError beyond it
+type limitedBody struct {
+	body  io.ReadCloser // The underlying body
+	limit int64         // The maximum number of bytes
+	n     int64         // Remaining bytes before the limit is reached
+}
+
+// newLimitedBody wraps body so that reading more than limit bytes fails
+func newLimitedBody(body io.ReadCloser, limit int64) *limitedBody {
+	return &limitedBody{body: body, limit: limit, n: limit}
+}
+
+// Read reads from the underlying body, failing once more than limit bytes are read
+func (l *limitedBody) Read(p []byte) (int, error) {
+	if l.n < 0 {
+		return 0, &bodyTooLargeError{limit: l.limit}
+	}
+	// Read one byte past the limit to detect bodies exceeding it
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+	n, err := l.body.Read(p)
+	l.n -= int64(n)
+	if l.n < 0 {
+		return n + int(l.n), &bodyTooLargeError{limit: l.limit}
+	}
+	return n, err
+}
+
+// Close closes the underlying body
+func (l *limitedBody) Close() error {
+	return l.body.Close()
+}
+
+// decompressedBody closes both the decompressor and the compressed body
+type decompressedBody struct {
+	io.Reader
+	closers []io.Closer
+}
+
+// Close closes the decompressors and the compressed body
+func (d *decompressedBody) Close() error {
+	var err error
+	for _, closer := range d.closers {
+		if closeErr := closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// requestBodyMiddleware creates a middleware that limits and decompresses request bodies
+// Parameters:
+//   - o: The server options holding the limits, decompressors and error encoder
+//
+// Returns:
+//   - Middleware: The request body middleware
+//
+// Behavior:
+//  1. Rejects requests whose Content-Length exceeds MaxRequestBytes with 413
+//  2. Limits the raw request body to MaxRequestBytes
+//  3. Decompresses the body according to Content-Encoding, rejecting unknown encodings with 415
+//  4. Limits the decompressed body to MaxDecompressedBytes
+//
+// Streamed request bodies of goose.HttpBodyStream endpoints are not limited,
+// and are left encoded, with their Content-Encoding header, when an encoding is unknown.
+func requestBodyMiddleware(o *options) Middleware {
+	return func(response http.ResponseWriter, request *http.Request, invoker http.HandlerFunc) {
+		if request.Body == nil || request.Body == http.NoBody {
+			invoker(response, request)
+			return
+		}
+		ctx := request.Context()
+		endpoint, _ := goose.EndpointFromContext(ctx)
+		streaming := endpoint != nil && endpoint.StreamingRequest
+		if o.maxRequestBytes > 0 && !streaming {
+			if request.ContentLength > o.maxRequestBytes {
+				o.errorEncoder(ctx, &bodyTooLargeError{limit: o.maxRequestBytes}, response)
+				return
+			}
+			request.Body = newLimitedBody(request.Body, o.maxRequestBytes)
+		}
+		encodings := contentEncodings(request.Header)
+		if len(encodings) > 0 && (!streaming || canDecompress(encodings, o.decompressors)) {
+			body, err := decompress(request.Body, encodings, o.decompressors)
+			if err != nil {
+				o.errorEncoder(ctx, err, response)
+				return
+			}
+			if o.maxDecompressedBytes > 0 && !streaming {
+				body = newLimitedBody(body, o.maxDecompressedBytes)
+			}
+			request.Body = body
+			request.Header.Del("Content-Encoding")
+			request.Header.Del("Content-Length")
+			request.ContentLength = -1
+		}
+		invoker(response, request)
+	}
+}
+
+// canDecompress reports whether all the encodings have a decompressor
+func canDecompress(encodings []string, decompressors map[string]Decompressor) bool {
+	for _, encoding := range encodings {
+		if decompressors[encoding] == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// contentEncodings returns the Content-Encoding codings of the header, ignoring "identity"
+func contentEncodings(header http.Header) []string {
+	var encodings []string
+	for _, value := range header.Values("Content-Encoding") {
+		for _, encoding := range strings.Split(value, ",") {
+			encoding = strings.ToLower(strings.TrimSpace(encoding))
+			if encoding == "" || encoding == "identity" {
+				continue
+			}
+			encodings = append(encodings, encoding)
+		}
+	}
+	return encodings
+}
+
+// decompress undoes the encodings in reverse order of application
+func decompress(body io.ReadCloser, encodings []string, decompressors map[string]Decompressor) (io.ReadCloser, error) {
+	result := &decompressedBody{Reader: body, closers: []io.Closer{body}}
+	for i := len(encodings) - 1; i >= 0; i-- {
+		decompressor, ok := decompressors[encodings[i]]
+		if !ok || decompressor == nil {
+			return nil, goose.NewError(http.StatusUnsupportedMediaType, fmt.Sprintf("goose: unsupported content encoding %q", encodings[i]))
+		}
+		reader, err := decompressor(result.Reader)
+		var tooLarge *bodyTooLargeError
+		if errors.As(err, &tooLarge) {
+			return nil, tooLarge
+		}
+		if err != nil {
+			return nil, goose.NewError(http.StatusBadRequest, fmt.Sprintf("goose: invalid %s request body: %s", encodings[i], err))
+		}
+		result.Reader = reader
+		result.closers = append([]io.Closer{reader}, result.closers...)
+	}
+	return result, nil
+}
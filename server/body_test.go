@@ -0,0 +1,177 @@
+package server
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-leo/goose"
+)
+
+// serveBody runs the request body middleware of opts and returns the recorder and the body read by the handler
+func serveBody(t *testing.T, request *http.Request, opts ...Option) (*httptest.ResponseRecorder, string) {
+	t.Helper()
+	o := NewOptions(opts...).(*options)
+	rec := httptest.NewRecorder()
+	var body string
+	Invoke(Chain(o.Middlewares()...), rec, request, func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			o.errorEncoder(r.Context(), err, w)
+			return
+		}
+		body = string(data)
+	})
+	return rec, body
+}
+
+func gzipData(t *testing.T, data string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, _ = w.Write([]byte(data))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestRequestBodyPassThrough(t *testing.T) {
+	request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("hello"))
+	rec, body := serveBody(t, request)
+	if rec.Code != http.StatusOK || body != "hello" {
+		t.Errorf("status = %d, body = %q, want 200 hello", rec.Code, body)
+	}
+}
+
+func TestMaxRequestBytesContentLength(t *testing.T) {
+	request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("hello world"))
+	rec, _ := serveBody(t, request, MaxRequestBytes(5))
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want 413", rec.Code)
+	}
+}
+
+func TestMaxRequestBytesChunked(t *testing.T) {
+	request := httptest.NewRequest(http.MethodPost, "/", io.MultiReader(strings.NewReader("hello world")))
+	request.ContentLength = -1
+	rec, _ := serveBody(t, request, MaxRequestBytes(5))
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want 413", rec.Code)
+	}
+
+	request = httptest.NewRequest(http.MethodPost, "/", io.MultiReader(strings.NewReader("hello")))
+	request.ContentLength = -1
+	rec, body := serveBody(t, request, MaxRequestBytes(5))
+	if rec.Code != http.StatusOK || body != "hello" {
+		t.Errorf("status = %d, body = %q, want 200 hello", rec.Code, body)
+	}
+}
+
+func TestGzipRequest(t *testing.T) {
+	request := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(gzipData(t, "hello")))
+	request.Header.Set("Content-Encoding", "gzip")
+	rec, body := serveBody(t, request)
+	if rec.Code != http.StatusOK || body != "hello" {
+		t.Errorf("status = %d, body = %q, want 200 hello", rec.Code, body)
+	}
+	if request.Header.Get("Content-Encoding") != "" {
+		t.Error("Content-Encoding header should be removed")
+	}
+}
+
+func TestDeflateRequest(t *testing.T) {
+	var zlibBuf bytes.Buffer
+	zw := zlib.NewWriter(&zlibBuf)
+	_, _ = zw.Write([]byte("hello"))
+	_ = zw.Close()
+	var rawBuf bytes.Buffer
+	fw, _ := flate.NewWriter(&rawBuf, flate.DefaultCompression)
+	_, _ = fw.Write([]byte("hello"))
+	_ = fw.Close()
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "zlib", data: zlibBuf.Bytes()},
+		{name: "raw deflate", data: rawBuf.Bytes()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(tt.data))
+			request.Header.Set("Content-Encoding", "deflate")
+			rec, body := serveBody(t, request)
+			if rec.Code != http.StatusOK || body != "hello" {
+				t.Errorf("status = %d, body = %q, want 200 hello", rec.Code, body)
+			}
+		})
+	}
+}
+
+func TestMaxDecompressedBytes(t *testing.T) {
+	bomb := gzipData(t, strings.Repeat("a", 1<<20))
+	request := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(bomb))
+	request.Header.Set("Content-Encoding", "gzip")
+	rec, _ := serveBody(t, request, MaxRequestBytes(int64(len(bomb))), MaxDecompressedBytes(1024))
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want 413", rec.Code)
+	}
+}
+
+func TestUnsupportedContentEncoding(t *testing.T) {
+	request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("hello"))
+	request.Header.Set("Content-Encoding", "br")
+	rec, _ := serveBody(t, request)
+	if rec.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("status = %d, want 415", rec.Code)
+	}
+
+	request = httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(gzipData(t, "hello")))
+	request.Header.Set("Content-Encoding", "gzip")
+	rec, _ = serveBody(t, request, Decompression("gzip", nil))
+	if rec.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("status = %d, want 415", rec.Code)
+	}
+}
+
+func TestCustomDecompression(t *testing.T) {
+	request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("HELLO"))
+	request.Header.Set("Content-Encoding", "Lower")
+	rec, body := serveBody(t, request, Decompression("lower", func(r io.Reader) (io.ReadCloser, error) {
+		data, err := io.ReadAll(r)
+		return io.NopCloser(strings.NewReader(strings.ToLower(string(data)))), err
+	}))
+	if rec.Code != http.StatusOK || body != "hello" {
+		t.Errorf("status = %d, body = %q, want 200 hello", rec.Code, body)
+	}
+}
+
+func TestStreamingRequestBody(t *testing.T) {
+	streaming := &goose.Endpoint{Method: http.MethodPost, Pattern: "/v1/files", StreamingRequest: true}
+	large := strings.Repeat("a", 1<<20)
+
+	request := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(gzipData(t, large)))
+	request = request.WithContext(goose.WithEndpoint(request.Context(), streaming))
+	request.Header.Set("Content-Encoding", "gzip")
+	rec, body := serveBody(t, request, MaxRequestBytes(1024), MaxDecompressedBytes(1024))
+	if rec.Code != http.StatusOK || body != large {
+		t.Errorf("status = %d, body length = %d, want 200 and %d bytes", rec.Code, len(body), len(large))
+	}
+
+	request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("hello"))
+	request = request.WithContext(goose.WithEndpoint(request.Context(), streaming))
+	request.Header.Set("Content-Encoding", "br")
+	rec, body = serveBody(t, request)
+	if rec.Code != http.StatusOK || body != "hello" {
+		t.Errorf("status = %d, body = %q, want 200 hello", rec.Code, body)
+	}
+	if request.Header.Get("Content-Encoding") != "br" {
+		t.Error("Content-Encoding header should be kept")
+	}
+}
@@ -109,3 +109,21 @@ func DecodeHttpRequest(ctx context.Context, request *http.Request, req *rpchttp.
 	req.Body = data
 	return nil
 }
+
+// DecodeHttpBodyStream decodes HTTP request into HttpBodyStream object without reading the body
+// Parameters:
+//   - ctx: Context object
+//   - request: HTTP request object
+//   - body: Target HttpBodyStream object
+//
+// Returns:
+//   - error: Always nil
+//
+// Behavior:
+//  1. Sets HttpBodyStream's ContentType field
+//  2. Hands the request body over to the service, which reads it while handling the request
+func DecodeHttpBodyStream(ctx context.Context, request *http.Request, body *goose.HttpBodyStream) error {
+	body.ContentType = request.Header.Get(goose.ContentTypeKey)
+	body.Body = request.Body
+	return nil
+}
@@ -105,3 +105,22 @@ func TestDecodeHttpRequest(t *testing.T) {
 		t.Errorf("req.Headers missing expected values: %v", req.Headers)
 	}
 }
+
+func TestDecodeHttpBodyStream(t *testing.T) {
+	data := "abc"
+	r := &http.Request{
+		Body:   io.NopCloser(strings.NewReader(data)),
+		Header: http.Header{goose.ContentTypeKey: []string{"application/test"}},
+	}
+	body := &goose.HttpBodyStream{}
+	err := DecodeHttpBodyStream(context.Background(), r, body)
+	if err != nil {
+		t.Fatalf("DecodeHttpBodyStream error: %v", err)
+	}
+	if body.Body != r.Body {
+		t.Error("body.Body is not the request body")
+	}
+	if body.ContentType != "application/test" {
+		t.Errorf("body.ContentType = %q, want application/test", body.ContentType)
+	}
+}
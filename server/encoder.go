@@ -2,6 +2,8 @@ package server
 
 import (
 	"context"
+	"errors"
+	"io"
 	"net/http"
 
 	"github.com/go-leo/goose"
@@ -12,7 +14,9 @@ import (
 )
 
 // EncodeResponse encodes a protobuf message as JSON into an HTTP response.
-// Sets Content-Type to application/json and status code to 200 OK.
+// Sets Content-Type to application/json and status code to 200 OK,
+// unless the service changed them with goose.SetStatus or goose.SetHeader.
+// No body is written for statuses that do not allow one, such as 204 No Content.
 //
 // Parameters:
 //
@@ -25,9 +29,26 @@ import (
 //
 //	error - if encoding or writing fails
 func EncodeResponse(ctx context.Context, response http.ResponseWriter, resp proto.Message, marshalOptions protojson.MarshalOptions) error {
-	// Set response headers for JSON content and HTTP 200 status
+	return EncodeResponseWithStatus(ctx, response, resp, http.StatusOK, marshalOptions)
+}
+
+// EncodeResponseWithStatus is like EncodeResponse, but uses status as the default status code.
+// It is used by methods declaring a success status with the (leo.goose.response) option.
+//
+// Parameters:
+//
+//	ctx - context.Context for the request
+//	response - http.ResponseWriter to write the response
+//	resp - proto.Message to encode
+//	status - default HTTP status code of the response
+//	marshalOptions - protojson.MarshalOptions for JSON encoding
+//
+// Returns:
+//
+//	error - if encoding or writing fails
+func EncodeResponseWithStatus(ctx context.Context, response http.ResponseWriter, resp proto.Message, status int, marshalOptions protojson.MarshalOptions) error {
+	// Set response headers for JSON content
 	response.Header().Set(goose.ContentTypeKey, goose.JsonContentType)
-	response.WriteHeader(http.StatusOK)
 
 	// Marshal the protocol buffer message into JSON
 	data, err := marshalOptions.Marshal(resp)
@@ -35,16 +56,22 @@ func EncodeResponse(ctx context.Context, response http.ResponseWriter, resp prot
 		return err
 	}
 
+	if status := writeHeader(ctx, response, status); !goose.BodyAllowedForStatus(status) {
+		return nil
+	}
+
 	// Write the JSON data to the response body
 	if _, err := response.Write(data); err != nil {
 		return err
 	}
 
+	writeTrailer(ctx, response)
 	return nil
 }
 
 // EncodeHttpBody encodes an httpbody.HttpBody into an HTTP response.
-// Sets Content-Type from the HttpBody and status code to 200 OK.
+// Sets Content-Type from the HttpBody and status code to 200 OK,
+// unless the service changed them with goose.SetStatus or goose.SetHeader.
 //
 // Parameters:
 //
@@ -56,19 +83,99 @@ func EncodeResponse(ctx context.Context, response http.ResponseWriter, resp prot
 //
 //	error - if writing fails
 func EncodeHttpBody(ctx context.Context, response http.ResponseWriter, resp *httpbody.HttpBody) error {
+	return EncodeHttpBodyWithStatus(ctx, response, resp, http.StatusOK)
+}
+
+// EncodeHttpBodyWithStatus is like EncodeHttpBody, but uses status as the default status code.
+// It is used by methods declaring a success status with the (leo.goose.response) option.
+//
+// Parameters:
+//
+//	ctx - context.Context for the request
+//	response - http.ResponseWriter to write the response
+//	resp - *httpbody.HttpBody to encode
+//	status - default HTTP status code of the response
+//
+// Returns:
+//
+//	error - if writing fails
+func EncodeHttpBodyWithStatus(ctx context.Context, response http.ResponseWriter, resp *httpbody.HttpBody, status int) error {
 	// Set response headers
 	response.Header().Set(goose.ContentTypeKey, resp.GetContentType())
-	response.WriteHeader(http.StatusOK)
+	if status := writeHeader(ctx, response, status); !goose.BodyAllowedForStatus(status) {
+		return nil
+	}
 
 	// Write response data
 	if _, err := response.Write(resp.GetData()); err != nil {
 		return err
 	}
+	writeTrailer(ctx, response)
+	return nil
+}
+
+// EncodeHttpBodyStream streams a goose.HttpBodyStream into an HTTP response.
+// Sets Content-Type from the HttpBodyStream and status code to 200 OK,
+// unless the service changed them with goose.SetStatus or goose.SetHeader.
+// The body is copied to the response without being buffered, then closed.
+//
+// Parameters:
+//
+//	ctx - context.Context for the request
+//	response - http.ResponseWriter to write the response
+//	resp - *goose.HttpBodyStream to encode
+//
+// Returns:
+//
+//	error - if copying the body fails
+func EncodeHttpBodyStream(ctx context.Context, response http.ResponseWriter, resp *goose.HttpBodyStream) error {
+	return EncodeHttpBodyStreamWithStatus(ctx, response, resp, http.StatusOK)
+}
+
+// EncodeHttpBodyStreamWithStatus is like EncodeHttpBodyStream, but uses status as the default status code.
+// It is used by methods declaring a success status with the (leo.goose.response) option.
+//
+// Parameters:
+//
+//	ctx - context.Context for the request
+//	response - http.ResponseWriter to write the response
+//	resp - *goose.HttpBodyStream to encode
+//	status - default HTTP status code of the response
+//
+// Returns:
+//
+//	error - if copying the body fails
+func EncodeHttpBodyStreamWithStatus(ctx context.Context, response http.ResponseWriter, resp *goose.HttpBodyStream, status int) (err error) {
+	if resp.Body != nil {
+		defer func() {
+			err = errors.Join(err, resp.Body.Close())
+		}()
+	}
+
+	// Allow the response body to be written while the request body is still being read,
+	// such as when streaming the request back, ignoring writers that do not support it
+	_ = http.NewResponseController(response).EnableFullDuplex()
+
+	// Set response headers
+	response.Header().Set(goose.ContentTypeKey, resp.ContentType)
+	if status := writeHeader(ctx, response, status); !goose.BodyAllowedForStatus(status) {
+		return nil
+	}
+
+	// Stream response data
+	if resp.Body != nil {
+		if _, err := io.Copy(response, resp.Body); err != nil {
+			return err
+		}
+	}
+	writeTrailer(ctx, response)
 	return nil
 }
 
 // EncodeHttpResponse encodes an rpchttp.HttpResponse into an HTTP response.
 // Sets headers, status code and body from the HttpResponse.
+// Headers, trailers and status code set with goose.SetHeader, goose.SetTrailer
+// and goose.SetStatus are applied on top of it.
 //
 // Parameters:
 //
@@ -87,11 +194,62 @@ func EncodeHttpResponse(ctx context.Context, response http.ResponseWriter, resp
 	}
 
 	// Write HTTP status code before body
-	response.WriteHeader(int(resp.GetStatus()))
+	if status := writeHeader(ctx, response, int(resp.GetStatus())); !goose.BodyAllowedForStatus(status) {
+		return nil
+	}
 
 	// Write response body and return any write errors
 	if _, err := response.Write(resp.GetBody()); err != nil {
 		return err
 	}
+	writeTrailer(ctx, response)
 	return nil
 }
+
+// writeHeader applies the response metadata of the context and writes the status code.
+// The Content-Type header is removed for statuses that do not allow a body.
+//
+// Parameters:
+//
+//	ctx - context.Context that may carry goose.ResponseMetadata
+//	response - http.ResponseWriter to write the header to
+//	status - default status code, used if the service did not call goose.SetStatus
+//
+// Returns:
+//
+//	int - the status code written
+func writeHeader(ctx context.Context, response http.ResponseWriter, status int) int {
+	if md, ok := goose.ResponseMetadataFromContext(ctx); ok {
+		header := response.Header()
+		for key, values := range md.Header() {
+			header[key] = values
+		}
+		if code := md.Status(); code != 0 {
+			status = code
+		}
+	}
+	if !goose.BodyAllowedForStatus(status) {
+		response.Header().Del(goose.ContentTypeKey)
+	}
+	response.WriteHeader(status)
+	return status
+}
+
+// writeTrailer sends the trailers set with goose.SetTrailer after the response body.
+//
+// Parameters:
+//
+//	ctx - context.Context that may carry goose.ResponseMetadata
+//	response - http.ResponseWriter to write the trailers to
+func writeTrailer(ctx context.Context, response http.ResponseWriter) {
+	md, ok := goose.ResponseMetadataFromContext(ctx)
+	if !ok {
+		return
+	}
+	header := response.Header()
+	for key, values := range md.Trailer() {
+		for _, value := range values {
+			header.Add(http.TrailerPrefix+key, value)
+		}
+	}
+}
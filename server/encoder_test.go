@@ -100,3 +100,104 @@ func TestEncodeHttpResponse(t *testing.T) {
 		t.Errorf("body = %q, want %q", body, "abc")
 	}
 }
+
+func TestEncodeResponseWithResponseMetadata(t *testing.T) {
+	rr := httptest.NewRecorder()
+	ctx := goose.NewResponseMetadataContext(context.Background())
+	_ = goose.SetStatus(ctx, http.StatusCreated)
+	_ = goose.SetHeader(ctx, "Location", "/v1/users/1")
+	_ = goose.SetTrailer(ctx, "X-Checksum", "abc")
+	err := EncodeResponse(ctx, rr, &httpbody.HttpBody{ContentType: "application/test"}, protojson.MarshalOptions{})
+	if err != nil {
+		t.Fatalf("EncodeResponse error: %v", err)
+	}
+	resp := rr.Result()
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("status = %d, want 201", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Location"); got != "/v1/users/1" {
+		t.Errorf("Location = %q, want /v1/users/1", got)
+	}
+	if got := resp.Trailer.Get("X-Checksum"); got != "abc" {
+		t.Errorf("X-Checksum trailer = %q, want abc", got)
+	}
+}
+
+func TestEncodeResponseNoContent(t *testing.T) {
+	rr := httptest.NewRecorder()
+	ctx := goose.NewResponseMetadataContext(context.Background())
+	_ = goose.SetStatus(ctx, http.StatusNoContent)
+	err := EncodeResponse(ctx, rr, &httpbody.HttpBody{ContentType: "application/test"}, protojson.MarshalOptions{})
+	if err != nil {
+		t.Fatalf("EncodeResponse error: %v", err)
+	}
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("status = %d, want 204", rr.Code)
+	}
+	if rr.Body.Len() != 0 {
+		t.Errorf("body = %q, want empty", rr.Body.String())
+	}
+}
+
+func TestEncodeResponseWithStatus(t *testing.T) {
+	rr := httptest.NewRecorder()
+	err := EncodeResponseWithStatus(context.Background(), rr, &httpbody.HttpBody{ContentType: "application/test"}, http.StatusNoContent, protojson.MarshalOptions{})
+	if err != nil {
+		t.Fatalf("EncodeResponseWithStatus error: %v", err)
+	}
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("status = %d, want 204", rr.Code)
+	}
+	if rr.Body.Len() != 0 {
+		t.Errorf("body = %q, want empty", rr.Body.String())
+	}
+	if ct := rr.Header().Get(goose.ContentTypeKey); ct != "" {
+		t.Errorf("Content-Type = %q, want empty", ct)
+	}
+}
+
+func TestEncodeHttpBodyWithStatus(t *testing.T) {
+	rr := httptest.NewRecorder()
+	err := EncodeHttpBodyWithStatus(context.Background(), rr, &httpbody.HttpBody{ContentType: "application/test", Data: []byte("hello")}, http.StatusAccepted)
+	if err != nil {
+		t.Fatalf("EncodeHttpBodyWithStatus error: %v", err)
+	}
+	if rr.Code != http.StatusAccepted {
+		t.Errorf("status = %d, want 202", rr.Code)
+	}
+	if rr.Body.String() != "hello" {
+		t.Errorf("body = %q, want hello", rr.Body.String())
+	}
+}
+
+func TestEncodeHttpBodyStream(t *testing.T) {
+	rr := httptest.NewRecorder()
+	body := &closeRecorder{Reader: bytes.NewBufferString("hello")}
+	err := EncodeHttpBodyStream(context.Background(), rr, &goose.HttpBodyStream{ContentType: "text/plain", Body: body})
+	if err != nil {
+		t.Fatalf("EncodeHttpBodyStream error: %v", err)
+	}
+	if rr.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", rr.Code)
+	}
+	if ct := rr.Header().Get(goose.ContentTypeKey); ct != "text/plain" {
+		t.Errorf("Content-Type = %q, want text/plain", ct)
+	}
+	if rr.Body.String() != "hello" {
+		t.Errorf("body = %q, want hello", rr.Body.String())
+	}
+	if !body.closed {
+		t.Error("body was not closed")
+	}
+}
+
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
@@ -0,0 +1,210 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+
+	"github.com/go-leo/goose"
+	"google.golang.org/genproto/googleapis/api/httpbody"
+)
+
+// DefaultMaxMultipartMemory is the maximum number of bytes of a multipart form kept in memory,
+// the remaining file parts are stored in temporary files, 32 MiB.
+const DefaultMaxMultipartMemory int64 = 32 << 20
+
+// IsFormRequest reports whether the request body is a URL-encoded or a multipart form
+// Parameters:
+//   - request: HTTP request object
+//
+// Returns:
+//   - bool: True if the Content-Type is application/x-www-form-urlencoded or multipart/form-data
+func IsFormRequest(request *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(request.Header.Get(goose.ContentTypeKey))
+	if err != nil {
+		return false
+	}
+	return mediaType == goose.FormContentType || mediaType == goose.MultipartFormContentType
+}
+
+// DecodeForm parses a URL-encoded or multipart form request body
+// Parameters:
+//   - ctx: Context object
+//   - request: HTTP request object
+//
+// Returns:
+//   - url.Values: The form values of the request body, file parts excluded
+//   - error: 400 Bad Request if the form is malformed
+//
+// Behavior:
+//  1. Parses multipart forms, keeping up to DefaultMaxMultipartMemory bytes in memory
+//  2. Parses URL-encoded forms otherwise
+//  3. File parts are then available to GetFormFile and GetFormHttpBody,
+//     RemoveForm removes their temporary files once they are read
+func DecodeForm(ctx context.Context, request *http.Request) (url.Values, error) {
+	mediaType, _, _ := mime.ParseMediaType(request.Header.Get(goose.ContentTypeKey))
+	var err error
+	if mediaType == goose.MultipartFormContentType {
+		err = request.ParseMultipartForm(DefaultMaxMultipartMemory)
+	} else {
+		err = request.ParseForm()
+	}
+	if err != nil {
+		var tooLarge *bodyTooLargeError
+		if errors.As(err, &tooLarge) {
+			return nil, tooLarge
+		}
+		return nil, goose.NewError(http.StatusBadRequest, fmt.Sprintf("goose: invalid form request body: %s", err))
+	}
+	return request.PostForm, nil
+}
+
+// RemoveForm removes the temporary files of a multipart form parsed by DecodeForm.
+// net/http only removes them for the request it received, not for the copies made by
+// WithContext in the middlewares, so the decoders remove them once the files are read.
+// Parameters:
+//   - request: HTTP request object, whose form is parsed by DecodeForm
+func RemoveForm(request *http.Request) {
+	if request.MultipartForm == nil {
+		return
+	}
+	if err := request.MultipartForm.RemoveAll(); err != nil {
+		log.Println("goose: failed to remove the temporary files of the form: ", err)
+	}
+}
+
+// GetFormFile reads the first file part of a form field
+// Parameters:
+//   - pre: Pre-existing error (if any, will be returned immediately)
+//   - request: HTTP request object, whose form is parsed by DecodeForm
+//   - key: Form field key
+//
+// Returns:
+//   - []byte: The content of the file, or the field value if the field is not a file, nil if absent
+//   - error: Reading error if any
+func GetFormFile(pre error, request *http.Request, key string) ([]byte, error) {
+	return goose.BreakOnError[[]byte](pre)(func() ([]byte, error) {
+		files := formFiles(request, key)
+		if len(files) > 0 {
+			return readFormFile(files[0])
+		}
+		if values, ok := request.PostForm[key]; ok && len(values) > 0 {
+			return []byte(values[0]), nil
+		}
+		return nil, nil
+	})
+}
+
+// GetFormFiles reads all file parts of a form field
+// Parameters:
+//   - pre: Pre-existing error (if any, will be returned immediately)
+//   - request: HTTP request object, whose form is parsed by DecodeForm
+//   - key: Form field key
+//
+// Returns:
+//   - [][]byte: The contents of the files, or the field values if the field is not a file
+//   - error: Reading error if any
+func GetFormFiles(pre error, request *http.Request, key string) ([][]byte, error) {
+	return goose.BreakOnError[[][]byte](pre)(func() ([][]byte, error) {
+		files := formFiles(request, key)
+		if len(files) == 0 {
+			values := request.PostForm[key]
+			if values == nil {
+				return nil, nil
+			}
+			r := make([][]byte, 0, len(values))
+			for _, value := range values {
+				r = append(r, []byte(value))
+			}
+			return r, nil
+		}
+		r := make([][]byte, 0, len(files))
+		for _, file := range files {
+			data, err := readFormFile(file)
+			if err != nil {
+				return nil, err
+			}
+			r = append(r, data)
+		}
+		return r, nil
+	})
+}
+
+// GetFormHttpBody reads the first file part of a form field into an HttpBody
+// Parameters:
+//   - pre: Pre-existing error (if any, will be returned immediately)
+//   - request: HTTP request object, whose form is parsed by DecodeForm
+//   - key: Form field key
+//
+// Returns:
+//   - *httpbody.HttpBody: The content and content type of the file, nil if absent
+//   - error: Reading error if any
+func GetFormHttpBody(pre error, request *http.Request, key string) (*httpbody.HttpBody, error) {
+	return goose.BreakOnError[*httpbody.HttpBody](pre)(func() (*httpbody.HttpBody, error) {
+		files := formFiles(request, key)
+		if len(files) == 0 {
+			return nil, nil
+		}
+		return readFormHttpBody(files[0])
+	})
+}
+
+// GetFormHttpBodies reads all file parts of a form field into HttpBody objects
+// Parameters:
+//   - pre: Pre-existing error (if any, will be returned immediately)
+//   - request: HTTP request object, whose form is parsed by DecodeForm
+//   - key: Form field key
+//
+// Returns:
+//   - []*httpbody.HttpBody: The contents and content types of the files, nil if absent
+//   - error: Reading error if any
+func GetFormHttpBodies(pre error, request *http.Request, key string) ([]*httpbody.HttpBody, error) {
+	return goose.BreakOnError[[]*httpbody.HttpBody](pre)(func() ([]*httpbody.HttpBody, error) {
+		files := formFiles(request, key)
+		if len(files) == 0 {
+			return nil, nil
+		}
+		r := make([]*httpbody.HttpBody, 0, len(files))
+		for _, file := range files {
+			body, err := readFormHttpBody(file)
+			if err != nil {
+				return nil, err
+			}
+			r = append(r, body)
+		}
+		return r, nil
+	})
+}
+
+// formFiles returns the file parts of a form field, nil if the form is not multipart
+func formFiles(request *http.Request, key string) []*multipart.FileHeader {
+	if request.MultipartForm == nil {
+		return nil
+	}
+	return request.MultipartForm.File[key]
+}
+
+// readFormFile reads the content of a file part
+func readFormFile(header *multipart.FileHeader) ([]byte, error) {
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
+
+// readFormHttpBody reads the content and the content type of a file part
+func readFormHttpBody(header *multipart.FileHeader) (*httpbody.HttpBody, error) {
+	data, err := readFormFile(header)
+	if err != nil {
+		return nil, err
+	}
+	return &httpbody.HttpBody{ContentType: header.Header.Get(goose.ContentTypeKey), Data: data}, nil
+}
@@ -0,0 +1,191 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/go-leo/goose"
+)
+
+// multipartRequest builds a multipart/form-data request with the given values and files
+func multipartRequest(t *testing.T, values map[string]string, files map[string][]string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for key, value := range values {
+		if err := writer.WriteField(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for key, contents := range files {
+		for _, content := range contents {
+			header := textproto.MIMEHeader{}
+			header.Set("Content-Disposition", `form-data; name="`+key+`"; filename="`+key+`.txt"`)
+			header.Set("Content-Type", "text/plain")
+			part, err := writer.CreatePart(header)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, _ = part.Write([]byte(content))
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	request := httptest.NewRequest(http.MethodPost, "/", &body)
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+	return request
+}
+
+func TestIsFormRequest(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        bool
+	}{
+		{"application/x-www-form-urlencoded", true},
+		{"application/x-www-form-urlencoded; charset=utf-8", true},
+		{"multipart/form-data; boundary=x", true},
+		{"application/json", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		request := httptest.NewRequest(http.MethodPost, "/", nil)
+		request.Header.Set("Content-Type", tt.contentType)
+		if got := IsFormRequest(request); got != tt.want {
+			t.Errorf("IsFormRequest(%q) = %v, want %v", tt.contentType, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeFormURLEncoded(t *testing.T) {
+	request := httptest.NewRequest(http.MethodPost, "/?name=query", strings.NewReader("name=jax&tags=a&tags=b&note=hi"))
+	request.Header.Set("Content-Type", goose.FormContentType)
+	form, err := DecodeForm(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if form.Get("name") != "jax" || len(form["tags"]) != 2 {
+		t.Errorf("form = %v, want body values only", form)
+	}
+	note, err := GetFormFile(nil, request, "note")
+	if err != nil || string(note) != "hi" {
+		t.Errorf("GetFormFile = %q, %v, want the field value", note, err)
+	}
+	missing, err := GetFormFile(nil, request, "missing")
+	if err != nil || missing != nil {
+		t.Errorf("GetFormFile = %q, %v, want nil", missing, err)
+	}
+}
+
+func TestDecodeFormMultipart(t *testing.T) {
+	request := multipartRequest(t, map[string]string{"name": "jax"}, map[string][]string{
+		"avatar": {"png"},
+		"docs":   {"a", "b"},
+	})
+	form, err := DecodeForm(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if form.Get("name") != "jax" {
+		t.Errorf("name = %q, want jax", form.Get("name"))
+	}
+	avatar, err := GetFormFile(nil, request, "avatar")
+	if err != nil || string(avatar) != "png" {
+		t.Errorf("GetFormFile = %q, %v, want png", avatar, err)
+	}
+	docs, err := GetFormFiles(nil, request, "docs")
+	if err != nil || len(docs) != 2 || string(docs[1]) != "b" {
+		t.Errorf("GetFormFiles = %q, %v, want [a b]", docs, err)
+	}
+	body, err := GetFormHttpBody(nil, request, "avatar")
+	if err != nil || body.GetContentType() != "text/plain" || string(body.GetData()) != "png" {
+		t.Errorf("GetFormHttpBody = %v, %v, want text/plain png", body, err)
+	}
+	bodies, err := GetFormHttpBodies(nil, request, "docs")
+	if err != nil || len(bodies) != 2 {
+		t.Errorf("GetFormHttpBodies = %v, %v, want 2 bodies", bodies, err)
+	}
+	body, err = GetFormHttpBody(nil, request, "name")
+	if err != nil || body != nil {
+		t.Errorf("GetFormHttpBody = %v, %v, want nil for a value", body, err)
+	}
+}
+
+func TestGetFormFilePreError(t *testing.T) {
+	pre := errors.New("pre")
+	request := multipartRequest(t, nil, map[string][]string{"avatar": {"png"}})
+	if _, err := DecodeForm(context.Background(), request); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetFormFile(pre, request, "avatar"); err != pre {
+		t.Errorf("err = %v, want pre", err)
+	}
+	if _, err := GetFormHttpBodies(pre, request, "avatar"); err != pre {
+		t.Errorf("err = %v, want pre", err)
+	}
+}
+
+func TestDecodeFormInvalid(t *testing.T) {
+	request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("--x\r\nbroken"))
+	request.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+	_, err := DecodeForm(context.Background(), request)
+	var statusErr interface{ StatusCode() int }
+	if !errors.As(err, &statusErr) || statusErr.StatusCode() != http.StatusBadRequest {
+		t.Errorf("err = %v, want 400", err)
+	}
+}
+
+func TestDecodeFormTooLarge(t *testing.T) {
+	request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("name="+strings.Repeat("x", 64)))
+	request.Header.Set("Content-Type", goose.FormContentType)
+	request.ContentLength = -1
+	request.Body = newLimitedBody(request.Body, 16)
+	_, err := DecodeForm(context.Background(), request)
+	var tooLarge *bodyTooLargeError
+	if !errors.As(err, &tooLarge) {
+		t.Errorf("err = %v, want bodyTooLargeError", err)
+	}
+}
+
+func TestRemoveForm(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+	large := strings.Repeat("a", int(DefaultMaxMultipartMemory)+1)
+	original := multipartRequest(t, map[string]string{"name": "bob"}, map[string][]string{"file": {large}})
+	// the middlewares decode a copy of the request received by net/http
+	request := original.WithContext(context.Background())
+	if _, err := DecodeForm(request.Context(), request); err != nil {
+		t.Fatal(err)
+	}
+	files, _ := os.ReadDir(tmp)
+	if len(files) == 0 {
+		t.Fatal("expected the large file part to be stored in a temporary file")
+	}
+	data, err := GetFormFile(nil, request, "file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != len(large) {
+		t.Fatalf("file has %d bytes, want %d", len(data), len(large))
+	}
+	RemoveForm(request)
+	if files, _ := os.ReadDir(tmp); len(files) != 0 {
+		t.Errorf("temporary files left: %v", files)
+	}
+
+	// a URL-encoded form has no temporary file
+	request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("name=bob"))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if _, err := DecodeForm(request.Context(), request); err != nil {
+		t.Fatal(err)
+	}
+	RemoveForm(request)
+}
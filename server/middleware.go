@@ -2,6 +2,8 @@ package server
 
 import (
 	"net/http"
+
+	"github.com/go-leo/goose"
 )
 
 // Middleware defines a function type for HTTP middleware.
@@ -61,6 +63,8 @@ func getInvoker(interceptors []Middleware, curr int, finalInvoker http.HandlerFu
 }
 
 // Invoke wraps a Middleware and a final handler function into an http.Handler.
+// It attaches an empty goose.ResponseMetadata to the request context, so that
+// services can call goose.SetStatus, goose.SetHeader and goose.SetTrailer.
 // If the middleware is nil, directly calls the final handler.
 // Otherwise, executes the middleware chain ending with the final handler.
 //
@@ -71,6 +75,7 @@ func getInvoker(interceptors []Middleware, curr int, finalInvoker http.HandlerFu
 //	response - http.ResponseWriter to write the HTTP response
 //	request - *http.Request representing the incoming HTTP request
 func Invoke(middleware Middleware, response http.ResponseWriter, request *http.Request, invoke http.HandlerFunc) {
+	request = request.WithContext(goose.NewResponseMetadataContext(request.Context()))
 	if middleware == nil {
 		invoke(response, request)
 		return
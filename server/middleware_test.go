@@ -4,6 +4,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/go-leo/goose"
 )
 
 func makeWriteMiddleware(s string, callNext bool) Middleware {
@@ -66,3 +68,16 @@ func TestChainMultipleMiddlewaresOrder(t *testing.T) {
 		t.Fatalf("expected body %q, got %q", "123F", got)
 	}
 }
+
+func TestInvokeAttachesResponseMetadata(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "http://example.test/", nil)
+
+	final := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := goose.SetStatus(r.Context(), http.StatusCreated); err != nil {
+			t.Errorf("SetStatus() error = %v", err)
+		}
+	})
+
+	Invoke(nil, rec, req, final)
+}
@@ -1,11 +1,15 @@
 package server
 
 import (
+	"strings"
+
 	"github.com/go-leo/goose"
 	"google.golang.org/protobuf/encoding/protojson"
 )
 
-// Options interface defines methods to access all configurable options for the server
+// Options interface defines methods to access all configurable options for the server.
+// It is implemented by NewOptions and gains methods as options are added, such as PathPrefix;
+// implementations outside of this package should embed the Options returned by NewOptions.
 type Options interface {
 	// UnmarshalOptions returns the protojson unmarshal options used for decoding requests
 	UnmarshalOptions() protojson.UnmarshalOptions
@@ -24,6 +28,12 @@ type Options interface {
 
 	// OnValidationErrCallback returns the validation error callback
 	OnValidationErrCallback() goose.OnValidationErrCallback
+
+	// PathPrefix returns the prefix of the paths of the routes
+	PathPrefix() string
+
+	// RouteRegistry returns the registry recording the routes
+	RouteRegistry() *goose.RouteRegistry
 }
 
 // options holds the configuration options for the server
@@ -34,6 +44,11 @@ type options struct {
 	middlewares             []Middleware                  // Middlewares applied to requests
 	shouldFailFast          bool                          // Flag indicating if fail-fast mode is enabled
 	onValidationErrCallback goose.OnValidationErrCallback // Callback for validation errors
+	maxRequestBytes         int64                         // Maximum size of the raw request body, 0 means unlimited
+	maxDecompressedBytes    int64                         // Maximum size of the decompressed request body, 0 means unlimited
+	decompressors           map[string]Decompressor       // Decompressors by Content-Encoding
+	pathPrefix              string                        // Prefix of the paths of the routes
+	routeRegistry           *goose.RouteRegistry          // Registry recording the routes
 }
 
 // Option defines a function type for modifying server options
@@ -77,12 +92,14 @@ func (o *options) ErrorEncoder() goose.ErrorEncoder {
 	return o.errorEncoder
 }
 
-// Middlewares returns the list of middlewares applied to requests
+// Middlewares returns the list of middlewares applied to requests.
+// The request body middleware, enforcing MaxRequestBytes and decompressing
+// request bodies, always comes first.
 //
 // Returns:
 //   - []Middleware: The list of middlewares
 func (o *options) Middlewares() []Middleware {
-	return o.middlewares
+	return append([]Middleware{requestBodyMiddleware(o)}, o.middlewares...)
 }
 
 // ShouldFailFast indicates if fail-fast mode is enabled
@@ -101,6 +118,22 @@ func (o *options) OnValidationErrCallback() goose.OnValidationErrCallback {
 	return o.onValidationErrCallback
 }
 
+// PathPrefix returns the prefix of the paths of the routes
+//
+// Returns:
+//   - string: The prefix, such as "/api/internal", empty by default
+func (o *options) PathPrefix() string {
+	return o.pathPrefix
+}
+
+// RouteRegistry returns the registry recording the routes
+//
+// Returns:
+//   - *goose.RouteRegistry: The registry, goose.DefaultRouteRegistry by default
+func (o *options) RouteRegistry() *goose.RouteRegistry {
+	return o.routeRegistry
+}
+
 // UnmarshalOptions sets the protojson unmarshal options used for decoding requests
 //
 // Parameters:
@@ -176,6 +209,88 @@ func FailFast() Option {
 	}
 }
 
+// MaxRequestBytes limits the size of request bodies as sent by the client.
+// Larger requests fail with 413 Request Entity Too Large, in all decoders.
+// Streamed request bodies (goose.HttpBodyStream) are not limited, the service reads them at its own pace.
+//
+// Parameters:
+//   - n: The maximum number of bytes, 0 means unlimited
+//
+// Returns:
+//   - Option: A function that sets the request body limit
+func MaxRequestBytes(n int64) Option {
+	return func(o *options) {
+		o.maxRequestBytes = n
+	}
+}
+
+// MaxDecompressedBytes limits the size of compressed request bodies once decompressed,
+// protecting against zip bombs. Larger requests fail with 413 Request Entity Too Large.
+// Defaults to DefaultMaxDecompressedBytes. Streamed request bodies (goose.HttpBodyStream) are not limited.
+//
+// Parameters:
+//   - n: The maximum number of bytes, 0 means unlimited
+//
+// Returns:
+//   - Option: A function that sets the decompressed request body limit
+func MaxDecompressedBytes(n int64) Option {
+	return func(o *options) {
+		o.maxDecompressedBytes = n
+	}
+}
+
+// Decompression registers the decompressor of a Content-Encoding.
+// Only "gzip" and "deflate" are built in and registered by default, other encodings such as "zstd"
+// need a decompressor, such as compress.ZstdDecompressor of the middleware/compress module.
+// A nil decompressor unregisters the encoding.
+// Requests with an unregistered encoding fail with 415 Unsupported Media Type,
+// except streamed request bodies (goose.HttpBodyStream), which are handed over still encoded.
+//
+// Parameters:
+//   - encoding: The Content-Encoding value, case-insensitive
+//   - decompressor: The decompressor of the encoding
+//
+// Returns:
+//   - Option: A function that registers the decompressor
+func Decompression(encoding string, decompressor Decompressor) Option {
+	return func(o *options) {
+		encoding = strings.ToLower(encoding)
+		if decompressor == nil {
+			delete(o.decompressors, encoding)
+			return
+		}
+		o.decompressors[encoding] = decompressor
+	}
+}
+
+// PathPrefix mounts the routes under a prefix, such as "/api/internal",
+// the clients must use the same prefix with client.PathPrefix.
+//
+// Parameters:
+//   - prefix: The prefix, slashes around it are normalized
+//
+// Returns:
+//   - Option: A function that sets the path prefix
+func PathPrefix(prefix string) Option {
+	return func(o *options) {
+		o.pathPrefix = goose.CleanPathPrefix(prefix)
+	}
+}
+
+// RouteRegistry sets the registry recording the routes and detecting their conflicts,
+// such as a registry scoped to a test instead of the global goose.DefaultRouteRegistry
+//
+// Parameters:
+//   - registry: The route registry
+//
+// Returns:
+//   - Option: A function that sets the route registry
+func RouteRegistry(registry *goose.RouteRegistry) Option {
+	return func(o *options) {
+		o.routeRegistry = registry
+	}
+}
+
 // NewOptions creates a new Options instance with default values and applies the provided options
 //
 // Parameters:
@@ -191,6 +306,10 @@ func NewOptions(opts ...Option) Options {
 		middlewares:             []Middleware{},
 		shouldFailFast:          false,
 		onValidationErrCallback: nil,
+		maxRequestBytes:         0,
+		maxDecompressedBytes:    DefaultMaxDecompressedBytes,
+		decompressors:           defaultDecompressors(),
+		routeRegistry:           goose.DefaultRouteRegistry,
 	}
 	o = o.apply(opts...)
 	return o
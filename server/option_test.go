@@ -26,6 +26,12 @@ func TestOptions_Defaults(t *testing.T) {
 	if opts.ErrorEncoder() == nil {
 		t.Errorf("default ErrorEncoder is nil")
 	}
+	if opts.PathPrefix() != "" {
+		t.Errorf("default PathPrefix not empty")
+	}
+	if opts.RouteRegistry() != goose.DefaultRouteRegistry {
+		t.Errorf("default RouteRegistry is not goose.DefaultRouteRegistry")
+	}
 }
 
 func TestOptions_WithOptions(t *testing.T) {
@@ -63,3 +69,17 @@ func TestOptions_Apply(t *testing.T) {
 		t.Errorf("Apply did not set MarshalOptions.UseProtoNames")
 	}
 }
+
+func TestOptions_PathPrefix(t *testing.T) {
+	opts := NewOptions(PathPrefix("api/internal/"))
+	if got := opts.PathPrefix(); got != "/api/internal" {
+		t.Errorf("PathPrefix not normalized, got %q", got)
+	}
+}
+
+func TestOptions_RouteRegistry(t *testing.T) {
+	registry := goose.NewRouteRegistry()
+	if NewOptions(RouteRegistry(registry)).RouteRegistry() != registry {
+		t.Errorf("RouteRegistry not set correctly")
+	}
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"net/http"
+)
+
+// RecordingResponseWriter wraps an http.ResponseWriter to record the status code and the body size of the response,
+// for middlewares logging or measuring the responses
+type RecordingResponseWriter struct {
+	http.ResponseWriter
+	statusCode  int   // Recorded HTTP status code
+	written     int64 // Number of body bytes written
+	wroteHeader bool  // Whether the final header was written
+}
+
+// NewRecordingResponseWriter wraps a response writer
+// Parameters:
+//   - response: The wrapped response writer
+//
+// Returns:
+//   - *RecordingResponseWriter: The recording writer, to pass to the next handler
+func NewRecordingResponseWriter(response http.ResponseWriter) *RecordingResponseWriter {
+	return &RecordingResponseWriter{ResponseWriter: response, statusCode: http.StatusOK}
+}
+
+// StatusCode returns the status code of the response,
+// 200 OK if the handler wrote the body without calling WriteHeader or wrote nothing
+func (w *RecordingResponseWriter) StatusCode() int {
+	return w.statusCode
+}
+
+// Written returns the number of body bytes written
+func (w *RecordingResponseWriter) Written() int64 {
+	return w.written
+}
+
+// WriteHeader records the status code before calling the wrapped WriteHeader.
+// Informational responses, such as 103 Early Hints, are not recorded.
+func (w *RecordingResponseWriter) WriteHeader(statusCode int) {
+	if !w.wroteHeader {
+		w.statusCode = statusCode
+		w.wroteHeader = statusCode >= http.StatusOK || statusCode == http.StatusSwitchingProtocols
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write counts the body bytes before calling the wrapped Write
+func (w *RecordingResponseWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(p)
+	w.written += int64(n)
+	return n, err
+}
+
+// Flush flushes the wrapped writer, for streamed responses
+func (w *RecordingResponseWriter) Flush() {
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap returns the wrapped writer, for http.ResponseController
+func (w *RecordingResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
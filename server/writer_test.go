@@ -0,0 +1,83 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRecordingResponseWriter(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		statusCode int
+		written    int64
+	}{
+		{
+			name:       "nothing written",
+			handler:    func(w http.ResponseWriter, r *http.Request) {},
+			statusCode: http.StatusOK,
+		},
+		{
+			name: "implicit status",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = io.WriteString(w, "hello")
+			},
+			statusCode: http.StatusOK,
+			written:    5,
+		},
+		{
+			name: "explicit status",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_, _ = io.WriteString(w, "unavailable")
+			},
+			statusCode: http.StatusServiceUnavailable,
+			written:    11,
+		},
+		{
+			name: "informational then final",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusEarlyHints)
+				w.WriteHeader(http.StatusCreated)
+			},
+			statusCode: http.StatusCreated,
+		},
+		{
+			name: "superfluous WriteHeader",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = io.WriteString(w, "ok")
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			statusCode: http.StatusOK,
+			written:    2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := NewRecordingResponseWriter(httptest.NewRecorder())
+			tt.handler(writer, httptest.NewRequest(http.MethodGet, "/", nil))
+			if writer.StatusCode() != tt.statusCode {
+				t.Errorf("StatusCode() = %d, want %d", writer.StatusCode(), tt.statusCode)
+			}
+			if writer.Written() != tt.written {
+				t.Errorf("Written() = %d, want %d", writer.Written(), tt.written)
+			}
+		})
+	}
+}
+
+func TestRecordingResponseWriterFlush(t *testing.T) {
+	rec := httptest.NewRecorder()
+	writer := NewRecordingResponseWriter(rec)
+	if err := http.NewResponseController(writer).Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if !rec.Flushed {
+		t.Error("the flush did not reach the wrapped writer")
+	}
+	if writer.Unwrap() != rec {
+		t.Error("Unwrap must return the wrapped writer")
+	}
+}
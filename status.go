@@ -3,6 +3,7 @@ package goose
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -150,6 +151,10 @@ type HeaderGetter interface {
 // - json.Marshaler: encodes error as JSON if implemented
 // - Headers() http.Header: adds headers to response if implemented
 // - StatusCode() int: uses custom status code if implemented
+// The status code and headers are also taken from wrapped errors, such as a
+// body limit error wrapped by a decoder.
+// Headers set by the service with SetHeader are sent as well, the status code set
+// with SetStatus is ignored.
 //
 // Parameters:
 //   - ctx: context.Context for the request
@@ -158,7 +163,8 @@ type HeaderGetter interface {
 func DefaultEncodeError(ctx context.Context, respErr error, response http.ResponseWriter) {
 	// Default to 500 status code unless error provides specific status code
 	code := http.StatusInternalServerError
-	if statusCodeGetter, ok := respErr.(StatusCodeGetter); ok {
+	var statusCodeGetter StatusCodeGetter
+	if errors.As(respErr, &statusCodeGetter) {
 		code = statusCodeGetter.StatusCode()
 	}
 
@@ -174,11 +180,18 @@ func DefaultEncodeError(ctx context.Context, respErr error, response http.Respon
 	}
 
 	header := response.Header()
+	// Apply the headers set by the service, such as Set-Cookie
+	if md, ok := ResponseMetadataFromContext(ctx); ok {
+		for key, values := range md.Header() {
+			header[key] = values
+		}
+	}
 	// Set response content type header
 	header.Set(ContentTypeKey, contentType)
 	// If error provides custom headers, add them to the response
 	keys := make([]string, 0)
-	if headerGetter, ok := respErr.(HeaderGetter); ok {
+	var headerGetter HeaderGetter
+	if errors.As(respErr, &headerGetter) {
 		for key, values := range headerGetter.Headers() {
 			for _, v := range values {
 				header.Add(key, v)
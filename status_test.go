@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -83,3 +84,19 @@ func TestDefaultEncodeError_status(t *testing.T) {
 		t.Errorf("status = %d, want 418", resp.StatusCode)
 	}
 }
+
+func TestDefaultEncodeError_wrapped(t *testing.T) {
+	rr := httptest.NewRecorder()
+	DefaultEncodeError(context.Background(), fmt.Errorf("decode: %w", statusErr{}), rr)
+	resp := rr.Result()
+	defer resp.Body.Close()
+	if resp.StatusCode != 418 {
+		t.Errorf("status = %d, want 418", resp.StatusCode)
+	}
+
+	rr = httptest.NewRecorder()
+	DefaultEncodeError(context.Background(), fmt.Errorf("decode: %w", headerErr{}), rr)
+	if rr.Header().Get("X-Test") != "1" {
+		t.Errorf("X-Test header not set")
+	}
+}